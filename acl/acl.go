@@ -0,0 +1,304 @@
+// Package acl implements an access control list and classification engine
+// for Ethernet frames.
+package acl
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// An ActionType is an action taken on a Frame which matches a Rule.
+type ActionType int
+
+// Possible ActionType values.  All actions other than Deny permit a Frame.
+const (
+	Permit ActionType = iota
+	Deny
+	SetPriority
+	SetVLAN
+	Mirror
+)
+
+// String returns the string representation of an ActionType.
+func (t ActionType) String() string {
+	switch t {
+	case Permit:
+		return "permit"
+	case Deny:
+		return "deny"
+	case SetPriority:
+		return "set-priority"
+	case SetVLAN:
+		return "set-vlan"
+	case Mirror:
+		return "mirror"
+	default:
+		return fmt.Sprintf("ActionType(%d)", int(t))
+	}
+}
+
+// An Action is taken on a Frame which matches a Rule.
+type Action struct {
+	// Type specifies the type of Action.
+	Type ActionType
+
+	// Priority specifies the new priority for a Frame when Type is
+	// SetPriority.
+	Priority ethernet.Priority
+
+	// VLAN specifies the new VLAN ID for a Frame when Type is SetVLAN.
+	VLAN uint16
+}
+
+// A Rule matches Frames using zero or more criteria.  A Frame matches a Rule
+// only if it satisfies all of the Rule's criteria.  A Rule with no criteria
+// matches every Frame.
+type Rule struct {
+	// Destination and Source match a Frame's destination and source hardware
+	// addresses.  If nil, the address is not matched.
+	//
+	// DestinationMask and SourceMask specify which bits of an address are
+	// matched.  If nil, an address must match exactly.
+	Destination, DestinationMask net.HardwareAddr
+	Source, SourceMask           net.HardwareAddr
+
+	// EtherType matches a Frame's EtherType.  If zero, the EtherType is not
+	// matched.
+	EtherType ethernet.EtherType
+
+	// VLAN matches the ID of a Frame's customer VLAN tag.  If nil, the VLAN
+	// ID is not matched.  Untagged Frames never match a non-nil VLAN, but
+	// priority tagged Frames match a VLAN of ethernet.VLANNone.
+	VLAN *uint16
+
+	// Priority matches the priority of a Frame's customer VLAN tag.  If nil,
+	// the priority is not matched.  Untagged Frames never match a non-nil
+	// Priority.
+	Priority *ethernet.Priority
+
+	// Action is taken on a Frame which matches this Rule.
+	Action Action
+}
+
+// An ACL is a compiled, ordered list of Rules.  The Action of the first Rule
+// which matches a Frame is taken; if no Rule matches, a default Action is
+// taken.  An ACL is safe for concurrent use.
+type ACL struct {
+	rules []rule
+	def   Action
+}
+
+// Compile compiles an ordered list of Rules into an ACL.  def specifies the
+// default Action when no Rule matches a Frame.
+func Compile(rules []Rule, def Action) (*ACL, error) {
+	if err := checkAction(def); err != nil {
+		return nil, fmt.Errorf("acl: default action: %v", err)
+	}
+
+	a := &ACL{
+		rules: make([]rule, 0, len(rules)),
+		def:   def,
+	}
+
+	for i, r := range rules {
+		cr, err := compile(r)
+		if err != nil {
+			return nil, fmt.Errorf("acl: rule %d: %v", i, err)
+		}
+
+		a.rules = append(a.rules, cr)
+	}
+
+	return a, nil
+}
+
+// Match returns the Action for the first Rule which matches f, along with
+// the index of that Rule.  If no Rule matches, the default Action and index
+// -1 are returned.
+func (a *ACL) Match(f *ethernet.Frame) (Action, int) {
+	var (
+		dst, dstOK = macUint64(f.Destination)
+		src, srcOK = macUint64(f.Source)
+	)
+
+	for i, r := range a.rules {
+		if r.flags&matchDestination != 0 && (!dstOK || dst&r.dstMask != r.dst) {
+			continue
+		}
+		if r.flags&matchSource != 0 && (!srcOK || src&r.srcMask != r.src) {
+			continue
+		}
+		if r.flags&matchEtherType != 0 && f.EtherType != r.etherType {
+			continue
+		}
+		if r.flags&matchVLAN != 0 && (f.VLAN == nil || f.VLAN.ID != r.vlan) {
+			continue
+		}
+		if r.flags&matchPriority != 0 && (f.VLAN == nil || f.VLAN.Priority != r.priority) {
+			continue
+		}
+
+		return r.action, i
+	}
+
+	return a.def, -1
+}
+
+// Permits reports whether f is permitted by the ACL.  Permits may be used as
+// a filter function in other packages.
+func (a *ACL) Permits(f *ethernet.Frame) bool {
+	action, _ := a.Match(f)
+	return action.Type != Deny
+}
+
+// Apply matches f against the ACL, and applies any modifications specified
+// by the resulting Action to f.  The Action is returned so the caller can
+// determine whether f should be dropped or mirrored.
+//
+// A SetPriority or SetVLAN Action applied to an untagged Frame adds a VLAN
+// tag to the Frame.
+func (a *ACL) Apply(f *ethernet.Frame) Action {
+	action, _ := a.Match(f)
+
+	switch action.Type {
+	case SetPriority:
+		if f.VLAN == nil {
+			f.VLAN = &ethernet.VLAN{}
+		}
+		f.VLAN.Priority = action.Priority
+	case SetVLAN:
+		if f.VLAN == nil {
+			f.VLAN = &ethernet.VLAN{}
+		}
+		f.VLAN.ID = action.VLAN
+	}
+
+	return action
+}
+
+// Flags which indicate which criteria a compiled rule matches.
+const (
+	matchDestination = 1 << iota
+	matchSource
+	matchEtherType
+	matchVLAN
+	matchPriority
+)
+
+// A rule is a compiled Rule.
+type rule struct {
+	flags        int
+	dst, dstMask uint64
+	src, srcMask uint64
+	etherType    ethernet.EtherType
+	vlan         uint16
+	priority     ethernet.Priority
+	action       Action
+}
+
+// compile compiles a single Rule.
+func compile(r Rule) (rule, error) {
+	if err := checkAction(r.Action); err != nil {
+		return rule{}, err
+	}
+
+	cr := rule{
+		etherType: r.EtherType,
+		action:    r.Action,
+	}
+
+	var err error
+	if r.Destination != nil {
+		cr.flags |= matchDestination
+		cr.dst, cr.dstMask, err = compileMAC(r.Destination, r.DestinationMask)
+		if err != nil {
+			return rule{}, fmt.Errorf("destination: %v", err)
+		}
+	}
+
+	if r.Source != nil {
+		cr.flags |= matchSource
+		cr.src, cr.srcMask, err = compileMAC(r.Source, r.SourceMask)
+		if err != nil {
+			return rule{}, fmt.Errorf("source: %v", err)
+		}
+	}
+
+	if r.EtherType != 0 {
+		cr.flags |= matchEtherType
+	}
+
+	if r.VLAN != nil {
+		if *r.VLAN >= ethernet.VLANMax {
+			return rule{}, ethernet.ErrInvalidVLAN
+		}
+
+		cr.flags |= matchVLAN
+		cr.vlan = *r.VLAN
+	}
+
+	if r.Priority != nil {
+		if *r.Priority > ethernet.PriorityNetworkControl {
+			return rule{}, ethernet.ErrInvalidVLAN
+		}
+
+		cr.flags |= matchPriority
+		cr.priority = *r.Priority
+	}
+
+	return cr, nil
+}
+
+// compileMAC compiles a hardware address and optional mask into integers.
+func compileMAC(addr, mask net.HardwareAddr) (uint64, uint64, error) {
+	a, ok := macUint64(addr)
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid hardware address: %q", addr)
+	}
+
+	if mask == nil {
+		return a, 0xffffffffffff, nil
+	}
+
+	m, ok := macUint64(mask)
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid hardware address mask: %q", mask)
+	}
+
+	return a & m, m, nil
+}
+
+// checkAction verifies that an Action's parameters are valid.
+func checkAction(a Action) error {
+	switch a.Type {
+	case Permit, Deny, Mirror:
+	case SetPriority:
+		if a.Priority > ethernet.PriorityNetworkControl {
+			return ethernet.ErrInvalidVLAN
+		}
+	case SetVLAN:
+		if a.VLAN >= ethernet.VLANMax {
+			return ethernet.ErrInvalidVLAN
+		}
+	default:
+		return fmt.Errorf("unknown action: %s", a.Type)
+	}
+
+	return nil
+}
+
+// macUint64 packs a 6-byte hardware address into an integer.
+func macUint64(addr net.HardwareAddr) (uint64, bool) {
+	if len(addr) != 6 {
+		return 0, false
+	}
+
+	var v uint64
+	for _, b := range addr {
+		v = v<<8 | uint64(b)
+	}
+
+	return v, true
+}
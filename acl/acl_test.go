@@ -0,0 +1,307 @@
+package acl
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestCompileErrors(t *testing.T) {
+	var (
+		badVLAN     = uint16(ethernet.VLANMax)
+		badPriority = ethernet.Priority(8)
+	)
+
+	tests := []struct {
+		desc  string
+		rules []Rule
+		def   Action
+	}{
+		{
+			desc: "bad default action",
+			def:  Action{Type: 100},
+		},
+		{
+			desc:  "bad destination",
+			rules: []Rule{{Destination: net.HardwareAddr{0}}},
+		},
+		{
+			desc: "bad source mask",
+			rules: []Rule{{
+				Source:     net.HardwareAddr{0, 0, 0, 0, 0, 0},
+				SourceMask: net.HardwareAddr{0},
+			}},
+		},
+		{
+			desc:  "bad VLAN",
+			rules: []Rule{{VLAN: &badVLAN}},
+		},
+		{
+			desc:  "bad priority",
+			rules: []Rule{{Priority: &badPriority}},
+		},
+		{
+			desc: "bad set priority",
+			rules: []Rule{{Action: Action{
+				Type:     SetPriority,
+				Priority: badPriority,
+			}}},
+		},
+		{
+			desc: "bad set VLAN",
+			rules: []Rule{{Action: Action{
+				Type: SetVLAN,
+				VLAN: badVLAN,
+			}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if _, err := Compile(tt.rules, tt.def); err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+		})
+	}
+}
+
+func TestACLMatch(t *testing.T) {
+	var (
+		vlan10   = uint16(10)
+		none     = uint16(ethernet.VLANNone)
+		priority = ethernet.PriorityVoice
+
+		deny   = Action{Type: Deny}
+		mirror = Action{Type: Mirror}
+	)
+
+	rules := []Rule{
+		// 0: deny a single source.
+		{
+			Source: net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0x00, 0x01},
+			Action: deny,
+		},
+		// 1: mirror all IPv4 multicast destinations.
+		{
+			Destination:     net.HardwareAddr{0x01, 0x00, 0x5e, 0x00, 0x00, 0x00},
+			DestinationMask: net.HardwareAddr{0xff, 0xff, 0xff, 0x80, 0x00, 0x00},
+			Action:          mirror,
+		},
+		// 2: deny ARP on VLAN 10.
+		{
+			EtherType: ethernet.EtherTypeARP,
+			VLAN:      &vlan10,
+			Action:    deny,
+		},
+		// 3: remark voice priority to VLAN 20.
+		{
+			Priority: &priority,
+			Action: Action{
+				Type: SetVLAN,
+				VLAN: 20,
+			},
+		},
+		// 4: mirror priority tagged frames.
+		{
+			VLAN:   &none,
+			Action: mirror,
+		},
+	}
+
+	a, err := Compile(rules, Action{Type: Permit})
+	if err != nil {
+		t.Fatalf("failed to compile: %v", err)
+	}
+
+	tests := []struct {
+		desc   string
+		f      *ethernet.Frame
+		action Action
+		i      int
+	}{
+		{
+			desc: "no match",
+			f: &ethernet.Frame{
+				Destination: ethernet.Broadcast,
+				Source:      net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0x00, 0x02},
+				EtherType:   ethernet.EtherTypeIPv4,
+			},
+			i: -1,
+		},
+		{
+			desc: "source",
+			f: &ethernet.Frame{
+				Destination: ethernet.Broadcast,
+				Source:      net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0x00, 0x01},
+				EtherType:   ethernet.EtherTypeIPv4,
+			},
+			action: deny,
+			i:      0,
+		},
+		{
+			desc: "masked destination",
+			f: &ethernet.Frame{
+				Destination: net.HardwareAddr{0x01, 0x00, 0x5e, 0x7f, 0xff, 0xfa},
+				EtherType:   ethernet.EtherTypeIPv4,
+			},
+			action: mirror,
+			i:      1,
+		},
+		{
+			desc: "masked destination outside mask",
+			f: &ethernet.Frame{
+				Destination: net.HardwareAddr{0x01, 0x00, 0x5e, 0x80, 0x00, 0x01},
+				EtherType:   ethernet.EtherTypeIPv4,
+			},
+			i: -1,
+		},
+		{
+			desc: "invalid destination length",
+			f: &ethernet.Frame{
+				Destination: net.HardwareAddr{0x01},
+				EtherType:   ethernet.EtherTypeIPv4,
+			},
+			i: -1,
+		},
+		{
+			desc: "EtherType without VLAN",
+			f: &ethernet.Frame{
+				EtherType: ethernet.EtherTypeARP,
+			},
+			i: -1,
+		},
+		{
+			desc: "EtherType and VLAN",
+			f: &ethernet.Frame{
+				VLAN:      &ethernet.VLAN{ID: 10},
+				EtherType: ethernet.EtherTypeARP,
+			},
+			action: deny,
+			i:      2,
+		},
+		{
+			desc: "priority",
+			f: &ethernet.Frame{
+				VLAN: &ethernet.VLAN{
+					Priority: ethernet.PriorityVoice,
+					ID:       10,
+				},
+				EtherType: ethernet.EtherTypeIPv4,
+			},
+			action: rules[3].Action,
+			i:      3,
+		},
+		{
+			desc: "priority tag",
+			f: &ethernet.Frame{
+				VLAN:      ethernet.PriorityTag(ethernet.PriorityBackground),
+				EtherType: ethernet.EtherTypeIPv4,
+			},
+			action: mirror,
+			i:      4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			action, i := a.Match(tt.f)
+			if want, got := tt.action, action; want != got {
+				t.Fatalf("unexpected action: %+v != %+v", want, got)
+			}
+			if want, got := tt.i, i; want != got {
+				t.Fatalf("unexpected rule index: %v != %v", want, got)
+			}
+			if want, got := tt.action.Type != Deny, a.Permits(tt.f); want != got {
+				t.Fatalf("unexpected permit: %v != %v", want, got)
+			}
+		})
+	}
+}
+
+func TestACLApply(t *testing.T) {
+	tests := []struct {
+		desc   string
+		action Action
+		in     *ethernet.VLAN
+		out    *ethernet.VLAN
+	}{
+		{
+			desc:   "permit",
+			action: Action{Type: Permit},
+			in:     &ethernet.VLAN{ID: 10},
+			out:    &ethernet.VLAN{ID: 10},
+		},
+		{
+			desc: "set priority, untagged",
+			action: Action{
+				Type:     SetPriority,
+				Priority: ethernet.PriorityVideo,
+			},
+			out: &ethernet.VLAN{Priority: ethernet.PriorityVideo},
+		},
+		{
+			desc: "set priority, tagged",
+			action: Action{
+				Type:     SetPriority,
+				Priority: ethernet.PriorityVideo,
+			},
+			in: &ethernet.VLAN{ID: 10},
+			out: &ethernet.VLAN{
+				Priority: ethernet.PriorityVideo,
+				ID:       10,
+			},
+		},
+		{
+			desc: "set VLAN, untagged",
+			action: Action{
+				Type: SetVLAN,
+				VLAN: 20,
+			},
+			out: &ethernet.VLAN{ID: 20},
+		},
+		{
+			desc: "set VLAN, tagged",
+			action: Action{
+				Type: SetVLAN,
+				VLAN: 20,
+			},
+			in: &ethernet.VLAN{
+				Priority: ethernet.PriorityVoice,
+				ID:       10,
+			},
+			out: &ethernet.VLAN{
+				Priority: ethernet.PriorityVoice,
+				ID:       20,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			a, err := Compile(nil, tt.action)
+			if err != nil {
+				t.Fatalf("failed to compile: %v", err)
+			}
+
+			f := &ethernet.Frame{VLAN: tt.in}
+			if want, got := tt.action, a.Apply(f); want != got {
+				t.Fatalf("unexpected action: %+v != %+v", want, got)
+			}
+
+			if want, got := tt.out, f.VLAN; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected VLAN:\n- want: %+v\n-  got: %+v", want, got)
+			}
+		})
+	}
+}
+
+func TestActionTypeString(t *testing.T) {
+	if want, got := "set-vlan", SetVLAN.String(); want != got {
+		t.Fatalf("unexpected string: %v != %v", want, got)
+	}
+	if want, got := "ActionType(100)", ActionType(100).String(); want != got {
+		t.Fatalf("unexpected string: %v != %v", want, got)
+	}
+}
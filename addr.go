@@ -0,0 +1,42 @@
+package ethernet
+
+import (
+	"bytes"
+	"net"
+)
+
+// IsBroadcast reports whether a is the broadcast hardware address.
+func IsBroadcast(a net.HardwareAddr) bool {
+	return bytes.Equal(a, Broadcast)
+}
+
+// IsMulticast reports whether a is a group hardware address, with the
+// multicast bit set.  The broadcast address is also a multicast address, so
+// callers which treat broadcast frames differently should check IsBroadcast
+// first.
+func IsMulticast(a net.HardwareAddr) bool {
+	return len(a) > 0 && a[0]&0x01 != 0
+}
+
+// IsUnicast reports whether a is an individual hardware address, with the
+// multicast bit unset.
+func IsUnicast(a net.HardwareAddr) bool {
+	return len(a) > 0 && a[0]&0x01 == 0
+}
+
+// IsBroadcast reports whether a Frame's destination is the broadcast
+// address.
+func (f *Frame) IsBroadcast() bool {
+	return IsBroadcast(f.Destination)
+}
+
+// IsMulticast reports whether a Frame's destination is a multicast address,
+// including the broadcast address.
+func (f *Frame) IsMulticast() bool {
+	return IsMulticast(f.Destination)
+}
+
+// IsUnicast reports whether a Frame's destination is a unicast address.
+func (f *Frame) IsUnicast() bool {
+	return IsUnicast(f.Destination)
+}
@@ -0,0 +1,50 @@
+package ethernet
+
+import (
+	"net"
+	"testing"
+)
+
+func TestFrameAddressClass(t *testing.T) {
+	tests := []struct {
+		desc                          string
+		addr                          net.HardwareAddr
+		broadcast, multicast, unicast bool
+	}{
+		{
+			desc: "empty",
+		},
+		{
+			desc:      "broadcast",
+			addr:      Broadcast,
+			broadcast: true,
+			multicast: true,
+		},
+		{
+			desc:      "multicast",
+			addr:      net.HardwareAddr{0x01, 0x00, 0x5e, 0x00, 0x00, 0x01},
+			multicast: true,
+		},
+		{
+			desc:    "unicast",
+			addr:    net.HardwareAddr{0x02, 0x00, 0x5e, 0x00, 0x00, 0x01},
+			unicast: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			f := &Frame{Destination: tt.addr}
+
+			if want, got := tt.broadcast, f.IsBroadcast(); want != got {
+				t.Fatalf("unexpected broadcast: %v != %v", want, got)
+			}
+			if want, got := tt.multicast, f.IsMulticast(); want != got {
+				t.Fatalf("unexpected multicast: %v != %v", want, got)
+			}
+			if want, got := tt.unicast, f.IsUnicast(); want != got {
+				t.Fatalf("unexpected unicast: %v != %v", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package ethernet
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+)
+
+// ARP constants for Ethernet and IPv4, as defined in RFC 826.
+const (
+	arpLen           = 28
+	arpHardwareEther = 1
+	arpOpRequest     = 1
+)
+
+// ErrInvalidIP is returned when an IP address of the wrong family is
+// provided, such as an IPv6 address where an IPv4 address is required.
+var ErrInvalidIP = errors.New("invalid IP address")
+
+// NewGratuitousARP creates a broadcast Frame which announces that IPv4
+// address ip is in use by hardware address mac, causing neighbors to update
+// their ARP caches.
+//
+// The Frame carries an ARP request with ip as both its sender and target
+// protocol addresses, and with a zero target hardware address, as
+// recommended for ARP announcements by RFC 5227.
+func NewGratuitousARP(mac net.HardwareAddr, ip net.IP) (*Frame, error) {
+	return newARP(mac, ip, ip)
+}
+
+// NewARPProbe creates a broadcast Frame which probes whether IPv4 address ip
+// is in use by another host, before hardware address mac claims it.
+//
+// The Frame carries an ARP request with an all-zeros sender protocol
+// address, so that neighbors do not update their ARP caches, and ip as its
+// target protocol address, as described in RFC 5227.  Any reply or probe
+// for ip from another host indicates an address conflict.
+func NewARPProbe(mac net.HardwareAddr, ip net.IP) (*Frame, error) {
+	return newARP(mac, net.IPv4zero, ip)
+}
+
+// newARP creates a broadcast Frame carrying an ARP request from mac, with
+// sender protocol address spa and target protocol address tpa.
+func newARP(mac net.HardwareAddr, spa, tpa net.IP) (*Frame, error) {
+	if len(mac) != 6 {
+		return nil, ErrInvalidHardwareAddr
+	}
+
+	spa4, tpa4 := spa.To4(), tpa.To4()
+	if spa4 == nil || tpa4 == nil {
+		return nil, ErrInvalidIP
+	}
+
+	b := make([]byte, arpLen)
+	binary.BigEndian.PutUint16(b[0:2], arpHardwareEther)
+	binary.BigEndian.PutUint16(b[2:4], uint16(EtherTypeIPv4))
+	b[4] = 6
+	b[5] = 4
+	binary.BigEndian.PutUint16(b[6:8], arpOpRequest)
+	copy(b[8:14], mac)
+	copy(b[14:18], spa4)
+	copy(b[24:28], tpa4)
+
+	return &Frame{
+		Destination: append(net.HardwareAddr(nil), Broadcast...),
+		Source:      mac,
+		EtherType:   EtherTypeARP,
+		Payload:     b,
+	}, nil
+}
@@ -0,0 +1,96 @@
+package ethernet
+
+import (
+	"bytes"
+	"net"
+	"testing"
+)
+
+func TestNewARP(t *testing.T) {
+	mac := net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}
+	ip := net.IPv4(192, 0, 2, 1)
+
+	tests := []struct {
+		desc    string
+		fn      func(mac net.HardwareAddr, ip net.IP) (*Frame, error)
+		mac     net.HardwareAddr
+		ip      net.IP
+		payload []byte
+		err     error
+	}{
+		{
+			desc: "gratuitous bad hardware address",
+			fn:   NewGratuitousARP,
+			mac:  net.HardwareAddr{0xde, 0xad},
+			ip:   ip,
+			err:  ErrInvalidHardwareAddr,
+		},
+		{
+			desc: "gratuitous IPv6",
+			fn:   NewGratuitousARP,
+			mac:  mac,
+			ip:   net.ParseIP("2001:db8::1"),
+			err:  ErrInvalidIP,
+		},
+		{
+			desc: "gratuitous OK",
+			fn:   NewGratuitousARP,
+			mac:  mac,
+			ip:   ip,
+			payload: []byte{
+				0x00, 0x01, 0x08, 0x00, 0x06, 0x04, 0x00, 0x01,
+				0xde, 0xad, 0xbe, 0xef, 0xde, 0xad, 192, 0, 2, 1,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 192, 0, 2, 1,
+			},
+		},
+		{
+			desc: "probe OK",
+			fn:   NewARPProbe,
+			mac:  mac,
+			ip:   ip,
+			payload: []byte{
+				0x00, 0x01, 0x08, 0x00, 0x06, 0x04, 0x00, 0x01,
+				0xde, 0xad, 0xbe, 0xef, 0xde, 0xad, 0, 0, 0, 0,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 192, 0, 2, 1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			f, err := tt.fn(tt.mac, tt.ip)
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error: %v != %v", want, got)
+			}
+			if err != nil {
+				return
+			}
+
+			if !f.IsBroadcast() {
+				t.Fatalf("frame is not broadcast: %v", f.Destination)
+			}
+			if want, got := tt.mac, f.Source; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected source: %v != %v", want, got)
+			}
+			if want, got := EtherTypeARP, f.EtherType; want != got {
+				t.Fatalf("unexpected EtherType: %v != %v", want, got)
+			}
+			if want, got := tt.payload, f.Payload; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected payload:\n- want: %v\n-  got: %v", want, got)
+			}
+		})
+	}
+}
+
+func TestNewARPDestinationCopy(t *testing.T) {
+	f, err := NewGratuitousARP(net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}, net.IPv4(192, 0, 2, 1))
+	if err != nil {
+		t.Fatalf("failed to create frame: %v", err)
+	}
+
+	// Modifying the Frame must not modify the package-level address.
+	f.Destination[0] = 0x00
+	if want, got := (net.HardwareAddr{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}), Broadcast; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected broadcast address: %v != %v", want, got)
+	}
+}
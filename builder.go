@@ -0,0 +1,178 @@
+package ethernet
+
+import "net"
+
+// A Builder constructs a Frame using a chain of method calls, such as:
+//
+//	f, err := ethernet.NewBuilder().
+//		Dst(ethernet.Broadcast).
+//		Src(mac).
+//		VLAN(ethernet.VLAN{ID: 10}).
+//		EtherType(ethernet.EtherTypeARP).
+//		Payload(arp).
+//		Build()
+//
+// Each method checks its input as it is called.  The first error detected
+// is retained, causing all further method calls to be ignored, and is
+// returned by Build or Bytes.
+type Builder struct {
+	f   Frame
+	err error
+}
+
+// NewBuilder returns a Builder for an empty Frame.
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// Dst sets the destination hardware address of the Frame.
+func (b *Builder) Dst(addr net.HardwareAddr) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if len(addr) != 6 {
+		b.err = &FieldError{Field: "Destination", Offset: 0, Err: ErrInvalidHardwareAddr}
+		return b
+	}
+
+	b.f.Destination = addr
+	return b
+}
+
+// Src sets the source hardware address of the Frame.
+func (b *Builder) Src(addr net.HardwareAddr) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if len(addr) != 6 {
+		b.err = &FieldError{Field: "Source", Offset: 6, Err: ErrInvalidHardwareAddr}
+		return b
+	}
+
+	b.f.Source = addr
+	return b
+}
+
+// ServiceVLAN sets the 802.1ad service VLAN tag of the Frame.  A customer
+// VLAN tag must also be set using VLAN.
+func (b *Builder) ServiceVLAN(v VLAN) *Builder {
+	return b.vlan("ServiceVLAN", 14, &b.f.ServiceVLAN, v)
+}
+
+// VLAN sets the 802.1Q customer VLAN tag of the Frame.
+func (b *Builder) VLAN(v VLAN) *Builder {
+	offset := 14
+	if b.f.ServiceVLAN != nil {
+		offset += 4
+	}
+
+	return b.vlan("VLAN", offset, &b.f.VLAN, v)
+}
+
+// vlan checks v, whose tag control information is located at offset, and
+// stores it in dst.
+func (b *Builder) vlan(field string, offset int, dst **VLAN, v VLAN) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if _, err := v.MarshalBinary(); err != nil {
+		b.err = &FieldError{Field: field, Offset: offset, Err: err}
+		return b
+	}
+
+	*dst = &v
+	return b
+}
+
+// EtherType sets the EtherType of the Frame.
+func (b *Builder) EtherType(et EtherType) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if et > maxPayload && et < minEtherType {
+		b.err = &FieldError{Field: "EtherType", Offset: 12, Err: ErrInvalidEtherType}
+		return b
+	}
+
+	b.f.EtherType = et
+	return b
+}
+
+// LLC sets the IEEE 802.2 LLC header of the Frame, causing it to be
+// marshaled as an IEEE 802.3 frame.
+func (b *Builder) LLC(l LLC) *Builder {
+	if b.err != nil {
+		return b
+	}
+
+	b.f.LLC = &l
+	return b
+}
+
+// SNAP sets the SNAP header of the Frame, which follows its LLC header.  If
+// no LLC header is set, the LLC header for SNAP is used: DSAP and SSAP
+// SAPSNAP, and unnumbered information control.  Otherwise, the LLC header
+// must use these values.
+func (b *Builder) SNAP(s SNAP) *Builder {
+	if b.err != nil {
+		return b
+	}
+
+	if b.f.LLC == nil {
+		b.f.LLC = &LLC{
+			DSAP:    SAPSNAP,
+			SSAP:    SAPSNAP,
+			Control: uint16(UnnumberedUI),
+		}
+	}
+	if !b.f.LLC.isSNAP() {
+		// The LLC header follows the EtherType, after any VLAN tags.
+		offset := 14
+		for _, v := range []*VLAN{b.f.ServiceVLAN, b.f.VLAN} {
+			if v != nil {
+				offset += 4
+			}
+		}
+
+		b.err = &FieldError{Field: "LLC", Offset: offset, Err: ErrInvalidLLC}
+		return b
+	}
+
+	b.f.SNAP = &s
+	return b
+}
+
+// Payload sets the payload of the Frame.
+func (b *Builder) Payload(p []byte) *Builder {
+	if b.err != nil {
+		return b
+	}
+
+	b.f.Payload = p
+	return b
+}
+
+// Build returns the Frame constructed by a Builder.  If any method detected
+// an error, that error is returned.  Otherwise, the Frame is checked using
+// Frame.Validate.
+func (b *Builder) Build() (*Frame, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if err := b.f.Validate(); err != nil {
+		return nil, err
+	}
+
+	f := b.f
+	return &f, nil
+}
+
+// Bytes builds the Frame, as with Build, and marshals it into binary form.
+func (b *Builder) Bytes() ([]byte, error) {
+	f, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return f.MarshalBinary()
+}
@@ -0,0 +1,145 @@
+package ethernet
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	src := net.HardwareAddr{0, 1, 0, 1, 0, 1}
+
+	f, err := NewBuilder().
+		Dst(Broadcast).
+		Src(src).
+		ServiceVLAN(VLAN{ID: 100}).
+		VLAN(VLAN{Priority: PriorityVoice, ID: 10}).
+		EtherType(EtherTypeARP).
+		Payload([]byte{1, 2, 3}).
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build: %v", err)
+	}
+
+	want := &Frame{
+		Destination: Broadcast,
+		Source:      src,
+		ServiceVLAN: &VLAN{ID: 100},
+		VLAN:        &VLAN{Priority: PriorityVoice, ID: 10},
+		EtherType:   EtherTypeARP,
+		Payload:     []byte{1, 2, 3},
+	}
+	if !reflect.DeepEqual(want, f) {
+		t.Fatalf("unexpected Frame:\n- want: %v\n-  got: %v", want, f)
+	}
+
+	b, err := NewBuilder().Dst(Broadcast).Src(src).EtherType(EtherTypeIPv4).Bytes()
+	if err != nil {
+		t.Fatalf("failed to build bytes: %v", err)
+	}
+	if want, got := 60, len(b); want != got {
+		t.Fatalf("unexpected length: %d != %d", want, got)
+	}
+}
+
+func TestBuilderSNAP(t *testing.T) {
+	src := net.HardwareAddr{0, 1, 0, 1, 0, 1}
+
+	f, err := NewBuilder().
+		Dst(Broadcast).
+		Src(src).
+		SNAP(SNAP{ProtocolID: uint16(EtherTypeIPv4)}).
+		Payload([]byte{1, 2, 3}).
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build: %v", err)
+	}
+
+	want := &Frame{
+		Destination: Broadcast,
+		Source:      src,
+		LLC:         &LLC{DSAP: SAPSNAP, SSAP: SAPSNAP, Control: uint16(UnnumberedUI)},
+		SNAP:        &SNAP{ProtocolID: uint16(EtherTypeIPv4)},
+		Payload:     []byte{1, 2, 3},
+	}
+	if !reflect.DeepEqual(want, f) {
+		t.Fatalf("unexpected Frame:\n- want: %v\n-  got: %v", want, f)
+	}
+
+	b, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	var got Frame
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if want, got := EncapsulationSNAP, got.Encapsulation; want != got {
+		t.Fatalf("unexpected encapsulation: %v != %v", want, got)
+	}
+	if !reflect.DeepEqual(want.SNAP, got.SNAP) {
+		t.Fatalf("unexpected SNAP:\n- want: %v\n-  got: %v", want.SNAP, got.SNAP)
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+	tests := []struct {
+		desc  string
+		b     *Builder
+		field string
+		err   error
+	}{
+		{
+			desc:  "bad destination",
+			b:     NewBuilder().Dst(net.HardwareAddr{0}),
+			field: "Destination",
+			err:   ErrInvalidHardwareAddr,
+		},
+		{
+			desc: "first error is retained",
+			b: NewBuilder().
+				Dst(Broadcast).
+				VLAN(VLAN{ID: VLANMax}).
+				Src(nil),
+			field: "VLAN",
+			err:   ErrInvalidVLAN,
+		},
+		{
+			desc:  "bad EtherType",
+			b:     NewBuilder().EtherType(0x05ff),
+			field: "EtherType",
+			err:   ErrInvalidEtherType,
+		},
+		{
+			desc:  "SNAP without SNAP LLC",
+			b:     NewBuilder().LLC(LLC{DSAP: 0x42, SSAP: 0x42, Control: 0x03}).SNAP(SNAP{}),
+			field: "LLC",
+			err:   ErrInvalidLLC,
+		},
+		{
+			desc:  "validation on build",
+			b:     NewBuilder().Dst(Broadcast).EtherType(EtherTypeIPv4),
+			field: "Source",
+			err:   ErrInvalidHardwareAddr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			_, err := tt.b.Build()
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v in %v", tt.err, err)
+			}
+
+			var fe *FieldError
+			if !errors.As(err, &fe) {
+				t.Fatalf("expected *FieldError, but got: %#v", err)
+			}
+			if want, got := tt.field, fe.Field; want != got {
+				t.Fatalf("unexpected field: %q != %q", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package capture
+
+import (
+	"io"
+
+	"github.com/mdlayher/ethernet/pcap"
+)
+
+// writePcap writes ps to w as a little-endian pcap file with nanosecond
+// resolution timestamps.
+func writePcap(w io.Writer, snapLen int, ps []Packet) error {
+	pw, err := pcap.NewWriter(w, snapLen)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range ps {
+		err := pw.WritePacket(&pcap.Packet{
+			Timestamp: p.Timestamp,
+			Length:    p.Length,
+			Data:      p.Data,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return pw.Flush()
+}
@@ -0,0 +1,44 @@
+package capture
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestRingDump(t *testing.T) {
+	r := NewRing(&RingConfig{SnapLen: 2})
+	r.now = func() time.Time { return time.Unix(1, 2) }
+	r.Add([]byte{0xaa, 0xbb, 0xcc})
+
+	var buf bytes.Buffer
+	if err := r.Dump(&buf); err != nil {
+		t.Fatalf("failed to dump: %v", err)
+	}
+
+	want := []byte{
+		// Global header: magic, version 2.4, zone, sigfigs, snaplen, link type.
+		0x4d, 0x3c, 0xb2, 0xa1,
+		0x02, 0x00, 0x04, 0x00,
+		0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00,
+		0x02, 0x00, 0x00, 0x00,
+		0x01, 0x00, 0x00, 0x00,
+		// Record header: seconds, nanoseconds, captured and original length.
+		0x01, 0x00, 0x00, 0x00,
+		0x02, 0x00, 0x00, 0x00,
+		0x02, 0x00, 0x00, 0x00,
+		0x03, 0x00, 0x00, 0x00,
+		// Data.
+		0xaa, 0xbb,
+	}
+
+	if got := buf.Bytes(); !bytes.Equal(want, got) {
+		t.Fatalf("unexpected pcap:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	// Capture continues after a dump.
+	if want, got := 1, r.Len(); want != got {
+		t.Fatalf("unexpected length after dump: %v != %v", want, got)
+	}
+}
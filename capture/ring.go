@@ -0,0 +1,182 @@
+// Package capture implements always-on, in-memory capture of recent Ethernet
+// frames, which can be dumped to a pcap file on demand.
+package capture
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/mdlayher/ethernet/conn"
+)
+
+// Defaults for RingConfig.
+const (
+	defaultMaxFrames = 4096
+	defaultSnapLen   = 65535
+)
+
+// RingConfig specifies configuration for a Ring.
+type RingConfig struct {
+	// MaxFrames specifies the maximum number of frames retained.  If zero,
+	// a default of 4096 is used.
+	MaxFrames int
+
+	// MaxBytes, if not zero, specifies the maximum number of captured bytes
+	// retained across all frames.
+	MaxBytes int
+
+	// MaxAge, if not zero, specifies the maximum age of retained frames.
+	MaxAge time.Duration
+
+	// SnapLen specifies the maximum number of bytes captured from each
+	// frame.  If zero, a default of 65535 is used.
+	SnapLen int
+}
+
+// A Packet is a frame captured by a Ring.
+type Packet struct {
+	// Timestamp is the time at which the frame was captured.
+	Timestamp time.Time
+
+	// Data is the captured frame, truncated to the Ring's snapshot length.
+	Data []byte
+
+	// Length is the original length of the frame.
+	Length int
+}
+
+// A Ring retains the most recently captured frames, discarding the oldest
+// when its limits are reached.  A Ring is safe for concurrent use.
+//
+// A Ring implements conn.Sink, so it can capture frames flowing through a
+// conn.Mirror.
+type Ring struct {
+	maxFrames, maxBytes, snapLen int
+	maxAge                       time.Duration
+
+	mu      sync.Mutex
+	packets []Packet
+	start   int
+	n       int
+	bytes   int
+
+	// now allows tests to control the passage of time.
+	now func() time.Time
+}
+
+var _ conn.Sink = &Ring{}
+
+// NewRing creates a Ring using the input configuration.  If cfg is nil, a
+// default configuration is used.
+func NewRing(cfg *RingConfig) *Ring {
+	if cfg == nil {
+		cfg = &RingConfig{}
+	}
+
+	r := &Ring{
+		maxFrames: cfg.MaxFrames,
+		maxBytes:  cfg.MaxBytes,
+		snapLen:   cfg.SnapLen,
+		maxAge:    cfg.MaxAge,
+		now:       time.Now,
+	}
+
+	if r.maxFrames == 0 {
+		r.maxFrames = defaultMaxFrames
+	}
+	if r.snapLen == 0 {
+		r.snapLen = defaultSnapLen
+	}
+
+	r.packets = make([]Packet, r.maxFrames)
+	return r
+}
+
+// Add captures a copy of frame b.
+func (r *Ring) Add(b []byte) {
+	data := b
+	if len(data) > r.snapLen {
+		data = data[:r.snapLen]
+	}
+
+	p := Packet{
+		Timestamp: r.now(),
+		Data:      append([]byte(nil), data...),
+		Length:    len(b),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expire(p.Timestamp)
+
+	for r.n > 0 && (r.n == r.maxFrames || (r.maxBytes > 0 && r.bytes+len(p.Data) > r.maxBytes)) {
+		r.pop()
+	}
+	if r.maxBytes > 0 && len(p.Data) > r.maxBytes {
+		// The frame alone exceeds the byte limit.
+		return
+	}
+
+	r.packets[(r.start+r.n)%len(r.packets)] = p
+	r.n++
+	r.bytes += len(p.Data)
+}
+
+// WriteFrame implements conn.Sink.  Frames are captured regardless of their
+// direction.
+func (r *Ring) WriteFrame(_ conn.Direction, b []byte) error {
+	r.Add(b)
+	return nil
+}
+
+// Packets returns the retained frames, oldest first.
+func (r *Ring) Packets() []Packet {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expire(r.now())
+
+	ps := make([]Packet, 0, r.n)
+	for i := 0; i < r.n; i++ {
+		ps = append(ps, r.packets[(r.start+i)%len(r.packets)])
+	}
+
+	return ps
+}
+
+// Len returns the number of retained frames.
+func (r *Ring) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expire(r.now())
+	return r.n
+}
+
+// Dump writes the retained frames to w as a pcap file.  The Ring is not
+// modified, so capture continues uninterrupted.
+func (r *Ring) Dump(w io.Writer) error {
+	return writePcap(w, r.snapLen, r.Packets())
+}
+
+// expire discards frames older than the maximum age.  r.mu must be held when
+// calling expire.
+func (r *Ring) expire(now time.Time) {
+	if r.maxAge == 0 {
+		return
+	}
+
+	for r.n > 0 && now.Sub(r.packets[r.start].Timestamp) > r.maxAge {
+		r.pop()
+	}
+}
+
+// pop discards the oldest frame.  r.mu must be held when calling pop.
+func (r *Ring) pop() {
+	r.bytes -= len(r.packets[r.start].Data)
+	r.packets[r.start] = Packet{}
+	r.start = (r.start + 1) % len(r.packets)
+	r.n--
+}
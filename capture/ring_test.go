@@ -0,0 +1,122 @@
+package capture
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/ethernet/conn"
+)
+
+func TestRing(t *testing.T) {
+	t0 := time.Unix(1, 0)
+
+	tests := []struct {
+		desc   string
+		cfg    *RingConfig
+		frames [][]byte
+		after  time.Duration
+		want   []Packet
+	}{
+		{
+			desc:   "nil config",
+			frames: [][]byte{{1}, {2, 2}},
+			want: []Packet{
+				{Timestamp: t0, Data: []byte{1}, Length: 1},
+				{Timestamp: t0.Add(time.Second), Data: []byte{2, 2}, Length: 2},
+			},
+		},
+		{
+			desc:   "max frames",
+			cfg:    &RingConfig{MaxFrames: 2},
+			frames: [][]byte{{1}, {2}, {3}},
+			want: []Packet{
+				{Timestamp: t0.Add(1 * time.Second), Data: []byte{2}, Length: 1},
+				{Timestamp: t0.Add(2 * time.Second), Data: []byte{3}, Length: 1},
+			},
+		},
+		{
+			desc:   "max bytes",
+			cfg:    &RingConfig{MaxBytes: 4},
+			frames: [][]byte{{1, 1}, {2, 2}, {3, 3, 3}},
+			want: []Packet{
+				{Timestamp: t0.Add(2 * time.Second), Data: []byte{3, 3, 3}, Length: 3},
+			},
+		},
+		{
+			desc:   "frame exceeds max bytes",
+			cfg:    &RingConfig{MaxBytes: 2},
+			frames: [][]byte{{1, 1, 1}},
+		},
+		{
+			desc:   "max age",
+			cfg:    &RingConfig{MaxAge: 1500 * time.Millisecond},
+			frames: [][]byte{{1}, {2}, {3}},
+			after:  time.Second,
+			want: []Packet{
+				{Timestamp: t0.Add(2 * time.Second), Data: []byte{3}, Length: 1},
+			},
+		},
+		{
+			desc:   "snap length",
+			cfg:    &RingConfig{SnapLen: 2},
+			frames: [][]byte{{1, 2, 3}},
+			want: []Packet{
+				{Timestamp: t0, Data: []byte{1, 2}, Length: 3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			r := NewRing(tt.cfg)
+
+			now := t0
+			r.now = func() time.Time { return now }
+
+			for _, f := range tt.frames {
+				r.Add(f)
+				now = now.Add(time.Second)
+			}
+			now = now.Add(tt.after - time.Second)
+
+			got := r.Packets()
+			if len(tt.want) == 0 && len(got) == 0 {
+				return
+			}
+
+			if want := tt.want; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected packets:\n- want: %v\n-  got: %v", want, got)
+			}
+			if want, got := len(tt.want), r.Len(); want != got {
+				t.Fatalf("unexpected length: %v != %v", want, got)
+			}
+		})
+	}
+}
+
+func TestRingMirror(t *testing.T) {
+	r := NewRing(nil)
+
+	c := conn.NewMirror(&nopConn{}, &conn.MirrorConfig{
+		Egress: conn.All,
+		Sink:   r,
+	})
+
+	b := make([]byte, 60)
+	if _, err := c.WriteTo(b, nil); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	if want, got := 1, r.Len(); want != got {
+		t.Fatalf("unexpected number of captured frames: %v != %v", want, got)
+	}
+}
+
+// nopConn is a net.PacketConn which discards writes.
+type nopConn struct {
+	net.PacketConn
+}
+
+func (nopConn) WriteTo(b []byte, _ net.Addr) (int, error) { return len(b), nil }
@@ -0,0 +1,98 @@
+package cdp
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+)
+
+// Address protocol types and protocols.
+const (
+	protocolTypeNLPID = 0x01
+	protocolType8022  = 0x02
+
+	// nlpidIPv4 is the NLPID protocol of an IPv4 address.
+	nlpidIPv4 = 0xcc
+)
+
+// protocolIPv6 is the IEEE 802.2 protocol of an IPv6 address: an LLC and
+// SNAP header carrying the IPv6 EtherType.
+var protocolIPv6 = []byte{0xaa, 0xaa, 0x03, 0x00, 0x00, 0x00, 0x86, 0xdd}
+
+// marshalAddresses marshals the value of an Addresses TLV.
+func marshalAddresses(ips []net.IP) ([]byte, error) {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, uint32(len(ips)))
+
+	for _, ip := range ips {
+		var (
+			proto []byte
+			addr  net.IP
+		)
+
+		if ip4 := ip.To4(); ip4 != nil {
+			proto = []byte{protocolTypeNLPID, 1, nlpidIPv4}
+			addr = ip4
+		} else if ip16 := ip.To16(); ip16 != nil {
+			proto = append([]byte{protocolType8022, uint8(len(protocolIPv6))}, protocolIPv6...)
+			addr = ip16
+		} else {
+			return nil, ErrInvalidTLV
+		}
+
+		var l [2]byte
+		binary.BigEndian.PutUint16(l[:], uint16(len(addr)))
+
+		b = append(b, proto...)
+		b = append(b, l[:]...)
+		b = append(b, addr...)
+	}
+
+	return b, nil
+}
+
+// parseAddresses parses the value of an Addresses TLV.  Addresses of
+// protocols other than IPv4 and IPv6 are skipped.
+func parseAddresses(b []byte) ([]net.IP, error) {
+	if len(b) < 4 {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	n := int(binary.BigEndian.Uint32(b[0:4]))
+	b = b[4:]
+
+	var ips []net.IP
+	for i := 0; i < n; i++ {
+		if len(b) < 2 {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		pt, pl := b[0], int(b[1])
+		if len(b) < 2+pl+2 {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		proto := b[2 : 2+pl]
+		al := int(binary.BigEndian.Uint16(b[2+pl : 4+pl]))
+		b = b[4+pl:]
+		if len(b) < al {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		addr := b[:al]
+		b = b[al:]
+
+		switch {
+		case pt == protocolTypeNLPID && pl == 1 && proto[0] == nlpidIPv4 && al == net.IPv4len:
+		case pt == protocolType8022 && string(proto) == string(protocolIPv6) && al == net.IPv6len:
+		default:
+			continue
+		}
+
+		ip := make(net.IP, al)
+		copy(ip, addr)
+		ips = append(ips, ip)
+	}
+
+	return ips, nil
+}
@@ -0,0 +1,290 @@
+// Package cdp implements marshaling and unmarshaling of Cisco Discovery
+// Protocol packets carried in IEEE 802.2 LLC and SNAP frames.
+package cdp
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// ProtocolID is the SNAP protocol ID which, with ethernet.OUICisco,
+// indicates a CDP packet in a Frame.
+const ProtocolID = 0x2000
+
+// Destination is the hardware address to which CDP packets are sent.
+var Destination = net.HardwareAddr{0x01, 0x00, 0x0c, 0xcc, 0xcc, 0xcc}
+
+const (
+	// headerLen is the length of a CDP packet's version, TTL, and
+	// checksum.
+	headerLen = 4
+
+	// tlvHeaderLen is the length of a TLV's type and length fields, which
+	// are included in the TLV's length.
+	tlvHeaderLen = 4
+
+	// controlUI is the LLC control field of an unnumbered information PDU.
+	controlUI = 0x03
+)
+
+var (
+	// ErrInvalidSNAP is returned when a Frame does not carry a SNAP header
+	// with ethernet.OUICisco and ProtocolID.
+	ErrInvalidSNAP = errors.New("cdp: invalid SNAP header")
+
+	// ErrInvalidTLV is returned when a TLV's length is too short or too
+	// long, or when a known TLV's value is malformed.
+	ErrInvalidTLV = errors.New("cdp: invalid TLV")
+)
+
+// A TLVType is the type of a CDP TLV.
+type TLVType uint16
+
+// Possible TLVType values.
+const (
+	TLVDeviceID        TLVType = 0x0001
+	TLVAddresses       TLVType = 0x0002
+	TLVPortID          TLVType = 0x0003
+	TLVCapabilities    TLVType = 0x0004
+	TLVSoftwareVersion TLVType = 0x0005
+	TLVPlatform        TLVType = 0x0006
+	TLVVTPDomain       TLVType = 0x0009
+	TLVNativeVLAN      TLVType = 0x000a
+	TLVDuplex          TLVType = 0x000b
+)
+
+// A TLV is a CDP TLV.
+type TLV struct {
+	Type  TLVType
+	Value []byte
+}
+
+// Capabilities are the device capabilities carried in a CDP packet.
+type Capabilities uint32
+
+// Possible Capabilities values.
+const (
+	CapabilityRouter            Capabilities = 0x01
+	CapabilityTransparentBridge Capabilities = 0x02
+	CapabilitySourceRouteBridge Capabilities = 0x04
+	CapabilitySwitch            Capabilities = 0x08
+	CapabilityHost              Capabilities = 0x10
+	CapabilityIGMP              Capabilities = 0x20
+	CapabilityRepeater          Capabilities = 0x40
+)
+
+// A Packet is a CDP packet.  Device ID, Addresses, Port ID, and Capabilities
+// TLVs are decoded into fields, and all other TLVs are stored in TLVs.
+type Packet struct {
+	// Version specifies the CDP version, which is normally 2.
+	Version uint8
+
+	// TTL specifies how long the receiver should retain the information in
+	// the packet, and is transmitted in whole seconds.
+	TTL time.Duration
+
+	// Checksum is the checksum of the packet.  It is set when unmarshaling,
+	// and is computed automatically when marshaling.
+	Checksum uint16
+
+	// DeviceID specifies the name of the sending device.
+	DeviceID string
+
+	// Addresses specifies the network addresses of the sending device.
+	// Only IPv4 and IPv6 addresses are decoded, and IPv4 addresses are
+	// decoded in their 4 byte form.
+	Addresses []net.IP
+
+	// PortID specifies the name of the port which sent the packet.
+	PortID string
+
+	// Capabilities specifies the capabilities of the sending device.
+	Capabilities Capabilities
+
+	// TLVs specifies any other TLVs, in the order they appear.
+	TLVs []TLV
+}
+
+// Parse unmarshals the CDP packet carried in the payload of Frame f.  If f
+// does not carry a SNAP header with ethernet.OUICisco and ProtocolID,
+// ErrInvalidSNAP is returned.
+func Parse(f *ethernet.Frame) (*Packet, error) {
+	if f.SNAP == nil || f.SNAP.OUI != ethernet.OUICisco || f.SNAP.ProtocolID != ProtocolID {
+		return nil, ErrInvalidSNAP
+	}
+
+	p := new(Packet)
+	if err := p.UnmarshalBinary(f.Payload); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Frame marshals a Packet into the payload of an IEEE 802.3 frame sent from
+// hardware address src to Destination.
+func (p *Packet) Frame(src net.HardwareAddr) (*ethernet.Frame, error) {
+	b, err := p.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ethernet.Frame{
+		Destination: append(net.HardwareAddr(nil), Destination...),
+		Source:      src,
+		LLC: &ethernet.LLC{
+			DSAP:    ethernet.SAPSNAP,
+			SSAP:    ethernet.SAPSNAP,
+			Control: controlUI,
+		},
+		SNAP: &ethernet.SNAP{
+			OUI:        ethernet.OUICisco,
+			ProtocolID: ProtocolID,
+		},
+		Payload: b,
+	}, nil
+}
+
+// MarshalBinary allocates a byte slice and marshals a Packet into binary
+// form.  The checksum is computed automatically.
+func (p *Packet) MarshalBinary() ([]byte, error) {
+	b := make([]byte, headerLen)
+	b[0] = p.Version
+	b[1] = uint8(p.TTL / time.Second)
+
+	var tlvs []TLV
+	if p.DeviceID != "" {
+		tlvs = append(tlvs, TLV{Type: TLVDeviceID, Value: []byte(p.DeviceID)})
+	}
+	if len(p.Addresses) > 0 {
+		v, err := marshalAddresses(p.Addresses)
+		if err != nil {
+			return nil, err
+		}
+
+		tlvs = append(tlvs, TLV{Type: TLVAddresses, Value: v})
+	}
+	if p.PortID != "" {
+		tlvs = append(tlvs, TLV{Type: TLVPortID, Value: []byte(p.PortID)})
+	}
+	if p.Capabilities != 0 {
+		var v [4]byte
+		binary.BigEndian.PutUint32(v[:], uint32(p.Capabilities))
+		tlvs = append(tlvs, TLV{Type: TLVCapabilities, Value: v[:]})
+	}
+
+	for _, tlv := range append(tlvs, p.TLVs...) {
+		n := tlvHeaderLen + len(tlv.Value)
+		if n > 0xffff {
+			return nil, ErrInvalidTLV
+		}
+
+		var h [tlvHeaderLen]byte
+		binary.BigEndian.PutUint16(h[0:2], uint16(tlv.Type))
+		binary.BigEndian.PutUint16(h[2:4], uint16(n))
+
+		b = append(b, h[:]...)
+		b = append(b, tlv.Value...)
+	}
+
+	binary.BigEndian.PutUint16(b[2:4], ^sum(b, false))
+	return b, nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into a Packet.  The checksum is
+// not verified; use VerifyChecksum to do so.
+func (p *Packet) UnmarshalBinary(b []byte) error {
+	if len(b) < headerLen {
+		return io.ErrUnexpectedEOF
+	}
+
+	out := Packet{
+		Version:  b[0],
+		TTL:      time.Duration(b[1]) * time.Second,
+		Checksum: binary.BigEndian.Uint16(b[2:4]),
+	}
+
+	b = b[headerLen:]
+	for len(b) > 0 {
+		if len(b) < tlvHeaderLen {
+			return io.ErrUnexpectedEOF
+		}
+
+		t := TLVType(binary.BigEndian.Uint16(b[0:2]))
+		n := int(binary.BigEndian.Uint16(b[2:4]))
+		if n < tlvHeaderLen {
+			return ErrInvalidTLV
+		}
+		if len(b) < n {
+			return io.ErrUnexpectedEOF
+		}
+
+		v := b[tlvHeaderLen:n]
+		b = b[n:]
+
+		switch t {
+		case TLVDeviceID:
+			out.DeviceID = string(v)
+		case TLVAddresses:
+			ips, err := parseAddresses(v)
+			if err != nil {
+				return err
+			}
+
+			out.Addresses = ips
+		case TLVPortID:
+			out.PortID = string(v)
+		case TLVCapabilities:
+			if len(v) != 4 {
+				return ErrInvalidTLV
+			}
+
+			out.Capabilities = Capabilities(binary.BigEndian.Uint32(v))
+		default:
+			value := make([]byte, len(v))
+			copy(value, v)
+			out.TLVs = append(out.TLVs, TLV{Type: t, Value: value})
+		}
+	}
+
+	*p = out
+	return nil
+}
+
+// VerifyChecksum reports whether the checksum of the CDP packet in b is
+// valid.  Cisco devices checksum packets of odd length by treating the last
+// byte as the low byte of a 16 bit word, rather than the high byte as
+// specified by RFC 1071, so either form is accepted.
+func VerifyChecksum(b []byte) bool {
+	return sum(b, false) == 0xffff || sum(b, true) == 0xffff
+}
+
+// sum computes the ones' complement sum of b.  If rfc1071 is false and b is
+// of odd length, its last byte is the low byte of the final 16 bit word, as
+// is done by Cisco devices.
+func sum(b []byte, rfc1071 bool) uint16 {
+	var s uint32
+	for len(b) >= 2 {
+		s += uint32(binary.BigEndian.Uint16(b[0:2]))
+		b = b[2:]
+	}
+
+	if len(b) == 1 {
+		if rfc1071 {
+			s += uint32(b[0]) << 8
+		} else {
+			s += uint32(b[0])
+		}
+	}
+
+	for s > 0xffff {
+		s = s>>16 + s&0xffff
+	}
+
+	return uint16(s)
+}
@@ -0,0 +1,219 @@
+package cdp
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestPacketMarshalUnmarshal(t *testing.T) {
+	p := &Packet{
+		Version:      2,
+		TTL:          180 * time.Second,
+		DeviceID:     "sw1",
+		Addresses:    []net.IP{{192, 0, 2, 1}},
+		PortID:       "Gi0/1",
+		Capabilities: CapabilitySwitch | CapabilityIGMP,
+	}
+
+	b, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	// An odd length packet, checksummed the way Cisco devices do.
+	want := []byte{
+		0x02, 0xb4, 0xcc, 0x2c,
+		// Device ID.
+		0x00, 0x01, 0x00, 0x07, 's', 'w', '1',
+		// Addresses.
+		0x00, 0x02, 0x00, 0x11,
+		0x00, 0x00, 0x00, 0x01,
+		0x01, 0x01, 0xcc,
+		0x00, 0x04, 0xc0, 0x00, 0x02, 0x01,
+		// Port ID.
+		0x00, 0x03, 0x00, 0x09, 'G', 'i', '0', '/', '1',
+		// Capabilities.
+		0x00, 0x04, 0x00, 0x08, 0x00, 0x00, 0x00, 0x28,
+	}
+
+	if !bytes.Equal(want, b) {
+		t.Fatalf("unexpected bytes:\n- want: %v\n-  got: %v", want, b)
+	}
+	if !VerifyChecksum(b) {
+		t.Fatal("expected checksum to verify")
+	}
+
+	got := new(Packet)
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	p.Checksum = 0xcc2c
+	if !reflect.DeepEqual(p, got) {
+		t.Fatalf("unexpected packet:\n- want: %#v\n-  got: %#v", p, got)
+	}
+}
+
+func TestPacketOtherTLVs(t *testing.T) {
+	p := &Packet{
+		Version: 2,
+		Addresses: []net.IP{
+			net.ParseIP("2001:db8::1"),
+			{192, 0, 2, 1},
+		},
+		TLVs: []TLV{
+			{Type: TLVPlatform, Value: []byte("cisco WS-C2960")},
+			{Type: TLVNativeVLAN, Value: []byte{0x00, 0x01}},
+		},
+	}
+
+	b, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	got := new(Packet)
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	got.Checksum = 0
+	if !reflect.DeepEqual(p, got) {
+		t.Fatalf("unexpected packet:\n- want: %#v\n-  got: %#v", p, got)
+	}
+}
+
+func TestPacketUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		b    []byte
+		err  error
+	}{
+		{
+			desc: "short header",
+			b:    []byte{0x02, 0xb4, 0x00},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "short TLV header",
+			b:    []byte{0x02, 0xb4, 0x00, 0x00, 0x00, 0x01},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "TLV length too short",
+			b:    []byte{0x02, 0xb4, 0x00, 0x00, 0x00, 0x01, 0x00, 0x03},
+			err:  ErrInvalidTLV,
+		},
+		{
+			desc: "truncated TLV",
+			b:    []byte{0x02, 0xb4, 0x00, 0x00, 0x00, 0x01, 0x00, 0x05},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "bad capabilities",
+			b:    []byte{0x02, 0xb4, 0x00, 0x00, 0x00, 0x04, 0x00, 0x05, 0x01},
+			err:  ErrInvalidTLV,
+		},
+		{
+			desc: "truncated addresses",
+			b: []byte{
+				0x02, 0xb4, 0x00, 0x00,
+				0x00, 0x02, 0x00, 0x0b,
+				0x00, 0x00, 0x00, 0x01,
+				0x01, 0x01, 0xcc,
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := new(Packet).UnmarshalBinary(tt.b); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	tests := []struct {
+		desc string
+		b    []byte
+		ok   bool
+	}{
+		{
+			desc: "even length",
+			b:    []byte{0x02, 0xb4, 0xfd, 0x4b},
+			ok:   true,
+		},
+		{
+			desc: "odd length, Cisco",
+			b:    []byte{0x02, 0xb4, 0xfd, 0x4a, 0x01},
+			ok:   true,
+		},
+		{
+			desc: "odd length, RFC 1071",
+			b:    []byte{0x02, 0xb4, 0xfc, 0x4b, 0x01},
+			ok:   true,
+		},
+		{
+			desc: "bad",
+			b:    []byte{0x02, 0xb4, 0x00, 0x00},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if want, got := tt.ok, VerifyChecksum(tt.b); want != got {
+				t.Fatalf("unexpected checksum validity: %v != %v", want, got)
+			}
+		})
+	}
+}
+
+func TestFrameParse(t *testing.T) {
+	p := &Packet{
+		Version:  2,
+		TTL:      60 * time.Second,
+		DeviceID: "router",
+	}
+
+	f, err := p.Frame(net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad})
+	if err != nil {
+		t.Fatalf("failed to create frame: %v", err)
+	}
+
+	b, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal frame: %v", err)
+	}
+
+	f = new(ethernet.Frame)
+	if err := f.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal frame: %v", err)
+	}
+
+	got, err := Parse(f)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if !VerifyChecksum(f.Payload) {
+		t.Fatal("expected checksum to verify")
+	}
+
+	got.Checksum = 0
+	if !reflect.DeepEqual(p, got) {
+		t.Fatalf("unexpected packet:\n- want: %#v\n-  got: %#v", p, got)
+	}
+
+	if _, err := Parse(&ethernet.Frame{EtherType: ethernet.EtherTypeIPv4}); err != ErrInvalidSNAP {
+		t.Fatalf("expected ErrInvalidSNAP, but got: %v", err)
+	}
+}
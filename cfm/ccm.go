@@ -0,0 +1,181 @@
+package cfm
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+const (
+	// ccmLen is the length of the fixed fields of a Continuity Check
+	// Message.
+	ccmLen = 70
+
+	// maidLen is the length of a maintenance association identifier.
+	maidLen = 48
+
+	// maxMEPID is the maximum value of a 13 bit MEP identifier.
+	maxMEPID = 0x1fff
+)
+
+// Maintenance domain and short MA name formats.
+const (
+	// MDFormatNone indicates that a MAID does not contain a maintenance
+	// domain name, as is the case for ITU-T Y.1731 MEG IDs.
+	MDFormatNone = 0x01
+
+	// MDFormatString indicates a character string maintenance domain
+	// name.
+	MDFormatString = 0x04
+
+	// MAFormatString indicates a character string short MA name.
+	MAFormatString = 0x02
+
+	// MAFormatICC indicates an ITU-T Y.1731 ICC-based MEG ID.
+	MAFormatICC = 0x20
+)
+
+var (
+	// ErrInvalidMAID is returned when a MAID's names do not fit in 48 bytes.
+	ErrInvalidMAID = errors.New("cfm: invalid MAID")
+
+	// ErrInvalidMEPID is returned when a MEP identifier does not fit in 13
+	// bits.
+	ErrInvalidMEPID = errors.New("cfm: invalid MEP identifier")
+)
+
+// A CCM contains the fixed fields of a Continuity Check Message.
+type CCM struct {
+	// Sequence specifies the sequence number of the message.
+	Sequence uint32
+
+	// MEPID specifies the 13 bit identifier of the transmitting
+	// maintenance association end point.
+	MEPID uint16
+
+	// MAID identifies the maintenance association, or the ITU-T Y.1731
+	// maintenance entity group, of the transmitting MEP.
+	MAID MAID
+
+	// TxFCf, RxFCb, and TxFCb specify the ITU-T Y.1731 frame loss
+	// counters, which are zero when unused.
+	TxFCf uint32
+	RxFCb uint32
+	TxFCb uint32
+}
+
+// A MAID is a maintenance association identifier.
+type MAID struct {
+	// MDFormat and MDName specify the format and value of the maintenance
+	// domain name.  If MDFormat is MDFormatNone, MDName must be empty.
+	MDFormat uint8
+	MDName   []byte
+
+	// MAFormat and MAName specify the format and value of the short MA
+	// name.
+	MAFormat uint8
+	MAName   []byte
+}
+
+// append appends the binary form of a CCM to b.
+func (c *CCM) append(b []byte) ([]byte, error) {
+	if c.MEPID > maxMEPID {
+		return nil, ErrInvalidMEPID
+	}
+
+	v := make([]byte, ccmLen)
+	binary.BigEndian.PutUint32(v[0:4], c.Sequence)
+	binary.BigEndian.PutUint16(v[4:6], c.MEPID)
+	if err := c.MAID.read(v[6:54]); err != nil {
+		return nil, err
+	}
+	binary.BigEndian.PutUint32(v[54:58], c.TxFCf)
+	binary.BigEndian.PutUint32(v[58:62], c.RxFCb)
+	binary.BigEndian.PutUint32(v[62:66], c.TxFCb)
+
+	return append(b, v...), nil
+}
+
+// unmarshal unmarshals the fixed fields of a CCM from b.
+func (c *CCM) unmarshal(b []byte) error {
+	if len(b) < ccmLen {
+		return io.ErrUnexpectedEOF
+	}
+
+	var maid MAID
+	if err := maid.unmarshal(b[6:54]); err != nil {
+		return err
+	}
+
+	*c = CCM{
+		Sequence: binary.BigEndian.Uint32(b[0:4]),
+		MEPID:    binary.BigEndian.Uint16(b[4:6]) & maxMEPID,
+		MAID:     maid,
+		TxFCf:    binary.BigEndian.Uint32(b[54:58]),
+		RxFCb:    binary.BigEndian.Uint32(b[58:62]),
+		TxFCb:    binary.BigEndian.Uint32(b[62:66]),
+	}
+
+	return nil
+}
+
+// read marshals a MAID into b, which must be 48 bytes.  Unused bytes are
+// zero.
+func (m *MAID) read(b []byte) error {
+	n := 2 + 2 + len(m.MAName)
+	if m.MDFormat != MDFormatNone {
+		n += len(m.MDName)
+	} else if len(m.MDName) > 0 {
+		return ErrInvalidMAID
+	}
+	if n > maidLen {
+		return ErrInvalidMAID
+	}
+
+	i := 0
+	b[i] = m.MDFormat
+	i++
+	if m.MDFormat != MDFormatNone {
+		b[i] = uint8(len(m.MDName))
+		i += 1 + copy(b[i+1:], m.MDName)
+	}
+
+	b[i] = m.MAFormat
+	b[i+1] = uint8(len(m.MAName))
+	copy(b[i+2:], m.MAName)
+
+	return nil
+}
+
+// unmarshal unmarshals a MAID from b, which must be 48 bytes.
+func (m *MAID) unmarshal(b []byte) error {
+	out := MAID{MDFormat: b[0]}
+
+	i := 1
+	if out.MDFormat != MDFormatNone {
+		l := int(b[i])
+		if i+1+l > len(b) {
+			return ErrInvalidMAID
+		}
+
+		out.MDName = make([]byte, l)
+		copy(out.MDName, b[i+1:i+1+l])
+		i += 1 + l
+	}
+
+	if i+2 > len(b) {
+		return ErrInvalidMAID
+	}
+
+	out.MAFormat = b[i]
+	l := int(b[i+1])
+	if i+2+l > len(b) {
+		return ErrInvalidMAID
+	}
+
+	out.MAName = make([]byte, l)
+	copy(out.MAName, b[i+2:i+2+l])
+
+	*m = out
+	return nil
+}
@@ -0,0 +1,141 @@
+package cfm
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestCCMMarshalUnmarshal(t *testing.T) {
+	tests := []struct {
+		desc string
+		p    *PDU
+		maid []byte
+	}{
+		{
+			desc: "802.1ag MAID",
+			p: &PDU{
+				Level:  7,
+				Opcode: OpcodeCCM,
+				Flags:  FlagRDI.WithInterval(Interval1s),
+				CCM: &CCM{
+					Sequence: 1,
+					MEPID:    100,
+					MAID: MAID{
+						MDFormat: MDFormatString,
+						MDName:   []byte("md"),
+						MAFormat: MAFormatString,
+						MAName:   []byte("ma1"),
+					},
+				},
+				TLVs: []TLV{{
+					Type:  TLVPortStatus,
+					Value: []byte{0x02},
+				}},
+			},
+			maid: []byte{0x04, 0x02, 'm', 'd', 0x02, 0x03, 'm', 'a', '1'},
+		},
+		{
+			desc: "Y.1731 MEG ID",
+			p: &PDU{
+				Opcode: OpcodeCCM,
+				Flags:  Flags(0).WithInterval(Interval3ms),
+				CCM: &CCM{
+					Sequence: 0,
+					MEPID:    maxMEPID,
+					MAID: MAID{
+						MDFormat: MDFormatNone,
+						MAFormat: MAFormatICC,
+						MAName:   []byte("ICCCODE000001"),
+					},
+					TxFCf: 1,
+					RxFCb: 2,
+					TxFCb: 3,
+				},
+			},
+			maid: append([]byte{0x01, 0x20, 0x0d}, "ICCCODE000001"...),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := tt.p.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			if want, got := uint8(ccmLen), b[3]; want != got {
+				t.Fatalf("unexpected first TLV offset: %v != %v", want, got)
+			}
+
+			maid := b[headerLen+6 : headerLen+6+maidLen]
+			want := append(tt.maid, make([]byte, maidLen-len(tt.maid))...)
+			if !bytes.Equal(want, maid) {
+				t.Fatalf("unexpected MAID:\n- want: %v\n-  got: %v", want, maid)
+			}
+
+			p := new(PDU)
+			if err := p.UnmarshalBinary(b); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+
+			if !reflect.DeepEqual(tt.p, p) {
+				t.Fatalf("unexpected PDU:\n- want: %#v\n-  got: %#v", tt.p, p)
+			}
+		})
+	}
+}
+
+func TestCCMErrors(t *testing.T) {
+	ccm := func(c CCM) *PDU {
+		return &PDU{Opcode: OpcodeCCM, CCM: &c}
+	}
+
+	tests := []struct {
+		desc string
+		p    *PDU
+		err  error
+	}{
+		{
+			desc: "MEP ID",
+			p:    ccm(CCM{MEPID: maxMEPID + 1}),
+			err:  ErrInvalidMEPID,
+		},
+		{
+			desc: "MD name with no MD format",
+			p: ccm(CCM{MAID: MAID{
+				MDFormat: MDFormatNone,
+				MDName:   []byte("md"),
+			}}),
+			err: ErrInvalidMAID,
+		},
+		{
+			desc: "MAID too long",
+			p: ccm(CCM{MAID: MAID{
+				MDFormat: MDFormatString,
+				MDName:   make([]byte, 30),
+				MAName:   make([]byte, 15),
+			}}),
+			err: ErrInvalidMAID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if _, err := tt.p.MarshalBinary(); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+
+	// A MAID whose MD name length runs past the end of the MAID.
+	b, err := ccm(CCM{MAID: MAID{MDFormat: MDFormatString}}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	b[headerLen+7] = maidLen
+
+	if err := new(PDU).UnmarshalBinary(b); err != ErrInvalidMAID {
+		t.Fatalf("expected ErrInvalidMAID, but got: %v", err)
+	}
+}
@@ -0,0 +1,379 @@
+// Package cfm implements marshaling and unmarshaling of IEEE 802.1ag
+// Connectivity Fault Management PDUs, such as Continuity Check Messages, and
+// ITU-T Y.1731 loss and delay measurement PDUs.
+package cfm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// EtherType is the EtherType which indicates a CFM PDU in a Frame.
+const EtherType = ethernet.EtherTypeCFM
+
+const (
+	// headerLen is the length of a CFM common header.
+	headerLen = 4
+
+	// maxLevel and maxVersion are the maximum values of the 3 bit
+	// maintenance domain level and the 5 bit version.
+	maxLevel   = 0x07
+	maxVersion = 0x1f
+)
+
+var (
+	// ErrInvalidHeader is returned when a PDU's level does not fit in 3
+	// bits, its version does not fit in 5 bits, or its fixed fields are
+	// longer than 255 bytes.
+	ErrInvalidHeader = errors.New("cfm: invalid header")
+
+	// ErrInvalidTLV is returned when a TLV is malformed, or when a PDU's
+	// TLVs are not terminated by an End TLV.
+	ErrInvalidTLV = errors.New("cfm: invalid TLV")
+)
+
+// Destination returns the class 1 multicast hardware address used for
+// Continuity Check Messages at maintenance domain level level.
+func Destination(level uint8) net.HardwareAddr {
+	return net.HardwareAddr{0x01, 0x80, 0xc2, 0x00, 0x00, 0x30 | level&maxLevel}
+}
+
+// An Opcode is the type of a CFM PDU.
+type Opcode uint8
+
+// Possible Opcode values.
+const (
+	OpcodeCCM Opcode = 1
+	OpcodeLBR Opcode = 2
+	OpcodeLBM Opcode = 3
+	OpcodeLTR Opcode = 4
+	OpcodeLTM Opcode = 5
+)
+
+// String returns the name of an Opcode.
+func (o Opcode) String() string {
+	switch o {
+	case OpcodeCCM:
+		return "CCM"
+	case OpcodeLBR:
+		return "LBR"
+	case OpcodeLBM:
+		return "LBM"
+	case OpcodeLTR:
+		return "LTR"
+	case OpcodeLTM:
+		return "LTM"
+	case OpcodeLMR:
+		return "LMR"
+	case OpcodeLMM:
+		return "LMM"
+	case Opcode1DM:
+		return "1DM"
+	case OpcodeDMR:
+		return "DMR"
+	case OpcodeDMM:
+		return "DMM"
+	default:
+		return fmt.Sprintf("Opcode(%d)", uint8(o))
+	}
+}
+
+// Flags are flags carried in a CFM PDU.  The meaning of Flags depends on a
+// PDU's Opcode.
+type Flags uint8
+
+// FlagRDI is the remote defect indication flag of a Continuity Check
+// Message.
+const FlagRDI Flags = 0x80
+
+// Interval returns the transmission interval carried in the Flags of a
+// Continuity Check Message.
+func (f Flags) Interval() Interval {
+	return Interval(f & 0x07)
+}
+
+// WithInterval returns a copy of Flags with its transmission interval set
+// to i.
+func (f Flags) WithInterval(i Interval) Flags {
+	return f&^0x07 | Flags(i&0x07)
+}
+
+// An Interval is the transmission interval of Continuity Check Messages.
+type Interval uint8
+
+// Possible Interval values.
+const (
+	IntervalInvalid Interval = 0
+	Interval3ms     Interval = 1
+	Interval10ms    Interval = 2
+	Interval100ms   Interval = 3
+	Interval1s      Interval = 4
+	Interval10s     Interval = 5
+	Interval1m      Interval = 6
+	Interval10m     Interval = 7
+)
+
+// Duration returns the duration of an Interval, or zero if the Interval is
+// not valid.  Interval3ms is 1/300th of a second.
+func (i Interval) Duration() time.Duration {
+	switch i {
+	case Interval3ms:
+		return time.Second / 300
+	case Interval10ms:
+		return 10 * time.Millisecond
+	case Interval100ms:
+		return 100 * time.Millisecond
+	case Interval1s:
+		return time.Second
+	case Interval10s:
+		return 10 * time.Second
+	case Interval1m:
+		return time.Minute
+	case Interval10m:
+		return 10 * time.Minute
+	default:
+		return 0
+	}
+}
+
+// A TLVType is the type of a CFM TLV.
+type TLVType uint8
+
+// Possible TLVType values.
+const (
+	TLVEnd             TLVType = 0
+	TLVSenderID        TLVType = 1
+	TLVPortStatus      TLVType = 2
+	TLVData            TLVType = 3
+	TLVInterfaceStatus TLVType = 4
+	TLVReplyIngress    TLVType = 5
+	TLVReplyEgress     TLVType = 6
+	TLVLTMEgressID     TLVType = 7
+	TLVLTREgressID     TLVType = 8
+	TLVOrgSpecific     TLVType = 31
+)
+
+// A TLV is a CFM TLV.
+type TLV struct {
+	Type  TLVType
+	Value []byte
+}
+
+// A PDU is a CFM PDU.  The field which carries the PDU's fixed fields
+// depends on its Opcode.
+type PDU struct {
+	// Level specifies the 3 bit maintenance domain level.
+	Level uint8
+
+	// Version specifies the 5 bit protocol version, which is zero.
+	Version uint8
+
+	// Opcode specifies the type of the PDU.
+	Opcode Opcode
+
+	// Flags specifies the PDU flags.
+	Flags Flags
+
+	// CCM specifies the fixed fields of a Continuity Check Message, and
+	// must be set when marshaling one.
+	CCM *CCM
+
+	// LM specifies the fixed fields of an LMM or LMR PDU, and must be set
+	// when marshaling one.
+	LM *LossMeasurement
+
+	// DM specifies the fixed fields of a DMM, DMR, or 1DM PDU, and must be
+	// set when marshaling one.
+	DM *DelayMeasurement
+
+	// Data specifies the fixed fields of PDUs with other opcodes, which
+	// precede the PDU's TLVs.
+	Data []byte
+
+	// TLVs specifies the TLVs which follow the PDU's fixed fields,
+	// excluding the End TLV.
+	TLVs []TLV
+}
+
+// Parse unmarshals the CFM PDU carried in the payload of Frame f.  If f's
+// EtherType is not EtherType, ethernet.ErrInvalidEtherType is returned.
+func Parse(f *ethernet.Frame) (*PDU, error) {
+	if f.EtherType != EtherType {
+		return nil, ethernet.ErrInvalidEtherType
+	}
+
+	p := new(PDU)
+	if err := p.UnmarshalBinary(f.Payload); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Frame marshals a PDU into the payload of an Ethernet frame sent from
+// hardware address src to the multicast address returned by Destination.
+// Callers may set the Frame's destination to a unicast address for PDUs
+// such as loopback messages.
+func (p *PDU) Frame(src net.HardwareAddr) (*ethernet.Frame, error) {
+	b, err := p.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ethernet.Frame{
+		Destination: Destination(p.Level),
+		Source:      src,
+		EtherType:   EtherType,
+		Payload:     b,
+	}, nil
+}
+
+// MarshalBinary allocates a byte slice and marshals a PDU into binary form.
+// The first TLV offset and the End TLV are added automatically.
+func (p *PDU) MarshalBinary() ([]byte, error) {
+	if p.Level > maxLevel || p.Version > maxVersion {
+		return nil, ErrInvalidHeader
+	}
+
+	b := make([]byte, headerLen)
+	b[0] = p.Level<<5 | p.Version
+	b[1] = uint8(p.Opcode)
+	b[2] = uint8(p.Flags)
+
+	var err error
+	switch p.Opcode {
+	case OpcodeCCM:
+		if p.CCM == nil {
+			return nil, ErrInvalidHeader
+		}
+
+		b, err = p.CCM.append(b)
+	case OpcodeLMM, OpcodeLMR:
+		if p.LM == nil {
+			return nil, ErrInvalidHeader
+		}
+
+		b = p.LM.append(b)
+	case OpcodeDMM, OpcodeDMR, Opcode1DM:
+		if p.DM == nil {
+			return nil, ErrInvalidHeader
+		}
+
+		b = p.DM.append(b, p.Opcode == Opcode1DM)
+	default:
+		b = append(b, p.Data...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(b) - headerLen
+	if n > 0xff {
+		return nil, ErrInvalidHeader
+	}
+	b[3] = uint8(n)
+
+	for _, tlv := range p.TLVs {
+		if tlv.Type == TLVEnd || len(tlv.Value) > 0xffff {
+			return nil, ErrInvalidTLV
+		}
+
+		var h [3]byte
+		h[0] = uint8(tlv.Type)
+		binary.BigEndian.PutUint16(h[1:3], uint16(len(tlv.Value)))
+
+		b = append(b, h[:]...)
+		b = append(b, tlv.Value...)
+	}
+
+	return append(b, uint8(TLVEnd)), nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into a PDU.  Any bytes following
+// the End TLV, such as Ethernet padding, are ignored.
+func (p *PDU) UnmarshalBinary(b []byte) error {
+	if len(b) < headerLen {
+		return io.ErrUnexpectedEOF
+	}
+
+	out := PDU{
+		Level:   b[0] >> 5,
+		Version: b[0] & maxVersion,
+		Opcode:  Opcode(b[1]),
+		Flags:   Flags(b[2]),
+	}
+
+	n := headerLen + int(b[3])
+	if len(b) < n {
+		return io.ErrUnexpectedEOF
+	}
+
+	fixed := b[headerLen:n]
+	switch out.Opcode {
+	case OpcodeCCM:
+		out.CCM = new(CCM)
+		if err := out.CCM.unmarshal(fixed); err != nil {
+			return err
+		}
+	case OpcodeLMM, OpcodeLMR:
+		out.LM = new(LossMeasurement)
+		if err := out.LM.unmarshal(fixed); err != nil {
+			return err
+		}
+	case OpcodeDMM, OpcodeDMR, Opcode1DM:
+		out.DM = new(DelayMeasurement)
+		if err := out.DM.unmarshal(fixed, out.Opcode == Opcode1DM); err != nil {
+			return err
+		}
+	default:
+		if len(fixed) > 0 {
+			out.Data = make([]byte, len(fixed))
+			copy(out.Data, fixed)
+		}
+	}
+
+	tlvs, err := parseTLVs(b[n:])
+	if err != nil {
+		return err
+	}
+	out.TLVs = tlvs
+
+	*p = out
+	return nil
+}
+
+// parseTLVs parses TLVs from b until an End TLV is found.
+func parseTLVs(b []byte) ([]TLV, error) {
+	var tlvs []TLV
+	for {
+		if len(b) < 1 {
+			return nil, ErrInvalidTLV
+		}
+
+		t := TLVType(b[0])
+		if t == TLVEnd {
+			return tlvs, nil
+		}
+
+		if len(b) < 3 {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		l := int(binary.BigEndian.Uint16(b[1:3]))
+		if len(b[3:]) < l {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		v := make([]byte, l)
+		copy(v, b[3:3+l])
+		tlvs = append(tlvs, TLV{Type: t, Value: v})
+
+		b = b[3+l:]
+	}
+}
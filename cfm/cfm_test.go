@@ -0,0 +1,203 @@
+package cfm
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestPDUMarshalUnmarshal(t *testing.T) {
+	tests := []struct {
+		desc string
+		p    *PDU
+		b    []byte
+	}{
+		{
+			desc: "LBM",
+			p: &PDU{
+				Level:  5,
+				Opcode: OpcodeLBM,
+				Data:   []byte{0x00, 0x00, 0x00, 0x01},
+				TLVs: []TLV{{
+					Type:  TLVData,
+					Value: []byte{0xaa, 0xbb},
+				}},
+			},
+			b: []byte{
+				0xa0, 0x03, 0x00, 0x04,
+				0x00, 0x00, 0x00, 0x01,
+				0x03, 0x00, 0x02, 0xaa, 0xbb,
+				0x00,
+			},
+		},
+		{
+			desc: "no data or TLVs",
+			p: &PDU{
+				Version: 1,
+				Opcode:  Opcode(0xff),
+				Flags:   0x01,
+			},
+			b: []byte{0x01, 0xff, 0x01, 0x00, 0x00},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := tt.p.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			if want, got := tt.b, b; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected bytes:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			// Trailing padding is ignored.
+			p := new(PDU)
+			if err := p.UnmarshalBinary(append(b, 0xff, 0xff)); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+
+			if !reflect.DeepEqual(tt.p, p) {
+				t.Fatalf("unexpected PDU:\n- want: %#v\n-  got: %#v", tt.p, p)
+			}
+		})
+	}
+}
+
+func TestPDUErrors(t *testing.T) {
+	marshal := []struct {
+		desc string
+		p    *PDU
+		err  error
+	}{
+		{
+			desc: "level",
+			p:    &PDU{Level: 8},
+			err:  ErrInvalidHeader,
+		},
+		{
+			desc: "version",
+			p:    &PDU{Version: 32},
+			err:  ErrInvalidHeader,
+		},
+		{
+			desc: "no CCM",
+			p:    &PDU{Opcode: OpcodeCCM},
+			err:  ErrInvalidHeader,
+		},
+		{
+			desc: "data too long",
+			p:    &PDU{Data: make([]byte, 256)},
+			err:  ErrInvalidHeader,
+		},
+		{
+			desc: "End TLV",
+			p:    &PDU{TLVs: []TLV{{Type: TLVEnd}}},
+			err:  ErrInvalidTLV,
+		},
+	}
+
+	for _, tt := range marshal {
+		t.Run(tt.desc, func(t *testing.T) {
+			if _, err := tt.p.MarshalBinary(); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+
+	unmarshal := []struct {
+		desc string
+		b    []byte
+		err  error
+	}{
+		{
+			desc: "short header",
+			b:    []byte{0x00, 0x03, 0x00},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "short data",
+			b:    []byte{0x00, 0x03, 0x00, 0x04, 0x00},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "no End TLV",
+			b:    []byte{0x00, 0x03, 0x00, 0x00},
+			err:  ErrInvalidTLV,
+		},
+		{
+			desc: "short TLV header",
+			b:    []byte{0x00, 0x03, 0x00, 0x00, 0x03, 0x00},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "short TLV value",
+			b:    []byte{0x00, 0x03, 0x00, 0x00, 0x03, 0x00, 0x02, 0xaa},
+			err:  io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tt := range unmarshal {
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := new(PDU).UnmarshalBinary(tt.b); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestFlagsInterval(t *testing.T) {
+	f := FlagRDI.WithInterval(Interval1s)
+	if want, got := Flags(0x84), f; want != got {
+		t.Fatalf("unexpected flags: %#x != %#x", want, got)
+	}
+	if want, got := time.Second, f.Interval().Duration(); want != got {
+		t.Fatalf("unexpected interval: %v != %v", want, got)
+	}
+	if want, got := time.Duration(0), IntervalInvalid.Duration(); want != got {
+		t.Fatalf("unexpected interval: %v != %v", want, got)
+	}
+}
+
+func TestDestination(t *testing.T) {
+	want := net.HardwareAddr{0x01, 0x80, 0xc2, 0x00, 0x00, 0x37}
+	if got := Destination(7); !bytes.Equal(want, got) {
+		t.Fatalf("unexpected destination: %v != %v", want, got)
+	}
+}
+
+func TestFrameParse(t *testing.T) {
+	p := &PDU{
+		Level:  3,
+		Opcode: OpcodeLTM,
+		Data:   make([]byte, 17),
+	}
+
+	f, err := p.Frame(net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad})
+	if err != nil {
+		t.Fatalf("failed to create frame: %v", err)
+	}
+
+	if want, got := Destination(3), f.Destination; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected destination: %v != %v", want, got)
+	}
+
+	got, err := Parse(f)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if !reflect.DeepEqual(p, got) {
+		t.Fatalf("unexpected PDU:\n- want: %#v\n-  got: %#v", p, got)
+	}
+
+	if _, err := Parse(&ethernet.Frame{EtherType: ethernet.EtherTypeIPv4}); err != ethernet.ErrInvalidEtherType {
+		t.Fatalf("expected ErrInvalidEtherType, but got: %v", err)
+	}
+}
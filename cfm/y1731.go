@@ -0,0 +1,165 @@
+package cfm
+
+import (
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+// ITU-T Y.1731 performance monitoring opcodes.
+const (
+	OpcodeLMR Opcode = 42
+	OpcodeLMM Opcode = 43
+	Opcode1DM Opcode = 45
+	OpcodeDMR Opcode = 46
+	OpcodeDMM Opcode = 47
+)
+
+const (
+	// lmLen is the length of the fixed fields of a loss measurement PDU.
+	lmLen = 12
+
+	// timestampLen is the length of a Y.1731 timestamp.
+	timestampLen = 8
+
+	// dmLen and oneDMLen are the lengths of the fixed fields of two-way
+	// and one-way delay measurement PDUs.
+	dmLen    = 4 * timestampLen
+	oneDMLen = 2 * timestampLen
+)
+
+// A LossMeasurement contains the frame counters of an LMM or LMR PDU.
+type LossMeasurement struct {
+	// TxFCf specifies the transmit counter of the LMM sender, RxFCf the
+	// receive counter of the LMM receiver, and TxFCb the transmit counter
+	// of the LMR sender.  RxFCf and TxFCb are zero in an LMM.
+	TxFCf uint32
+	RxFCf uint32
+	TxFCb uint32
+}
+
+// A Timestamp is a Y.1731 timestamp, which uses the format of a PTP
+// timestamp with a 32 bit seconds field.
+type Timestamp struct {
+	Seconds     uint32
+	Nanoseconds uint32
+}
+
+// NewTimestamp creates a Timestamp from time t.
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{
+		Seconds:     uint32(t.Unix()),
+		Nanoseconds: uint32(t.Nanosecond()),
+	}
+}
+
+// Time converts a Timestamp into a time.Time.
+func (ts Timestamp) Time() time.Time {
+	return time.Unix(int64(ts.Seconds), int64(ts.Nanoseconds))
+}
+
+// sub returns the duration ts-u.
+func (ts Timestamp) sub(u Timestamp) time.Duration {
+	return time.Duration(int64(ts.Seconds)-int64(u.Seconds))*time.Second +
+		time.Duration(int64(ts.Nanoseconds)-int64(u.Nanoseconds))
+}
+
+// A DelayMeasurement contains the timestamps of a DMM, DMR, or 1DM PDU.
+type DelayMeasurement struct {
+	// TxTimestampf specifies the transmit time of the DMM or 1DM, and
+	// RxTimestampf its receive time.
+	TxTimestampf Timestamp
+	RxTimestampf Timestamp
+
+	// TxTimestampb specifies the transmit time of the DMR, and RxTimestampb
+	// its receive time.  Both are omitted from 1DM PDUs.
+	TxTimestampb Timestamp
+	RxTimestampb Timestamp
+}
+
+// Delay returns the two-way frame delay described by a DelayMeasurement.  If
+// the responder filled in RxTimestampf and TxTimestampb, its processing time
+// is excluded from the delay.
+func (dm *DelayMeasurement) Delay() time.Duration {
+	d := dm.RxTimestampb.sub(dm.TxTimestampf)
+	if dm.RxTimestampf != (Timestamp{}) && dm.TxTimestampb != (Timestamp{}) {
+		d -= dm.TxTimestampb.sub(dm.RxTimestampf)
+	}
+
+	return d
+}
+
+// append appends the binary form of a LossMeasurement to b.
+func (lm *LossMeasurement) append(b []byte) []byte {
+	var v [lmLen]byte
+	binary.BigEndian.PutUint32(v[0:4], lm.TxFCf)
+	binary.BigEndian.PutUint32(v[4:8], lm.RxFCf)
+	binary.BigEndian.PutUint32(v[8:12], lm.TxFCb)
+
+	return append(b, v[:]...)
+}
+
+// unmarshal unmarshals a LossMeasurement from b.
+func (lm *LossMeasurement) unmarshal(b []byte) error {
+	if len(b) < lmLen {
+		return io.ErrUnexpectedEOF
+	}
+
+	*lm = LossMeasurement{
+		TxFCf: binary.BigEndian.Uint32(b[0:4]),
+		RxFCf: binary.BigEndian.Uint32(b[4:8]),
+		TxFCb: binary.BigEndian.Uint32(b[8:12]),
+	}
+
+	return nil
+}
+
+// append appends the binary form of a DelayMeasurement to b.  If oneWay is
+// true, the backward timestamps are omitted.
+func (dm *DelayMeasurement) append(b []byte, oneWay bool) []byte {
+	var v [dmLen]byte
+	n := dmLen
+	if oneWay {
+		n = oneDMLen
+	}
+
+	for i, ts := range []Timestamp{
+		dm.TxTimestampf,
+		dm.RxTimestampf,
+		dm.TxTimestampb,
+		dm.RxTimestampb,
+	}[:n/timestampLen] {
+		binary.BigEndian.PutUint32(v[i*timestampLen:], ts.Seconds)
+		binary.BigEndian.PutUint32(v[i*timestampLen+4:], ts.Nanoseconds)
+	}
+
+	return append(b, v[:n]...)
+}
+
+// unmarshal unmarshals a DelayMeasurement from b.  If oneWay is true, the
+// backward timestamps are not present.
+func (dm *DelayMeasurement) unmarshal(b []byte, oneWay bool) error {
+	n := dmLen
+	if oneWay {
+		n = oneDMLen
+	}
+	if len(b) < n {
+		return io.ErrUnexpectedEOF
+	}
+
+	var out DelayMeasurement
+	for i, ts := range []*Timestamp{
+		&out.TxTimestampf,
+		&out.RxTimestampf,
+		&out.TxTimestampb,
+		&out.RxTimestampb,
+	}[:n/timestampLen] {
+		*ts = Timestamp{
+			Seconds:     binary.BigEndian.Uint32(b[i*timestampLen:]),
+			Nanoseconds: binary.BigEndian.Uint32(b[i*timestampLen+4:]),
+		}
+	}
+
+	*dm = out
+	return nil
+}
@@ -0,0 +1,182 @@
+package cfm
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestY1731MarshalUnmarshal(t *testing.T) {
+	tests := []struct {
+		desc string
+		p    *PDU
+		b    []byte
+	}{
+		{
+			desc: "LMM",
+			p: &PDU{
+				Level:  4,
+				Opcode: OpcodeLMM,
+				LM:     &LossMeasurement{TxFCf: 0x01020304},
+			},
+			b: []byte{
+				0x80, 0x2b, 0x00, 0x0c,
+				0x01, 0x02, 0x03, 0x04,
+				0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00,
+				0x00,
+			},
+		},
+		{
+			desc: "LMR",
+			p: &PDU{
+				Level:  4,
+				Opcode: OpcodeLMR,
+				LM: &LossMeasurement{
+					TxFCf: 1,
+					RxFCf: 2,
+					TxFCb: 3,
+				},
+			},
+			b: []byte{
+				0x80, 0x2a, 0x00, 0x0c,
+				0x00, 0x00, 0x00, 0x01,
+				0x00, 0x00, 0x00, 0x02,
+				0x00, 0x00, 0x00, 0x03,
+				0x00,
+			},
+		},
+		{
+			desc: "DMR",
+			p: &PDU{
+				Opcode: OpcodeDMR,
+				DM: &DelayMeasurement{
+					TxTimestampf: Timestamp{Seconds: 1, Nanoseconds: 2},
+					RxTimestampf: Timestamp{Seconds: 3, Nanoseconds: 4},
+					TxTimestampb: Timestamp{Seconds: 5, Nanoseconds: 6},
+				},
+			},
+			b: []byte{
+				0x00, 0x2e, 0x00, 0x20,
+				0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x02,
+				0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x04,
+				0x00, 0x00, 0x00, 0x05, 0x00, 0x00, 0x00, 0x06,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00,
+			},
+		},
+		{
+			desc: "1DM",
+			p: &PDU{
+				Opcode: Opcode1DM,
+				DM: &DelayMeasurement{
+					TxTimestampf: Timestamp{Seconds: 1, Nanoseconds: 2},
+				},
+				TLVs: []TLV{{Type: TLVData, Value: []byte{0xaa}}},
+			},
+			b: []byte{
+				0x00, 0x2d, 0x00, 0x10,
+				0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x02,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x03, 0x00, 0x01, 0xaa,
+				0x00,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := tt.p.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			if want, got := tt.b, b; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected bytes:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			p := new(PDU)
+			if err := p.UnmarshalBinary(b); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+
+			if !reflect.DeepEqual(tt.p, p) {
+				t.Fatalf("unexpected PDU:\n- want: %#v\n-  got: %#v", tt.p, p)
+			}
+		})
+	}
+}
+
+func TestY1731Errors(t *testing.T) {
+	for _, p := range []*PDU{
+		{Opcode: OpcodeLMM},
+		{Opcode: OpcodeDMM},
+	} {
+		if _, err := p.MarshalBinary(); err != ErrInvalidHeader {
+			t.Fatalf("expected ErrInvalidHeader for %v, but got: %v", p.Opcode, err)
+		}
+	}
+
+	tests := []struct {
+		desc string
+		b    []byte
+	}{
+		{
+			desc: "short LMM",
+			b:    []byte{0x00, 0x2b, 0x00, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+		{
+			desc: "short DMM",
+			b:    append([]byte{0x00, 0x2f, 0x00, 0x10}, make([]byte, 17)...),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := new(PDU).UnmarshalBinary(tt.b); err != io.ErrUnexpectedEOF {
+				t.Fatalf("expected io.ErrUnexpectedEOF, but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestDelayMeasurementDelay(t *testing.T) {
+	tests := []struct {
+		desc string
+		dm   *DelayMeasurement
+		d    time.Duration
+	}{
+		{
+			desc: "responder timestamps",
+			dm: &DelayMeasurement{
+				TxTimestampf: Timestamp{Seconds: 1, Nanoseconds: 900000000},
+				RxTimestampf: Timestamp{Seconds: 10, Nanoseconds: 0},
+				TxTimestampb: Timestamp{Seconds: 10, Nanoseconds: 500},
+				RxTimestampb: Timestamp{Seconds: 2, Nanoseconds: 100000000},
+			},
+			d: 200*time.Millisecond - 500*time.Nanosecond,
+		},
+		{
+			desc: "no responder timestamps",
+			dm: &DelayMeasurement{
+				TxTimestampf: NewTimestamp(time.Unix(1, 0)),
+				RxTimestampb: NewTimestamp(time.Unix(1, 1000)),
+			},
+			d: time.Microsecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if want, got := tt.d, tt.dm.Delay(); want != got {
+				t.Fatalf("unexpected delay: %v != %v", want, got)
+			}
+		})
+	}
+
+	if want, got := time.Unix(1, 2), (Timestamp{Seconds: 1, Nanoseconds: 2}).Time(); !want.Equal(got) {
+		t.Fatalf("unexpected time: %v != %v", want, got)
+	}
+}
@@ -0,0 +1,50 @@
+package ethernet
+
+// Clone returns a deep copy of a Frame, which shares no memory with the
+// original.  Clone is useful when handing off a Frame unmarshaled from a
+// shared buffer to another goroutine.
+//
+// Nil slices and headers remain nil in the copy.
+func (f *Frame) Clone() *Frame {
+	if f == nil {
+		return nil
+	}
+
+	g := *f
+
+	// Copy all byte slices into a single allocation.
+	b := make([]byte, 0, len(f.Destination)+len(f.Source)+len(f.Payload)+len(f.Padding))
+	for _, s := range []*[]byte{
+		(*[]byte)(&g.Destination),
+		(*[]byte)(&g.Source),
+		&g.Payload,
+		&g.Padding,
+	} {
+		if *s == nil {
+			continue
+		}
+
+		n := len(b)
+		b = append(b, *s...)
+		*s = b[n:len(b):len(b)]
+	}
+
+	if f.ServiceVLAN != nil {
+		v := *f.ServiceVLAN
+		g.ServiceVLAN = &v
+	}
+	if f.VLAN != nil {
+		v := *f.VLAN
+		g.VLAN = &v
+	}
+	if f.LLC != nil {
+		l := *f.LLC
+		g.LLC = &l
+	}
+	if f.SNAP != nil {
+		s := *f.SNAP
+		g.SNAP = &s
+	}
+
+	return &g
+}
@@ -0,0 +1,63 @@
+package ethernet
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestFrameClone(t *testing.T) {
+	if (*Frame)(nil).Clone() != nil {
+		t.Fatal("expected nil clone of nil Frame")
+	}
+
+	f := &Frame{
+		Destination: Broadcast,
+		Source:      net.HardwareAddr{0, 1, 0, 1, 0, 1},
+		ServiceVLAN: &VLAN{ID: 100},
+		VLAN:        &VLAN{Priority: 1, ID: 101},
+		EtherType:   10,
+		LLC:         &LLC{DSAP: 0xaa, SSAP: 0xaa, Control: 0x03},
+		SNAP:        &SNAP{ProtocolID: 0x0800},
+		Payload:     []byte{1, 2},
+		Padding:     []byte{0, 0, 0},
+	}
+
+	// Keep a separate copy of the original to detect modifications.
+	orig := &Frame{
+		Destination: append(net.HardwareAddr(nil), f.Destination...),
+		Source:      append(net.HardwareAddr(nil), f.Source...),
+		ServiceVLAN: &VLAN{ID: 100},
+		VLAN:        &VLAN{Priority: 1, ID: 101},
+		EtherType:   10,
+		LLC:         &LLC{DSAP: 0xaa, SSAP: 0xaa, Control: 0x03},
+		SNAP:        &SNAP{ProtocolID: 0x0800},
+		Payload:     []byte{1, 2},
+		Padding:     []byte{0, 0, 0},
+	}
+
+	g := f.Clone()
+	if !reflect.DeepEqual(f, g) {
+		t.Fatalf("unexpected clone:\n- want: %v\n-  got: %v", f, g)
+	}
+
+	// Modify every field of the clone, and ensure the original is intact.
+	g.Destination[0] = 0
+	g.Source[0] = 0xff
+	g.ServiceVLAN.ID = 1
+	g.VLAN.ID = 1
+	g.LLC.DSAP = 0x42
+	g.SNAP.ProtocolID = 0x86dd
+	g.Payload[0] = 0xff
+	g.Padding[0] = 0xff
+	_ = append(g.Destination, 0xff)
+
+	if !reflect.DeepEqual(orig, f) {
+		t.Fatalf("original Frame was modified:\n- want: %v\n-  got: %v", orig, f)
+	}
+
+	// Nil fields remain nil.
+	if g := (&Frame{}).Clone(); !reflect.DeepEqual(&Frame{}, g) {
+		t.Fatalf("unexpected clone of empty Frame: %v", g)
+	}
+}
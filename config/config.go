@@ -0,0 +1,338 @@
+// Package config loads JSON configuration describing access control lists,
+// frame filters, and per-interface bindings, and reloads it at runtime so
+// that long-running programs can be reconfigured without dropping frames.
+//
+// An example configuration:
+//
+//	{
+//	  "acls": {
+//	    "edge": {
+//	      "default": "permit",
+//	      "rules": [
+//	        {"ether_type": "0x86dd", "action": "deny"},
+//	        {"vlan": 10, "action": "set-priority", "set_priority": 5}
+//	      ]
+//	    }
+//	  },
+//	  "filters": {
+//	    "arp": [{"ether_type": "ARP"}]
+//	  },
+//	  "interfaces": {
+//	    "eth0": {
+//	      "ingress_acl": "edge",
+//	      "vlan_map": {"100": 200}
+//	    }
+//	  }
+//	}
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mdlayher/ethernet"
+	"github.com/mdlayher/ethernet/acl"
+	"github.com/mdlayher/ethernet/conn"
+)
+
+// A Config is a parsed and compiled configuration.
+type Config struct {
+	// ACLs are access control lists, by name.
+	ACLs map[string]*acl.ACL
+
+	// Filters are frame filters, by name.  A frame passes a filter if it
+	// matches any of the filter's rules.
+	Filters map[string]conn.Filter
+
+	// Interfaces are access control list bindings, by interface name.
+	Interfaces map[string]Interface
+}
+
+// An Interface binds access control lists to a network interface.
+type Interface struct {
+	// Ingress and Egress are the access control lists for frames read from
+	// and written to the interface.  If nil, all frames are permitted.
+	//
+	// If the interface has a VLAN map, its entries are evaluated after the
+	// rules of the ingress access control list.
+	Ingress, Egress *acl.ACL
+}
+
+// Apply atomically updates each conn.ACL in conns with the access control
+// lists bound to its interface name.  Interfaces which are not present in
+// c permit all frames.
+func (c *Config) Apply(conns map[string]*conn.ACL) {
+	for name, a := range conns {
+		ifi := c.Interfaces[name]
+		a.Update(ifi.Ingress, ifi.Egress)
+	}
+}
+
+// Load reads and parses a JSON configuration file.
+func Load(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(b)
+}
+
+// Parse parses and compiles a JSON configuration.
+func Parse(b []byte) (*Config, error) {
+	var f file
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("config: %v", err)
+	}
+
+	c := &Config{
+		ACLs:       make(map[string]*acl.ACL, len(f.ACLs)),
+		Filters:    make(map[string]conn.Filter, len(f.Filters)),
+		Interfaces: make(map[string]Interface, len(f.Interfaces)),
+	}
+
+	// Retain the source rules of each ACL so that VLAN maps can be appended
+	// to them.
+	type source struct {
+		rules []acl.Rule
+		def   acl.Action
+	}
+	sources := make(map[string]source, len(f.ACLs))
+
+	for name, ja := range f.ACLs {
+		def, err := parseAction(ja.Default, jsonRule{})
+		if err != nil {
+			return nil, fmt.Errorf("config: ACL %q: default: %v", name, err)
+		}
+
+		rules, err := parseRules(ja.Rules, true)
+		if err != nil {
+			return nil, fmt.Errorf("config: ACL %q: %v", name, err)
+		}
+
+		l, err := acl.Compile(rules, def)
+		if err != nil {
+			return nil, fmt.Errorf("config: ACL %q: %v", name, err)
+		}
+
+		c.ACLs[name] = l
+		sources[name] = source{rules: rules, def: def}
+	}
+
+	for name, jrs := range f.Filters {
+		rules, err := parseRules(jrs, false)
+		if err != nil {
+			return nil, fmt.Errorf("config: filter %q: %v", name, err)
+		}
+
+		l, err := acl.Compile(rules, acl.Action{Type: acl.Deny})
+		if err != nil {
+			return nil, fmt.Errorf("config: filter %q: %v", name, err)
+		}
+
+		c.Filters[name] = l.Permits
+	}
+
+	for name, ji := range f.Interfaces {
+		for _, l := range []string{ji.Ingress, ji.Egress} {
+			if _, ok := sources[l]; l != "" && !ok {
+				return nil, fmt.Errorf("config: interface %q: unknown ACL %q", name, l)
+			}
+		}
+
+		ifi := Interface{
+			Ingress: c.ACLs[ji.Ingress],
+			Egress:  c.ACLs[ji.Egress],
+		}
+
+		if len(ji.VLANMap) > 0 {
+			rules, err := parseVLANMap(ji.VLANMap)
+			if err != nil {
+				return nil, fmt.Errorf("config: interface %q: %v", name, err)
+			}
+
+			in := sources[ji.Ingress]
+			l, err := acl.Compile(append(append([]acl.Rule(nil), in.rules...), rules...), in.def)
+			if err != nil {
+				return nil, fmt.Errorf("config: interface %q: %v", name, err)
+			}
+
+			ifi.Ingress = l
+		}
+
+		c.Interfaces[name] = ifi
+	}
+
+	return c, nil
+}
+
+// A file is the JSON representation of a Config.
+type file struct {
+	ACLs       map[string]jsonACL       `json:"acls"`
+	Filters    map[string][]jsonRule    `json:"filters"`
+	Interfaces map[string]jsonInterface `json:"interfaces"`
+}
+
+// A jsonACL is the JSON representation of an acl.ACL.
+type jsonACL struct {
+	Default string     `json:"default"`
+	Rules   []jsonRule `json:"rules"`
+}
+
+// A jsonRule is the JSON representation of an acl.Rule.
+type jsonRule struct {
+	Destination     string          `json:"destination"`
+	DestinationMask string          `json:"destination_mask"`
+	Source          string          `json:"source"`
+	SourceMask      string          `json:"source_mask"`
+	EtherType       json.RawMessage `json:"ether_type"`
+	VLAN            *uint16         `json:"vlan"`
+	Priority        *uint8          `json:"priority"`
+	Action          string          `json:"action"`
+	SetPriority     uint8           `json:"set_priority"`
+	SetVLAN         uint16          `json:"set_vlan"`
+}
+
+// A jsonInterface is the JSON representation of an Interface.
+type jsonInterface struct {
+	Ingress string            `json:"ingress_acl"`
+	Egress  string            `json:"egress_acl"`
+	VLANMap map[string]uint16 `json:"vlan_map"`
+}
+
+// parseRules parses rules.  If actions is false, rule actions are not
+// permitted and all rules use the Permit action.
+func parseRules(jrs []jsonRule, actions bool) ([]acl.Rule, error) {
+	rules := make([]acl.Rule, 0, len(jrs))
+	for i, jr := range jrs {
+		r, err := parseRule(jr, actions)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %v", i, err)
+		}
+
+		rules = append(rules, r)
+	}
+
+	return rules, nil
+}
+
+// parseRule parses a single rule.
+func parseRule(jr jsonRule, actions bool) (acl.Rule, error) {
+	var (
+		r   acl.Rule
+		err error
+	)
+
+	for _, a := range []struct {
+		s   string
+		dst *net.HardwareAddr
+	}{
+		{s: jr.Destination, dst: &r.Destination},
+		{s: jr.DestinationMask, dst: &r.DestinationMask},
+		{s: jr.Source, dst: &r.Source},
+		{s: jr.SourceMask, dst: &r.SourceMask},
+	} {
+		if a.s == "" {
+			continue
+		}
+
+		if *a.dst, err = net.ParseMAC(a.s); err != nil {
+			return acl.Rule{}, err
+		}
+	}
+
+	if r.EtherType, err = parseEtherType(jr.EtherType); err != nil {
+		return acl.Rule{}, err
+	}
+
+	r.VLAN = jr.VLAN
+	if jr.Priority != nil {
+		p := ethernet.Priority(*jr.Priority)
+		r.Priority = &p
+	}
+
+	if !actions {
+		if jr.Action != "" {
+			return acl.Rule{}, fmt.Errorf("actions are not permitted in filters")
+		}
+
+		return r, nil
+	}
+
+	if r.Action, err = parseAction(jr.Action, jr); err != nil {
+		return acl.Rule{}, err
+	}
+
+	return r, nil
+}
+
+// parseEtherType parses an EtherType specified as either a JSON number or a
+// string containing a name, such as "ARP", or a decimal or hexadecimal value.
+func parseEtherType(b json.RawMessage) (ethernet.EtherType, error) {
+	if len(b) == 0 {
+		return 0, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		s = string(b)
+	}
+
+	var et ethernet.EtherType
+	if err := et.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("invalid EtherType %s", b)
+	}
+
+	return et, nil
+}
+
+// parseAction parses an action by name, using the parameters in jr.  An
+// empty name is a Permit action.
+func parseAction(s string, jr jsonRule) (acl.Action, error) {
+	for _, t := range []acl.ActionType{acl.Permit, acl.Deny, acl.SetPriority, acl.SetVLAN, acl.Mirror} {
+		if s != "" && !strings.EqualFold(s, t.String()) {
+			continue
+		}
+
+		return acl.Action{
+			Type:     t,
+			Priority: ethernet.Priority(jr.SetPriority),
+			VLAN:     jr.SetVLAN,
+		}, nil
+	}
+
+	return acl.Action{}, fmt.Errorf("unknown action %q", s)
+}
+
+// parseVLANMap parses a VLAN map into rules which translate VLAN IDs.
+func parseVLANMap(m map[string]uint16) ([]acl.Rule, error) {
+	rules := make([]acl.Rule, 0, len(m))
+	for from, to := range m {
+		v, err := strconv.ParseUint(from, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid VLAN map entry %q", from)
+		}
+
+		id := uint16(v)
+		rules = append(rules, acl.Rule{
+			VLAN: &id,
+			Action: acl.Action{
+				Type: acl.SetVLAN,
+				VLAN: to,
+			},
+		})
+	}
+
+	// Each rule matches a distinct VLAN, but sort them anyway so that
+	// compiled ACLs are deterministic.
+	sort.Slice(rules, func(i, j int) bool {
+		return *rules[i].VLAN < *rules[j].VLAN
+	})
+
+	return rules, nil
+}
@@ -0,0 +1,232 @@
+package config
+
+import (
+	"net"
+	"testing"
+
+	"github.com/mdlayher/ethernet"
+	"github.com/mdlayher/ethernet/acl"
+	"github.com/mdlayher/ethernet/conn"
+)
+
+const testConfig = `{
+  "acls": {
+    "edge": {
+      "default": "permit",
+      "rules": [
+        {"ether_type": "IPv6", "action": "deny"},
+        {"source": "de:ad:be:ef:00:00", "source_mask": "ff:ff:ff:ff:00:00", "action": "mirror"},
+        {"vlan": 10, "action": "set-priority", "set_priority": 5}
+      ]
+    },
+    "closed": {"default": "deny"}
+  },
+  "filters": {
+    "arp": [{"ether_type": 2054}]
+  },
+  "interfaces": {
+    "eth0": {
+      "ingress_acl": "edge",
+      "egress_acl": "closed",
+      "vlan_map": {"100": 200}
+    },
+    "eth1": {"vlan_map": {"1": 2}}
+  }
+}`
+
+func TestParse(t *testing.T) {
+	c, err := Parse([]byte(testConfig))
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	var (
+		src   = net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0x12, 0x34}
+		other = net.HardwareAddr{0x00, 0x00, 0x00, 0x00, 0x00, 0x01}
+	)
+
+	frame := func(src net.HardwareAddr, vlan uint16, et ethernet.EtherType) *ethernet.Frame {
+		f := &ethernet.Frame{
+			Destination: ethernet.Broadcast,
+			Source:      src,
+			EtherType:   et,
+		}
+		if vlan != 0 {
+			f.VLAN = &ethernet.VLAN{ID: vlan}
+		}
+
+		return f
+	}
+
+	tests := []struct {
+		desc string
+		l    *acl.ACL
+		f    *ethernet.Frame
+		a    acl.Action
+	}{
+		{
+			desc: "edge deny IPv6",
+			l:    c.ACLs["edge"],
+			f:    frame(src, 0, ethernet.EtherTypeIPv6),
+			a:    acl.Action{Type: acl.Deny},
+		},
+		{
+			desc: "edge mirror masked source",
+			l:    c.ACLs["edge"],
+			f:    frame(src, 0, ethernet.EtherTypeIPv4),
+			a:    acl.Action{Type: acl.Mirror},
+		},
+		{
+			desc: "edge set priority",
+			l:    c.ACLs["edge"],
+			f:    frame(other, 10, ethernet.EtherTypeIPv4),
+			a:    acl.Action{Type: acl.SetPriority, Priority: 5},
+		},
+		{
+			desc: "edge default",
+			l:    c.ACLs["edge"],
+			f:    frame(other, 0, ethernet.EtherTypeIPv4),
+			a:    acl.Action{Type: acl.Permit},
+		},
+		{
+			desc: "closed default",
+			l:    c.ACLs["closed"],
+			f:    frame(other, 0, ethernet.EtherTypeIPv4),
+			a:    acl.Action{Type: acl.Deny},
+		},
+		{
+			desc: "eth0 ingress ACL rule",
+			l:    c.Interfaces["eth0"].Ingress,
+			f:    frame(src, 100, ethernet.EtherTypeIPv6),
+			a:    acl.Action{Type: acl.Deny},
+		},
+		{
+			desc: "eth0 ingress VLAN map",
+			l:    c.Interfaces["eth0"].Ingress,
+			f:    frame(other, 100, ethernet.EtherTypeIPv4),
+			a:    acl.Action{Type: acl.SetVLAN, VLAN: 200},
+		},
+		{
+			desc: "eth0 egress",
+			l:    c.Interfaces["eth0"].Egress,
+			f:    frame(other, 0, ethernet.EtherTypeIPv4),
+			a:    acl.Action{Type: acl.Deny},
+		},
+		{
+			desc: "eth1 VLAN map only",
+			l:    c.Interfaces["eth1"].Ingress,
+			f:    frame(other, 1, ethernet.EtherTypeIPv4),
+			a:    acl.Action{Type: acl.SetVLAN, VLAN: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if tt.l == nil {
+				t.Fatal("ACL is nil")
+			}
+
+			a, _ := tt.l.Match(tt.f)
+			if want, got := tt.a, a; want != got {
+				t.Fatalf("unexpected action: %+v != %+v", want, got)
+			}
+		})
+	}
+
+	if c.Interfaces["eth1"].Egress != nil {
+		t.Fatal("expected nil egress ACL for eth1")
+	}
+
+	arp := c.Filters["arp"]
+	if !arp(frame(other, 0, ethernet.EtherTypeARP)) || arp(frame(other, 0, ethernet.EtherTypeIPv4)) {
+		t.Fatal("ARP filter did not match as expected")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		s    string
+	}{
+		{
+			desc: "bad JSON",
+			s:    `{`,
+		},
+		{
+			desc: "bad action",
+			s:    `{"acls": {"a": {"rules": [{"action": "explode"}]}}}`,
+		},
+		{
+			desc: "bad default",
+			s:    `{"acls": {"a": {"default": "explode"}}}`,
+		},
+		{
+			desc: "bad MAC",
+			s:    `{"acls": {"a": {"rules": [{"source": "zz"}]}}}`,
+		},
+		{
+			desc: "bad EtherType",
+			s:    `{"acls": {"a": {"rules": [{"ether_type": "0x10000"}]}}}`,
+		},
+		{
+			desc: "bad priority",
+			s:    `{"acls": {"a": {"rules": [{"priority": 8}]}}}`,
+		},
+		{
+			desc: "filter action",
+			s:    `{"filters": {"f": [{"action": "deny"}]}}`,
+		},
+		{
+			desc: "unknown ACL",
+			s:    `{"interfaces": {"eth0": {"ingress_acl": "nope"}}}`,
+		},
+		{
+			desc: "bad VLAN map",
+			s:    `{"interfaces": {"eth0": {"vlan_map": {"x": 1}}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.s)); err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+		})
+	}
+}
+
+func TestConfigApply(t *testing.T) {
+	c, err := Parse([]byte(testConfig))
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	a := conn.NewACL(&nopConn{}, nil)
+	c.Apply(map[string]*conn.ACL{"eth0": a})
+
+	// eth0's egress ACL denies all frames.
+	f := &ethernet.Frame{
+		Destination: ethernet.Broadcast,
+		Source:      net.HardwareAddr{0, 0, 0, 0, 0, 1},
+		EtherType:   ethernet.EtherTypeIPv4,
+		Payload:     make([]byte, 46),
+	}
+	b, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	if _, err := a.WriteTo(b, nil); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	if want, got := uint64(1), a.Stats().Denied; want != got {
+		t.Fatalf("unexpected number of denied frames: %v != %v", want, got)
+	}
+}
+
+// nopConn is a net.PacketConn which discards writes.
+type nopConn struct {
+	net.PacketConn
+}
+
+func (nopConn) WriteTo(b []byte, _ net.Addr) (int, error) { return len(b), nil }
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// defaultWatchInterval is the default interval at which Watch checks a
+// configuration file for changes.
+const defaultWatchInterval = 5 * time.Second
+
+// WatchConfig specifies configuration for Watch.
+type WatchConfig struct {
+	// Interval specifies how often the configuration file is checked for
+	// modification.  If zero, a default of 5 seconds is used.  If negative,
+	// the file is only reloaded when a signal is received.
+	Interval time.Duration
+
+	// Signals specifies the signals which trigger a reload.  If nil, SIGHUP
+	// is used.
+	Signals []os.Signal
+
+	// Error, if not nil, is invoked when a reloaded configuration cannot be
+	// loaded or applied.
+	Error func(err error)
+}
+
+// Watch loads the configuration file at path and passes it to apply.  Watch
+// then reloads the file whenever a configured signal is received or its
+// modification time changes, until ctx is canceled.
+//
+// If the initial configuration cannot be loaded or applied, Watch returns
+// the error immediately.  If a reloaded configuration cannot be loaded or
+// applied, the error is reported using cfg.Error and the previous
+// configuration remains in effect.  If cfg is nil, a default configuration
+// is used.
+func Watch(ctx context.Context, path string, apply func(c *Config) error, cfg *WatchConfig) error {
+	if cfg == nil {
+		cfg = &WatchConfig{}
+	}
+
+	interval := cfg.Interval
+	if interval == 0 {
+		interval = defaultWatchInterval
+	}
+
+	signals := cfg.Signals
+	if signals == nil {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+
+	mtime, err := load(path, apply)
+	if err != nil {
+		return err
+	}
+
+	sigC := make(chan os.Signal, 1)
+	signal.Notify(sigC, signals...)
+	defer signal.Stop(sigC)
+
+	var tickC <-chan time.Time
+	if interval > 0 {
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		tickC = tick.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-sigC:
+		case <-tickC:
+			fi, err := os.Stat(path)
+			if err != nil {
+				report(cfg, err)
+				continue
+			}
+			if fi.ModTime().Equal(mtime) {
+				continue
+			}
+
+			// Don't retry a broken file until it changes again.
+			mtime = fi.ModTime()
+		}
+
+		t, err := load(path, apply)
+		if err != nil {
+			report(cfg, err)
+			continue
+		}
+
+		mtime = t
+	}
+}
+
+// load loads the configuration file at path and passes it to apply,
+// returning the file's modification time.
+func load(path string, apply func(c *Config) error) (time.Time, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	c, err := Load(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if err := apply(c); err != nil {
+		return time.Time{}, err
+	}
+
+	return fi.ModTime(), nil
+}
+
+// report reports err using cfg.Error, if set.
+func report(cfg *WatchConfig, err error) {
+	if cfg.Error != nil {
+		cfg.Error(err)
+	}
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ethernet-config")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.json")
+	write := func(s string, mtime time.Time) {
+		if err := ioutil.WriteFile(path, []byte(s), 0644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatalf("failed to set modification time: %v", err)
+		}
+	}
+
+	t0 := time.Unix(1000, 0)
+	write(`{"acls": {"a": {}}}`, t0)
+
+	var (
+		applied = make(chan *Config, 4)
+		errC    = make(chan error, 4)
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- Watch(ctx, path, func(c *Config) error {
+			applied <- c
+			return nil
+		}, &WatchConfig{
+			Interval: 5 * time.Millisecond,
+			Error:    func(err error) { errC <- err },
+		})
+	}()
+
+	if c := <-applied; len(c.ACLs) != 1 {
+		t.Fatalf("unexpected initial config: %+v", c)
+	}
+
+	// A broken configuration is reported and not applied.
+	write(`{`, t0.Add(time.Second))
+	<-errC
+
+	write(`{"acls": {"a": {}, "b": {}}}`, t0.Add(2*time.Second))
+	if c := <-applied; len(c.ACLs) != 2 {
+		t.Fatalf("unexpected reloaded config: %+v", c)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("unexpected error: %v != %v", context.Canceled, err)
+	}
+}
+
+func TestWatchInitialError(t *testing.T) {
+	err := Watch(context.Background(), "/nonexistent/config.json", func(*Config) error {
+		panic("should not be called")
+	}, nil)
+	if !os.IsNotExist(err) {
+		t.Fatalf("expected not exist error, but got: %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package conn
+
+import (
+	"io"
+	"net"
+	"sync/atomic"
+
+	"github.com/mdlayher/ethernet"
+	"github.com/mdlayher/ethernet/acl"
+)
+
+// ACLConfig specifies configuration for an ACL.
+type ACLConfig struct {
+	// Ingress and Egress specify access control lists for frames read from
+	// and written to the underlying net.PacketConn, respectively.  If an
+	// access control list is nil, all frames in that direction are
+	// permitted without modification.
+	Ingress, Egress *acl.ACL
+
+	// Sink, if not nil, receives copies of frames which match a rule with a
+	// Mirror action.
+	Sink Sink
+}
+
+// An ACL is a net.PacketConn which applies access control lists to frames.
+// Denied frames are dropped, and frames may be modified or mirrored as
+// specified by the matching rule.
+//
+// Frames which cannot be unmarshaled are always permitted.
+//
+// The access control lists of an ACL may be replaced at any time using
+// Update, without interrupting frames in flight.
+type ACL struct {
+	// Atomics must come first.
+	denied, modified, mirrored uint64
+
+	net.PacketConn
+	lists atomic.Value // *aclLists
+	sink  Sink
+}
+
+// aclLists are the access control lists in use by an ACL.
+type aclLists struct {
+	ingress, egress *acl.ACL
+}
+
+// ACLStats contains statistics about an ACL.
+type ACLStats struct {
+	// Denied is the number of frames dropped by a Deny action.
+	Denied uint64
+
+	// Modified is the number of frames modified by a SetPriority or SetVLAN
+	// action.
+	Modified uint64
+
+	// Mirrored is the number of frames sent to the Sink by a Mirror action.
+	Mirrored uint64
+}
+
+// NewACL wraps c with an ACL using the input configuration.  If cfg is nil,
+// all frames are permitted without modification.
+func NewACL(c net.PacketConn, cfg *ACLConfig) *ACL {
+	if cfg == nil {
+		cfg = &ACLConfig{}
+	}
+
+	a := &ACL{
+		PacketConn: c,
+		sink:       cfg.Sink,
+	}
+	a.Update(cfg.Ingress, cfg.Egress)
+
+	return a
+}
+
+// Update atomically replaces the ingress and egress access control lists
+// of an ACL.  Frames already being processed use the previous lists.
+func (a *ACL) Update(ingress, egress *acl.ACL) {
+	a.lists.Store(&aclLists{
+		ingress: ingress,
+		egress:  egress,
+	})
+}
+
+// ReadFrom implements net.PacketConn.  Denied frames are dropped, and
+// ReadFrom continues reading until a frame is permitted or an error occurs.
+//
+// If a frame grows due to the addition of a VLAN tag and no longer fits in
+// b, io.ErrShortBuffer is returned.
+func (a *ACL) ReadFrom(b []byte) (int, net.Addr, error) {
+	for {
+		n, addr, err := a.PacketConn.ReadFrom(b)
+		if err != nil {
+			return n, addr, err
+		}
+
+		out, ok, err := a.apply(Ingress, a.load().ingress, b[:n])
+		if err != nil {
+			return 0, addr, err
+		}
+		if !ok {
+			continue
+		}
+
+		if len(out) > len(b) {
+			return 0, addr, io.ErrShortBuffer
+		}
+
+		return copy(b, out), addr, nil
+	}
+}
+
+// WriteTo implements net.PacketConn.  Denied frames are dropped without
+// returning an error.
+func (a *ACL) WriteTo(b []byte, addr net.Addr) (int, error) {
+	out, ok, err := a.apply(Egress, a.load().egress, b)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return len(b), nil
+	}
+
+	if _, err := a.PacketConn.WriteTo(out, addr); err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+// Stats returns the current statistics for an ACL.
+func (a *ACL) Stats() ACLStats {
+	return ACLStats{
+		Denied:   atomic.LoadUint64(&a.denied),
+		Modified: atomic.LoadUint64(&a.modified),
+		Mirrored: atomic.LoadUint64(&a.mirrored),
+	}
+}
+
+// load returns the current access control lists.
+func (a *ACL) load() *aclLists {
+	return a.lists.Load().(*aclLists)
+}
+
+// apply applies l to frame b, returning the resulting frame and whether or
+// not it is permitted.
+func (a *ACL) apply(d Direction, l *acl.ACL, b []byte) ([]byte, bool, error) {
+	if l == nil {
+		return b, true, nil
+	}
+
+	var f ethernet.Frame
+	if err := f.UnmarshalBinary(b); err != nil {
+		return b, true, nil
+	}
+
+	action := l.Apply(&f)
+	switch action.Type {
+	case acl.Deny:
+		atomic.AddUint64(&a.denied, 1)
+		return nil, false, nil
+	case acl.SetPriority, acl.SetVLAN:
+		out, err := f.MarshalBinary()
+		if err != nil {
+			return nil, false, err
+		}
+
+		atomic.AddUint64(&a.modified, 1)
+		return out, true, nil
+	case acl.Mirror:
+		if a.sink != nil && a.sink.WriteFrame(d, b) == nil {
+			atomic.AddUint64(&a.mirrored, 1)
+		}
+	}
+
+	return b, true, nil
+}
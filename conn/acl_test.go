@@ -0,0 +1,148 @@
+package conn
+
+import (
+	"io"
+	"testing"
+
+	"github.com/mdlayher/ethernet"
+	"github.com/mdlayher/ethernet/acl"
+)
+
+func TestACL(t *testing.T) {
+	var (
+		ipv4 = frame(t, ethernet.EtherTypeIPv4)
+		ipv6 = frame(t, ethernet.EtherTypeIPv6)
+		arp  = frame(t, ethernet.EtherTypeARP)
+	)
+
+	l, err := acl.Compile([]acl.Rule{
+		{
+			EtherType: ethernet.EtherTypeARP,
+			Action:    acl.Action{Type: acl.Deny},
+		},
+		{
+			EtherType: ethernet.EtherTypeIPv6,
+			Action:    acl.Action{Type: acl.Mirror},
+		},
+		{
+			EtherType: ethernet.EtherTypeIPv4,
+			Action: acl.Action{
+				Type: acl.SetVLAN,
+				VLAN: 10,
+			},
+		},
+	}, acl.Action{Type: acl.Permit})
+	if err != nil {
+		t.Fatalf("failed to compile ACL: %v", err)
+	}
+
+	var mirrored [][]byte
+	sink := SinkFunc(func(_ Direction, b []byte) error {
+		mirrored = append(mirrored, append([]byte(nil), b...))
+		return nil
+	})
+
+	c := &testConn{reads: [][]byte{arp, ipv6, ipv4}}
+	a := NewACL(c, &ACLConfig{
+		Ingress: l,
+		Egress:  l,
+		Sink:    sink,
+	})
+
+	// The ARP frame is denied, so the IPv6 and IPv4 frames are read.
+	var reads [][]byte
+	b := make([]byte, 128)
+	for {
+		n, _, err := a.ReadFrom(b)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read: %v", err)
+		}
+
+		reads = append(reads, append([]byte(nil), b[:n]...))
+	}
+
+	for _, b := range [][]byte{arp, ipv6, ipv4} {
+		if _, err := a.WriteTo(b, nil); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+	}
+
+	tagged := ethernet.Frame{
+		Destination: dstMAC,
+		Source:      srcMAC,
+		VLAN:        &ethernet.VLAN{ID: 10},
+		EtherType:   ethernet.EtherTypeIPv4,
+		Payload:     make([]byte, 46),
+	}
+	tagged.Payload[0] = 0xff
+
+	ipv4VLAN, err := tagged.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal frame: %v", err)
+	}
+
+	want := [][]byte{ipv6, ipv4VLAN}
+	if got := reads; !equalFrames(want, got) {
+		t.Fatalf("unexpected reads:\n- want: %v\n-  got: %v", want, got)
+	}
+	if got := c.written(); !equalFrames(want, got) {
+		t.Fatalf("unexpected writes:\n- want: %v\n-  got: %v", want, got)
+	}
+	if want, got := [][]byte{ipv6, ipv6}, mirrored; !equalFrames(want, got) {
+		t.Fatalf("unexpected mirrored frames:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	stats := ACLStats{
+		Denied:   2,
+		Modified: 2,
+		Mirrored: 2,
+	}
+	if want, got := stats, a.Stats(); want != got {
+		t.Fatalf("unexpected stats: %+v != %+v", want, got)
+	}
+}
+
+func TestACLReadFromShortBuffer(t *testing.T) {
+	l, err := acl.Compile(nil, acl.Action{
+		Type: acl.SetVLAN,
+		VLAN: 10,
+	})
+	if err != nil {
+		t.Fatalf("failed to compile ACL: %v", err)
+	}
+
+	b := frame(t, ethernet.EtherTypeIPv4)
+	a := NewACL(&testConn{reads: [][]byte{b}}, &ACLConfig{Ingress: l})
+
+	if _, _, err := a.ReadFrom(make([]byte, len(b))); err != io.ErrShortBuffer {
+		t.Fatalf("unexpected error: %v != %v", io.ErrShortBuffer, err)
+	}
+}
+
+func TestACLUpdate(t *testing.T) {
+	deny, err := acl.Compile(nil, acl.Action{Type: acl.Deny})
+	if err != nil {
+		t.Fatalf("failed to compile ACL: %v", err)
+	}
+
+	c := &testConn{}
+	a := NewACL(c, nil)
+
+	b := frame(t, ethernet.EtherTypeIPv4)
+	for _, l := range []*acl.ACL{nil, deny, nil} {
+		a.Update(nil, l)
+		if _, err := a.WriteTo(b, nil); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+	}
+
+	if want, got := 2, len(c.written()); want != got {
+		t.Fatalf("unexpected number of written frames: %v != %v", want, got)
+	}
+	if want, got := uint64(1), a.Stats().Denied; want != got {
+		t.Fatalf("unexpected number of denied frames: %v != %v", want, got)
+	}
+}
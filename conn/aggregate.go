@@ -0,0 +1,206 @@
+package conn
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// errNoConns is returned when an Aggregate is created with no member conns.
+var errNoConns = errors.New("conn: at least one member net.PacketConn is required")
+
+// AggregateConfig specifies configuration for an Aggregate.
+type AggregateConfig struct {
+	// Hash specifies the Frame fields used to select a member conn for an
+	// egress frame.  If zero, HashSource|HashDestination is used.
+	Hash HashFields
+
+	// HashPayload specifies the number of leading payload bytes included in
+	// the hash, in addition to the fields selected by Hash.
+	HashPayload int
+
+	// ReadBufferSize specifies the size of the buffer used to read frames
+	// from each member conn.  If zero, a default suitable for jumbo frames
+	// is used.
+	ReadBufferSize int
+
+	// LACP, if not nil, runs the Link Aggregation Control Protocol with the
+	// peer on each member conn.
+	LACP *LACPConfig
+}
+
+// An Aggregate is a net.PacketConn which distributes egress frames across
+// several member net.PacketConns and merges ingress frames from all of them.
+//
+// Frames belonging to a single flow, as determined by the hashed fields, are
+// always written to the same member conn, to avoid reordering.
+//
+// If LACP is enabled, frames are only written to and read from member conns
+// which are aggregated with the peer, and LACPDUs are not returned by
+// ReadFrom.  WriteTo returns an error if no member conns are aggregated.
+type Aggregate struct {
+	conns   []net.PacketConn
+	hash    HashFields
+	payload int
+	m       *merger
+
+	lacp  *lacpActor
+	stopC chan struct{}
+	wg    sync.WaitGroup
+}
+
+// NewAggregate creates an Aggregate from one or more member conns using the
+// input configuration.  If cfg is nil, a default configuration is used.
+//
+// The Aggregate takes ownership of the member conns, and immediately begins
+// reading frames from each of them.
+func NewAggregate(conns []net.PacketConn, cfg *AggregateConfig) (*Aggregate, error) {
+	if len(conns) == 0 {
+		return nil, errNoConns
+	}
+	if cfg == nil {
+		cfg = &AggregateConfig{}
+	}
+
+	hash := cfg.Hash
+	if hash == 0 {
+		hash = HashSource | HashDestination
+	}
+
+	a := &Aggregate{
+		conns:   conns,
+		hash:    hash,
+		payload: cfg.HashPayload,
+		stopC:   make(chan struct{}),
+	}
+
+	if cfg.LACP == nil {
+		a.m = newMerger(conns, cfg.ReadBufferSize, nil)
+		return a, nil
+	}
+
+	l, err := newLACPActor(conns, *cfg.LACP)
+	if err != nil {
+		return nil, err
+	}
+
+	a.lacp = l
+	a.m = newMerger(conns, cfg.ReadBufferSize, l.receive)
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		l.run(a.stopC)
+	}()
+
+	return a, nil
+}
+
+// ReadFrom implements net.PacketConn.  Frames are returned from all member
+// conns in the order they arrive.  A transient read error from a member conn
+// is returned, and does not stop reading from that member conn.
+func (a *Aggregate) ReadFrom(b []byte) (int, net.Addr, error) {
+	if a.lacp == nil {
+		return a.m.readFrom(b, nil)
+	}
+
+	return a.m.readFrom(b, a.lacp.collecting)
+}
+
+// WriteTo implements net.PacketConn.  The member conn used to write b is
+// selected by hashing the configured fields of the frame.
+func (a *Aggregate) WriteTo(b []byte, addr net.Addr) (int, error) {
+	if a.lacp == nil {
+		return a.conns[a.index(b, len(a.conns))].WriteTo(b, addr)
+	}
+
+	idx := a.lacp.distributing()
+	if len(idx) == 0 {
+		return 0, errNotDistributing
+	}
+
+	return a.conns[idx[a.index(b, len(idx))]].WriteTo(b, addr)
+}
+
+// Distributing returns the indices of the member conns to which frames are
+// written.  If LACP is not enabled, all member conns are used.
+func (a *Aggregate) Distributing() []int {
+	if a.lacp != nil {
+		return a.lacp.distributing()
+	}
+
+	idx := make([]int, 0, len(a.conns))
+	for i := range a.conns {
+		idx = append(idx, i)
+	}
+
+	return idx
+}
+
+// index selects an index from 0 to n for frame b.
+func (a *Aggregate) index(b []byte, n int) int {
+	if n == 1 {
+		return 0
+	}
+
+	var f ethernet.Frame
+	if err := f.UnmarshalBinary(b); err != nil {
+		// Any frame which cannot be parsed is sent on the first conn.
+		return 0
+	}
+
+	return int(HashFrame(&f, a.hash, a.payload) % uint32(n))
+}
+
+// Close implements net.PacketConn.  Close closes all member conns and
+// returns the first error encountered, if any.
+func (a *Aggregate) Close() error {
+	if !a.m.close() {
+		return nil
+	}
+
+	close(a.stopC)
+	err := closeAll(a.conns)
+	a.m.wait()
+	a.wg.Wait()
+	return err
+}
+
+// LocalAddr implements net.PacketConn.  LocalAddr returns the address of the
+// first member conn.
+func (a *Aggregate) LocalAddr() net.Addr { return a.conns[0].LocalAddr() }
+
+// SetDeadline implements net.PacketConn.
+func (a *Aggregate) SetDeadline(t time.Time) error {
+	if err := a.SetReadDeadline(t); err != nil {
+		return err
+	}
+
+	return a.SetWriteDeadline(t)
+}
+
+// SetReadDeadline implements net.PacketConn.
+func (a *Aggregate) SetReadDeadline(t time.Time) error {
+	a.m.setReadDeadline(t)
+	return nil
+}
+
+// SetWriteDeadline implements net.PacketConn.  The write deadline is applied
+// to all member conns.
+func (a *Aggregate) SetWriteDeadline(t time.Time) error {
+	return setWriteDeadlines(a.conns, t)
+}
+
+// setWriteDeadlines applies a write deadline to all conns.
+func setWriteDeadlines(conns []net.PacketConn, t time.Time) error {
+	for _, c := range conns {
+		if err := c.SetWriteDeadline(t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package conn
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestNewAggregateNoConns(t *testing.T) {
+	if _, err := NewAggregate(nil, nil); err != errNoConns {
+		t.Fatalf("unexpected error: %v != %v", errNoConns, err)
+	}
+}
+
+func TestAggregateWriteTo(t *testing.T) {
+	conns := []*testConn{{}, {}, {}, {}}
+	a := newAggregate(t, conns, &AggregateConfig{Hash: HashSource})
+	defer a.Close()
+
+	// Send several frames for each of several flows, and verify that each
+	// flow is always sent on the same member conn.
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 16; j++ {
+			if _, err := a.WriteTo(flow(t, byte(j)), nil); err != nil {
+				t.Fatalf("failed to write: %v", err)
+			}
+		}
+	}
+
+	var used int
+	seen := make(map[byte]int)
+	for i, c := range conns {
+		w := c.written()
+		if len(w) > 0 {
+			used++
+		}
+
+		for _, b := range w {
+			src := b[11]
+			if j, ok := seen[src]; ok && i != j {
+				t.Fatalf("flow %d sent on conns %d and %d", src, i, j)
+			}
+			seen[src] = i
+		}
+	}
+
+	if used < 2 {
+		t.Fatalf("frames were not distributed among conns: %d used", used)
+	}
+}
+
+func TestAggregateWriteToUnparseable(t *testing.T) {
+	conns := []*testConn{{}, {}}
+	a := newAggregate(t, conns, nil)
+	defer a.Close()
+
+	b := []byte{0xff}
+	if _, err := a.WriteTo(b, nil); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	if want, got := [][]byte{b}, conns[0].written(); !equalFrames(want, got) {
+		t.Fatalf("unexpected frames:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestAggregateReadFrom(t *testing.T) {
+	conns := []*testConn{
+		{reads: [][]byte{flow(t, 0), flow(t, 1)}},
+		{reads: [][]byte{flow(t, 2)}},
+	}
+
+	a := newAggregate(t, conns, nil)
+	defer a.Close()
+
+	// Each member conn returns io.EOF after its frames are exhausted.
+	var eofs int
+	seen := make(map[byte]bool)
+	b := make([]byte, 128)
+	for eofs < len(conns) {
+		n, _, err := a.ReadFrom(b)
+		if err == io.EOF {
+			eofs++
+			continue
+		}
+		if err != nil {
+			t.Fatalf("failed to read: %v", err)
+		}
+
+		seen[b[:n][11]] = true
+	}
+
+	if want, got := 3, len(seen); want != got {
+		t.Fatalf("unexpected number of frames: %v != %v", want, got)
+	}
+}
+
+func TestAggregateReadFromTransientError(t *testing.T) {
+	errTransient := errors.New("transient")
+	c := newFlakyConn(errTransient)
+
+	a, err := NewAggregate([]net.PacketConn{c}, nil)
+	if err != nil {
+		t.Fatalf("failed to create aggregate: %v", err)
+	}
+	defer a.Close()
+
+	b := make([]byte, 128)
+	if _, _, err := a.ReadFrom(b); err != errTransient {
+		t.Fatalf("unexpected error: %v != %v", errTransient, err)
+	}
+
+	// The member conn is still read after the error.
+	want := flow(t, 0)
+	go func() { c.frames <- want }()
+
+	n, _, err := a.ReadFrom(b)
+	if err != nil {
+		t.Fatalf("failed to read: %v", err)
+	}
+
+	if !equalFrames([][]byte{want}, [][]byte{b[:n]}) {
+		t.Fatalf("unexpected frame:\n- want: %v\n-  got: %v", want, b[:n])
+	}
+}
+
+func TestAggregateReadFromDeadline(t *testing.T) {
+	a := newAggregate(t, []*testConn{{}}, nil)
+	defer a.Close()
+
+	if err := a.SetReadDeadline(time.Now().Add(-1 * time.Second)); err != nil {
+		t.Fatalf("failed to set deadline: %v", err)
+	}
+
+	_, _, err := a.ReadFrom(make([]byte, 128))
+	if !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("unexpected error: %v != %v", os.ErrDeadlineExceeded, err)
+	}
+}
+
+func TestAggregateReadFromClosed(t *testing.T) {
+	a := newAggregate(t, []*testConn{{}}, nil)
+	if err := a.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+
+	_, _, err := a.ReadFrom(make([]byte, 128))
+	if !errors.Is(err, net.ErrClosed) {
+		t.Fatalf("unexpected error: %v != %v", net.ErrClosed, err)
+	}
+}
+
+// newAggregate creates an Aggregate from testConns.
+func newAggregate(t *testing.T, conns []*testConn, cfg *AggregateConfig) *Aggregate {
+	t.Helper()
+
+	pcs := make([]net.PacketConn, 0, len(conns))
+	for _, c := range conns {
+		pcs = append(pcs, c)
+	}
+
+	a, err := NewAggregate(pcs, cfg)
+	if err != nil {
+		t.Fatalf("failed to create aggregate: %v", err)
+	}
+
+	return a
+}
+
+// flow produces a marshaled Frame whose source address ends with id.
+func flow(t *testing.T, id byte) []byte {
+	t.Helper()
+
+	f := &ethernet.Frame{
+		Destination: dstMAC,
+		Source:      net.HardwareAddr{0, 0, 0, 0, 0, id},
+		EtherType:   ethernet.EtherTypeIPv4,
+		Payload:     []byte{0xff},
+	}
+
+	b, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal frame: %v", err)
+	}
+
+	return b
+}
@@ -0,0 +1,113 @@
+package conn
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// A Unit is the unit of measure for a Limit.
+type Unit int
+
+// Possible Unit values.
+const (
+	// Packets limits the number of frames per second.
+	Packets Unit = iota
+
+	// Bits limits the number of bits per second.
+	Bits
+)
+
+// A Limit is a rate limit enforced by a token bucket.
+type Limit struct {
+	// Rate is the sustained rate permitted, in Unit per second.
+	Rate uint64
+
+	// Burst is the maximum number of Unit which may be permitted at once.  If
+	// zero, Burst is equal to Rate.
+	Burst uint64
+
+	// Unit specifies whether Rate and Burst count frames or bits.
+	Unit Unit
+}
+
+// cost returns the number of tokens required to permit a frame of n bytes.
+func (l Limit) cost(n int) float64 {
+	if l.Unit == Bits {
+		return float64(n) * 8
+	}
+
+	return 1
+}
+
+// A bucket is a token bucket which enforces a Limit.
+type bucket struct {
+	mu     sync.Mutex
+	limit  Limit
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newBucket creates a full bucket which enforces l.
+func newBucket(l Limit, now time.Time) *bucket {
+	burst := l.Burst
+	if burst == 0 {
+		burst = l.Rate
+	}
+
+	return &bucket{
+		limit:  l,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   now,
+	}
+}
+
+// allow reports whether a frame of n bytes conforms to the bucket's Limit at
+// time now, consuming tokens if so.
+func (b *bucket) allow(n int, now time.Time) bool {
+	return b.delay(n, now) == 0
+}
+
+// delay consumes tokens and returns zero if a frame of n bytes conforms to
+// the bucket's Limit at time now.  Otherwise, delay returns the time until
+// the frame would conform.
+//
+// A frame whose cost exceeds a non-zero burst size conforms once the bucket
+// is full.
+func (b *bucket) delay(n int, now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if d := now.Sub(b.last); d > 0 {
+		b.tokens += d.Seconds() * float64(b.limit.Rate)
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+	}
+
+	cost := b.limit.cost(n)
+	if cost > b.burst && b.burst > 0 {
+		cost = b.burst
+	}
+
+	if b.tokens >= cost {
+		b.tokens -= cost
+		return 0
+	}
+
+	if b.limit.Rate == 0 {
+		// The bucket never refills.
+		return math.MaxInt64
+	}
+
+	d := time.Duration((cost - b.tokens) / float64(b.limit.Rate) * float64(time.Second))
+	if d <= 0 {
+		// Guard against rounding to zero, which indicates conformance.
+		d = 1
+	}
+
+	return d
+}
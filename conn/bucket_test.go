@@ -0,0 +1,105 @@
+package conn
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBucket(t *testing.T) {
+	tests := []struct {
+		desc  string
+		l     Limit
+		steps []bucketStep
+	}{
+		{
+			desc: "packets, burst equals rate",
+			l:    Limit{Rate: 2},
+			steps: []bucketStep{
+				{n: 64, ok: true},
+				{n: 64, ok: true},
+				{n: 64, ok: false},
+				{d: 500 * time.Millisecond, n: 64, ok: true},
+				{n: 64, ok: false},
+			},
+		},
+		{
+			desc: "packets, burst",
+			l:    Limit{Rate: 1, Burst: 3},
+			steps: []bucketStep{
+				{n: 64, ok: true},
+				{n: 64, ok: true},
+				{n: 64, ok: true},
+				{n: 64, ok: false},
+				// Tokens never exceed the burst size.
+				{d: 10 * time.Second, n: 64, ok: true},
+				{n: 64, ok: true},
+				{n: 64, ok: true},
+				{n: 64, ok: false},
+			},
+		},
+		{
+			desc: "bits",
+			l:    Limit{Rate: 1024, Unit: Bits},
+			steps: []bucketStep{
+				{n: 100, ok: true},
+				{n: 28, ok: true},
+				{n: 1, ok: false},
+				{d: 1 * time.Second, n: 128, ok: true},
+				{n: 1, ok: false},
+			},
+		},
+		{
+			desc: "bits, frame exceeds burst",
+			l:    Limit{Rate: 8, Unit: Bits},
+			steps: []bucketStep{
+				{n: 64, ok: true},
+				{n: 64, ok: false},
+				{d: 1 * time.Second, n: 64, ok: true},
+			},
+		},
+		{
+			desc: "zero rate",
+			l:    Limit{},
+			steps: []bucketStep{
+				{n: 64, ok: false},
+				{d: 1 * time.Hour, n: 64, ok: false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			now := time.Unix(0, 0)
+			b := newBucket(tt.l, now)
+
+			for i, s := range tt.steps {
+				now = now.Add(s.d)
+				if want, got := s.ok, b.allow(s.n, now); want != got {
+					t.Fatalf("unexpected result for step %d: %v != %v", i, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestBucketDelay(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := newBucket(Limit{Rate: 1000, Unit: Bits}, now)
+
+	if want, got := time.Duration(0), b.delay(125, now); want != got {
+		t.Fatalf("unexpected delay: %v != %v", want, got)
+	}
+
+	// The bucket is empty, so 496 bits must accumulate at 1000 bits per second.
+	if want, got := 496*time.Millisecond, b.delay(62, now); want != got {
+		t.Fatalf("unexpected delay: %v != %v", want, got)
+	}
+}
+
+// A bucketStep advances time by d and then attempts to pass a frame of n
+// bytes through a bucket.
+type bucketStep struct {
+	d  time.Duration
+	n  int
+	ok bool
+}
@@ -0,0 +1,239 @@
+package conn
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// A CreditClass specifies the parameters of an IEEE 802.1Qav credit-based
+// shaper for a single traffic class.
+type CreditClass struct {
+	// IdleSlope is the rate, in bits per second, at which credit accumulates
+	// while frames are waiting for transmission.  IdleSlope is the bandwidth
+	// reserved for the class, and must be less than the port rate.
+	IdleSlope uint64
+
+	// SendSlope is the rate, in bits per second, at which credit is consumed
+	// while a frame is being transmitted, and must be negative.  If zero,
+	// SendSlope is IdleSlope minus the port rate.
+	SendSlope int64
+}
+
+// CreditShaperConfig specifies configuration for a CreditShaper.
+type CreditShaperConfig struct {
+	// PortRate is the transmission rate of the underlying link, in bits per
+	// second.
+	PortRate uint64
+
+	// Classes specifies the credit-based shaper parameters for frames with
+	// customer VLAN tags of each priority.  Frames with other priorities,
+	// and untagged frames, are not shaped.
+	Classes map[ethernet.Priority]CreditClass
+}
+
+// A CreditShaper is a net.PacketConn which shapes egress frames using the
+// IEEE 802.1Qav credit-based shaper algorithm, as used by AVB and TSN
+// streams.
+//
+// WriteTo blocks until a shaped frame is eligible for transmission.  Frames
+// of each class are transmitted in order, and classes are shaped
+// independently.  Close interrupts any WriteTo which is waiting for credit.
+type CreditShaper struct {
+	net.PacketConn
+
+	portRate float64
+	classes  map[ethernet.Priority]*creditState
+
+	mu     sync.Mutex
+	closed bool
+	done   chan struct{}
+
+	// now and wait allow tests to control the passage of time.
+	now  func() time.Time
+	wait func(d time.Duration) bool
+}
+
+// creditState is the state of a credit-based shaper for a single class.
+type creditState struct {
+	mu        sync.Mutex
+	idleSlope float64
+	sendSlope float64
+	credit    float64
+	last      time.Time
+}
+
+// NewCreditShaper wraps c with a CreditShaper using the input configuration.
+func NewCreditShaper(c net.PacketConn, cfg CreditShaperConfig) (*CreditShaper, error) {
+	return newCreditShaper(c, cfg, time.Now)
+}
+
+// newCreditShaper creates a CreditShaper which uses now to observe the
+// passage of time.
+func newCreditShaper(c net.PacketConn, cfg CreditShaperConfig, now func() time.Time) (*CreditShaper, error) {
+	if cfg.PortRate == 0 {
+		return nil, errors.New("conn: credit-based shaper port rate must be set")
+	}
+
+	s := &CreditShaper{
+		PacketConn: c,
+		portRate:   float64(cfg.PortRate),
+		classes:    make(map[ethernet.Priority]*creditState, len(cfg.Classes)),
+		done:       make(chan struct{}),
+		now:        now,
+	}
+	s.wait = s.sleep
+
+	t := now()
+	for p, cc := range cfg.Classes {
+		if p > ethernet.PriorityNetworkControl {
+			return nil, ethernet.ErrInvalidVLAN
+		}
+		if cc.IdleSlope == 0 || cc.IdleSlope >= cfg.PortRate {
+			return nil, errors.New("conn: credit-based shaper idle slope must be non-zero and less than port rate")
+		}
+
+		send := float64(cc.SendSlope)
+		switch {
+		case cc.SendSlope == 0:
+			send = float64(cc.IdleSlope) - s.portRate
+		case cc.SendSlope > 0:
+			return nil, errors.New("conn: credit-based shaper send slope must be negative")
+		}
+
+		s.classes[p] = &creditState{
+			idleSlope: float64(cc.IdleSlope),
+			sendSlope: send,
+			last:      t,
+		}
+	}
+
+	return s, nil
+}
+
+// WriteTo implements net.PacketConn.
+func (s *CreditShaper) WriteTo(b []byte, addr net.Addr) (int, error) {
+	cs := s.class(b)
+	if cs == nil {
+		return s.PacketConn.WriteTo(b, addr)
+	}
+
+	// Reserve the frame's transmission in order with others of its class,
+	// and wait for it without holding the class's lock.
+	r := cs.reserve(s.now(), len(b), s.portRate)
+	if d := r.start.Sub(s.now()); d > 0 && !s.wait(d) {
+		cs.cancel(r)
+		return 0, net.ErrClosed
+	}
+
+	n, err := s.PacketConn.WriteTo(b, addr)
+	if err != nil {
+		cs.cancel(r)
+		return n, err
+	}
+
+	return n, nil
+}
+
+// Close implements net.PacketConn.  Close interrupts any WriteTo which is
+// waiting for credit and closes the underlying net.PacketConn.
+func (s *CreditShaper) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.done)
+	s.mu.Unlock()
+
+	return s.PacketConn.Close()
+}
+
+// sleep waits for duration d, and reports whether s is still open.
+func (s *CreditShaper) sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+// A reservation is the transmission of a frame reserved by
+// creditState.reserve.
+type reservation struct {
+	start, end time.Time
+	credit     float64
+}
+
+// reserve reserves the transmission of an n byte frame at the earliest time
+// after now at which the class has sufficient credit, and consumes credit as
+// if the frame were transmitted.
+func (cs *creditState) reserve(now time.Time, n int, portRate float64) reservation {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	// Wait for the previous frame of this class to finish transmission.
+	t := now
+	if cs.last.After(t) {
+		t = cs.last
+	}
+
+	// Negative credit recovers while idle, but positive credit is not
+	// retained while no frames are waiting.
+	cs.credit += t.Sub(cs.last).Seconds() * cs.idleSlope
+	if cs.credit > 0 {
+		cs.credit = 0
+	}
+
+	// Wait for credit to accumulate to zero so the frame may be sent.
+	if cs.credit < 0 {
+		t = t.Add(time.Duration(-cs.credit / cs.idleSlope * float64(time.Second)))
+		cs.credit = 0
+	}
+
+	// Consume credit for the duration of the frame's transmission on the
+	// link.
+	tx := float64(n) * 8 / portRate
+	r := reservation{
+		start:  t,
+		end:    t.Add(time.Duration(tx * float64(time.Second))),
+		credit: tx * cs.sendSlope,
+	}
+
+	cs.credit += r.credit
+	cs.last = r.end
+
+	return r
+}
+
+// cancel returns the credit consumed by reservation r, if it is the most
+// recent reservation for the class.
+func (cs *creditState) cancel(r reservation) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if !cs.last.Equal(r.end) {
+		return
+	}
+
+	cs.credit -= r.credit
+	cs.last = r.start
+}
+
+// class returns the credit state for frame b, or nil if b is not shaped.
+func (s *CreditShaper) class(b []byte) *creditState {
+	var f ethernet.Frame
+	if err := f.UnmarshalBinary(b); err != nil || f.VLAN == nil {
+		return nil
+	}
+
+	return s.classes[f.VLAN.Priority]
+}
@@ -0,0 +1,188 @@
+package conn
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestNewCreditShaperErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		cfg  CreditShaperConfig
+	}{
+		{
+			desc: "no port rate",
+		},
+		{
+			desc: "invalid priority",
+			cfg: CreditShaperConfig{
+				PortRate: 1000,
+				Classes: map[ethernet.Priority]CreditClass{
+					8: {IdleSlope: 100},
+				},
+			},
+		},
+		{
+			desc: "no idle slope",
+			cfg: CreditShaperConfig{
+				PortRate: 1000,
+				Classes: map[ethernet.Priority]CreditClass{
+					ethernet.PriorityVideo: {},
+				},
+			},
+		},
+		{
+			desc: "idle slope exceeds port rate",
+			cfg: CreditShaperConfig{
+				PortRate: 1000,
+				Classes: map[ethernet.Priority]CreditClass{
+					ethernet.PriorityVideo: {IdleSlope: 1000},
+				},
+			},
+		},
+		{
+			desc: "positive send slope",
+			cfg: CreditShaperConfig{
+				PortRate: 1000,
+				Classes: map[ethernet.Priority]CreditClass{
+					ethernet.PriorityVideo: {
+						IdleSlope: 100,
+						SendSlope: 100,
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if _, err := NewCreditShaper(&testConn{}, tt.cfg); err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+		})
+	}
+}
+
+func TestCreditShaper(t *testing.T) {
+	var (
+		start = time.Unix(0, 0)
+		now   = start
+	)
+
+	c := &testConn{}
+	s, err := newCreditShaper(c, CreditShaperConfig{
+		// 1000 bytes per second, with 25% reserved for the video class.
+		PortRate: 8000,
+		Classes: map[ethernet.Priority]CreditClass{
+			ethernet.PriorityVideo: {IdleSlope: 2000},
+		},
+	}, func() time.Time {
+		return now
+	})
+	if err != nil {
+		t.Fatalf("failed to create shaper: %v", err)
+	}
+
+	s.wait = func(d time.Duration) bool {
+		now = now.Add(d)
+		return true
+	}
+
+	var (
+		shaped   = priorityFrame(t, ethernet.PriorityVideo)
+		unshaped = priorityFrame(t, ethernet.PriorityBestEffort)
+	)
+
+	// Each 64 byte frame takes 64ms to transmit, leaving the class with
+	// -384 bits of credit, which takes 192ms to recover at the idle slope.
+	// Unshaped frames are never delayed.
+	steps := []struct {
+		b []byte
+		d time.Duration
+	}{
+		{b: shaped, d: 0},
+		{b: unshaped, d: 0},
+		{b: shaped, d: 256 * time.Millisecond},
+		{b: shaped, d: 512 * time.Millisecond},
+		{b: unshaped, d: 512 * time.Millisecond},
+	}
+
+	for i, st := range steps {
+		if _, err := s.WriteTo(st.b, nil); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+
+		if d := now.Sub(start) - st.d; d < -time.Microsecond || d > time.Microsecond {
+			t.Fatalf("unexpected time for step %d: %v != %v", i, st.d, now.Sub(start))
+		}
+	}
+
+	if want, got := len(steps), len(c.written()); want != got {
+		t.Fatalf("unexpected number of frames: %v != %v", want, got)
+	}
+
+	// After a long idle period, credit does not exceed zero and the next
+	// frame is sent immediately.
+	now = now.Add(time.Hour)
+	idle := now
+
+	if _, err := s.WriteTo(shaped, nil); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	if !now.Equal(idle) {
+		t.Fatalf("frame was delayed after idle period: %v", now.Sub(idle))
+	}
+}
+
+func TestCreditShaperCloseWhileWaiting(t *testing.T) {
+	c := &testConn{}
+	s, err := NewCreditShaper(c, CreditShaperConfig{
+		// The first frame leaves the class without credit for several
+		// minutes.
+		PortRate: 1 << 20,
+		Classes: map[ethernet.Priority]CreditClass{
+			ethernet.PriorityVideo: {IdleSlope: 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create shaper: %v", err)
+	}
+
+	shaped := priorityFrame(t, ethernet.PriorityVideo)
+	if _, err := s.WriteTo(shaped, nil); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	errC := make(chan error, 1)
+	go func() {
+		_, err := s.WriteTo(shaped, nil)
+		errC <- err
+	}()
+
+	// Frames of other classes are not delayed by the waiting writer.
+	time.Sleep(10 * time.Millisecond)
+	if _, err := s.WriteTo(priorityFrame(t, ethernet.PriorityBestEffort), nil); err != nil {
+		t.Fatalf("failed to write unshaped frame: %v", err)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+
+	select {
+	case err := <-errC:
+		if !errors.Is(err, net.ErrClosed) {
+			t.Fatalf("unexpected error: %v != %v", net.ErrClosed, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for WriteTo")
+	}
+
+	if want, got := 2, len(c.written()); want != got {
+		t.Fatalf("unexpected number of frames: %v != %v", want, got)
+	}
+}
@@ -0,0 +1,52 @@
+// Package conn implements net.PacketConn middleware which operates on
+// Ethernet frames, for building software forwarding planes and tooling on
+// top of raw sockets.
+package conn
+
+import (
+	"github.com/mdlayher/ethernet"
+)
+
+// A Direction indicates whether a frame was read from (ingress) or written to
+// (egress) a net.PacketConn.
+type Direction int
+
+// Possible Direction values.
+const (
+	Ingress Direction = iota
+	Egress
+)
+
+// String returns the string representation of a Direction.
+func (d Direction) String() string {
+	switch d {
+	case Ingress:
+		return "ingress"
+	case Egress:
+		return "egress"
+	default:
+		return "unknown"
+	}
+}
+
+// A Filter reports whether a Frame should be processed by a piece of
+// middleware.
+type Filter func(f *ethernet.Frame) bool
+
+// All is a Filter which matches every Frame.
+func All(_ *ethernet.Frame) bool { return true }
+
+// match unmarshals b into a Frame and reports whether it matches fn.  Frames
+// which cannot be unmarshaled never match.
+func match(fn Filter, b []byte) bool {
+	if fn == nil {
+		return false
+	}
+
+	var f ethernet.Frame
+	if err := f.UnmarshalBinary(b); err != nil {
+		return false
+	}
+
+	return fn(&f)
+}
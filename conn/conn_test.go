@@ -0,0 +1,286 @@
+package conn
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestDirectionString(t *testing.T) {
+	tests := []struct {
+		d Direction
+		s string
+	}{
+		{d: Ingress, s: "ingress"},
+		{d: Egress, s: "egress"},
+		{d: 10, s: "unknown"},
+	}
+
+	for _, tt := range tests {
+		if want, got := tt.s, tt.d.String(); want != got {
+			t.Fatalf("unexpected string: %v != %v", want, got)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		desc string
+		fn   Filter
+		b    []byte
+		ok   bool
+	}{
+		{
+			desc: "nil filter",
+			b:    frame(t, ethernet.EtherTypeIPv4),
+		},
+		{
+			desc: "short frame",
+			fn:   All,
+			b:    []byte{0},
+		},
+		{
+			desc: "no match",
+			fn: func(f *ethernet.Frame) bool {
+				return f.EtherType == ethernet.EtherTypeARP
+			},
+			b: frame(t, ethernet.EtherTypeIPv4),
+		},
+		{
+			desc: "match",
+			fn: func(f *ethernet.Frame) bool {
+				return f.EtherType == ethernet.EtherTypeIPv4
+			},
+			b:  frame(t, ethernet.EtherTypeIPv4),
+			ok: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if want, got := tt.ok, match(tt.fn, tt.b); want != got {
+				t.Fatalf("unexpected match: %v != %v", want, got)
+			}
+		})
+	}
+}
+
+var (
+	srcMAC = net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}
+	dstMAC = net.HardwareAddr{0xad, 0xbe, 0xef, 0xde, 0xad, 0xde}
+)
+
+// frame produces a marshaled Frame with the specified EtherType.
+func frame(t *testing.T, et ethernet.EtherType) []byte {
+	t.Helper()
+
+	f := &ethernet.Frame{
+		Destination: dstMAC,
+		Source:      srcMAC,
+		EtherType:   et,
+		Payload:     []byte{0xff},
+	}
+
+	b, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal frame: %v", err)
+	}
+
+	return b
+}
+
+var _ net.PacketConn = &testConn{}
+
+// A testConn is an in-memory net.PacketConn.  Frames queued in reads are
+// returned by ReadFrom, and frames passed to WriteTo are stored in writes.
+type testConn struct {
+	mu     sync.Mutex
+	reads  [][]byte
+	writes [][]byte
+	err    error
+}
+
+func (c *testConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return 0, nil, c.err
+	}
+	if len(c.reads) == 0 {
+		return 0, nil, io.EOF
+	}
+
+	n := copy(b, c.reads[0])
+	c.reads = c.reads[1:]
+	return n, &testAddr{}, nil
+}
+
+func (c *testConn) WriteTo(b []byte, _ net.Addr) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	c.writes = append(c.writes, append([]byte(nil), b...))
+	return len(b), nil
+}
+
+// written returns a copy of all frames written to the testConn.
+func (c *testConn) written() [][]byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([][]byte(nil), c.writes...)
+}
+
+var errClosed = errors.New("closed")
+
+func (c *testConn) Close() error                       { return nil }
+func (c *testConn) LocalAddr() net.Addr                { return &testAddr{} }
+func (c *testConn) SetDeadline(_ time.Time) error      { return nil }
+func (c *testConn) SetReadDeadline(_ time.Time) error  { return nil }
+func (c *testConn) SetWriteDeadline(_ time.Time) error { return nil }
+
+type testAddr struct{}
+
+func (*testAddr) Network() string { return "test" }
+func (*testAddr) String() string  { return "test" }
+
+// equalFrames reports whether a and b contain identical frames.
+func equalFrames(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+var _ net.PacketConn = &flakyConn{}
+
+// A flakyConn is a net.PacketConn whose first ReadFrom returns readErr, if
+// set.  Later calls to ReadFrom block until a frame is sent on frames, or
+// until the flakyConn is closed.
+type flakyConn struct {
+	testConn
+
+	once    sync.Once
+	readErr error
+	frames  chan []byte
+	done    chan struct{}
+}
+
+func newFlakyConn(err error) *flakyConn {
+	return &flakyConn{
+		readErr: err,
+		frames:  make(chan []byte),
+		done:    make(chan struct{}),
+	}
+}
+
+func (c *flakyConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	var err error
+	c.once.Do(func() { err = c.readErr })
+	if err != nil {
+		return 0, nil, err
+	}
+
+	select {
+	case f := <-c.frames:
+		return copy(b, f), &testAddr{}, nil
+	case <-c.done:
+		return 0, nil, net.ErrClosed
+	}
+}
+
+func (c *flakyConn) Close() error {
+	close(c.done)
+	return nil
+}
+
+var _ net.PacketConn = &blockingConn{}
+
+// A blockingConn is a net.PacketConn whose WriteTo blocks until the
+// blockingConn is closed.  writing is closed when WriteTo is first called.
+type blockingConn struct {
+	testConn
+
+	once    sync.Once
+	writing chan struct{}
+	done    chan struct{}
+}
+
+func newBlockingConn() *blockingConn {
+	return &blockingConn{
+		writing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+func (c *blockingConn) WriteTo(_ []byte, _ net.Addr) (int, error) {
+	c.once.Do(func() { close(c.writing) })
+	<-c.done
+	return 0, net.ErrClosed
+}
+
+func (c *blockingConn) Close() error {
+	close(c.done)
+	return nil
+}
+
+var _ net.PacketConn = &pipeConn{}
+
+// A pipeConn is a net.PacketConn whose frames are written to its peer, as
+// created by newPipe.
+type pipeConn struct {
+	testConn
+
+	once    sync.Once
+	in, out chan []byte
+	done    chan struct{}
+}
+
+// newPipe creates a pair of connected pipeConns.
+func newPipe() (*pipeConn, *pipeConn) {
+	ab, ba := make(chan []byte, 16), make(chan []byte, 16)
+
+	return &pipeConn{in: ba, out: ab, done: make(chan struct{})},
+		&pipeConn{in: ab, out: ba, done: make(chan struct{})}
+}
+
+func (c *pipeConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	select {
+	case f := <-c.in:
+		return copy(b, f), &testAddr{}, nil
+	case <-c.done:
+		return 0, nil, net.ErrClosed
+	}
+}
+
+func (c *pipeConn) WriteTo(b []byte, _ net.Addr) (int, error) {
+	select {
+	case c.out <- append([]byte(nil), b...):
+		return len(b), nil
+	case <-c.done:
+		return 0, net.ErrClosed
+	}
+}
+
+func (c *pipeConn) Close() error {
+	c.once.Do(func() { close(c.done) })
+	return nil
+}
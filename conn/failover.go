@@ -0,0 +1,224 @@
+package conn
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// defaultMonitorInterval is the default interval for link monitoring, which
+// matches the common miimon setting for Linux bonding.
+const defaultMonitorInterval = 100 * time.Millisecond
+
+// A FailoverMember is a member conn of a Failover.
+type FailoverMember struct {
+	// Conn is the member's net.PacketConn.
+	Conn net.PacketConn
+
+	// Up reports whether the member's link is usable.  Up may perform a
+	// carrier check (see Carrier) or exchange probe frames with a peer.
+	// If nil, the member's link is assumed to always be up.
+	Up func() bool
+}
+
+// FailoverConfig specifies configuration for a Failover.
+type FailoverConfig struct {
+	// Interval specifies how often the links of member conns are checked.
+	// If zero, a default of 100 milliseconds is used.
+	Interval time.Duration
+
+	// OnFailover, if not nil, is called with the indices of the previous and
+	// new active member conns whenever the active member conn changes.
+	OnFailover func(from, to int)
+
+	// OnReadError, if not nil, is called with the index of an inactive
+	// member conn and its error whenever a read from that member conn fails.
+	// Such errors are not returned by ReadFrom, and the member conn
+	// continues to be read.
+	OnReadError func(i int, err error)
+
+	// ReadBufferSize specifies the size of the buffer used to read frames
+	// from each member conn.  If zero, a default suitable for jumbo frames
+	// is used.
+	ReadBufferSize int
+}
+
+// A Failover is a net.PacketConn which implements active-backup bonding.
+// Frames are written to and read from only the active member conn.  The
+// active member conn is always the first member, in order of preference,
+// whose link is up.  Frames read from inactive member conns are discarded.
+//
+// If no member links are up, the previously active member conn remains
+// active.
+//
+// Transient read errors from the active member conn are returned by
+// ReadFrom, and do not stop reading from that member conn.
+type Failover struct {
+	members []FailoverMember
+	conns   []net.PacketConn
+	m       *merger
+	onFail  func(from, to int)
+
+	mu     sync.RWMutex
+	active int
+
+	stopC chan struct{}
+	wg    sync.WaitGroup
+}
+
+// NewFailover creates a Failover from one or more member conns, in order of
+// preference, using the input configuration.  If cfg is nil, a default
+// configuration is used.
+//
+// The Failover takes ownership of the member conns, and immediately begins
+// monitoring their links and reading frames from each of them.
+func NewFailover(members []FailoverMember, cfg *FailoverConfig) (*Failover, error) {
+	if len(members) == 0 {
+		return nil, errNoConns
+	}
+	if cfg == nil {
+		cfg = &FailoverConfig{}
+	}
+
+	interval := cfg.Interval
+	if interval == 0 {
+		interval = defaultMonitorInterval
+	}
+
+	conns := make([]net.PacketConn, 0, len(members))
+	for _, m := range members {
+		conns = append(conns, m.Conn)
+	}
+
+	f := &Failover{
+		members: members,
+		conns:   conns,
+		onFail:  cfg.OnFailover,
+		stopC:   make(chan struct{}),
+	}
+
+	// Select the initial active member before accepting any frames.
+	f.check()
+	f.m = newMerger(conns, cfg.ReadBufferSize, nil)
+	f.m.discard = cfg.OnReadError
+
+	f.wg.Add(1)
+	go f.monitor(interval)
+
+	return f, nil
+}
+
+// Active returns the index of the active member conn.
+func (f *Failover) Active() int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.active
+}
+
+// monitor checks member links at regular intervals until f is closed.
+func (f *Failover) monitor(interval time.Duration) {
+	defer f.wg.Done()
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			f.check()
+		case <-f.stopC:
+			return
+		}
+	}
+}
+
+// check checks member links and selects a new active member if needed.
+func (f *Failover) check() {
+	next := -1
+	for i, m := range f.members {
+		if m.Up == nil || m.Up() {
+			next = i
+			break
+		}
+	}
+
+	f.mu.Lock()
+	prev := f.active
+	if next == -1 || next == prev {
+		f.mu.Unlock()
+		return
+	}
+	f.active = next
+	f.mu.Unlock()
+
+	if f.onFail != nil {
+		f.onFail(prev, next)
+	}
+}
+
+// ReadFrom implements net.PacketConn.
+func (f *Failover) ReadFrom(b []byte) (int, net.Addr, error) {
+	return f.m.readFrom(b, func(i int) bool {
+		return i == f.Active()
+	})
+}
+
+// WriteTo implements net.PacketConn.
+func (f *Failover) WriteTo(b []byte, addr net.Addr) (int, error) {
+	return f.conns[f.Active()].WriteTo(b, addr)
+}
+
+// Close implements net.PacketConn.  Close stops link monitoring, closes all
+// member conns, and returns the first error encountered, if any.
+func (f *Failover) Close() error {
+	if !f.m.close() {
+		return nil
+	}
+
+	close(f.stopC)
+	err := closeAll(f.conns)
+	f.m.wait()
+	f.wg.Wait()
+	return err
+}
+
+// LocalAddr implements net.PacketConn.  LocalAddr returns the address of the
+// active member conn.
+func (f *Failover) LocalAddr() net.Addr { return f.conns[f.Active()].LocalAddr() }
+
+// SetDeadline implements net.PacketConn.
+func (f *Failover) SetDeadline(t time.Time) error {
+	if err := f.SetReadDeadline(t); err != nil {
+		return err
+	}
+
+	return f.SetWriteDeadline(t)
+}
+
+// SetReadDeadline implements net.PacketConn.
+func (f *Failover) SetReadDeadline(t time.Time) error {
+	f.m.setReadDeadline(t)
+	return nil
+}
+
+// SetWriteDeadline implements net.PacketConn.  The write deadline is applied
+// to all member conns.
+func (f *Failover) SetWriteDeadline(t time.Time) error {
+	return setWriteDeadlines(f.conns, t)
+}
+
+// Carrier returns a function suitable for use with FailoverMember.Up, which
+// reports whether the named network interface is administratively up and
+// has carrier.
+func Carrier(name string) func() bool {
+	return func() bool {
+		ifi, err := net.InterfaceByName(name)
+		if err != nil {
+			return false
+		}
+
+		const flags = net.FlagUp | net.FlagRunning
+		return ifi.Flags&flags == flags
+	}
+}
@@ -0,0 +1,213 @@
+package conn
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestNewFailoverNoConns(t *testing.T) {
+	if _, err := NewFailover(nil, nil); err != errNoConns {
+		t.Fatalf("unexpected error: %v != %v", errNoConns, err)
+	}
+}
+
+func TestFailover(t *testing.T) {
+	var (
+		primary, backup = &testConn{}, &testConn{}
+		primaryUp       = int32(1)
+		events          [][2]int
+	)
+
+	f, err := NewFailover([]FailoverMember{
+		{
+			Conn: primary,
+			Up:   func() bool { return atomic.LoadInt32(&primaryUp) == 1 },
+		},
+		{Conn: backup},
+	}, &FailoverConfig{
+		// Links are checked manually.
+		Interval: time.Hour,
+		OnFailover: func(from, to int) {
+			events = append(events, [2]int{from, to})
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create failover: %v", err)
+	}
+	defer f.Close()
+
+	write := func(want *testConn) {
+		t.Helper()
+
+		b := frame(t, ethernet.EtherTypeIPv4)
+		if _, err := f.WriteTo(b, nil); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+
+		if got := want.written(); !equalFrames([][]byte{b}, got[len(got)-1:]) {
+			t.Fatalf("frame was not written to expected conn")
+		}
+	}
+
+	if want, got := 0, f.Active(); want != got {
+		t.Fatalf("unexpected active conn: %v != %v", want, got)
+	}
+	write(primary)
+
+	atomic.StoreInt32(&primaryUp, 0)
+	f.check()
+
+	if want, got := 1, f.Active(); want != got {
+		t.Fatalf("unexpected active conn: %v != %v", want, got)
+	}
+	write(backup)
+
+	atomic.StoreInt32(&primaryUp, 1)
+	f.check()
+
+	if want, got := 0, f.Active(); want != got {
+		t.Fatalf("unexpected active conn: %v != %v", want, got)
+	}
+	write(primary)
+
+	if want, got := [][2]int{{0, 1}, {1, 0}}, events; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected failover events:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestFailoverNoLinksUp(t *testing.T) {
+	down := func() bool { return false }
+
+	f, err := NewFailover([]FailoverMember{
+		{Conn: &testConn{}, Up: down},
+		{Conn: &testConn{}, Up: down},
+	}, &FailoverConfig{Interval: time.Hour})
+	if err != nil {
+		t.Fatalf("failed to create failover: %v", err)
+	}
+	defer f.Close()
+
+	if want, got := 0, f.Active(); want != got {
+		t.Fatalf("unexpected active conn: %v != %v", want, got)
+	}
+}
+
+func TestFailoverReadFromInactive(t *testing.T) {
+	var (
+		active   = frame(t, ethernet.EtherTypeIPv4)
+		inactive = frame(t, ethernet.EtherTypeARP)
+	)
+
+	f, err := NewFailover([]FailoverMember{
+		{Conn: &testConn{reads: [][]byte{active}}},
+		{Conn: &testConn{reads: [][]byte{inactive}}},
+	}, &FailoverConfig{Interval: time.Hour})
+	if err != nil {
+		t.Fatalf("failed to create failover: %v", err)
+	}
+	defer f.Close()
+
+	b := make([]byte, 128)
+	n, _, err := f.ReadFrom(b)
+	if err != nil {
+		t.Fatalf("failed to read: %v", err)
+	}
+
+	if want, got := [][]byte{active}, [][]byte{b[:n]}; !equalFrames(want, got) {
+		t.Fatalf("unexpected frame:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	if _, _, err := f.ReadFrom(b); err != io.EOF {
+		t.Fatalf("unexpected error: %v != %v", io.EOF, err)
+	}
+}
+
+func TestFailoverInactiveReadError(t *testing.T) {
+	var (
+		primary   = newFlakyConn(nil)
+		backup    = newFlakyConn(errors.New("transient"))
+		primaryUp = int32(1)
+		errC      = make(chan int, 1)
+	)
+
+	f, err := NewFailover([]FailoverMember{
+		{
+			Conn: primary,
+			Up:   func() bool { return atomic.LoadInt32(&primaryUp) == 1 },
+		},
+		{Conn: backup},
+	}, &FailoverConfig{
+		// Links are checked manually.
+		Interval:    time.Hour,
+		OnReadError: func(i int, _ error) { errC <- i },
+	})
+	if err != nil {
+		t.Fatalf("failed to create failover: %v", err)
+	}
+	defer f.Close()
+
+	// The error from the inactive backup is reported, but not returned.
+	if err := f.SetReadDeadline(time.Now().Add(100 * time.Millisecond)); err != nil {
+		t.Fatalf("failed to set deadline: %v", err)
+	}
+
+	b := make([]byte, 128)
+	if _, _, err := f.ReadFrom(b); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("unexpected error: %v != %v", os.ErrDeadlineExceeded, err)
+	}
+
+	select {
+	case i := <-errC:
+		if want, got := 1, i; want != got {
+			t.Fatalf("unexpected member conn index: %v != %v", want, got)
+		}
+	default:
+		t.Fatal("read error from inactive member conn was not reported")
+	}
+
+	// After failover, the backup still carries traffic.
+	atomic.StoreInt32(&primaryUp, 0)
+	f.check()
+
+	if err := f.SetReadDeadline(time.Time{}); err != nil {
+		t.Fatalf("failed to clear deadline: %v", err)
+	}
+
+	want := frame(t, ethernet.EtherTypeIPv4)
+	go func() { backup.frames <- want }()
+
+	n, _, err := f.ReadFrom(b)
+	if err != nil {
+		t.Fatalf("failed to read: %v", err)
+	}
+
+	if !equalFrames([][]byte{want}, [][]byte{b[:n]}) {
+		t.Fatalf("unexpected frame:\n- want: %v\n-  got: %v", want, b[:n])
+	}
+}
+
+func TestCarrier(t *testing.T) {
+	if Carrier("ethernet-test-does-not-exist")() {
+		t.Fatal("nonexistent interface reported carrier")
+	}
+
+	ifis, err := net.Interfaces()
+	if err != nil {
+		t.Skipf("failed to list interfaces: %v", err)
+	}
+
+	for _, ifi := range ifis {
+		const flags = net.FlagUp | net.FlagRunning
+		if want, got := ifi.Flags&flags == flags, Carrier(ifi.Name)(); want != got {
+			t.Fatalf("unexpected carrier for %q: %v != %v", ifi.Name, want, got)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package conn
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// HashFields is a bitmask which specifies the Frame fields used to compute a
+// hash to distribute frames among several net.PacketConns or links.
+type HashFields int
+
+// Possible HashFields values.
+const (
+	HashSource HashFields = 1 << iota
+	HashDestination
+	HashVLAN
+	HashEtherType
+
+	// HashSymmetric causes the source and destination addresses to be hashed
+	// independently of their order, so that both directions of a
+	// conversation produce the same hash.  HashSymmetric has no effect unless
+	// both HashSource and HashDestination are set.
+	HashSymmetric
+)
+
+// FNV-1a constants, used to hash frames.
+const (
+	fnvOffset32 = 2166136261
+	fnvPrime32  = 16777619
+)
+
+// HashFrame computes a 32-bit FNV-1a hash of the fields of f specified by
+// fields, followed by up to payload leading bytes of f's payload.  The
+// result is stable across releases, so that load-balancing decisions made
+// by an Aggregate can be reproduced by taking the hash modulo the number of
+// member conns.
+func HashFrame(f *ethernet.Frame, fields HashFields, payload int) uint32 {
+	h := uint32(fnvOffset32)
+	add := func(b []byte) {
+		for _, c := range b {
+			h ^= uint32(c)
+			h *= fnvPrime32
+		}
+	}
+
+	src, dst := f.Source, f.Destination
+	if fields&(HashSymmetric|HashSource|HashDestination) == HashSymmetric|HashSource|HashDestination &&
+		bytes.Compare(src, dst) > 0 {
+		src, dst = dst, src
+	}
+
+	if fields&HashSource != 0 {
+		add(src)
+	}
+	if fields&HashDestination != 0 {
+		add(dst)
+	}
+	if fields&HashVLAN != 0 && f.VLAN != nil {
+		var b [2]byte
+		binary.BigEndian.PutUint16(b[:], f.VLAN.ID)
+		add(b[:])
+	}
+	if fields&HashEtherType != 0 {
+		var b [2]byte
+		binary.BigEndian.PutUint16(b[:], uint16(f.EtherType))
+		add(b[:])
+	}
+
+	if payload > len(f.Payload) {
+		payload = len(f.Payload)
+	}
+	if payload > 0 {
+		add(f.Payload[:payload])
+	}
+
+	return h
+}
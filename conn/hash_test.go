@@ -0,0 +1,109 @@
+package conn
+
+import (
+	"testing"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestHashFrame(t *testing.T) {
+	a := &ethernet.Frame{
+		Destination: dstMAC,
+		Source:      srcMAC,
+		VLAN:        &ethernet.VLAN{ID: 10},
+		EtherType:   ethernet.EtherTypeIPv4,
+		Payload:     []byte{0x01, 0x02},
+	}
+
+	b := *a
+	b.VLAN = &ethernet.VLAN{ID: 20}
+	b.EtherType = ethernet.EtherTypeIPv6
+	b.Payload = []byte{0x01, 0x03}
+
+	r := *a
+	r.Source, r.Destination = a.Destination, a.Source
+
+	const addrs = HashSource | HashDestination
+
+	tests := []struct {
+		desc    string
+		a, b    *ethernet.Frame
+		fields  HashFields
+		payload int
+		equal   bool
+	}{
+		{
+			desc:   "identical addresses",
+			a:      a,
+			b:      &b,
+			fields: addrs,
+			equal:  true,
+		},
+		{
+			desc:   "different VLANs",
+			a:      a,
+			b:      &b,
+			fields: HashVLAN,
+		},
+		{
+			desc:   "different EtherTypes",
+			a:      a,
+			b:      &b,
+			fields: HashEtherType,
+		},
+		{
+			desc:    "identical payload prefix",
+			a:       a,
+			b:       &b,
+			fields:  addrs,
+			payload: 1,
+			equal:   true,
+		},
+		{
+			desc:    "different payload",
+			a:       a,
+			b:       &b,
+			fields:  addrs,
+			payload: 2,
+		},
+		{
+			desc:    "payload longer than frame",
+			a:       a,
+			b:       a,
+			fields:  addrs,
+			payload: 1500,
+			equal:   true,
+		},
+		{
+			desc:   "asymmetric reversed addresses",
+			a:      a,
+			b:      &r,
+			fields: addrs,
+		},
+		{
+			desc:   "symmetric reversed addresses",
+			a:      a,
+			b:      &r,
+			fields: addrs | HashSymmetric,
+			equal:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ha := HashFrame(tt.a, tt.fields, tt.payload)
+			hb := HashFrame(tt.b, tt.fields, tt.payload)
+
+			if want, got := tt.equal, ha == hb; want != got {
+				t.Fatalf("unexpected hash equality: %v != %v (%#x, %#x)", want, got, ha, hb)
+			}
+		})
+	}
+}
+
+func TestHashFrameStable(t *testing.T) {
+	// The empty hash is the FNV-1a offset basis, and must never change.
+	if want, got := uint32(fnvOffset32), HashFrame(&ethernet.Frame{}, 0, 0); want != got {
+		t.Fatalf("unexpected hash: %#x != %#x", want, got)
+	}
+}
@@ -0,0 +1,336 @@
+package conn
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+	"github.com/mdlayher/ethernet/lacp"
+)
+
+// LACP timers and defaults, as specified by IEEE 802.1AX.
+const (
+	// lacpFastPeriodic and lacpSlowPeriodic are the intervals at which
+	// LACPDUs are transmitted when the partner requests a short or long
+	// timeout.
+	lacpFastPeriodic = time.Second
+	lacpSlowPeriodic = 30 * time.Second
+
+	// lacpTimeoutFactor is the number of periodic intervals after which
+	// partner information expires.
+	lacpTimeoutFactor = 3
+
+	// defaultLACPPriority is the default system and port priority.
+	defaultLACPPriority = 0x8000
+)
+
+// errNotDistributing is returned by Aggregate.WriteTo when LACP is enabled
+// and no member conns are aggregated with the peer.
+var errNotDistributing = errors.New("conn: no aggregated member conns are distributing frames")
+
+// LACPConfig specifies configuration for running the IEEE 802.1AX Link
+// Aggregation Control Protocol on the member conns of an Aggregate.
+type LACPConfig struct {
+	// System is the hardware address which identifies the local system,
+	// and is used as the source address of LACPDUs.
+	System net.HardwareAddr
+
+	// SystemPriority specifies the priority of the local system.  If zero,
+	// a default of 32768 is used.
+	SystemPriority uint16
+
+	// Key specifies the operational key of the member conns.  Only member
+	// conns whose partners share the same system and key are aggregated.
+	Key uint16
+
+	// Fast requests that the peer transmit LACPDUs every second, rather
+	// than every 30 seconds, so that failed links are detected sooner.
+	Fast bool
+}
+
+// An lacpActor runs LACP on the member conns of an Aggregate.
+type lacpActor struct {
+	conns    []net.PacketConn
+	system   net.HardwareAddr
+	priority uint16
+	key      uint16
+	fast     bool
+
+	// nttC signals that an LACPDU must be transmitted immediately.
+	nttC chan struct{}
+
+	mu    sync.Mutex
+	ports []lacpPort
+
+	// now allows tests to control the passage of time.
+	now func() time.Time
+}
+
+// An lacpPort is the LACP state of a single member conn.
+type lacpPort struct {
+	partner lacp.Info
+	expires time.Time
+	ntt     bool
+	nextTx  time.Time
+}
+
+// newLACPActor creates an lacpActor for conns using the input configuration.
+func newLACPActor(conns []net.PacketConn, cfg LACPConfig) (*lacpActor, error) {
+	if len(cfg.System) != 6 {
+		return nil, errors.New("conn: LACP system must be a 6 byte hardware address")
+	}
+
+	priority := cfg.SystemPriority
+	if priority == 0 {
+		priority = defaultLACPPriority
+	}
+
+	a := &lacpActor{
+		conns:    conns,
+		system:   cfg.System,
+		priority: priority,
+		key:      cfg.Key,
+		fast:     cfg.Fast,
+		nttC:     make(chan struct{}, 1),
+		ports:    make([]lacpPort, len(conns)),
+		now:      time.Now,
+	}
+
+	// Announce the local system on all member conns immediately.
+	for i := range a.ports {
+		a.ports[i].ntt = true
+	}
+
+	return a, nil
+}
+
+// run transmits LACPDUs on member conns as needed until done is closed.
+func (a *lacpActor) run(done <-chan struct{}) {
+	t := time.NewTicker(lacpFastPeriodic)
+	defer t.Stop()
+
+	for {
+		a.transmit()
+
+		select {
+		case <-t.C:
+		case <-a.nttC:
+		case <-done:
+			return
+		}
+	}
+}
+
+// transmit transmits an LACPDU on each member conn which needs one.
+func (a *lacpActor) transmit() {
+	now := a.now()
+
+	a.mu.Lock()
+	frames := make([][]byte, len(a.ports))
+	for i := range a.ports {
+		p := &a.ports[i]
+		if !p.ntt && now.Before(p.nextTx) {
+			continue
+		}
+
+		b, err := a.frame(i, now)
+		if err != nil {
+			continue
+		}
+
+		frames[i] = b
+		p.ntt = false
+		p.nextTx = now.Add(a.periodic(p, now))
+	}
+	a.mu.Unlock()
+
+	// Transmission errors are ignored, as the member conn's partner will
+	// eventually expire if its link is down.
+	for i, b := range frames {
+		if b != nil {
+			_, _ = a.conns[i].WriteTo(b, nil)
+		}
+	}
+}
+
+// frame marshals an LACPDU for the member conn at index i.  a.mu must be
+// held when calling frame.
+func (a *lacpActor) frame(i int, now time.Time) ([]byte, error) {
+	partner := lacp.Info{System: make(net.HardwareAddr, 6)}
+	if p := &a.ports[i]; a.current(p, now) {
+		partner = p.partner
+	}
+
+	pdu := &lacp.LACPDU{
+		Actor:   a.actor(i, now),
+		Partner: partner,
+	}
+
+	f, err := pdu.Frame(a.system)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.MarshalBinary()
+}
+
+// periodic returns the interval at which LACPDUs are transmitted on port p,
+// as requested by its partner.  a.mu must be held when calling periodic.
+func (a *lacpActor) periodic(p *lacpPort, now time.Time) time.Duration {
+	if a.current(p, now) && p.partner.State&lacp.StateTimeout != 0 {
+		return lacpFastPeriodic
+	}
+
+	return lacpSlowPeriodic
+}
+
+// actor returns the actor information for the member conn at index i.
+// a.mu must be held when calling actor.
+func (a *lacpActor) actor(i int, now time.Time) lacp.Info {
+	return lacp.Info{
+		SystemPriority: a.priority,
+		System:         a.system,
+		Key:            a.key,
+		PortPriority:   defaultLACPPriority,
+		Port:           uint16(i + 1),
+		State:          a.state(i, now),
+	}
+}
+
+// state returns the actor state of the member conn at index i.  a.mu must
+// be held when calling state.
+func (a *lacpActor) state(i int, now time.Time) lacp.State {
+	s := lacp.StateActivity | lacp.StateAggregation
+	if a.fast {
+		s |= lacp.StateTimeout
+	}
+
+	p := &a.ports[i]
+	if !a.current(p, now) {
+		return s | lacp.StateDefaulted
+	}
+	if !a.selected(i, now) {
+		return s
+	}
+
+	s |= lacp.StateSynchronization
+	if p.partner.State&lacp.StateSynchronization != 0 {
+		s |= lacp.StateCollecting | lacp.StateDistributing
+	}
+
+	return s
+}
+
+// current reports whether port p has partner information which has not
+// expired.  a.mu must be held when calling current.
+func (a *lacpActor) current(p *lacpPort, now time.Time) bool {
+	return now.Before(p.expires)
+}
+
+// selected reports whether the member conn at index i is aggregated: its
+// partner is willing to aggregate, and has the same system and key as the
+// partner of the first such member conn.  a.mu must be held when calling
+// selected.
+func (a *lacpActor) selected(i int, now time.Time) bool {
+	aggregatable := func(p *lacpPort) bool {
+		return a.current(p, now) && p.partner.State&lacp.StateAggregation != 0
+	}
+
+	if !aggregatable(&a.ports[i]) {
+		return false
+	}
+
+	for j := range a.ports {
+		if p := &a.ports[j]; aggregatable(p) {
+			q := &a.ports[i].partner
+			return bytes.Equal(p.partner.System, q.System) &&
+				p.partner.SystemPriority == q.SystemPriority &&
+				p.partner.Key == q.Key
+		}
+	}
+
+	return false
+}
+
+// receive processes frame b read from the member conn at index i, and
+// reports whether b is an LACPDU which was consumed.
+func (a *lacpActor) receive(i int, b []byte) bool {
+	var f ethernet.Frame
+	if err := f.UnmarshalBinary(b); err != nil || f.EtherType != lacp.EtherType {
+		return false
+	}
+
+	pdu, err := lacp.Parse(&f)
+	if err != nil {
+		// Other slow protocols, such as OAM, are returned to the caller, but
+		// malformed LACPDUs are discarded.
+		return !errors.Is(err, lacp.ErrInvalidSubtype)
+	}
+
+	now := a.now()
+	timeout := lacpSlowPeriodic
+	if pdu.Actor.State&lacp.StateTimeout != 0 {
+		timeout = lacpFastPeriodic
+	}
+
+	a.mu.Lock()
+	p := &a.ports[i]
+	p.partner = pdu.Actor
+	p.expires = now.Add(lacpTimeoutFactor * timeout)
+
+	// Notify the partner if its view of the local port is out of date.
+	ntt := !equalInfo(pdu.Partner, a.actor(i, now))
+	if ntt {
+		p.ntt = true
+	}
+	a.mu.Unlock()
+
+	if ntt {
+		select {
+		case a.nttC <- struct{}{}:
+		default:
+		}
+	}
+
+	return true
+}
+
+// collecting reports whether frames read from the member conn at index i
+// are accepted.
+func (a *lacpActor) collecting(i int) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.state(i, a.now())&lacp.StateCollecting != 0
+}
+
+// distributing returns the indices of the member conns on which frames may
+// be transmitted.
+func (a *lacpActor) distributing() []int {
+	now := a.now()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var idx []int
+	for i := range a.ports {
+		if a.state(i, now)&lacp.StateDistributing != 0 {
+			idx = append(idx, i)
+		}
+	}
+
+	return idx
+}
+
+// equalInfo reports whether x and y are equal.
+func equalInfo(x, y lacp.Info) bool {
+	return x.SystemPriority == y.SystemPriority &&
+		bytes.Equal(x.System, y.System) &&
+		x.Key == y.Key &&
+		x.PortPriority == y.PortPriority &&
+		x.Port == y.Port &&
+		x.State == y.State
+}
@@ -0,0 +1,184 @@
+package conn
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+	"github.com/mdlayher/ethernet/lacp"
+)
+
+func TestNewAggregateLACPInvalidSystem(t *testing.T) {
+	_, err := NewAggregate([]net.PacketConn{&testConn{}}, &AggregateConfig{
+		LACP: &LACPConfig{System: net.HardwareAddr{0xde, 0xad}},
+	})
+	if err == nil {
+		t.Fatal("expected an error, but none occurred")
+	}
+}
+
+func TestAggregateLACPNoPartner(t *testing.T) {
+	c := &testConn{}
+	a := newAggregate(t, []*testConn{c}, &AggregateConfig{
+		LACP: &LACPConfig{System: srcMAC},
+	})
+	defer a.Close()
+
+	if _, err := a.WriteTo(frame(t, ethernet.EtherTypeIPv4), nil); err != errNotDistributing {
+		t.Fatalf("unexpected error: %v != %v", errNotDistributing, err)
+	}
+
+	// The local system is announced even without a partner.
+	pdu := waitLACPDU(t, c)
+	if want, got := srcMAC, pdu.Actor.System; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected actor system: %v != %v", want, got)
+	}
+	if pdu.Actor.State&lacp.StateDefaulted == 0 {
+		t.Fatalf("expected defaulted actor state: %#x", pdu.Actor.State)
+	}
+}
+
+func TestAggregateLACP(t *testing.T) {
+	var (
+		a1, b1 = newPipe()
+		a2, b2 = newPipe()
+	)
+
+	newLACPAggregate := func(system net.HardwareAddr, conns ...net.PacketConn) *Aggregate {
+		a, err := NewAggregate(conns, &AggregateConfig{
+			LACP: &LACPConfig{System: system, Key: 1, Fast: true},
+		})
+		if err != nil {
+			t.Fatalf("failed to create aggregate: %v", err)
+		}
+
+		return a
+	}
+
+	a := newLACPAggregate(srcMAC, a1, a2)
+	defer a.Close()
+	b := newLACPAggregate(dstMAC, b1, b2)
+	defer b.Close()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(a.Distributing()) < 2 || len(b.Distributing()) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for aggregation: %v, %v", a.Distributing(), b.Distributing())
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	want := frame(t, ethernet.EtherTypeIPv4)
+	if _, err := a.WriteTo(want, nil); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	// Only the data frame is returned, and LACPDUs are consumed.
+	if err := b.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatalf("failed to set deadline: %v", err)
+	}
+
+	buf := make([]byte, 128)
+	n, _, err := b.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("failed to read: %v", err)
+	}
+
+	if got := buf[:n]; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected frame:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestLACPActorExpiry(t *testing.T) {
+	now := time.Unix(0, 0)
+
+	l, err := newLACPActor([]net.PacketConn{&testConn{}, &testConn{}}, LACPConfig{System: srcMAC})
+	if err != nil {
+		t.Fatalf("failed to create actor: %v", err)
+	}
+	l.now = func() time.Time { return now }
+
+	partner := func(port uint16, key uint16) []byte {
+		pdu := &lacp.LACPDU{
+			Actor: lacp.Info{
+				System: dstMAC,
+				Key:    key,
+				Port:   port,
+				State: lacp.StateActivity | lacp.StateTimeout | lacp.StateAggregation |
+					lacp.StateSynchronization,
+			},
+			Partner: lacp.Info{System: srcMAC},
+		}
+
+		f, err := pdu.Frame(dstMAC)
+		if err != nil {
+			t.Fatalf("failed to create LACPDU: %v", err)
+		}
+
+		b, err := f.MarshalBinary()
+		if err != nil {
+			t.Fatalf("failed to marshal LACPDU: %v", err)
+		}
+
+		return b
+	}
+
+	if !l.receive(0, partner(1, 1)) || !l.receive(1, partner(2, 2)) {
+		t.Fatal("LACPDUs were not consumed")
+	}
+	if l.receive(0, frame(t, ethernet.EtherTypeIPv4)) {
+		t.Fatal("data frame was consumed")
+	}
+
+	// Only the first member conn is aggregated, because the partner of the
+	// second uses a different key.
+	if want, got := []int{0}, l.distributing(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected distributing members: %v != %v", want, got)
+	}
+	if !l.collecting(0) || l.collecting(1) {
+		t.Fatal("unexpected collecting members")
+	}
+
+	// The partner requested a short timeout, so its information expires
+	// after three seconds.
+	now = now.Add(3 * lacpFastPeriodic)
+	if got := l.distributing(); len(got) != 0 {
+		t.Fatalf("expected no distributing members, but got: %v", got)
+	}
+}
+
+// waitLACPDU waits for an LACPDU to be written to c.
+func waitLACPDU(t *testing.T, c *testConn) *lacp.LACPDU {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		for _, b := range c.written() {
+			var f ethernet.Frame
+			if err := f.UnmarshalBinary(b); err != nil {
+				t.Fatalf("failed to unmarshal frame: %v", err)
+			}
+
+			pdu, err := lacp.Parse(&f)
+			if errors.Is(err, ethernet.ErrInvalidEtherType) {
+				continue
+			}
+			if err != nil {
+				t.Fatalf("failed to parse LACPDU: %v", err)
+			}
+
+			return pdu
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for LACPDU")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,213 @@
+package conn
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// defaultReadBufferSize is the default size of the buffers used to read
+// frames from member conns.
+const defaultReadBufferSize = 9216
+
+// Bounds of the delay before a member conn is read again after a transient
+// read error.
+const (
+	minReadBackoff = 5 * time.Millisecond
+	maxReadBackoff = time.Second
+)
+
+// A merger merges ingress frames from several member net.PacketConns.
+type merger struct {
+	readC chan readResult
+	done  chan struct{}
+	wg    sync.WaitGroup
+
+	// consume, if not nil, is called with each frame read from a member
+	// conn, and reports whether the frame was consumed and should not be
+	// returned by readFrom.
+	consume func(i int, b []byte) bool
+
+	// discard, if not nil, is called with errors from member conns whose
+	// results are not accepted by readFrom.
+	discard func(i int, err error)
+
+	mu       sync.Mutex
+	deadline time.Time
+	closed   bool
+}
+
+// A readResult is the result of a single ReadFrom call on a member conn.
+type readResult struct {
+	i    int
+	b    []byte
+	addr net.Addr
+	err  error
+}
+
+// newMerger creates a merger which immediately begins reading frames from
+// conns using buffers of the specified size.  If consume is not nil, it is
+// called with each frame as described by merger.consume.
+func newMerger(conns []net.PacketConn, size int, consume func(i int, b []byte) bool) *merger {
+	if size == 0 {
+		size = defaultReadBufferSize
+	}
+
+	m := &merger{
+		readC:   make(chan readResult),
+		done:    make(chan struct{}),
+		consume: consume,
+	}
+
+	m.wg.Add(len(conns))
+	for i, c := range conns {
+		go m.read(i, c, size)
+	}
+
+	return m
+}
+
+// read continuously reads frames from c and sends them to m.readC until m
+// is closed.  Read errors are also sent to m.readC.  After a transient read
+// error, c is read again following a backoff delay.  Reading stops when c
+// returns net.ErrClosed or io.EOF, which indicate that c will never return
+// another frame.
+func (m *merger) read(i int, c net.PacketConn, size int) {
+	defer m.wg.Done()
+
+	var (
+		b       = make([]byte, size)
+		backoff time.Duration
+	)
+
+	for {
+		n, addr, err := c.ReadFrom(b)
+		if err == nil && m.consume != nil && m.consume(i, b[:n]) {
+			backoff = 0
+			continue
+		}
+
+		res := readResult{i: i}
+		if err != nil {
+			res.err = err
+		} else {
+			res.b = append([]byte(nil), b[:n]...)
+			res.addr = addr
+		}
+
+		select {
+		case m.readC <- res:
+		case <-m.done:
+			return
+		}
+
+		if err == nil {
+			backoff = 0
+			continue
+		}
+		if errors.Is(err, net.ErrClosed) || err == io.EOF {
+			return
+		}
+
+		backoff *= 2
+		if backoff < minReadBackoff {
+			backoff = minReadBackoff
+		}
+		if backoff > maxReadBackoff {
+			backoff = maxReadBackoff
+		}
+
+		t := time.NewTimer(backoff)
+		select {
+		case <-t.C:
+		case <-m.done:
+			t.Stop()
+			return
+		}
+	}
+}
+
+// readFrom reads the next frame or error from a member conn whose index is
+// accepted by accept.  Results from other member conns are discarded, and
+// their errors are passed to m.discard.  If accept is nil, results from all
+// member conns are accepted.
+func (m *merger) readFrom(b []byte, accept func(i int) bool) (int, net.Addr, error) {
+	m.mu.Lock()
+	deadline := m.deadline
+	m.mu.Unlock()
+
+	var timeoutC <-chan time.Time
+	if !deadline.IsZero() {
+		d := time.Until(deadline)
+		if d <= 0 {
+			return 0, nil, os.ErrDeadlineExceeded
+		}
+
+		t := time.NewTimer(d)
+		defer t.Stop()
+		timeoutC = t.C
+	}
+
+	for {
+		select {
+		case res := <-m.readC:
+			if accept != nil && !accept(res.i) {
+				if res.err != nil && m.discard != nil {
+					m.discard(res.i, res.err)
+				}
+				continue
+			}
+			if res.err != nil {
+				return 0, nil, res.err
+			}
+
+			return copy(b, res.b), res.addr, nil
+		case <-timeoutC:
+			return 0, nil, os.ErrDeadlineExceeded
+		case <-m.done:
+			return 0, nil, net.ErrClosed
+		}
+	}
+}
+
+// setReadDeadline sets the deadline for future calls to readFrom.
+func (m *merger) setReadDeadline(t time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.deadline = t
+}
+
+// close stops reading from member conns.  close reports whether m was open
+// before the call, and must be followed by wait once the member conns are
+// closed.
+func (m *merger) close() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return false
+	}
+
+	m.closed = true
+	close(m.done)
+	return true
+}
+
+// wait waits for all member conn read loops to exit.
+func (m *merger) wait() { m.wg.Wait() }
+
+// closeAll closes all conns and returns the first error encountered, if any.
+func closeAll(conns []net.PacketConn) error {
+	var err error
+	for _, c := range conns {
+		if cerr := c.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+
+	return err
+}
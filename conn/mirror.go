@@ -0,0 +1,116 @@
+package conn
+
+import (
+	"net"
+	"sync/atomic"
+)
+
+// A Sink is a mirror destination which receives copies of frames.
+//
+// WriteFrame must not retain b after it returns.
+type Sink interface {
+	WriteFrame(d Direction, b []byte) error
+}
+
+// SinkFunc adapts an ordinary function into a Sink.
+type SinkFunc func(d Direction, b []byte) error
+
+// WriteFrame implements Sink.
+func (fn SinkFunc) WriteFrame(d Direction, b []byte) error { return fn(d, b) }
+
+// ConnSink returns a Sink which writes mirrored frames to addr using c,
+// effectively designating c as a mirror (SPAN) port.
+func ConnSink(c net.PacketConn, addr net.Addr) Sink {
+	return SinkFunc(func(_ Direction, b []byte) error {
+		_, err := c.WriteTo(b, addr)
+		return err
+	})
+}
+
+// MirrorConfig specifies configuration for a Mirror.
+type MirrorConfig struct {
+	// Ingress and Egress specify filters for frames read from and written to
+	// the underlying net.PacketConn, respectively.  If a filter is nil, no
+	// frames are mirrored in that direction.  Use All to mirror every frame.
+	Ingress, Egress Filter
+
+	// Sink is the mirror destination which receives copies of frames.
+	Sink Sink
+}
+
+// A Mirror is a net.PacketConn which sends copies of frames that pass through
+// it to a mirror destination, for troubleshooting a forwarding plane.
+type Mirror struct {
+	// Atomics must come first.
+	mirrored, errors uint64
+
+	net.PacketConn
+	cfg MirrorConfig
+}
+
+// MirrorStats contains statistics about a Mirror.
+type MirrorStats struct {
+	// Mirrored is the number of frames successfully sent to the Sink.
+	Mirrored uint64
+
+	// Errors is the number of frames which could not be sent to the Sink.
+	Errors uint64
+}
+
+// NewMirror wraps c with a Mirror using the input configuration.  If cfg is
+// nil or cfg.Sink is nil, no frames are mirrored.
+func NewMirror(c net.PacketConn, cfg *MirrorConfig) *Mirror {
+	if cfg == nil {
+		cfg = &MirrorConfig{}
+	}
+
+	return &Mirror{
+		PacketConn: c,
+		cfg:        *cfg,
+	}
+}
+
+// ReadFrom implements net.PacketConn.
+func (m *Mirror) ReadFrom(b []byte) (int, net.Addr, error) {
+	n, addr, err := m.PacketConn.ReadFrom(b)
+	if err != nil {
+		return n, addr, err
+	}
+
+	m.mirror(Ingress, m.cfg.Ingress, b[:n])
+	return n, addr, nil
+}
+
+// WriteTo implements net.PacketConn.
+func (m *Mirror) WriteTo(b []byte, addr net.Addr) (int, error) {
+	n, err := m.PacketConn.WriteTo(b, addr)
+	if err != nil {
+		return n, err
+	}
+
+	m.mirror(Egress, m.cfg.Egress, b)
+	return n, nil
+}
+
+// Stats returns the current statistics for a Mirror.
+func (m *Mirror) Stats() MirrorStats {
+	return MirrorStats{
+		Mirrored: atomic.LoadUint64(&m.mirrored),
+		Errors:   atomic.LoadUint64(&m.errors),
+	}
+}
+
+// mirror sends b to the Sink if it matches fn.  Errors from the Sink are
+// counted but never interrupt the flow of frames through the Mirror.
+func (m *Mirror) mirror(d Direction, fn Filter, b []byte) {
+	if m.cfg.Sink == nil || !match(fn, b) {
+		return
+	}
+
+	if err := m.cfg.Sink.WriteFrame(d, b); err != nil {
+		atomic.AddUint64(&m.errors, 1)
+		return
+	}
+
+	atomic.AddUint64(&m.mirrored, 1)
+}
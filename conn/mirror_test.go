@@ -0,0 +1,124 @@
+package conn
+
+import (
+	"testing"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestMirror(t *testing.T) {
+	var (
+		ipv4 = frame(t, ethernet.EtherTypeIPv4)
+		arp  = frame(t, ethernet.EtherTypeARP)
+	)
+
+	isARP := func(f *ethernet.Frame) bool {
+		return f.EtherType == ethernet.EtherTypeARP
+	}
+
+	tests := []struct {
+		desc            string
+		cfg             *MirrorConfig
+		ingress, egress [][]byte
+		stats           MirrorStats
+	}{
+		{
+			desc: "nil config",
+		},
+		{
+			desc: "ingress only",
+			cfg:  &MirrorConfig{Ingress: All},
+			ingress: [][]byte{
+				ipv4, arp,
+			},
+			stats: MirrorStats{Mirrored: 2},
+		},
+		{
+			desc: "egress only",
+			cfg:  &MirrorConfig{Egress: All},
+			egress: [][]byte{
+				ipv4, arp,
+			},
+			stats: MirrorStats{Mirrored: 2},
+		},
+		{
+			desc: "both, filtered",
+			cfg: &MirrorConfig{
+				Ingress: isARP,
+				Egress:  isARP,
+			},
+			ingress: [][]byte{arp},
+			egress:  [][]byte{arp},
+			stats:   MirrorStats{Mirrored: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var ingress, egress [][]byte
+			if tt.cfg != nil {
+				tt.cfg.Sink = SinkFunc(func(d Direction, b []byte) error {
+					b = append([]byte(nil), b...)
+					switch d {
+					case Ingress:
+						ingress = append(ingress, b)
+					case Egress:
+						egress = append(egress, b)
+					}
+					return nil
+				})
+			}
+
+			m := NewMirror(&testConn{reads: [][]byte{ipv4, arp}}, tt.cfg)
+
+			b := make([]byte, 128)
+			for i := 0; i < 2; i++ {
+				n, _, err := m.ReadFrom(b)
+				if err != nil {
+					t.Fatalf("failed to read: %v", err)
+				}
+
+				if _, err := m.WriteTo(b[:n], nil); err != nil {
+					t.Fatalf("failed to write: %v", err)
+				}
+			}
+
+			if want, got := tt.ingress, ingress; !equalFrames(want, got) {
+				t.Fatalf("unexpected ingress frames:\n- want: %v\n-  got: %v", want, got)
+			}
+			if want, got := tt.egress, egress; !equalFrames(want, got) {
+				t.Fatalf("unexpected egress frames:\n- want: %v\n-  got: %v", want, got)
+			}
+			if want, got := tt.stats, m.Stats(); want != got {
+				t.Fatalf("unexpected stats: %+v != %+v", want, got)
+			}
+		})
+	}
+}
+
+func TestMirrorConnSink(t *testing.T) {
+	span := &testConn{}
+	m := NewMirror(&testConn{}, &MirrorConfig{
+		Egress: All,
+		Sink:   ConnSink(span, nil),
+	})
+
+	b := frame(t, ethernet.EtherTypeIPv4)
+	if _, err := m.WriteTo(b, nil); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	if want, got := [][]byte{b}, span.written(); !equalFrames(want, got) {
+		t.Fatalf("unexpected mirrored frames:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	// Errors from the mirror port must not interrupt the mirrored conn.
+	span.err = errClosed
+	if _, err := m.WriteTo(b, nil); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	if want, got := (MirrorStats{Mirrored: 1, Errors: 1}), m.Stats(); want != got {
+		t.Fatalf("unexpected stats: %+v != %+v", want, got)
+	}
+}
@@ -0,0 +1,260 @@
+package conn
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// maxPolicedSources is the maximum number of source addresses tracked by a
+// per-source policer.  Frames from additional sources share a single bucket,
+// so that hostile traffic cannot cause unbounded memory growth.
+const maxPolicedSources = 1024
+
+// A PolicerAction is an action taken on a frame by a Policer.  The zero
+// value transmits a frame without modification.
+type PolicerAction struct {
+	// Drop specifies that the frame should be dropped.
+	Drop bool
+
+	// Priority, if not nil, specifies a new priority for the frame.
+	Priority *ethernet.Priority
+
+	// DropEligible specifies that the frame should be marked drop eligible.
+	DropEligible bool
+}
+
+// A PolicerClass specifies a token bucket policer for a single priority.
+type PolicerClass struct {
+	// Limit is the rate limit enforced by the policer.
+	Limit Limit
+
+	// PerSource specifies that Limit applies to each source hardware
+	// address independently, rather than to all frames of this priority.
+	PerSource bool
+
+	// Conform and Exceed specify the actions taken on frames which conform
+	// to and exceed Limit, respectively.  To drop frames which exceed Limit,
+	// set Exceed.Drop.
+	Conform, Exceed PolicerAction
+}
+
+// PolicerConfig specifies configuration for a Policer.
+type PolicerConfig struct {
+	// Classes specifies policers for ingress frames with customer VLAN tags
+	// of each priority.  Untagged frames are policed as
+	// ethernet.PriorityBestEffort.  Frames with other priorities are not
+	// policed.
+	Classes map[ethernet.Priority]PolicerClass
+}
+
+// PolicerStats contains statistics for a single priority of a Policer.
+type PolicerStats struct {
+	// Conformed and Exceeded are the number of frames which conformed to and
+	// exceeded the policer's Limit, respectively.
+	Conformed, Exceeded uint64
+
+	// Dropped is the number of frames dropped by a PolicerAction.
+	Dropped uint64
+}
+
+// A Policer is a net.PacketConn which polices ingress frames using token
+// bucket policers keyed by priority, and optionally by source hardware
+// address.
+//
+// If a PolicerAction modifies a frame by adding a VLAN tag and the frame no
+// longer fits in the buffer passed to ReadFrom, io.ErrShortBuffer is
+// returned.
+type Policer struct {
+	net.PacketConn
+	classes map[ethernet.Priority]*policerState
+
+	// now allows tests to control the passage of time.
+	now func() time.Time
+}
+
+// policerState is the state of a policer for a single priority.
+type policerState struct {
+	class PolicerClass
+
+	mu       sync.Mutex
+	bucket   *bucket
+	sources  map[[6]byte]*bucket
+	overflow *bucket
+	stats    PolicerStats
+}
+
+// NewPolicer wraps c with a Policer using the input configuration.
+func NewPolicer(c net.PacketConn, cfg PolicerConfig) (*Policer, error) {
+	return newPolicer(c, cfg, time.Now)
+}
+
+// newPolicer creates a Policer which uses now to determine the current time.
+func newPolicer(c net.PacketConn, cfg PolicerConfig, now func() time.Time) (*Policer, error) {
+	p := &Policer{
+		PacketConn: c,
+		classes:    make(map[ethernet.Priority]*policerState, len(cfg.Classes)),
+		now:        now,
+	}
+
+	t := now()
+	for pri, pc := range cfg.Classes {
+		if pri > ethernet.PriorityNetworkControl {
+			return nil, ethernet.ErrInvalidVLAN
+		}
+		if err := checkPolicerAction(pc.Conform); err != nil {
+			return nil, err
+		}
+		if err := checkPolicerAction(pc.Exceed); err != nil {
+			return nil, err
+		}
+
+		ps := &policerState{class: pc}
+		if pc.PerSource {
+			ps.sources = make(map[[6]byte]*bucket)
+			ps.overflow = newBucket(pc.Limit, t)
+		} else {
+			ps.bucket = newBucket(pc.Limit, t)
+		}
+
+		p.classes[pri] = ps
+	}
+
+	return p, nil
+}
+
+// ReadFrom implements net.PacketConn.  Dropped frames are not returned, and
+// ReadFrom continues reading until a frame is permitted or an error occurs.
+func (p *Policer) ReadFrom(b []byte) (int, net.Addr, error) {
+	for {
+		n, addr, err := p.PacketConn.ReadFrom(b)
+		if err != nil {
+			return n, addr, err
+		}
+
+		out, ok, err := p.police(b[:n])
+		if err != nil {
+			return 0, addr, err
+		}
+		if !ok {
+			continue
+		}
+
+		if len(out) > len(b) {
+			return 0, addr, io.ErrShortBuffer
+		}
+
+		return copy(b, out), addr, nil
+	}
+}
+
+// Stats returns statistics for each policed priority.
+func (p *Policer) Stats() map[ethernet.Priority]PolicerStats {
+	stats := make(map[ethernet.Priority]PolicerStats, len(p.classes))
+	for pri, ps := range p.classes {
+		ps.mu.Lock()
+		stats[pri] = ps.stats
+		ps.mu.Unlock()
+	}
+
+	return stats
+}
+
+// police polices frame b, returning the resulting frame and whether or not
+// it is permitted.
+func (p *Policer) police(b []byte) ([]byte, bool, error) {
+	var f ethernet.Frame
+	if err := f.UnmarshalBinary(b); err != nil {
+		return b, true, nil
+	}
+
+	pri := ethernet.PriorityBestEffort
+	if f.VLAN != nil {
+		pri = f.VLAN.Priority
+	}
+
+	ps, ok := p.classes[pri]
+	if !ok {
+		return b, true, nil
+	}
+
+	action := ps.police(&f, len(b), p.now())
+	if action.Drop {
+		return nil, false, nil
+	}
+	if action.Priority == nil && !action.DropEligible {
+		return b, true, nil
+	}
+
+	if f.VLAN == nil {
+		f.VLAN = &ethernet.VLAN{}
+	}
+	if action.Priority != nil {
+		f.VLAN.Priority = *action.Priority
+	}
+	if action.DropEligible {
+		f.VLAN.DropEligible = true
+	}
+
+	out, err := f.MarshalBinary()
+	if err != nil {
+		return nil, false, err
+	}
+
+	return out, true, nil
+}
+
+// police determines the PolicerAction for frame f of n bytes at time now.
+func (ps *policerState) police(f *ethernet.Frame, n int, now time.Time) PolicerAction {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	action := ps.class.Conform
+	if ps.source(f.Source, now).allow(n, now) {
+		ps.stats.Conformed++
+	} else {
+		ps.stats.Exceeded++
+		action = ps.class.Exceed
+	}
+
+	if action.Drop {
+		ps.stats.Dropped++
+	}
+
+	return action
+}
+
+// source returns the bucket for a source address.  ps.mu must be held when
+// calling source.
+func (ps *policerState) source(addr net.HardwareAddr, now time.Time) *bucket {
+	if !ps.class.PerSource {
+		return ps.bucket
+	}
+
+	var key [6]byte
+	copy(key[:], addr)
+
+	if bk, ok := ps.sources[key]; ok {
+		return bk
+	}
+	if len(ps.sources) >= maxPolicedSources {
+		return ps.overflow
+	}
+
+	bk := newBucket(ps.class.Limit, now)
+	ps.sources[key] = bk
+	return bk
+}
+
+// checkPolicerAction verifies that a PolicerAction's parameters are valid.
+func checkPolicerAction(a PolicerAction) error {
+	if a.Priority != nil && *a.Priority > ethernet.PriorityNetworkControl {
+		return errors.New("conn: invalid policer action priority")
+	}
+
+	return nil
+}
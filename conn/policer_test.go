@@ -0,0 +1,235 @@
+package conn
+
+import (
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestNewPolicerErrors(t *testing.T) {
+	bad := ethernet.Priority(8)
+
+	tests := []struct {
+		desc string
+		cfg  PolicerConfig
+	}{
+		{
+			desc: "invalid priority",
+			cfg: PolicerConfig{
+				Classes: map[ethernet.Priority]PolicerClass{
+					bad: {},
+				},
+			},
+		},
+		{
+			desc: "invalid conform action",
+			cfg: PolicerConfig{
+				Classes: map[ethernet.Priority]PolicerClass{
+					ethernet.PriorityVideo: {
+						Conform: PolicerAction{Priority: &bad},
+					},
+				},
+			},
+		},
+		{
+			desc: "invalid exceed action",
+			cfg: PolicerConfig{
+				Classes: map[ethernet.Priority]PolicerClass{
+					ethernet.PriorityVideo: {
+						Exceed: PolicerAction{Priority: &bad},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if _, err := NewPolicer(&testConn{}, tt.cfg); err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+		})
+	}
+}
+
+func TestPolicer(t *testing.T) {
+	var (
+		background = ethernet.PriorityBackground
+		srcA       = net.HardwareAddr{0, 0, 0, 0, 0, 0xa}
+		srcB       = net.HardwareAddr{0, 0, 0, 0, 0, 0xb}
+	)
+
+	tests := []struct {
+		desc  string
+		class PolicerClass
+		in    []*ethernet.Frame
+		out   []*ethernet.Frame
+		stats PolicerStats
+	}{
+		{
+			desc: "drop exceeding",
+			class: PolicerClass{
+				Limit:  Limit{Rate: 1},
+				Exceed: PolicerAction{Drop: true},
+			},
+			in: []*ethernet.Frame{
+				policerFrame(srcA, nil),
+				policerFrame(srcA, nil),
+			},
+			out: []*ethernet.Frame{
+				policerFrame(srcA, nil),
+			},
+			stats: PolicerStats{
+				Conformed: 1,
+				Exceeded:  1,
+				Dropped:   1,
+			},
+		},
+		{
+			desc: "remark exceeding",
+			class: PolicerClass{
+				Limit: Limit{Rate: 1},
+				Exceed: PolicerAction{
+					Priority:     &background,
+					DropEligible: true,
+				},
+			},
+			in: []*ethernet.Frame{
+				policerFrame(srcA, &ethernet.VLAN{ID: 10}),
+				policerFrame(srcA, &ethernet.VLAN{ID: 10}),
+			},
+			out: []*ethernet.Frame{
+				policerFrame(srcA, &ethernet.VLAN{ID: 10}),
+				policerFrame(srcA, &ethernet.VLAN{
+					Priority:     background,
+					DropEligible: true,
+					ID:           10,
+				}),
+			},
+			stats: PolicerStats{
+				Conformed: 1,
+				Exceeded:  1,
+			},
+		},
+		{
+			desc: "per source",
+			class: PolicerClass{
+				Limit:     Limit{Rate: 1},
+				PerSource: true,
+				Exceed:    PolicerAction{Drop: true},
+			},
+			in: []*ethernet.Frame{
+				policerFrame(srcA, nil),
+				policerFrame(srcB, nil),
+				policerFrame(srcA, nil),
+			},
+			out: []*ethernet.Frame{
+				policerFrame(srcA, nil),
+				policerFrame(srcB, nil),
+			},
+			stats: PolicerStats{
+				Conformed: 2,
+				Exceeded:  1,
+				Dropped:   1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			c := &testConn{}
+			for _, f := range tt.in {
+				c.reads = append(c.reads, marshal(t, f))
+			}
+
+			// Freeze time so that buckets never refill.
+			now := time.Unix(0, 0)
+			p, err := newPolicer(c, PolicerConfig{
+				Classes: map[ethernet.Priority]PolicerClass{
+					ethernet.PriorityBestEffort: tt.class,
+				},
+			}, func() time.Time { return now })
+			if err != nil {
+				t.Fatalf("failed to create policer: %v", err)
+			}
+
+			var out [][]byte
+			b := make([]byte, 128)
+			for {
+				n, _, err := p.ReadFrom(b)
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("failed to read: %v", err)
+				}
+
+				out = append(out, append([]byte(nil), b[:n]...))
+			}
+
+			var want [][]byte
+			for _, f := range tt.out {
+				want = append(want, marshal(t, f))
+			}
+
+			if got := out; !equalFrames(want, got) {
+				t.Fatalf("unexpected frames:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			stats := map[ethernet.Priority]PolicerStats{
+				ethernet.PriorityBestEffort: tt.stats,
+			}
+			if want, got := stats, p.Stats(); !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected stats:\n- want: %+v\n-  got: %+v", want, got)
+			}
+		})
+	}
+}
+
+func TestPolicerUnpolicedPriority(t *testing.T) {
+	b := priorityFrame(t, ethernet.PriorityVoice)
+
+	now := time.Unix(0, 0)
+	p, err := newPolicer(&testConn{reads: [][]byte{b, b}}, PolicerConfig{
+		Classes: map[ethernet.Priority]PolicerClass{
+			ethernet.PriorityBestEffort: {Exceed: PolicerAction{Drop: true}},
+		},
+	}, func() time.Time { return now })
+	if err != nil {
+		t.Fatalf("failed to create policer: %v", err)
+	}
+
+	buf := make([]byte, 128)
+	for i := 0; i < 2; i++ {
+		if _, _, err := p.ReadFrom(buf); err != nil {
+			t.Fatalf("failed to read: %v", err)
+		}
+	}
+}
+
+// policerFrame creates a Frame with the specified source and VLAN.
+func policerFrame(src net.HardwareAddr, vlan *ethernet.VLAN) *ethernet.Frame {
+	return &ethernet.Frame{
+		Destination: dstMAC,
+		Source:      src,
+		VLAN:        vlan,
+		EtherType:   ethernet.EtherTypeIPv4,
+		Payload:     make([]byte, 46),
+	}
+}
+
+// marshal marshals f into binary form.
+func marshal(t *testing.T, f *ethernet.Frame) []byte {
+	t.Helper()
+
+	b, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal frame: %v", err)
+	}
+
+	return b
+}
@@ -0,0 +1,334 @@
+package conn
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// defaultQueueLength is the default maximum number of frames in each
+// Scheduler queue.
+const defaultQueueLength = 64
+
+// SchedulerConfig specifies configuration for a Scheduler.
+type SchedulerConfig struct {
+	// Queues specifies the number of traffic class queues, from 1 to 8.  If
+	// zero, 8 queues are used.
+	Queues int
+
+	// QueueLength specifies the maximum number of frames in each queue.
+	// Frames which arrive when a queue is full are dropped.  If zero, a
+	// default of 64 is used.
+	QueueLength int
+
+	// Weights specifies the weighted round-robin weight for each queue,
+	// indexed by traffic class.  Queues with a weight of zero are serviced
+	// with strict priority before any weighted queues, highest traffic class
+	// first.  If nil, all queues use strict priority.
+	Weights []int
+
+	// Rate, if not nil, limits the transmission rate of the Scheduler, to
+	// emulate a slower link and cause congestion.  Rate.Rate must not be
+	// zero.
+	Rate *Limit
+}
+
+// A Scheduler is a net.PacketConn which schedules egress frames using a set
+// of traffic class queues.  The priority of each frame's customer VLAN tag
+// is mapped to a traffic class using ethernet.Priority.TrafficClass, and
+// untagged frames are treated as ethernet.PriorityBestEffort.
+//
+// WriteTo enqueues a frame for transmission and returns immediately.
+// Errors which occur during transmission are counted in QueueStats.
+type Scheduler struct {
+	net.PacketConn
+
+	rate  *bucket
+	limit int
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queues []queue
+	rr     int
+	credit int
+	closed bool
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// QueueStats contains statistics about a single Scheduler queue.
+type QueueStats struct {
+	// Length is the number of frames currently in the queue.
+	Length int
+
+	// Sent is the number of frames transmitted from the queue.
+	Sent uint64
+
+	// Dropped is the number of frames dropped because the queue was full.
+	Dropped uint64
+
+	// Errors is the number of frames which could not be transmitted.
+	Errors uint64
+}
+
+// A queue is a single traffic class queue.
+type queue struct {
+	frames []queued
+	weight int
+	stats  QueueStats
+}
+
+// A queued is a frame waiting for transmission.
+type queued struct {
+	b    []byte
+	addr net.Addr
+	tc   int
+}
+
+// NewScheduler wraps c with a Scheduler using the input configuration.  If
+// cfg is nil, a default configuration is used.
+func NewScheduler(c net.PacketConn, cfg *SchedulerConfig) (*Scheduler, error) {
+	s, err := newScheduler(c, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	s.wg.Add(1)
+	go s.transmit()
+
+	return s, nil
+}
+
+// newScheduler creates a Scheduler without beginning transmission.
+func newScheduler(c net.PacketConn, cfg *SchedulerConfig) (*Scheduler, error) {
+	if cfg == nil {
+		cfg = &SchedulerConfig{}
+	}
+
+	n := cfg.Queues
+	if n == 0 {
+		n = 8
+	}
+	if n < 1 || n > 8 {
+		return nil, errors.New("conn: scheduler must have 1 to 8 queues")
+	}
+
+	if cfg.Weights != nil && len(cfg.Weights) != n {
+		return nil, errors.New("conn: scheduler must have one weight per queue")
+	}
+
+	limit := cfg.QueueLength
+	if limit == 0 {
+		limit = defaultQueueLength
+	}
+
+	if cfg.Rate != nil && cfg.Rate.Rate == 0 {
+		return nil, errors.New("conn: scheduler rate must not be zero")
+	}
+
+	s := &Scheduler{
+		PacketConn: c,
+		limit:      limit,
+		queues:     make([]queue, n),
+		done:       make(chan struct{}),
+	}
+	s.cond = sync.NewCond(&s.mu)
+
+	for i, w := range cfg.Weights {
+		if w < 0 {
+			return nil, errors.New("conn: scheduler weights must not be negative")
+		}
+
+		s.queues[i].weight = w
+	}
+	s.credit = s.queues[0].weight
+
+	if cfg.Rate != nil {
+		s.rate = newBucket(*cfg.Rate, time.Now())
+	}
+
+	return s, nil
+}
+
+// WriteTo implements net.PacketConn.  WriteTo enqueues a copy of b for
+// transmission.  If the frame's queue is full, the frame is dropped without
+// returning an error.
+func (s *Scheduler) WriteTo(b []byte, addr net.Addr) (int, error) {
+	tc := trafficClass(b, len(s.queues))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, net.ErrClosed
+	}
+
+	if s.queues[tc].push(b, addr, tc, s.limit) {
+		s.cond.Signal()
+	}
+
+	return len(b), nil
+}
+
+// Close implements net.PacketConn.  Close discards any queued frames and
+// closes the underlying net.PacketConn.  A frame which is waiting for the
+// transmission rate limit is discarded, and the underlying net.PacketConn is
+// closed before waiting for transmission to stop, to interrupt any write in
+// progress.
+func (s *Scheduler) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.done)
+	s.cond.Broadcast()
+	s.mu.Unlock()
+
+	err := s.PacketConn.Close()
+	s.wg.Wait()
+	return err
+}
+
+// Stats returns statistics for each queue, indexed by traffic class.
+func (s *Scheduler) Stats() []QueueStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return queueStats(s.queues)
+}
+
+// transmit dequeues and transmits frames until s is closed.
+func (s *Scheduler) transmit() {
+	defer s.wg.Done()
+
+	for {
+		s.mu.Lock()
+		qf, ok := s.next()
+		for !ok && !s.closed {
+			s.cond.Wait()
+			qf, ok = s.next()
+		}
+		closed := s.closed
+		s.mu.Unlock()
+
+		if closed {
+			return
+		}
+
+		if s.rate != nil && !s.wait(len(qf.b)) {
+			return
+		}
+
+		_, err := s.PacketConn.WriteTo(qf.b, qf.addr)
+
+		s.mu.Lock()
+		if err != nil {
+			s.queues[qf.tc].stats.Errors++
+		} else {
+			s.queues[qf.tc].stats.Sent++
+		}
+		s.mu.Unlock()
+	}
+}
+
+// wait waits until a frame of n bytes conforms to the transmission rate
+// limit.  wait reports false if s is closed while waiting.
+func (s *Scheduler) wait(n int) bool {
+	for {
+		d := s.rate.delay(n, time.Now())
+		if d == 0 {
+			return true
+		}
+
+		t := time.NewTimer(d)
+		select {
+		case <-t.C:
+		case <-s.done:
+			t.Stop()
+			return false
+		}
+	}
+}
+
+// next dequeues the next frame for transmission.  Strict priority queues are
+// serviced first, followed by weighted round-robin queues.  s.mu must be
+// held when calling next.
+func (s *Scheduler) next() (queued, bool) {
+	for i := len(s.queues) - 1; i >= 0; i-- {
+		if q := &s.queues[i]; q.weight == 0 && len(q.frames) > 0 {
+			return q.pop(), true
+		}
+	}
+
+	// Each weighted queue may transmit up to its weight in frames before the
+	// next weighted queue is serviced.
+	for i := 0; i <= len(s.queues); i++ {
+		q := &s.queues[s.rr]
+		if q.weight > 0 && s.credit > 0 && len(q.frames) > 0 {
+			s.credit--
+			return q.pop(), true
+		}
+
+		s.rr = (s.rr + 1) % len(s.queues)
+		s.credit = s.queues[s.rr].weight
+	}
+
+	return queued{}, false
+}
+
+// trafficClass determines the traffic class for frame b on a port with n
+// traffic classes.  Untagged frames and frames which cannot be unmarshaled
+// are treated as ethernet.PriorityBestEffort.
+func trafficClass(b []byte, n int) int {
+	p := ethernet.PriorityBestEffort
+
+	var f ethernet.Frame
+	if err := f.UnmarshalBinary(b); err == nil && f.VLAN != nil {
+		p = f.VLAN.Priority
+	}
+
+	return p.TrafficClass(n)
+}
+
+// queueStats returns statistics for each queue in queues.
+func queueStats(queues []queue) []QueueStats {
+	stats := make([]QueueStats, 0, len(queues))
+	for _, q := range queues {
+		qs := q.stats
+		qs.Length = len(q.frames)
+		stats = append(stats, qs)
+	}
+
+	return stats
+}
+
+// push appends a copy of frame b to a queue, or drops it if the queue
+// already contains limit frames.  push reports whether b was enqueued.
+func (q *queue) push(b []byte, addr net.Addr, tc, limit int) bool {
+	if len(q.frames) >= limit {
+		q.stats.Dropped++
+		return false
+	}
+
+	q.frames = append(q.frames, queued{
+		b:    append([]byte(nil), b...),
+		addr: addr,
+		tc:   tc,
+	})
+
+	return true
+}
+
+// pop removes the frame at the head of a queue.
+func (q *queue) pop() queued {
+	qf := q.frames[0]
+	q.frames[0] = queued{}
+	q.frames = q.frames[1:]
+	return qf
+}
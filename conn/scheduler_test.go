@@ -0,0 +1,253 @@
+package conn
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestNewSchedulerErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		cfg  *SchedulerConfig
+	}{
+		{
+			desc: "too many queues",
+			cfg:  &SchedulerConfig{Queues: 9},
+		},
+		{
+			desc: "too few weights",
+			cfg: &SchedulerConfig{
+				Queues:  2,
+				Weights: []int{1},
+			},
+		},
+		{
+			desc: "negative weight",
+			cfg: &SchedulerConfig{
+				Queues:  2,
+				Weights: []int{1, -1},
+			},
+		},
+		{
+			desc: "zero rate",
+			cfg:  &SchedulerConfig{Rate: &Limit{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if _, err := NewScheduler(&testConn{}, tt.cfg); err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+		})
+	}
+}
+
+func TestSchedulerNext(t *testing.T) {
+	tests := []struct {
+		desc       string
+		cfg        *SchedulerConfig
+		priorities []ethernet.Priority
+		tcs        []int
+	}{
+		{
+			desc: "strict priority",
+			priorities: []ethernet.Priority{
+				ethernet.PriorityBestEffort,
+				ethernet.PriorityBackground,
+				ethernet.PriorityNetworkControl,
+				ethernet.PriorityVoice,
+			},
+			tcs: []int{7, 5, 1, 0},
+		},
+		{
+			desc: "weighted round-robin",
+			cfg: &SchedulerConfig{
+				Queues:  2,
+				Weights: []int{1, 2},
+			},
+			priorities: []ethernet.Priority{
+				ethernet.PriorityBestEffort,
+				ethernet.PriorityBestEffort,
+				ethernet.PriorityBestEffort,
+				ethernet.PriorityVoice,
+				ethernet.PriorityVoice,
+				ethernet.PriorityVoice,
+			},
+			tcs: []int{0, 1, 1, 0, 1, 0},
+		},
+		{
+			desc: "strict priority and weighted round-robin",
+			cfg: &SchedulerConfig{
+				Queues:  3,
+				Weights: []int{1, 1, 0},
+			},
+			priorities: []ethernet.Priority{
+				ethernet.PriorityBestEffort,
+				ethernet.PriorityVideo,
+				ethernet.PriorityBestEffort,
+				ethernet.PriorityVideo,
+				ethernet.PriorityNetworkControl,
+			},
+			tcs: []int{2, 0, 1, 0, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			s, err := newScheduler(&testConn{}, tt.cfg)
+			if err != nil {
+				t.Fatalf("failed to create scheduler: %v", err)
+			}
+
+			for _, p := range tt.priorities {
+				if _, err := s.WriteTo(priorityFrame(t, p), nil); err != nil {
+					t.Fatalf("failed to write: %v", err)
+				}
+			}
+
+			var tcs []int
+			for {
+				qf, ok := s.next()
+				if !ok {
+					break
+				}
+
+				tcs = append(tcs, qf.tc)
+			}
+
+			if want, got := tt.tcs, tcs; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected traffic classes:\n- want: %v\n-  got: %v", want, got)
+			}
+		})
+	}
+}
+
+func TestSchedulerQueueFull(t *testing.T) {
+	s, err := newScheduler(&testConn{}, &SchedulerConfig{
+		Queues:      1,
+		QueueLength: 1,
+	})
+	if err != nil {
+		t.Fatalf("failed to create scheduler: %v", err)
+	}
+
+	b := priorityFrame(t, ethernet.PriorityBestEffort)
+	for i := 0; i < 3; i++ {
+		if _, err := s.WriteTo(b, nil); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+	}
+
+	stats := []QueueStats{{
+		Length:  1,
+		Dropped: 2,
+	}}
+	if want, got := stats, s.Stats(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected stats:\n- want: %+v\n-  got: %+v", want, got)
+	}
+}
+
+func TestSchedulerTransmit(t *testing.T) {
+	c := &testConn{}
+	s, err := NewScheduler(c, &SchedulerConfig{
+		Queues: 1,
+		Rate: &Limit{
+			Rate: 1000,
+			Unit: Packets,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create scheduler: %v", err)
+	}
+
+	b := priorityFrame(t, ethernet.PriorityBestEffort)
+	for i := 0; i < 4; i++ {
+		if _, err := s.WriteTo(b, nil); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(c.written()) < 4 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for frames to be transmitted")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+
+	if _, err := s.WriteTo(b, nil); err != net.ErrClosed {
+		t.Fatalf("unexpected error: %v != %v", net.ErrClosed, err)
+	}
+
+	stats := []QueueStats{{Sent: 4}}
+	if want, got := stats, s.Stats(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected stats:\n- want: %+v\n-  got: %+v", want, got)
+	}
+}
+
+func TestSchedulerCloseWhileShaping(t *testing.T) {
+	s, err := NewScheduler(&testConn{}, &SchedulerConfig{
+		Queues: 1,
+		Rate: &Limit{
+			Rate:  1,
+			Burst: 1 << 20,
+			Unit:  Bits,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create scheduler: %v", err)
+	}
+
+	// Empty the bucket, so the frame must wait several minutes for tokens.
+	s.rate.tokens = 0
+
+	if _, err := s.WriteTo(priorityFrame(t, ethernet.PriorityBestEffort), nil); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	// Give the transmitter time to begin waiting on the rate limit.
+	time.Sleep(10 * time.Millisecond)
+
+	errC := make(chan error, 1)
+	go func() { errC <- s.Close() }()
+
+	select {
+	case err := <-errC:
+		if err != nil {
+			t.Fatalf("failed to close: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for Close")
+	}
+}
+
+// priorityFrame produces a marshaled Frame with a VLAN tag of the specified
+// priority.
+func priorityFrame(t *testing.T, p ethernet.Priority) []byte {
+	t.Helper()
+
+	f := &ethernet.Frame{
+		Destination: dstMAC,
+		Source:      srcMAC,
+		VLAN:        &ethernet.VLAN{Priority: p},
+		EtherType:   ethernet.EtherTypeIPv4,
+		Payload:     []byte{0xff},
+	}
+
+	b, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal frame: %v", err)
+	}
+
+	return b
+}
@@ -0,0 +1,137 @@
+package conn
+
+import (
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// StormControlConfig specifies configuration for a StormControl.
+type StormControlConfig struct {
+	// Broadcast, Multicast, and UnknownUnicast specify rate limits for
+	// ingress frames with broadcast, multicast, and unknown unicast
+	// destination addresses, respectively.  If a Limit is nil, frames of
+	// that class are not limited.
+	Broadcast, Multicast, UnknownUnicast *Limit
+
+	// Known reports whether a unicast destination address is known, such as
+	// when it is present in a forwarding database.  If nil, UnknownUnicast
+	// has no effect.
+	Known func(addr net.HardwareAddr) bool
+}
+
+// A StormControl is a net.PacketConn which rate limits ingress broadcast,
+// multicast, and unknown unicast frames.  Frames which exceed a limit are
+// dropped and counted.
+type StormControl struct {
+	// Atomics must come first.
+	broadcast, multicast, unknown uint64
+
+	net.PacketConn
+	known func(addr net.HardwareAddr) bool
+
+	bcast, mcast, ucast *bucket
+
+	// now allows tests to control the passage of time.
+	now func() time.Time
+}
+
+// StormControlStats contains the number of frames dropped by a StormControl.
+type StormControlStats struct {
+	Broadcast, Multicast, UnknownUnicast uint64
+}
+
+// NewStormControl wraps c with a StormControl using the input configuration.
+// If cfg is nil, no frames are limited.
+func NewStormControl(c net.PacketConn, cfg *StormControlConfig) *StormControl {
+	if cfg == nil {
+		cfg = &StormControlConfig{}
+	}
+
+	return newStormControl(c, cfg, time.Now)
+}
+
+// newStormControl creates a StormControl which uses now to determine the
+// current time.
+func newStormControl(c net.PacketConn, cfg *StormControlConfig, now func() time.Time) *StormControl {
+	t := now()
+	newBucketOrNil := func(l *Limit) *bucket {
+		if l == nil {
+			return nil
+		}
+
+		return newBucket(*l, t)
+	}
+
+	s := &StormControl{
+		PacketConn: c,
+		bcast:      newBucketOrNil(cfg.Broadcast),
+		mcast:      newBucketOrNil(cfg.Multicast),
+		now:        now,
+	}
+
+	if cfg.Known != nil {
+		s.known = cfg.Known
+		s.ucast = newBucketOrNil(cfg.UnknownUnicast)
+	}
+
+	return s
+}
+
+// ReadFrom implements net.PacketConn.  Frames which exceed a configured
+// limit are dropped, and ReadFrom continues reading until a frame is
+// permitted or an error occurs.
+func (s *StormControl) ReadFrom(b []byte) (int, net.Addr, error) {
+	for {
+		n, addr, err := s.PacketConn.ReadFrom(b)
+		if err != nil {
+			return n, addr, err
+		}
+
+		if s.allow(b[:n]) {
+			return n, addr, nil
+		}
+	}
+}
+
+// Stats returns the number of frames dropped by a StormControl.
+func (s *StormControl) Stats() StormControlStats {
+	return StormControlStats{
+		Broadcast:      atomic.LoadUint64(&s.broadcast),
+		Multicast:      atomic.LoadUint64(&s.multicast),
+		UnknownUnicast: atomic.LoadUint64(&s.unknown),
+	}
+}
+
+// allow reports whether frame b is permitted, counting it if not.
+func (s *StormControl) allow(b []byte) bool {
+	// Frames too short to contain a destination address are left for the
+	// caller to deal with.
+	if len(b) < 6 {
+		return true
+	}
+
+	var (
+		bk    *bucket
+		count *uint64
+	)
+
+	dst := net.HardwareAddr(b[0:6])
+	switch {
+	case ethernet.IsBroadcast(dst):
+		bk, count = s.bcast, &s.broadcast
+	case ethernet.IsMulticast(dst):
+		bk, count = s.mcast, &s.multicast
+	case s.known != nil && !s.known(dst):
+		bk, count = s.ucast, &s.unknown
+	}
+
+	if bk == nil || bk.allow(len(b), s.now()) {
+		return true
+	}
+
+	atomic.AddUint64(count, 1)
+	return false
+}
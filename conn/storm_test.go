@@ -0,0 +1,129 @@
+package conn
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestStormControl(t *testing.T) {
+	var (
+		known   = net.HardwareAddr{0x00, 0x00, 0x00, 0x00, 0x00, 0x01}
+		unknown = net.HardwareAddr{0x00, 0x00, 0x00, 0x00, 0x00, 0x02}
+		mcast   = net.HardwareAddr{0x01, 0x00, 0x5e, 0x00, 0x00, 0x01}
+	)
+
+	tests := []struct {
+		desc  string
+		cfg   *StormControlConfig
+		dsts  []net.HardwareAddr
+		n     int
+		stats StormControlStats
+	}{
+		{
+			desc: "nil config",
+			dsts: []net.HardwareAddr{ethernet.Broadcast, ethernet.Broadcast},
+			n:    2,
+		},
+		{
+			desc: "broadcast",
+			cfg: &StormControlConfig{
+				Broadcast: &Limit{Rate: 1},
+			},
+			dsts:  []net.HardwareAddr{ethernet.Broadcast, mcast, ethernet.Broadcast, unknown},
+			n:     3,
+			stats: StormControlStats{Broadcast: 1},
+		},
+		{
+			desc: "multicast",
+			cfg: &StormControlConfig{
+				Multicast: &Limit{Rate: 1},
+			},
+			dsts:  []net.HardwareAddr{mcast, ethernet.Broadcast, mcast, mcast},
+			n:     2,
+			stats: StormControlStats{Multicast: 2},
+		},
+		{
+			desc: "unknown unicast without Known",
+			cfg: &StormControlConfig{
+				UnknownUnicast: &Limit{Rate: 1},
+			},
+			dsts: []net.HardwareAddr{unknown, unknown},
+			n:    2,
+		},
+		{
+			desc: "unknown unicast",
+			cfg: &StormControlConfig{
+				UnknownUnicast: &Limit{Rate: 1},
+				Known: func(addr net.HardwareAddr) bool {
+					return bytes.Equal(addr, known)
+				},
+			},
+			dsts:  []net.HardwareAddr{unknown, known, unknown, known},
+			n:     3,
+			stats: StormControlStats{UnknownUnicast: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			c := &testConn{}
+			for _, dst := range tt.dsts {
+				c.reads = append(c.reads, frameTo(t, dst))
+			}
+
+			cfg := tt.cfg
+			if cfg == nil {
+				cfg = &StormControlConfig{}
+			}
+
+			// Freeze time so that buckets never refill.
+			now := time.Unix(0, 0)
+			s := newStormControl(c, cfg, func() time.Time { return now })
+
+			var n int
+			b := make([]byte, 128)
+			for {
+				_, _, err := s.ReadFrom(b)
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("failed to read: %v", err)
+				}
+
+				n++
+			}
+
+			if want, got := tt.n, n; want != got {
+				t.Fatalf("unexpected number of frames: %v != %v", want, got)
+			}
+			if want, got := tt.stats, s.Stats(); want != got {
+				t.Fatalf("unexpected stats: %+v != %+v", want, got)
+			}
+		})
+	}
+}
+
+// frameTo produces a marshaled Frame with the specified destination.
+func frameTo(t *testing.T, dst net.HardwareAddr) []byte {
+	t.Helper()
+
+	f := &ethernet.Frame{
+		Destination: dst,
+		Source:      srcMAC,
+		EtherType:   ethernet.EtherTypeIPv4,
+		Payload:     []byte{0xff},
+	}
+
+	b, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal frame: %v", err)
+	}
+
+	return b
+}
@@ -0,0 +1,264 @@
+package conn
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// A GateEntry is a single entry in an IEEE 802.1Qbv gate control list.
+type GateEntry struct {
+	// Gates is a bitmask of open transmission gates, where bit i controls the
+	// gate for traffic class i.
+	Gates uint8
+
+	// Interval is the length of time the entry is in effect.
+	Interval time.Duration
+}
+
+// TimeAwareShaperConfig specifies configuration for a TimeAwareShaper.
+type TimeAwareShaperConfig struct {
+	// BaseTime is the start time of the first cycle of the gate control list.
+	// All gates are closed before BaseTime.  If zero, the first cycle begins
+	// when the TimeAwareShaper is created.
+	BaseTime time.Time
+
+	// GateControlList specifies the gate states for each cycle.  The cycle
+	// time is the sum of the intervals of all entries.
+	GateControlList []GateEntry
+
+	// Queues specifies the number of traffic class queues, from 1 to 8.  If
+	// zero, 8 queues are used.
+	Queues int
+
+	// QueueLength specifies the maximum number of frames in each queue.
+	// Frames which arrive when a queue is full are dropped.  If zero, a
+	// default of 64 is used.
+	QueueLength int
+}
+
+// A TimeAwareShaper is a net.PacketConn which transmits egress frames
+// according to an IEEE 802.1Qbv gate control list.  Frames are mapped to
+// traffic class queues in the same way as a Scheduler, and each queue only
+// transmits while its gate is open.  When several gates are open, the
+// highest traffic class is serviced first.
+//
+// WriteTo enqueues a frame for transmission and returns immediately.
+// Errors which occur during transmission are counted in QueueStats.
+type TimeAwareShaper struct {
+	net.PacketConn
+
+	base  time.Time
+	gcl   []GateEntry
+	cycle time.Duration
+	limit int
+
+	mu     sync.Mutex
+	queues []queue
+	closed bool
+
+	wakeC chan struct{}
+	doneC chan struct{}
+	wg    sync.WaitGroup
+}
+
+// NewTimeAwareShaper wraps c with a TimeAwareShaper using the input
+// configuration.
+func NewTimeAwareShaper(c net.PacketConn, cfg TimeAwareShaperConfig) (*TimeAwareShaper, error) {
+	s, err := newTimeAwareShaper(c, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	s.wg.Add(1)
+	go s.transmit()
+
+	return s, nil
+}
+
+// newTimeAwareShaper creates a TimeAwareShaper without beginning
+// transmission.
+func newTimeAwareShaper(c net.PacketConn, cfg TimeAwareShaperConfig) (*TimeAwareShaper, error) {
+	n := cfg.Queues
+	if n == 0 {
+		n = 8
+	}
+	if n < 1 || n > 8 {
+		return nil, errors.New("conn: time-aware shaper must have 1 to 8 queues")
+	}
+
+	var cycle time.Duration
+	for _, e := range cfg.GateControlList {
+		if e.Interval <= 0 {
+			return nil, errors.New("conn: time-aware shaper gate intervals must be positive")
+		}
+
+		cycle += e.Interval
+	}
+	if cycle == 0 {
+		return nil, errors.New("conn: time-aware shaper gate control list must not be empty")
+	}
+
+	base := cfg.BaseTime
+	if base.IsZero() {
+		base = time.Now()
+	}
+
+	limit := cfg.QueueLength
+	if limit == 0 {
+		limit = defaultQueueLength
+	}
+
+	return &TimeAwareShaper{
+		PacketConn: c,
+		base:       base,
+		gcl:        cfg.GateControlList,
+		cycle:      cycle,
+		limit:      limit,
+		queues:     make([]queue, n),
+		wakeC:      make(chan struct{}, 1),
+		doneC:      make(chan struct{}),
+	}, nil
+}
+
+// WriteTo implements net.PacketConn.  WriteTo enqueues a copy of b for
+// transmission.  If the frame's queue is full, the frame is dropped without
+// returning an error.
+func (s *TimeAwareShaper) WriteTo(b []byte, addr net.Addr) (int, error) {
+	tc := trafficClass(b, len(s.queues))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, net.ErrClosed
+	}
+
+	if s.queues[tc].push(b, addr, tc, s.limit) {
+		// Wake the transmitter in case the frame's gate is already open.
+		select {
+		case s.wakeC <- struct{}{}:
+		default:
+		}
+	}
+
+	return len(b), nil
+}
+
+// Close implements net.PacketConn.  Close discards any queued frames and
+// closes the underlying net.PacketConn.
+func (s *TimeAwareShaper) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.doneC)
+	s.mu.Unlock()
+
+	// Close the underlying net.PacketConn first to interrupt any blocked
+	// WriteTo in the transmitter.
+	err := s.PacketConn.Close()
+	s.wg.Wait()
+	return err
+}
+
+// Stats returns statistics for each queue, indexed by traffic class.
+func (s *TimeAwareShaper) Stats() []QueueStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return queueStats(s.queues)
+}
+
+// gates returns the gate states in effect at time now, and the time at which
+// the gate states next change.
+func (s *TimeAwareShaper) gates(now time.Time) (uint8, time.Time) {
+	if now.Before(s.base) {
+		return 0, s.base
+	}
+
+	elapsed := now.Sub(s.base) % s.cycle
+
+	var end time.Duration
+	for _, e := range s.gcl {
+		end += e.Interval
+		if elapsed < end {
+			return e.Gates, now.Add(end - elapsed)
+		}
+	}
+
+	panic("conn: unreachable gate control list state")
+}
+
+// transmit transmits frames from queues with open gates until s is closed.
+func (s *TimeAwareShaper) transmit() {
+	defer s.wg.Done()
+
+	for {
+		gates, next := s.gates(time.Now())
+
+		for time.Now().Before(next) {
+			s.mu.Lock()
+			qf, ok := s.next(gates)
+			s.mu.Unlock()
+			if !ok {
+				break
+			}
+
+			_, err := s.PacketConn.WriteTo(qf.b, qf.addr)
+
+			s.mu.Lock()
+			if err != nil {
+				s.queues[qf.tc].stats.Errors++
+			} else {
+				s.queues[qf.tc].stats.Sent++
+			}
+			s.mu.Unlock()
+		}
+
+		if !s.wait(next) {
+			return
+		}
+	}
+}
+
+// next dequeues the next frame from the highest traffic class queue whose
+// gate is open.  s.mu must be held when calling next.
+func (s *TimeAwareShaper) next(gates uint8) (queued, bool) {
+	for i := len(s.queues) - 1; i >= 0; i-- {
+		if q := &s.queues[i]; gates&(1<<uint(i)) != 0 && len(q.frames) > 0 {
+			return q.pop(), true
+		}
+	}
+
+	return queued{}, false
+}
+
+// wait waits until time t or until a frame is enqueued, and reports whether
+// s is still open.
+func (s *TimeAwareShaper) wait(t time.Time) bool {
+	d := time.Until(t)
+	if d <= 0 {
+		select {
+		case <-s.doneC:
+			return false
+		default:
+			return true
+		}
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-s.wakeC:
+		return true
+	case <-s.doneC:
+		return false
+	}
+}
@@ -0,0 +1,213 @@
+package conn
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestNewTimeAwareShaperErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		cfg  TimeAwareShaperConfig
+	}{
+		{
+			desc: "too many queues",
+			cfg: TimeAwareShaperConfig{
+				Queues:          9,
+				GateControlList: []GateEntry{{Gates: 0xff, Interval: time.Millisecond}},
+			},
+		},
+		{
+			desc: "empty gate control list",
+		},
+		{
+			desc: "zero interval",
+			cfg: TimeAwareShaperConfig{
+				GateControlList: []GateEntry{{Gates: 0xff}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if _, err := NewTimeAwareShaper(&testConn{}, tt.cfg); err == nil {
+				t.Fatal("expected an error, but none occurred")
+			}
+		})
+	}
+}
+
+func TestTimeAwareShaperGates(t *testing.T) {
+	base := time.Unix(1, 0)
+
+	s, err := newTimeAwareShaper(&testConn{}, TimeAwareShaperConfig{
+		BaseTime: base,
+		GateControlList: []GateEntry{
+			{Gates: 0x01, Interval: 10 * time.Millisecond},
+			{Gates: 0x02, Interval: 20 * time.Millisecond},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create shaper: %v", err)
+	}
+
+	tests := []struct {
+		desc  string
+		now   time.Duration
+		gates uint8
+		next  time.Duration
+	}{
+		{
+			desc: "before base time",
+			now:  -1 * time.Millisecond,
+			next: 0,
+		},
+		{
+			desc:  "first entry",
+			now:   0,
+			gates: 0x01,
+			next:  10 * time.Millisecond,
+		},
+		{
+			desc:  "second entry",
+			now:   15 * time.Millisecond,
+			gates: 0x02,
+			next:  30 * time.Millisecond,
+		},
+		{
+			desc:  "second cycle",
+			now:   35 * time.Millisecond,
+			gates: 0x01,
+			next:  40 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			gates, next := s.gates(base.Add(tt.now))
+			if want, got := tt.gates, gates; want != got {
+				t.Fatalf("unexpected gates: %#02x != %#02x", want, got)
+			}
+			if want, got := base.Add(tt.next), next; !want.Equal(got) {
+				t.Fatalf("unexpected next gate event: %v != %v", want, got)
+			}
+		})
+	}
+}
+
+func TestTimeAwareShaperNext(t *testing.T) {
+	s, err := newTimeAwareShaper(&testConn{}, TimeAwareShaperConfig{
+		GateControlList: []GateEntry{{Gates: 0xff, Interval: time.Second}},
+	})
+	if err != nil {
+		t.Fatalf("failed to create shaper: %v", err)
+	}
+
+	for _, p := range []ethernet.Priority{ethernet.PriorityBackground, ethernet.PriorityNetworkControl} {
+		if _, err := s.WriteTo(priorityFrame(t, p), nil); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+	}
+
+	tests := []struct {
+		gates uint8
+		tc    int
+		ok    bool
+	}{
+		{gates: 0x00},
+		{gates: 0x01, tc: 0, ok: true},
+		{gates: 0x01},
+		{gates: 0xff, tc: 7, ok: true},
+		{gates: 0xff},
+	}
+
+	for i, tt := range tests {
+		qf, ok := s.next(tt.gates)
+		if want, got := tt.ok, ok; want != got {
+			t.Fatalf("unexpected dequeue for step %d: %v != %v", i, want, got)
+		}
+		if ok && qf.tc != tt.tc {
+			t.Fatalf("unexpected traffic class for step %d: %v != %v", i, tt.tc, qf.tc)
+		}
+	}
+}
+
+func TestTimeAwareShaperTransmit(t *testing.T) {
+	c := &testConn{}
+	s, err := NewTimeAwareShaper(c, TimeAwareShaperConfig{
+		// Only traffic class 7 may ever transmit.
+		GateControlList: []GateEntry{
+			{Gates: 0x80, Interval: 5 * time.Millisecond},
+			{Gates: 0x00, Interval: 5 * time.Millisecond},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create shaper: %v", err)
+	}
+	defer s.Close()
+
+	for _, p := range []ethernet.Priority{ethernet.PriorityBestEffort, ethernet.PriorityNetworkControl} {
+		if _, err := s.WriteTo(priorityFrame(t, p), nil); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(c.written()) < 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for frames to be transmitted")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	// Allow several more cycles to pass.
+	time.Sleep(30 * time.Millisecond)
+
+	want := [][]byte{priorityFrame(t, ethernet.PriorityNetworkControl)}
+	if got := c.written(); !equalFrames(want, got) {
+		t.Fatalf("unexpected frames:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	stats := make([]QueueStats, 8)
+	stats[1].Length = 1
+	stats[7].Sent = 1
+	if want, got := stats, s.Stats(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected stats:\n- want: %+v\n-  got: %+v", want, got)
+	}
+}
+
+func TestTimeAwareShaperCloseWhileWriting(t *testing.T) {
+	c := newBlockingConn()
+	s, err := NewTimeAwareShaper(c, TimeAwareShaperConfig{
+		GateControlList: []GateEntry{{Gates: 0xff, Interval: time.Millisecond}},
+	})
+	if err != nil {
+		t.Fatalf("failed to create shaper: %v", err)
+	}
+
+	if _, err := s.WriteTo(priorityFrame(t, ethernet.PriorityBestEffort), nil); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	select {
+	case <-c.writing:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for frame to be transmitted")
+	}
+
+	errC := make(chan error, 1)
+	go func() { errC <- s.Close() }()
+
+	select {
+	case err := <-errC:
+		if err != nil {
+			t.Fatalf("failed to close: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for Close")
+	}
+}
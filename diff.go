@@ -0,0 +1,148 @@
+package ethernet
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Diff returns a human-readable description of the differences between
+// Frames a and b, with one line per differing field in the form
+// "field: a != b".  Payload and padding differences include hexadecimal
+// context around the first differing byte.  Diff returns the empty string
+// if a and b are equal, as reported by Frame.Equal.
+//
+// Diff is intended for use in tests, to make failing comparisons of Frames
+// easier to read.
+func Diff(a, b *Frame) string {
+	if a.Equal(b) {
+		return ""
+	}
+	if a == nil || b == nil {
+		return fmt.Sprintf("Frame: %v != %v", a, b)
+	}
+
+	d := &differ{}
+
+	d.str("Destination", macString(a.Destination), macString(b.Destination))
+	d.str("Source", macString(a.Source), macString(b.Source))
+	d.vlan("ServiceVLAN", a.ServiceVLAN, b.ServiceVLAN)
+	d.vlan("VLAN", a.VLAN, b.VLAN)
+
+	if a.EtherType != b.EtherType {
+		at, _ := a.EtherType.MarshalText()
+		bt, _ := b.EtherType.MarshalText()
+		d.addf("EtherType: %s != %s", at, bt)
+	}
+	if a.Encapsulation != b.Encapsulation {
+		d.addf("Encapsulation: %v != %v", a.Encapsulation, b.Encapsulation)
+	}
+	if !a.LLC.Equal(b.LLC) {
+		d.addf("LLC: %s != %s", ptrString(a.LLC), ptrString(b.LLC))
+	}
+	if !a.SNAP.Equal(b.SNAP) {
+		d.addf("SNAP: %s != %s", ptrString(a.SNAP), ptrString(b.SNAP))
+	}
+
+	d.data("Payload", a.Payload, b.Payload)
+	d.data("Padding", a.Padding, b.Padding)
+
+	return strings.Join(d.lines, "\n")
+}
+
+// A differ accumulates the lines of output produced by Diff.
+type differ struct {
+	lines []string
+}
+
+// addf adds a formatted line of output.
+func (d *differ) addf(format string, v ...interface{}) {
+	d.lines = append(d.lines, fmt.Sprintf(format, v...))
+}
+
+// str adds a line if the string forms of a field differ.
+func (d *differ) str(field, a, b string) {
+	if a != b {
+		d.addf("%s: %s != %s", field, a, b)
+	}
+}
+
+// vlan adds a line for each field which differs between VLANs a and b.
+func (d *differ) vlan(field string, a, b *VLAN) {
+	switch {
+	case a.Equal(b):
+		return
+	case a == nil || b == nil:
+		d.addf("%s: %s != %s", field, ptrString(a), ptrString(b))
+		return
+	}
+
+	if a.Priority != b.Priority {
+		d.addf("%s.Priority: %d != %d", field, a.Priority, b.Priority)
+	}
+	if a.DropEligible != b.DropEligible {
+		d.addf("%s.DropEligible: %v != %v", field, a.DropEligible, b.DropEligible)
+	}
+	if a.ID != b.ID {
+		d.addf("%s.ID: %d != %d", field, a.ID, b.ID)
+	}
+	if a.TPID != b.TPID {
+		d.addf("%s.TPID: 0x%04x != 0x%04x", field, uint16(a.TPID), uint16(b.TPID))
+	}
+}
+
+// data adds lines describing the first difference between byte slices a
+// and b, with a line of hexadecimal context from each.
+func (d *differ) data(field string, a, b []byte) {
+	if bytes.Equal(a, b) {
+		return
+	}
+
+	// Find the first differing byte, which may be the end of the shorter
+	// slice.
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+
+	d.addf("%s: length %d != %d, first difference at offset %d", field, len(a), len(b), i)
+
+	// Print the 16 byte aligned line containing the difference.
+	off := i &^ 0x0f
+	d.addf("- %s", hexLine(a, off))
+	d.addf("+ %s", hexLine(b, off))
+}
+
+// hexLine formats up to 16 bytes of b beginning at offset off.
+func hexLine(b []byte, off int) string {
+	if off >= len(b) {
+		return fmt.Sprintf("%04x", off)
+	}
+
+	end := off + 16
+	if end > len(b) {
+		end = len(b)
+	}
+
+	return fmt.Sprintf("%04x  % x", off, b[off:end])
+}
+
+// ptrString formats a pointer to a header struct, using "<nil>" for nil.
+func ptrString(v interface{}) string {
+	switch v := v.(type) {
+	case *VLAN:
+		if v != nil {
+			return vlanString(v)
+		}
+	case *LLC:
+		if v != nil {
+			return fmt.Sprintf("%+v", *v)
+		}
+	case *SNAP:
+		if v != nil {
+			return fmt.Sprintf("%+v", *v)
+		}
+	}
+
+	return "<nil>"
+}
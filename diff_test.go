@@ -0,0 +1,67 @@
+package ethernet
+
+import (
+	"net"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	base := func() *Frame {
+		return &Frame{
+			Destination: Broadcast,
+			Source:      net.HardwareAddr{0, 1, 0, 1, 0, 1},
+			VLAN:        &VLAN{ID: 10},
+			EtherType:   EtherTypeIPv4,
+			Payload:     make([]byte, 40),
+		}
+	}
+
+	tests := []struct {
+		desc   string
+		modify func(f *Frame)
+		diff   string
+	}{
+		{
+			desc:   "equal",
+			modify: func(_ *Frame) {},
+		},
+		{
+			desc: "addresses and EtherType",
+			modify: func(f *Frame) {
+				f.Source = nil
+				f.EtherType = 0x88b5
+			},
+			diff: "Source: 00:01:00:01:00:01 != ?\nEtherType: IPv4 != 0x88B5",
+		},
+		{
+			desc: "VLANs",
+			modify: func(f *Frame) {
+				f.ServiceVLAN = &VLAN{ID: 100}
+				f.VLAN.Priority = PriorityVoice
+				f.VLAN.ID = 11
+			},
+			diff: "ServiceVLAN: <nil> != vid 100 pri 0\nVLAN.Priority: 0 != 5\nVLAN.ID: 10 != 11",
+		},
+		{
+			desc: "payload",
+			modify: func(f *Frame) {
+				f.Payload[20] = 0xff
+				f.Payload = f.Payload[:22]
+			},
+			diff: "Payload: length 40 != 22, first difference at offset 20\n" +
+				"- 0010  00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00\n" +
+				"+ 0010  00 00 00 00 ff 00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			a, b := base(), base()
+			tt.modify(b)
+
+			if want, got := tt.diff, Diff(a, b); want != got {
+				t.Fatalf("unexpected diff:\n- want:\n%s\n-  got:\n%s", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package ethernet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+)
+
+// Dump writes an annotated hex dump of the Ethernet frame in b to w, in the
+// style of Wireshark.  Each header field is printed on its own line with
+// its offset and a description, followed by the payload and any padding.
+// For example, with the hexadecimal column abbreviated:
+//
+//	0000  ff ff ff ff ff ff    Destination: ff:ff:ff:ff:ff:ff
+//	0006  00 11 22 33 44 55    Source: 00:11:22:33:44:55
+//	000c  81 00                VLAN TPID: 0x8100
+//	000e  20 65                VLAN: vid 101 pri 1
+//	0010  08 00                EtherType: IPv4 (0x0800)
+//	0012  00 01 02 03 ...      Payload: 46 bytes
+//
+// Dump does not require b to be a valid frame: fields are annotated up to
+// the point where b is truncated or malformed, and any remaining bytes are
+// labeled as such.  This makes Dump useful for debugging malformed frames.
+func Dump(w io.Writer, b []byte) error {
+	d := &dumper{b: b}
+	d.frame()
+
+	_, err := w.Write(d.buf.Bytes())
+	return err
+}
+
+// A dumper writes an annotated hex dump of a frame.
+type dumper struct {
+	b   []byte
+	n   int
+	buf bytes.Buffer
+}
+
+// frame dumps the fields of a frame.
+func (d *dumper) frame() {
+	if len(d.b) < 12 {
+		d.rest("Malformed: truncated hardware addresses")
+		return
+	}
+
+	d.field(6, "Destination: %s", net.HardwareAddr(d.b[0:6]))
+	d.field(6, "Source: %s", net.HardwareAddr(d.b[6:12]))
+
+	for {
+		if d.remaining() < 2 {
+			d.rest("Malformed: truncated EtherType")
+			return
+		}
+
+		et := EtherType(binary.BigEndian.Uint16(d.b[d.n : d.n+2]))
+
+		var name string
+		switch et {
+		case EtherTypeServiceVLAN:
+			name = "ServiceVLAN"
+		case EtherTypeVLAN:
+			name = "VLAN"
+		default:
+			d.etherType(et)
+			return
+		}
+
+		d.field(2, "%s TPID: 0x%04x", name, uint16(et))
+		if d.remaining() < 2 {
+			d.rest("Malformed: truncated %s", name)
+			return
+		}
+
+		var v VLAN
+		if err := v.UnmarshalBinary(d.b[d.n : d.n+2]); err != nil {
+			d.field(2, "%s: malformed: %v", name, err)
+		} else {
+			d.field(2, "%s: %s", name, vlanString(&v))
+		}
+	}
+}
+
+// etherType dumps the EtherType or length field et, and all following
+// fields.
+func (d *dumper) etherType(et EtherType) {
+	if et >= minEtherType {
+		if s, ok := etherTypeName(et); ok {
+			d.field(2, "EtherType: %s (0x%04x)", s, uint16(et))
+		} else {
+			d.field(2, "EtherType: 0x%04x", uint16(et))
+		}
+
+		d.rest("Payload: %d bytes", d.remaining())
+		return
+	}
+
+	// IEEE 802.3 frame: any bytes beyond the length are padding.
+	d.field(2, "Length: %d", et)
+
+	l := int(et)
+	if l > d.remaining() {
+		l = d.remaining()
+	}
+	end := d.n + l
+	data := d.b[d.n:end]
+
+	switch {
+	case et < 3:
+	case len(data) >= 2 && data[0] == 0xff && data[1] == 0xff:
+		d.field(0, "Novell raw IPX")
+	default:
+		var llc LLC
+		n, err := llc.unmarshal(data)
+		if err != nil {
+			d.rest("Malformed: truncated LLC header")
+			return
+		}
+
+		d.field(n, "LLC: dsap 0x%02x ssap 0x%02x ctrl 0x%02x", llc.DSAP, llc.SSAP, llc.Control)
+		if !llc.isSNAP() {
+			break
+		}
+
+		if end-d.n < snapLen {
+			d.rest("Malformed: truncated SNAP header")
+			return
+		}
+
+		var s SNAP
+		_ = s.UnmarshalBinary(d.b[d.n : d.n+snapLen])
+		d.field(snapLen, "SNAP: oui 0x%02x%02x%02x pid 0x%04x",
+			s.OUI[0], s.OUI[1], s.OUI[2], s.ProtocolID)
+	}
+
+	if n := end - d.n; n > 0 {
+		d.field(n, "Payload: %d bytes", n)
+	}
+
+	d.rest("Padding: %d bytes", d.remaining())
+}
+
+// remaining returns the number of bytes which have not been dumped.
+func (d *dumper) remaining() int {
+	return len(d.b) - d.n
+}
+
+// rest dumps all remaining bytes, if any, as a single field.
+func (d *dumper) rest(format string, args ...interface{}) {
+	if d.remaining() > 0 {
+		d.field(d.remaining(), format, args...)
+	}
+}
+
+// field dumps the next n bytes as a field, 16 bytes per line, with a label
+// on the first line.
+func (d *dumper) field(n int, format string, args ...interface{}) {
+	const width = 16*3 - 1
+
+	label := fmt.Sprintf(format, args...)
+	b := d.b[d.n : d.n+n]
+
+	// Fields without bytes are only labels.
+	if len(b) == 0 {
+		fmt.Fprintf(&d.buf, "%04x  %-*s    %s\n", d.n, width, "", label)
+		return
+	}
+
+	for i := 0; i < len(b); i += 16 {
+		end := i + 16
+		if end > len(b) {
+			end = len(b)
+		}
+
+		var hex bytes.Buffer
+		for j, c := range b[i:end] {
+			if j > 0 {
+				hex.WriteByte(' ')
+			}
+			fmt.Fprintf(&hex, "%02x", c)
+		}
+
+		if i == 0 {
+			fmt.Fprintf(&d.buf, "%04x  %-*s    %s\n", d.n+i, width, hex.String(), label)
+		} else {
+			fmt.Fprintf(&d.buf, "%04x  %s\n", d.n+i, hex.String())
+		}
+	}
+
+	d.n += n
+}
+
+// vlanString formats the fields of a VLAN tag.
+func vlanString(v *VLAN) string {
+	s := fmt.Sprintf("vid %d pri %d", v.ID, v.Priority)
+	if v.DropEligible {
+		s += " dei"
+	}
+
+	return s
+}
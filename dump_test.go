@@ -0,0 +1,94 @@
+package ethernet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDump(t *testing.T) {
+	tests := []struct {
+		desc string
+		b    []byte
+		s    []string
+	}{
+		{
+			desc: "truncated",
+			b:    []byte{0xff, 0xff, 0xff},
+			s: []string{
+				"0000  ff ff ff                                           Malformed: truncated hardware addresses",
+			},
+		},
+		{
+			desc: "C-VLAN, IPv4",
+			b: append([]byte{
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0x00, 0x11, 0x22, 0x33, 0x44, 0x55,
+				0x81, 0x00,
+				0x20, 0x65,
+				0x08, 0x00,
+			}, bytes.Repeat([]byte{0xab}, 18)...),
+			s: []string{
+				"0000  ff ff ff ff ff ff                                  Destination: ff:ff:ff:ff:ff:ff",
+				"0006  00 11 22 33 44 55                                  Source: 00:11:22:33:44:55",
+				"000c  81 00                                              VLAN TPID: 0x8100",
+				"000e  20 65                                              VLAN: vid 101 pri 1",
+				"0010  08 00                                              EtherType: IPv4 (0x0800)",
+				"0012  ab ab ab ab ab ab ab ab ab ab ab ab ab ab ab ab    Payload: 18 bytes",
+				"0022  ab ab",
+			},
+		},
+		{
+			desc: "SNAP, padding",
+			b: []byte{
+				0x01, 0x00, 0x0c, 0xcc, 0xcc, 0xcc,
+				0x00, 0x11, 0x22, 0x33, 0x44, 0x55,
+				0x00, 0x09,
+				0xaa, 0xaa, 0x03,
+				0x00, 0x00, 0x0c, 0x20, 0x00,
+				0x01,
+				0x00, 0x00,
+			},
+			s: []string{
+				"0000  01 00 0c cc cc cc                                  Destination: 01:00:0c:cc:cc:cc",
+				"0006  00 11 22 33 44 55                                  Source: 00:11:22:33:44:55",
+				"000c  00 09                                              Length: 9",
+				"000e  aa aa 03                                           LLC: dsap 0xaa ssap 0xaa ctrl 0x03",
+				"0011  00 00 0c 20 00                                     SNAP: oui 0x00000c pid 0x2000",
+				"0016  01                                                 Payload: 1 bytes",
+				"0017  00 00                                              Padding: 2 bytes",
+			},
+		},
+		{
+			desc: "bad VLAN, truncated EtherType",
+			b: []byte{
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0x00, 0x11, 0x22, 0x33, 0x44, 0x55,
+				0x88, 0xa8,
+				0x0f, 0xff,
+				0x81,
+			},
+			s: []string{
+				"0000  ff ff ff ff ff ff                                  Destination: ff:ff:ff:ff:ff:ff",
+				"0006  00 11 22 33 44 55                                  Source: 00:11:22:33:44:55",
+				"000c  88 a8                                              ServiceVLAN TPID: 0x88a8",
+				"000e  0f ff                                              ServiceVLAN: malformed: invalid VLAN",
+				"0010  81                                                 Malformed: truncated EtherType",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Dump(&buf, tt.b); err != nil {
+				t.Fatalf("failed to dump: %v", err)
+			}
+
+			want := strings.Join(tt.s, "\n") + "\n"
+			if got := buf.String(); want != got {
+				t.Fatalf("unexpected dump:\n- want:\n%s\n-  got:\n%s", want, got)
+			}
+		})
+	}
+}
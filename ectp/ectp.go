@@ -0,0 +1,275 @@
+// Package ectp implements marshaling and unmarshaling of Ethernet
+// Configuration Testing Protocol (loopback) packets, as described in the
+// Ethernet Version 2.0 specification.
+package ectp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// EtherType is the EtherType which indicates an ECTP packet in a Frame.
+const EtherType = ethernet.EtherTypeECTP
+
+// Destination is the Loopback Assistance multicast address, which may be
+// used to find stations willing to forward ECTP packets.
+var Destination = net.HardwareAddr{0xcf, 0x00, 0x00, 0x00, 0x00, 0x00}
+
+const (
+	// skipLen is the length of the skip count field.
+	skipLen = 2
+
+	// forwardLen and replyLen are the lengths of forward messages and of
+	// reply messages without data.
+	forwardLen = 8
+	replyLen   = 4
+)
+
+var (
+	// ErrInvalidFunction is returned when an ECTP message has an unknown
+	// function code, or when a forward message follows a reply message.
+	ErrInvalidFunction = errors.New("ectp: invalid function")
+
+	// ErrInvalidSkipCount is returned when a packet's skip count does not
+	// indicate the start of one of its messages.
+	ErrInvalidSkipCount = errors.New("ectp: invalid skip count")
+)
+
+// A Function is the function code of an ECTP message.
+type Function uint16
+
+// Possible Function values.
+const (
+	FunctionReply   Function = 1
+	FunctionForward Function = 2
+)
+
+// String returns the name of a Function.
+func (f Function) String() string {
+	switch f {
+	case FunctionReply:
+		return "Reply"
+	case FunctionForward:
+		return "Forward"
+	default:
+		return fmt.Sprintf("Function(%d)", uint16(f))
+	}
+}
+
+// A Message is an ECTP forward or reply message.
+type Message struct {
+	// Function specifies the type of the message.
+	Function Function
+
+	// Address specifies the station to which a forward message sends the
+	// packet.  Forward messages only.
+	Address net.HardwareAddr
+
+	// ReceiptNumber and Data are opaque to forwarding stations, and
+	// identify a reply to its requester.  Reply messages only.  Data runs
+	// to the end of the packet, so it includes any Ethernet padding when
+	// unmarshaled.
+	ReceiptNumber uint16
+	Data          []byte
+}
+
+// A Packet is an ECTP packet.
+type Packet struct {
+	// SkipCount specifies the byte offset of the current message, relative
+	// to the start of the first message.
+	SkipCount uint16
+
+	// Messages specifies the messages of the packet.  Any number of forward
+	// messages may be followed by at most one reply message.
+	Messages []Message
+}
+
+// NewPing creates a Packet which asks a station to send a reply message
+// back to hardware address src, the sender of the Packet.
+func NewPing(src net.HardwareAddr, receipt uint16, data []byte) *Packet {
+	return &Packet{
+		Messages: []Message{
+			{
+				Function: FunctionForward,
+				Address:  src,
+			},
+			{
+				Function:      FunctionReply,
+				ReceiptNumber: receipt,
+				Data:          data,
+			},
+		},
+	}
+}
+
+// Parse unmarshals the ECTP packet carried in the payload of Frame f.  If
+// f's EtherType is not EtherType, ethernet.ErrInvalidEtherType is returned.
+func Parse(f *ethernet.Frame) (*Packet, error) {
+	if f.EtherType != EtherType {
+		return nil, ethernet.ErrInvalidEtherType
+	}
+
+	p := new(Packet)
+	if err := p.UnmarshalBinary(f.Payload); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Frame marshals a Packet into the payload of an Ethernet frame sent from
+// hardware address src to hardware address dst.
+func (p *Packet) Frame(dst, src net.HardwareAddr) (*ethernet.Frame, error) {
+	b, err := p.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ethernet.Frame{
+		Destination: dst,
+		Source:      src,
+		EtherType:   EtherType,
+		Payload:     b,
+	}, nil
+}
+
+// Current returns the message indicated by the Packet's skip count.
+func (p *Packet) Current() (*Message, error) {
+	var n int
+	for i := range p.Messages {
+		if n == int(p.SkipCount) {
+			return &p.Messages[i], nil
+		}
+
+		n += p.Messages[i].len()
+	}
+
+	return nil, ErrInvalidSkipCount
+}
+
+// Forward processes a Packet as a forwarding station with hardware address
+// src would.  If the current message is a forward message, Forward returns
+// a Frame which carries a copy of the Packet, with its skip count advanced
+// past the message, to the message's address.  If the current message is a
+// reply message, the Packet has reached its destination and
+// ErrInvalidFunction is returned.
+func (p *Packet) Forward(src net.HardwareAddr) (*ethernet.Frame, error) {
+	m, err := p.Current()
+	if err != nil {
+		return nil, err
+	}
+	if m.Function != FunctionForward {
+		return nil, ErrInvalidFunction
+	}
+
+	next := &Packet{
+		SkipCount: p.SkipCount + forwardLen,
+		Messages:  p.Messages,
+	}
+
+	return next.Frame(m.Address, src)
+}
+
+// MarshalBinary allocates a byte slice and marshals a Packet into binary
+// form.  ECTP fields are little-endian.
+func (p *Packet) MarshalBinary() ([]byte, error) {
+	n := skipLen
+	for i, m := range p.Messages {
+		switch m.Function {
+		case FunctionForward:
+			if len(m.Address) != 6 {
+				return nil, ethernet.ErrInvalidHardwareAddr
+			}
+		case FunctionReply:
+			if i != len(p.Messages)-1 {
+				return nil, ErrInvalidFunction
+			}
+		default:
+			return nil, ErrInvalidFunction
+		}
+
+		n += m.len()
+	}
+
+	b := make([]byte, n)
+	binary.LittleEndian.PutUint16(b[0:2], p.SkipCount)
+
+	n = skipLen
+	for _, m := range p.Messages {
+		binary.LittleEndian.PutUint16(b[n:n+2], uint16(m.Function))
+
+		if m.Function == FunctionForward {
+			copy(b[n+2:n+forwardLen], m.Address)
+		} else {
+			binary.LittleEndian.PutUint16(b[n+2:n+replyLen], m.ReceiptNumber)
+			copy(b[n+replyLen:], m.Data)
+		}
+
+		n += m.len()
+	}
+
+	return b, nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into a Packet.
+func (p *Packet) UnmarshalBinary(b []byte) error {
+	if len(b) < skipLen {
+		return io.ErrUnexpectedEOF
+	}
+
+	out := Packet{
+		SkipCount: binary.LittleEndian.Uint16(b[0:2]),
+	}
+
+	b = b[skipLen:]
+	for len(b) > 0 {
+		if len(b) < 2 {
+			return io.ErrUnexpectedEOF
+		}
+
+		m := Message{
+			Function: Function(binary.LittleEndian.Uint16(b[0:2])),
+		}
+
+		switch m.Function {
+		case FunctionForward:
+			if len(b) < forwardLen {
+				return io.ErrUnexpectedEOF
+			}
+
+			m.Address = make(net.HardwareAddr, 6)
+			copy(m.Address, b[2:forwardLen])
+			b = b[forwardLen:]
+		case FunctionReply:
+			if len(b) < replyLen {
+				return io.ErrUnexpectedEOF
+			}
+
+			m.ReceiptNumber = binary.LittleEndian.Uint16(b[2:replyLen])
+			m.Data = make([]byte, len(b[replyLen:]))
+			copy(m.Data, b[replyLen:])
+			b = nil
+		default:
+			return ErrInvalidFunction
+		}
+
+		out.Messages = append(out.Messages, m)
+	}
+
+	*p = out
+	return nil
+}
+
+// len returns the marshaled length of a Message.
+func (m *Message) len() int {
+	if m.Function == FunctionForward {
+		return forwardLen
+	}
+
+	return replyLen + len(m.Data)
+}
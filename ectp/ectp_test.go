@@ -0,0 +1,183 @@
+package ectp
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestPacketMarshalUnmarshal(t *testing.T) {
+	src := net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}
+
+	p := NewPing(src, 0x0102, []byte{0xaa, 0xbb})
+
+	b, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	want := []byte{
+		// Skip count.
+		0x00, 0x00,
+		// Forward to src.
+		0x02, 0x00, 0xde, 0xad, 0xbe, 0xef, 0xde, 0xad,
+		// Reply.
+		0x01, 0x00, 0x02, 0x01, 0xaa, 0xbb,
+	}
+
+	if !bytes.Equal(want, b) {
+		t.Fatalf("unexpected bytes:\n- want: %v\n-  got: %v", want, b)
+	}
+
+	p2 := new(Packet)
+	if err := p2.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if !reflect.DeepEqual(p, p2) {
+		t.Fatalf("unexpected packet:\n- want: %#v\n-  got: %#v", p, p2)
+	}
+}
+
+func TestPacketForward(t *testing.T) {
+	requester := net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}
+	responder := net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x01}
+
+	f, err := NewPing(requester, 1, []byte("hello")).Frame(responder, requester)
+	if err != nil {
+		t.Fatalf("failed to create frame: %v", err)
+	}
+
+	// The responder forwards the packet back to the requester.
+	p, err := Parse(f)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	reply, err := p.Forward(responder)
+	if err != nil {
+		t.Fatalf("failed to forward: %v", err)
+	}
+
+	if want, got := requester, reply.Destination; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected destination: %v != %v", want, got)
+	}
+	if want, got := responder, reply.Source; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected source: %v != %v", want, got)
+	}
+
+	// The requester finds its reply message.
+	p, err = Parse(reply)
+	if err != nil {
+		t.Fatalf("failed to parse reply: %v", err)
+	}
+
+	m, err := p.Current()
+	if err != nil {
+		t.Fatalf("failed to get current message: %v", err)
+	}
+
+	want := &Message{
+		Function:      FunctionReply,
+		ReceiptNumber: 1,
+		Data:          []byte("hello"),
+	}
+
+	if !reflect.DeepEqual(want, m) {
+		t.Fatalf("unexpected message:\n- want: %#v\n-  got: %#v", want, m)
+	}
+
+	if _, err := p.Forward(requester); err != ErrInvalidFunction {
+		t.Fatalf("expected ErrInvalidFunction, but got: %v", err)
+	}
+
+	p.SkipCount = 3
+	if _, err := p.Current(); err != ErrInvalidSkipCount {
+		t.Fatalf("expected ErrInvalidSkipCount, but got: %v", err)
+	}
+}
+
+func TestPacketMarshalErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		p    *Packet
+		err  error
+	}{
+		{
+			desc: "bad function",
+			p:    &Packet{Messages: []Message{{Function: 3}}},
+			err:  ErrInvalidFunction,
+		},
+		{
+			desc: "reply before forward",
+			p: &Packet{
+				Messages: []Message{
+					{Function: FunctionReply},
+					{Function: FunctionForward, Address: ethernet.Broadcast},
+				},
+			},
+			err: ErrInvalidFunction,
+		},
+		{
+			desc: "bad forward address",
+			p: &Packet{
+				Messages: []Message{{Function: FunctionForward, Address: net.HardwareAddr{0x01}}},
+			},
+			err: ethernet.ErrInvalidHardwareAddr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if _, err := tt.p.MarshalBinary(); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestPacketUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		b    []byte
+		err  error
+	}{
+		{
+			desc: "short skip count",
+			b:    []byte{0x00},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "short function",
+			b:    []byte{0x00, 0x00, 0x02},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "short forward",
+			b:    []byte{0x00, 0x00, 0x02, 0x00, 0xde, 0xad},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "short reply",
+			b:    []byte{0x00, 0x00, 0x01, 0x00, 0x01},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "bad function",
+			b:    []byte{0x00, 0x00, 0x03, 0x00},
+			err:  ErrInvalidFunction,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := new(Packet).UnmarshalBinary(tt.b); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package ethernet
+
+import "bytes"
+
+// Equal reports whether f and g are semantically equal: hardware addresses,
+// payloads, and padding are compared byte-wise, treating nil and empty
+// slices as equal, and VLAN, LLC, and SNAP headers are compared by value.
+//
+// Encapsulation is only compared when one of the Frames uses
+// EncapsulationNovellRaw, because otherwise it does not affect the binary
+// form of a Frame.
+func (f *Frame) Equal(g *Frame) bool {
+	if f == nil || g == nil {
+		return f == g
+	}
+
+	if (f.Encapsulation == EncapsulationNovellRaw) != (g.Encapsulation == EncapsulationNovellRaw) {
+		return false
+	}
+
+	return bytes.Equal(f.Destination, g.Destination) &&
+		bytes.Equal(f.Source, g.Source) &&
+		f.ServiceVLAN.equal(g.ServiceVLAN, EtherTypeServiceVLAN) &&
+		f.VLAN.equal(g.VLAN, EtherTypeVLAN) &&
+		f.EtherType == g.EtherType &&
+		f.LLC.Equal(g.LLC) &&
+		f.SNAP.Equal(g.SNAP) &&
+		bytes.Equal(f.Payload, g.Payload) &&
+		bytes.Equal(f.Padding, g.Padding)
+}
+
+// Equal reports whether v and w are equal.  Two nil VLANs are equal.  A
+// zero TPID is treated as EtherTypeVLAN, the TPID used to marshal it.
+func (v *VLAN) Equal(w *VLAN) bool {
+	return v.equal(w, EtherTypeVLAN)
+}
+
+// equal reports whether v and w are equal, treating a zero TPID as tpid.
+func (v *VLAN) equal(w *VLAN, tpid EtherType) bool {
+	if v == nil || w == nil {
+		return v == w
+	}
+
+	vv, ww := *v, *w
+	if vv.TPID == 0 {
+		vv.TPID = tpid
+	}
+	if ww.TPID == 0 {
+		ww.TPID = tpid
+	}
+
+	return vv == ww
+}
+
+// Equal reports whether l and m are equal.  Two nil LLCs are equal.
+func (l *LLC) Equal(m *LLC) bool {
+	if l == nil || m == nil {
+		return l == m
+	}
+
+	return *l == *m
+}
+
+// Equal reports whether s and t are equal.  Two nil SNAPs are equal.
+func (s *SNAP) Equal(t *SNAP) bool {
+	if s == nil || t == nil {
+		return s == t
+	}
+
+	return *s == *t
+}
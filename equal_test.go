@@ -0,0 +1,158 @@
+package ethernet
+
+import (
+	"net"
+	"testing"
+)
+
+func TestFrameEqual(t *testing.T) {
+	frame := func() *Frame {
+		return &Frame{
+			Destination: Broadcast,
+			Source:      net.HardwareAddr{0, 1, 0, 1, 0, 1},
+			ServiceVLAN: &VLAN{ID: 100},
+			VLAN:        &VLAN{Priority: 1, ID: 101},
+			EtherType:   EtherTypeARP,
+			Payload:     []byte{1, 2, 3},
+		}
+	}
+
+	tests := []struct {
+		desc string
+		a, b *Frame
+		ok   bool
+	}{
+		{
+			desc: "both nil",
+			ok:   true,
+		},
+		{
+			desc: "one nil",
+			a:    frame(),
+		},
+		{
+			desc: "empty, nil and empty slices",
+			a:    &Frame{},
+			b: &Frame{
+				Destination: net.HardwareAddr{},
+				Source:      net.HardwareAddr{},
+				Payload:     []byte{},
+				Padding:     []byte{},
+			},
+			ok: true,
+		},
+		{
+			desc: "equal, separate VLAN pointers",
+			a:    frame(),
+			b:    frame(),
+			ok:   true,
+		},
+		{
+			desc: "different source",
+			a:    frame(),
+			b: func() *Frame {
+				f := frame()
+				f.Source = net.HardwareAddr{0, 1, 0, 1, 0, 2}
+				return f
+			}(),
+		},
+		{
+			desc: "missing VLAN",
+			a:    frame(),
+			b: func() *Frame {
+				f := frame()
+				f.ServiceVLAN = nil
+				return f
+			}(),
+		},
+		{
+			desc: "different VLAN",
+			a:    frame(),
+			b: func() *Frame {
+				f := frame()
+				f.VLAN.DropEligible = true
+				return f
+			}(),
+		},
+		{
+			desc: "explicit default TPIDs",
+			a:    frame(),
+			b: func() *Frame {
+				f := frame()
+				f.ServiceVLAN.TPID = EtherTypeServiceVLAN
+				f.VLAN.TPID = EtherTypeVLAN
+				return f
+			}(),
+			ok: true,
+		},
+		{
+			desc: "different TPID",
+			a:    frame(),
+			b: func() *Frame {
+				f := frame()
+				f.ServiceVLAN.TPID = EtherTypeVLAN
+				return f
+			}(),
+		},
+		{
+			desc: "different padding",
+			a:    frame(),
+			b: func() *Frame {
+				f := frame()
+				f.Padding = []byte{0}
+				return f
+			}(),
+		},
+		{
+			desc: "different SNAP",
+			a: &Frame{
+				LLC:  &LLC{DSAP: 0xaa, SSAP: 0xaa, Control: 0x03},
+				SNAP: &SNAP{ProtocolID: 0x0800},
+			},
+			b: &Frame{
+				LLC:  &LLC{DSAP: 0xaa, SSAP: 0xaa, Control: 0x03},
+				SNAP: &SNAP{ProtocolID: 0x0806},
+			},
+		},
+		{
+			desc: "LLC encapsulation ignored",
+			a: &Frame{
+				LLC: &LLC{DSAP: 0x42, SSAP: 0x42, Control: 0x03},
+			},
+			b: &Frame{
+				LLC:           &LLC{DSAP: 0x42, SSAP: 0x42, Control: 0x03},
+				Encapsulation: EncapsulationLLC,
+			},
+			ok: true,
+		},
+		{
+			desc: "Novell raw encapsulation",
+			a:    &Frame{},
+			b:    &Frame{Encapsulation: EncapsulationNovellRaw},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if want, got := tt.ok, tt.a.Equal(tt.b); want != got {
+				t.Fatalf("unexpected a.Equal(b): %v != %v", want, got)
+			}
+			if want, got := tt.ok, tt.b.Equal(tt.a); want != got {
+				t.Fatalf("unexpected b.Equal(a): %v != %v", want, got)
+			}
+		})
+	}
+}
+
+func TestVLANEqualTPID(t *testing.T) {
+	a := &VLAN{ID: 10}
+	b := &VLAN{ID: 10, TPID: EtherTypeVLAN}
+	c := &VLAN{ID: 10, TPID: EtherTypeServiceVLAN}
+
+	if !a.Equal(b) || !b.Equal(a) {
+		t.Fatal("expected zero TPID to equal EtherTypeVLAN")
+	}
+	if a.Equal(c) || c.Equal(a) {
+		t.Fatal("expected zero TPID not to equal EtherTypeServiceVLAN")
+	}
+}
@@ -0,0 +1,166 @@
+// Package erspan implements parsing of ERSPAN Type II and Type III headers,
+// for decoding Ethernet frames mirrored by Encapsulated Remote SPAN.
+package erspan
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// GRE protocol types which indicate an ERSPAN header.
+const (
+	EtherTypeTypeII  ethernet.EtherType = 0x88be
+	EtherTypeTypeIII ethernet.EtherType = 0x22eb
+)
+
+// ERSPAN header versions.
+const (
+	VersionTypeII  = 1
+	VersionTypeIII = 2
+)
+
+const (
+	// headerLenII and headerLenIII are the lengths of the fixed ERSPAN
+	// headers.
+	headerLenII  = 8
+	headerLenIII = 12
+
+	// platformLen is the length of a Type III platform specific subheader.
+	platformLen = 8
+)
+
+var (
+	// ErrInvalidVersion is returned when an ERSPAN header has an unknown
+	// version.
+	ErrInvalidVersion = errors.New("erspan: invalid version")
+
+	// ErrNotEthernet is returned when an ERSPAN Type III header indicates
+	// that the mirrored packet is not an Ethernet frame.
+	ErrNotEthernet = errors.New("erspan: mirrored packet is not an Ethernet frame")
+)
+
+// A Header is an ERSPAN Type II or Type III header.  Fields which are only
+// present in Type III headers are zero for Type II headers, and vice versa.
+type Header struct {
+	// Version is VersionTypeII or VersionTypeIII.
+	Version int
+
+	// VLAN is the VLAN of the original frame.
+	VLAN uint16
+
+	// COS is the class of service of the original frame.
+	COS uint8
+
+	// Encapsulation specifies how VLAN tags were handled in the original
+	// frame.  In Type III headers, this field is the bad/short/oversized
+	// indicator.
+	Encapsulation uint8
+
+	// Truncated reports whether the mirrored frame was truncated.
+	Truncated bool
+
+	// SessionID identifies the ERSPAN session.
+	SessionID uint16
+
+	// Index is the port index of the mirrored frame.  Type II only.
+	Index uint32
+
+	// Timestamp is the hardware timestamp of the mirrored frame, with a
+	// granularity specified by Granularity.  Type III only.
+	Timestamp uint32
+
+	// SGT is the security group tag of the mirrored frame.  Type III only.
+	SGT uint16
+
+	// HardwareID identifies the mirroring hardware.  Type III only.
+	HardwareID uint8
+
+	// Egress reports whether the frame was mirrored on egress rather than
+	// ingress.  Type III only.
+	Egress bool
+
+	// Granularity specifies the units of Timestamp.  Type III only.
+	Granularity uint8
+
+	// Platform is the platform specific subheader, if present.  Type III
+	// only.
+	Platform []byte
+}
+
+// Parse parses an ERSPAN header from the payload of a GRE packet, and
+// unmarshals the mirrored Ethernet frame which follows it.  The Header's
+// Platform field aliases b.
+func Parse(b []byte) (*Header, *ethernet.Frame, error) {
+	if len(b) < 2 {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+
+	h := &Header{
+		Version: int(b[0] >> 4),
+	}
+
+	var n int
+	switch h.Version {
+	case VersionTypeII:
+		if len(b) < headerLenII {
+			return nil, nil, io.ErrUnexpectedEOF
+		}
+
+		h.parseCommon(b)
+		h.Index = binary.BigEndian.Uint32(b[4:8]) & 0x000fffff
+		n = headerLenII
+	case VersionTypeIII:
+		if len(b) < headerLenIII {
+			return nil, nil, io.ErrUnexpectedEOF
+		}
+
+		h.parseCommon(b)
+		h.Timestamp = binary.BigEndian.Uint32(b[4:8])
+		h.SGT = binary.BigEndian.Uint16(b[8:10])
+
+		// P (1 bit), frame type (5 bits), hardware ID (6 bits), direction
+		// (1 bit), granularity (2 bits), optional subheader (1 bit).
+		v := binary.BigEndian.Uint16(b[10:12])
+		if ft := (v >> 10) & 0x1f; ft != 0 {
+			return nil, nil, ErrNotEthernet
+		}
+		h.HardwareID = uint8((v >> 4) & 0x3f)
+		h.Egress = v&0x0008 != 0
+		h.Granularity = uint8((v >> 1) & 0x03)
+		n = headerLenIII
+
+		if v&0x0001 != 0 {
+			if len(b[n:]) < platformLen {
+				return nil, nil, io.ErrUnexpectedEOF
+			}
+
+			h.Platform = b[n : n+platformLen : n+platformLen]
+			n += platformLen
+		}
+	default:
+		return nil, nil, ErrInvalidVersion
+	}
+
+	f := new(ethernet.Frame)
+	if err := f.UnmarshalBinary(b[n:]); err != nil {
+		return nil, nil, err
+	}
+
+	return h, f, nil
+}
+
+// parseCommon parses the fields shared by Type II and Type III headers.
+func (h *Header) parseCommon(b []byte) {
+	h.VLAN = binary.BigEndian.Uint16(b[0:2]) & 0x0fff
+
+	// COS (3 bits), encapsulation (2 bits), truncated (1 bit), session ID
+	// (10 bits).
+	v := binary.BigEndian.Uint16(b[2:4])
+	h.COS = uint8(v >> 13)
+	h.Encapsulation = uint8((v >> 11) & 0x03)
+	h.Truncated = v&0x0400 != 0
+	h.SessionID = v & 0x03ff
+}
@@ -0,0 +1,134 @@
+package erspan
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestParse(t *testing.T) {
+	f := &ethernet.Frame{
+		Destination: ethernet.Broadcast,
+		Source:      ethernet.Broadcast,
+		EtherType:   ethernet.EtherTypeIPv4,
+		Payload:     bytes.Repeat([]byte{0xff}, 46),
+	}
+
+	fb, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	tests := []struct {
+		desc string
+		b    []byte
+		h    *Header
+		err  error
+	}{
+		{
+			desc: "short",
+			b:    []byte{0x10},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "bad version",
+			b:    []byte{0x30, 0x00},
+			err:  ErrInvalidVersion,
+		},
+		{
+			desc: "Type II short",
+			b:    []byte{0x10, 0x00, 0x00, 0x00},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "Type II",
+			b: append([]byte{
+				// Version 1, VLAN 10.
+				0x10, 0x0a,
+				// COS 5, encapsulation 3, truncated, session 513.
+				0xbe, 0x01,
+				// Reserved, index 0x12345.
+				0x00, 0x01, 0x23, 0x45,
+			}, fb...),
+			h: &Header{
+				Version:       VersionTypeII,
+				VLAN:          10,
+				COS:           5,
+				Encapsulation: 3,
+				Truncated:     true,
+				SessionID:     513,
+				Index:         0x12345,
+			},
+		},
+		{
+			desc: "Type III not Ethernet",
+			b: []byte{
+				0x20, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x08, 0x00,
+			},
+			err: ErrNotEthernet,
+		},
+		{
+			desc: "Type III platform short",
+			b: []byte{
+				0x20, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x01,
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "Type III",
+			b: append([]byte{
+				// Version 2, VLAN 20.
+				0x20, 0x14,
+				// COS 1, session 2.
+				0x20, 0x02,
+				// Timestamp.
+				0xde, 0xad, 0xbe, 0xef,
+				// SGT.
+				0x00, 0x03,
+				// Hardware ID 5, egress, granularity 3, platform subheader.
+				0x00, 0x5f,
+				// Platform subheader.
+				0x01, 0x02, 0x03, 0x04,
+				0x05, 0x06, 0x07, 0x08,
+			}, fb...),
+			h: &Header{
+				Version:     VersionTypeIII,
+				VLAN:        20,
+				COS:         1,
+				SessionID:   2,
+				Timestamp:   0xdeadbeef,
+				SGT:         3,
+				HardwareID:  5,
+				Egress:      true,
+				Granularity: 3,
+				Platform:    []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			h, got, err := Parse(tt.b)
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error: %v != %v", want, got)
+			}
+			if err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(tt.h, h) {
+				t.Fatalf("unexpected header:\n- want: %#v\n-  got: %#v", tt.h, h)
+			}
+			if !reflect.DeepEqual(f, got) {
+				t.Fatalf("unexpected frame:\n- want: %#v\n-  got: %#v", f, got)
+			}
+		})
+	}
+}
@@ -1,5 +1,6 @@
 // Package ethernet implements marshaling and unmarshaling of IEEE 802.3
-// Ethernet II frames and IEEE 802.1Q VLAN tags.
+// Ethernet II frames, IEEE 802.1Q VLAN tags, and IEEE 802.2 LLC and SNAP
+// headers.
 package ethernet
 
 import (
@@ -11,14 +12,26 @@ import (
 	"net"
 )
 
+// The generated String method is renamed so that EtherType.String can also
+// consult the EtherType registry.
 //go:generate stringer -output=string.go -type=EtherType
+//go:generate sed -i.orig -e "s/^func (i EtherType) String() string {/func (i EtherType) builtinString() string {/" string.go
+//go:generate rm string.go.orig
 
 const (
 	// minPayload is the minimum payload size for an Ethernet frame, assuming
 	// that no 802.1Q VLAN tags are present.
 	minPayload = 46
+
+	// minFrame is the minimum size for an Ethernet frame, excluding the
+	// frame check sequence.
+	minFrame = 60
 )
 
+// preamble is the 7 byte preamble and 1 byte start frame delimiter which
+// precede each Ethernet frame on the wire.
+var preamble = [8]byte{0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0xd5}
+
 // Broadcast is a special hardware address which indicates a Frame should
 // be sent to every device on a given LAN segment.
 var Broadcast = net.HardwareAddr{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
@@ -27,6 +40,32 @@ var Broadcast = net.HardwareAddr{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
 // Ethernet frame check sequence in a byte slice for a Frame.
 var ErrInvalidFCS = errors.New("invalid frame check sequence")
 
+// A ChecksumError is returned when Frame.UnmarshalFCS detects an incorrect
+// Ethernet frame check sequence.  ChecksumError wraps ErrInvalidFCS, so
+// errors.Is(err, ErrInvalidFCS) reports true for a *ChecksumError.
+type ChecksumError struct {
+	// Want is the frame check sequence present in the byte slice.
+	Want uint32
+
+	// Got is the frame check sequence computed over the frame.
+	Got uint32
+}
+
+// Error implements error.
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("%v: want %#08x, got %#08x", ErrInvalidFCS, e.Want, e.Got)
+}
+
+// Unwrap returns ErrInvalidFCS.
+func (e *ChecksumError) Unwrap() error {
+	return ErrInvalidFCS
+}
+
+// ErrRuntFrame is returned when UnmarshalOptions.Strict is set and a frame is
+// shorter than the minimum Ethernet frame length: 60 bytes, or 64 bytes
+// including the frame check sequence.
+var ErrRuntFrame = errors.New("runt frame")
+
 // An EtherType is a value used to identify an upper layer protocol
 // encapsulated in a Frame.
 //
@@ -46,6 +85,45 @@ const (
 	EtherTypeServiceVLAN EtherType = 0x88a8
 )
 
+// An Encapsulation is the method used to identify the upper layer protocol
+// encapsulated in a Frame.
+type Encapsulation int
+
+// Possible Encapsulation values.
+const (
+	// EncapsulationEthernetII indicates an Ethernet II frame, which uses an
+	// EtherType.
+	EncapsulationEthernetII Encapsulation = iota
+
+	// EncapsulationLLC indicates an IEEE 802.3 frame with an IEEE 802.2 LLC
+	// header.
+	EncapsulationLLC
+
+	// EncapsulationSNAP indicates an IEEE 802.3 frame with LLC and SNAP
+	// headers.
+	EncapsulationSNAP
+
+	// EncapsulationNovellRaw indicates a Novell "raw" IEEE 802.3 frame,
+	// which carries an IPX packet immediately after the length field.
+	EncapsulationNovellRaw
+)
+
+// String returns the name of an Encapsulation.
+func (e Encapsulation) String() string {
+	switch e {
+	case EncapsulationEthernetII:
+		return "Ethernet II"
+	case EncapsulationLLC:
+		return "LLC"
+	case EncapsulationSNAP:
+		return "SNAP"
+	case EncapsulationNovellRaw:
+		return "Novell raw"
+	default:
+		return fmt.Sprintf("Encapsulation(%d)", int(e))
+	}
+}
+
 // A Frame is an IEEE 802.3 Ethernet II frame.  A Frame contains information
 // such as source and destination hardware addresses, zero or more optional
 // 802.1Q VLAN tags, an EtherType, and payload data.
@@ -76,47 +154,188 @@ type Frame struct {
 
 	// EtherType is a value used to identify an upper layer protocol
 	// encapsulated in this Frame.
+	//
+	// If EtherType is less than 0x0600 (1536), the Frame is an IEEE 802.3
+	// frame and EtherType is the length of its LLC header and payload.
 	EtherType EtherType
 
+	// LLC specifies an optional IEEE 802.2 LLC header, which is present in
+	// IEEE 802.3 frames that do not use an EtherType.  If LLC is not nil,
+	// EtherType is ignored when marshaling a Frame, and the length of the
+	// LLC header and payload is computed automatically.
+	//
+	// When unmarshaling, LLC is only set if the length field describes at
+	// least an LLC header, and any bytes beyond the length field are stored
+	// in Padding.
+	LLC *LLC
+
+	// SNAP specifies an optional SNAP header which follows an LLC header.
+	// If SNAP is not nil, LLC must not be nil as well, and should normally
+	// specify SAPSNAP and an unnumbered information control field.
+	SNAP *SNAP
+
+	// Encapsulation reports the encapsulation detected when a Frame is
+	// unmarshaled.
+	//
+	// When marshaling a Frame, Encapsulation is ignored unless it is
+	// EncapsulationNovellRaw, in which case the length of Payload is
+	// computed automatically and stored in place of EtherType.  Otherwise,
+	// the encapsulation is determined by the LLC and SNAP fields.
+	Encapsulation Encapsulation
+
 	// Payload is a variable length data payload encapsulated by this Frame.
 	Payload []byte
+
+	// Padding specifies optional bytes which follow Payload, such as the
+	// padding added to short frames to meet the minimum Ethernet frame
+	// length.  Padding is always marshaled after Payload, and additional
+	// zero padding is added if the frame is still too short.
+	//
+	// When unmarshaling, Padding is only set if the length of Payload is
+	// known: for IEEE 802.3 frames, using the length field, and for
+	// Ethernet II frames, using UnmarshalOptions.PayloadLength.  Otherwise,
+	// any padding remains in Payload.
+	Padding []byte
+}
+
+// Reset clears all fields of a Frame so that it may be reused, such as in a
+// receive loop or with a sync.Pool.  The memory used by the Frame's hardware
+// addresses, payload, and padding is retained, and is reused by the next
+// call to UnmarshalBinary if it is large enough.
+//
+// Slices obtained from a Frame must not be used after calling Reset.
+func (f *Frame) Reset() {
+	*f = Frame{
+		Destination: f.Destination[:0],
+		Source:      f.Source[:0],
+		Payload:     f.Payload[:0],
+		Padding:     f.Padding[:0],
+	}
 }
 
 // MarshalBinary allocates a byte slice and marshals a Frame into binary form.
+//
+// Frames are always zero-padded to the minimum Ethernet frame length of 60
+// bytes, excluding the frame check sequence, so callers need not pad short
+// payloads themselves.
 func (f *Frame) MarshalBinary() ([]byte, error) {
-	b := make([]byte, f.length())
+	b := make([]byte, f.Length())
 	_, err := f.read(b)
 	return b, err
 }
 
+// MarshalTo marshals a Frame into binary form in b, and returns the number
+// of bytes written.  MarshalTo does not allocate, so it may be used with
+// preallocated or pooled buffers.
+//
+// If b is too small to contain the Frame, io.ErrShortBuffer is returned.
+func (f *Frame) MarshalTo(b []byte) (int, error) {
+	n := f.Length()
+	if len(b) < n {
+		return 0, io.ErrShortBuffer
+	}
+
+	return f.read(b[:n])
+}
+
+// AppendBinary implements encoding.BinaryAppender.  AppendBinary marshals a
+// Frame into binary form, as with MarshalBinary, and appends it to b.  If b
+// has sufficient capacity, AppendBinary does not allocate.
+func (f *Frame) AppendBinary(b []byte) ([]byte, error) {
+	n := len(b)
+	b = grow(b, f.Length())
+	if _, err := f.read(b[n:]); err != nil {
+		return b[:n], err
+	}
+
+	return b, nil
+}
+
+// grow extends b by n bytes, reallocating only if b has insufficient
+// capacity.
+func grow(b []byte, n int) []byte {
+	if l := len(b) + n; l <= cap(b) {
+		return b[:l]
+	}
+
+	bb := make([]byte, len(b)+n)
+	copy(bb, b)
+	return bb
+}
+
 // MarshalFCS allocates a byte slice, marshals a Frame into binary form, and
-// finally calculates and places a 4-byte IEEE CRC32 frame check sequence at
-// the end of the slice.
+// places a 4-byte IEEE CRC32 frame check sequence at the end of the slice.
+// The frame check sequence is calculated as the Frame is marshaled.  As
+// with MarshalBinary, frames are zero-padded so that the result is at least
+// 64 bytes.
 //
 // Most users should use MarshalBinary instead.  MarshalFCS is provided as a
 // convenience for rare occasions when the operating system cannot
 // automatically generate a frame check sequence for an Ethernet frame.
 func (f *Frame) MarshalFCS() ([]byte, error) {
 	// Frame length with 4 extra bytes for frame check sequence
-	b := make([]byte, f.length()+4)
-	if _, err := f.read(b); err != nil {
+	b := make([]byte, f.Length()+4)
+	if err := f.readFCS(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalWire allocates a byte slice and marshals a Frame into the complete
+// octet stream transmitted on the wire: the 7 byte preamble and start frame
+// delimiter, followed by the frame and its frame check sequence, as
+// produced by MarshalFCS.
+//
+// MarshalWire is intended for use with FPGAs, simulators, and physical
+// layer test equipment.  Most users should use MarshalBinary instead.
+func (f *Frame) MarshalWire() ([]byte, error) {
+	// Preamble and SFD, frame, and 4 extra bytes for frame check sequence.
+	b := make([]byte, len(preamble)+f.Length()+4)
+	n := copy(b, preamble[:])
+
+	if err := f.readFCS(b[n:]); err != nil {
 		return nil, err
 	}
 
-	// Compute IEEE CRC32 checksum of frame bytes and place it directly
-	// in the last four bytes of the slice
-	binary.BigEndian.PutUint32(b[len(b)-4:], crc32.ChecksumIEEE(b[0:len(b)-4]))
 	return b, nil
 }
 
 // read reads data from a Frame into b.  read is used to marshal a Frame
 // into binary form, but does not allocate on its own.
 func (f *Frame) read(b []byte) (int, error) {
+	n, err := f.readHeader(b)
+	if err != nil {
+		return 0, err
+	}
+
+	// Copy payload and padding into output bytes.
+	n += copy(b[n:], f.Payload)
+	n += copy(b[n:], f.Padding)
+
+	// Zero-pad the remainder, in case b is being reused.
+	zero := b[n:]
+	for i := range zero {
+		zero[i] = 0
+	}
+
+	return len(b), nil
+}
+
+// readHeader reads the hardware addresses, VLAN tags, EtherType, and any
+// LLC and SNAP headers of a Frame into b, and returns the number of bytes
+// read.
+func (f *Frame) readHeader(b []byte) (int, error) {
 	// S-VLAN must also have accompanying C-VLAN.
 	if f.ServiceVLAN != nil && f.VLAN == nil {
 		return 0, ErrInvalidVLAN
 	}
 
+	// SNAP must also have accompanying LLC.
+	if f.SNAP != nil && f.LLC == nil {
+		return 0, ErrInvalidLLC
+	}
+
 	copy(b[0:6], f.Destination)
 	copy(b[6:12], f.Source)
 
@@ -138,52 +357,266 @@ func (f *Frame) read(b []byte) (int, error) {
 		}
 
 		// Add VLAN EtherType and VLAN bytes.
-		binary.BigEndian.PutUint16(b[n:n+2], uint16(vt.tpid))
+		tpid := vt.tpid
+		if vt.vlan.TPID != 0 {
+			tpid = vt.vlan.TPID
+		}
+		binary.BigEndian.PutUint16(b[n:n+2], uint16(tpid))
 		if _, err := vt.vlan.read(b[n+2 : n+4]); err != nil {
 			return 0, err
 		}
 		n += 4
 	}
 
-	// Marshal actual EtherType after any VLANs, copy payload into
-	// output bytes.
-	binary.BigEndian.PutUint16(b[n:n+2], uint16(f.EtherType))
-	copy(b[n+2:], f.Payload)
+	// IEEE 802.3 frames carry a length in place of an EtherType.
+	et := f.EtherType
+	if f.LLC != nil || f.Encapsulation == EncapsulationNovellRaw {
+		et = EtherType(f.llcLength() + len(f.Payload))
+		if et >= minEtherType {
+			return 0, ErrInvalidLength
+		}
+	}
 
-	return len(b), nil
+	// Marshal actual EtherType after any VLANs.
+	binary.BigEndian.PutUint16(b[n:n+2], uint16(et))
+	n += 2
+
+	// Marshal LLC and SNAP headers, if present.
+	if f.LLC != nil {
+		nn, _ := f.LLC.read(b[n:])
+		n += nn
+	}
+	if f.SNAP != nil {
+		nn, _ := f.SNAP.read(b[n:])
+		n += nn
+	}
+
+	return n, nil
 }
 
 // UnmarshalBinary unmarshals a byte slice into a Frame.
+//
+// UnmarshalBinary does not enforce the minimum Ethernet frame length: only
+// the hardware addresses, EtherType, and any VLAN tags must be present.
+// This permits parsing unpadded frames captured on virtual interfaces such
+// as veth, tap, and loopback devices.  To reject short frames, use
+// UnmarshalOptions.Strict.
+//
+// If b is malformed, the returned error is a *FieldError which identifies
+// the offending field and its offset, and wraps an error such as
+// io.ErrUnexpectedEOF or ErrInvalidVLAN.
 func (f *Frame) UnmarshalBinary(b []byte) error {
+	return UnmarshalOptions{}.Unmarshal(b, f)
+}
+
+// UnmarshalOptions specifies options for unmarshaling a Frame.  The zero
+// value of UnmarshalOptions behaves identically to Frame.UnmarshalBinary.
+type UnmarshalOptions struct {
+	// ServiceVLANTPIDs specifies additional EtherTypes which are recognized
+	// as the Tag Protocol Identifier of an outer 802.1ad service VLAN tag,
+	// such as the legacy values 0x9100 and 0x9200 emitted by some switches.
+	// EtherTypeServiceVLAN is always recognized.
+	//
+	// Frames unmarshaled using these TPIDs record the TPID in the
+	// ServiceVLAN's TPID field, so that it is preserved when marshaling.
+	// If one of these TPIDs is not followed by an 802.1Q tag, it introduces
+	// the only VLAN tag of the frame, and is recorded in the VLAN's TPID
+	// field instead.
+	ServiceVLANTPIDs []EtherType
+
+	// PayloadLength, if not nil, is called with the EtherType and payload
+	// of each unmarshaled Ethernet II frame, and returns the length of the
+	// payload without any trailing padding.  Bytes beyond the returned
+	// length are stored in Frame.Padding.  If the returned length is
+	// negative or not smaller than the payload, no padding is removed.
+	//
+	// InferPayloadLength may be used to remove padding from common upper
+	// layer protocols.
+	PayloadLength func(et EtherType, payload []byte) int
+
+	// Strict rejects frames which are shorter than the minimum Ethernet
+	// frame length with ErrRuntFrame, and IEEE 802.3 frames whose length
+	// field exceeds the remaining data with ErrInvalidLength, rather than
+	// following the robustness principle.  Strict is intended for
+	// compliance testing and capture validation.
+	Strict bool
+
+	// MaxVLANTags rejects frames which carry more than MaxVLANTags VLAN
+	// tags with ErrTooManyVLANs.  If zero, a default of eight is used.  If
+	// negative, no limit is applied.
+	//
+	// A Frame can store at most two VLAN tags, so no more than two are ever
+	// parsed.  Without a limit, any further tags are left in the payload,
+	// and the EtherType is set to the TPID of the first of them.
+	MaxVLANTags int
+
+	// MaxLength, if greater than zero, rejects frames which are longer than
+	// MaxLength bytes, excluding the frame check sequence, with
+	// ErrFrameTooLarge.  MaxFrameLength and MaxJumboFrameLength are common
+	// values.
+	MaxLength int
+
+	// ZeroCopy causes the Destination, Source, Payload, and Padding fields
+	// of an unmarshaled Frame to alias the input byte slice, rather than
+	// being copied into newly allocated memory.  The caller must not modify
+	// or reuse the input byte slice while the Frame is in use.
+	ZeroCopy bool
+}
+
+// InferPayloadLength returns the length of an IPv4, IPv6, or Ethernet ARP
+// payload, as specified by the payload's own header.  It returns -1 if et is
+// not one of these EtherTypes or if the header is truncated.
+//
+// InferPayloadLength is intended for use with UnmarshalOptions.PayloadLength.
+func InferPayloadLength(et EtherType, payload []byte) int {
+	switch et {
+	case EtherTypeIPv4:
+		// Total length includes the IPv4 header.
+		if len(payload) >= 20 {
+			return int(binary.BigEndian.Uint16(payload[2:4]))
+		}
+	case EtherTypeIPv6:
+		// Payload length excludes the fixed 40 byte IPv6 header.
+		if len(payload) >= 40 {
+			return 40 + int(binary.BigEndian.Uint16(payload[4:6]))
+		}
+	case EtherTypeARP:
+		// Fixed 8 byte header, followed by sender and target hardware and
+		// protocol addresses.
+		if len(payload) >= 8 {
+			return 8 + 2*int(payload[4]) + 2*int(payload[5])
+		}
+	}
+
+	return -1
+}
+
+// Unmarshal unmarshals a byte slice into Frame f using the options
+// specified in o.
+func (o UnmarshalOptions) Unmarshal(b []byte, f *Frame) error {
+	if o.Strict && len(b) < minFrame {
+		return ErrRuntFrame
+	}
+	if o.MaxLength > 0 && len(b) > o.MaxLength {
+		return ErrFrameTooLarge
+	}
+
 	// Verify that both hardware addresses and a single EtherType are present
 	if len(b) < 14 {
-		return io.ErrUnexpectedEOF
+		return headerError(len(b))
 	}
 
+	// Clear any headers left by a previous call, so that a reused Frame
+	// only carries those present in b.
+	f.ServiceVLAN, f.VLAN = nil, nil
+	f.LLC, f.SNAP = nil, nil
+	f.Encapsulation = EncapsulationEthernetII
+
 	// Track offset in packet for reading data
 	n := 14
 
 	// Continue looping and parsing VLAN tags until no more VLAN EtherType
 	// values are detected
 	et := EtherType(binary.BigEndian.Uint16(b[n-2 : n]))
-	switch et {
-	case EtherTypeServiceVLAN, EtherTypeVLAN:
+	if et == EtherTypeVLAN || o.isServiceVLAN(et) {
+		// A legacy TPID which is not followed by a C-VLAN tag introduces the
+		// only VLAN tag of the frame.
+		service := o.isServiceVLAN(et)
+		if service && et != EtherTypeServiceVLAN && len(b) >= n+4 &&
+			EtherType(binary.BigEndian.Uint16(b[n+2:n+4])) != EtherTypeVLAN {
+			service = false
+		}
+
 		// VLAN type is hinted for further parsing.  An index is returned which
 		// indicates how many bytes were consumed by VLAN tags.
-		nn, err := f.unmarshalVLANs(et, b[n:])
+		nn, err := f.unmarshalVLANs(service, b[n:])
 		if err != nil {
 			return err
 		}
 
+		// Record a non-default TPID so it is preserved when marshaling.
+		if et != EtherTypeVLAN && et != EtherTypeServiceVLAN {
+			if service {
+				f.ServiceVLAN.TPID = et
+			} else {
+				f.VLAN.TPID = et
+			}
+		}
+
 		n += nn
-	default:
+	} else {
 		// No VLANs detected.
 		f.EtherType = et
 	}
 
+	// Count VLAN tags, including a nested tag which follows those parsed.
+	limit := o.MaxVLANTags
+	if limit == 0 {
+		limit = defaultMaxVLANTags
+	}
+	if limit > 0 {
+		tags := (n - 14) / 4
+		if f.EtherType == EtherTypeVLAN || o.isServiceVLAN(f.EtherType) {
+			tags++
+		}
+
+		if tags > limit {
+			return &FieldError{Field: "VLAN", Offset: 12 + 4*limit, Err: ErrTooManyVLANs}
+		}
+	}
+
+	// IEEE 802.3 frames carry a length in place of an EtherType, which must
+	// not exceed the remaining data in strict mode.
+	if l, ok := f.LengthField(); ok && o.Strict && l > len(b[n:]) {
+		return &FieldError{Field: "EtherType", Offset: n - 2, Err: ErrInvalidLength}
+	}
+
+	// If the length describes at least an LLC header, parse it, unless the
+	// frame is a Novell raw frame, whose IPX checksum is always 0xffff.
+	var (
+		data = b[n:]
+		pad  []byte
+	)
+	if l := int(f.EtherType); l < minEtherType && l >= 3 {
+		// Any bytes beyond the length are padding.
+		if l < len(data) {
+			data, pad = data[:l], data[l:]
+		}
+
+		if len(data) >= 2 && data[0] == 0xff && data[1] == 0xff {
+			f.Encapsulation = EncapsulationNovellRaw
+		} else {
+			nn, err := f.unmarshalLLC(n, data)
+			if err != nil {
+				return err
+			}
+
+			data = data[nn:]
+		}
+	} else if f.EtherType >= minEtherType && o.PayloadLength != nil {
+		if l := o.PayloadLength(f.EtherType, data); l >= 0 && l < len(data) {
+			data, pad = data[:l], data[l:]
+		}
+	}
+
+	// Alias b directly if requested, limiting capacity so that appending to
+	// one field cannot overwrite the next.
+	if o.ZeroCopy {
+		f.Destination = b[0:6:6]
+		f.Source = b[6:12:12]
+		f.Payload = data[:len(data):len(data)]
+
+		f.Padding = nil
+		if len(pad) > 0 {
+			f.Padding = pad
+		}
+
+		return nil
+	}
+
 	// Allocate single byte slice to store destination and source hardware
-	// addresses, and payload
-	bb := make([]byte, 6+6+len(b[n:]))
+	// addresses, payload, and padding
+	bb := f.buffer(6 + 6 + len(data) + len(pad))
 	copy(bb[0:6], b[0:6])
 	f.Destination = bb[0:6]
 	copy(bb[6:12], b[6:12])
@@ -193,12 +626,47 @@ func (f *Frame) UnmarshalBinary(b []byte) error {
 	// long as two hardware addresses and an EtherType are present, it
 	// doesn't really matter what is contained in the payload.  We will
 	// follow the "robustness principle".
-	copy(bb[12:], b[n:])
-	f.Payload = bb[12:]
+	pl := 12 + len(data)
+	copy(bb[12:pl], data)
+	f.Payload = bb[12:pl:pl]
+
+	f.Padding = nil
+	if len(pad) > 0 {
+		copy(bb[pl:], pad)
+		f.Padding = bb[pl:]
+	}
 
 	return nil
 }
 
+// buffer returns a byte slice of length n for use when unmarshaling a
+// Frame, reusing the memory retained by Reset if possible.
+func (f *Frame) buffer(n int) []byte {
+	// Destination is the start of the single byte slice allocated by a
+	// previous call to Unmarshal.
+	if len(f.Destination) == 0 && cap(f.Destination) >= n {
+		return f.Destination[:n]
+	}
+
+	return make([]byte, n)
+}
+
+// isServiceVLAN reports whether et is recognized as the TPID of a service
+// VLAN tag.
+func (o UnmarshalOptions) isServiceVLAN(et EtherType) bool {
+	if et == EtherTypeServiceVLAN {
+		return true
+	}
+
+	for _, tpid := range o.ServiceVLANTPIDs {
+		if et == tpid {
+			return true
+		}
+	}
+
+	return false
+}
+
 // UnmarshalFCS computes the IEEE CRC32 frame check sequence of a Frame,
 // verifies it against the checksum present in the byte slice, and finally,
 // unmarshals a byte slice into a Frame.
@@ -207,27 +675,46 @@ func (f *Frame) UnmarshalBinary(b []byte) error {
 // a convenience for rare occasions when the operating system cannot
 // automatically verify a frame check sequence for an Ethernet frame.
 func (f *Frame) UnmarshalFCS(b []byte) error {
+	return UnmarshalOptions{}.UnmarshalFCS(b, f)
+}
+
+// UnmarshalFCS verifies the frame check sequence of a byte slice, and
+// unmarshals it into Frame f using the options specified in o.
+func (o UnmarshalOptions) UnmarshalFCS(b []byte, f *Frame) error {
+	if o.Strict && len(b) < minFrame+4 {
+		return ErrRuntFrame
+	}
+
 	// Must contain enough data for FCS, to avoid panics
 	if len(b) < 4 {
-		return io.ErrUnexpectedEOF
+		return &FieldError{Field: "FCS", Err: io.ErrUnexpectedEOF}
 	}
 
 	// Verify checksum in slice versus newly computed checksum
 	want := binary.BigEndian.Uint32(b[len(b)-4:])
 	got := crc32.ChecksumIEEE(b[0 : len(b)-4])
 	if want != got {
-		return ErrInvalidFCS
+		return &ChecksumError{Want: want, Got: got}
 	}
 
-	return f.UnmarshalBinary(b[0 : len(b)-4])
+	return o.Unmarshal(b[0:len(b)-4], f)
+}
+
+// Length returns the number of bytes required to store a Frame in binary
+// form, as produced by MarshalBinary and MarshalTo, including any VLAN tags
+// and padding.  MarshalFCS produces 4 additional bytes for the frame check
+// sequence.
+func (f *Frame) Length() int {
+	return f.length(true)
 }
 
-// length calculates the number of bytes required to store a Frame.
-func (f *Frame) length() int {
+// length calculates the number of bytes required to store a Frame in binary
+// form, optionally padded to the minimum Ethernet frame length.
+func (f *Frame) length(pad bool) int {
 	// If payload is less than the required minimum length, we zero-pad up to
 	// the required minimum length
-	pl := len(f.Payload)
-	if pl < minPayload {
+	pl := f.llcLength() + len(f.Payload) + len(f.Padding)
+	if pad && pl < minPayload {
 		pl = minPayload
 	}
 
@@ -244,54 +731,126 @@ func (f *Frame) length() int {
 	// 6 bytes: source hardware address
 	// N bytes: VLAN tags (if present)
 	// 2 bytes: EtherType
-	// N bytes: payload length (may be padded)
+	// N bytes: LLC and SNAP headers (if present), payload, and padding
+	//          (may be padded further)
 	return 6 + 6 + vlanLen + 2 + pl
 }
 
-// unmarshalVLANs unmarshals S/C-VLAN tags.  It is assumed that tpid
-// is a valid S/C-VLAN TPID.
-func (f *Frame) unmarshalVLANs(tpid EtherType, b []byte) (int, error) {
+// unmarshalVLANs unmarshals S/C-VLAN tags.  If service is true, an S-VLAN
+// tag is expected first; otherwise, a C-VLAN tag is expected.  b begins
+// immediately after the first TPID, at offset 14 of the frame.
+func (f *Frame) unmarshalVLANs(service bool, b []byte) (int, error) {
+	// Field names for errors, depending on the first tag.
+	field := "VLAN"
+	if service {
+		field = "ServiceVLAN"
+	}
+
 	// 4 or more bytes must remain for valid S/C-VLAN tag and EtherType.
 	if len(b) < 4 {
-		return 0, io.ErrUnexpectedEOF
+		return 0, &FieldError{Field: field, Offset: 12, Err: io.ErrUnexpectedEOF}
 	}
 
 	// Track how many bytes are consumed by VLAN tags.
 	var n int
 
-	switch tpid {
-	case EtherTypeServiceVLAN:
-		vlan := new(VLAN)
-		if err := vlan.UnmarshalBinary(b[n : n+2]); err != nil {
-			return 0, err
+	// Allocate storage for all VLAN tags at once, to avoid an allocation
+	// per tag.
+	var svlan, cvlan *VLAN
+	if service {
+		vs := new([2]VLAN)
+		svlan, cvlan = &vs[0], &vs[1]
+	} else {
+		cvlan = new(VLAN)
+	}
+
+	if service {
+		if err := svlan.UnmarshalBinary(b[n : n+2]); err != nil {
+			return 0, &FieldError{Field: "ServiceVLAN.ID", Offset: 14, Err: err}
 		}
-		f.ServiceVLAN = vlan
+		f.ServiceVLAN = svlan
 
 		// Assume that a C-VLAN immediately trails an S-VLAN.
 		if EtherType(binary.BigEndian.Uint16(b[n+2:n+4])) != EtherTypeVLAN {
-			return 0, ErrInvalidVLAN
+			return 0, &FieldError{Field: "VLAN", Offset: 16, Err: ErrInvalidVLAN}
 		}
 
 		// 4 or more bytes must remain for valid C-VLAN tag and EtherType.
 		n += 4
 		if len(b[n:]) < 4 {
-			return 0, io.ErrUnexpectedEOF
+			return 0, &FieldError{Field: "VLAN", Offset: 16, Err: io.ErrUnexpectedEOF}
 		}
+	}
 
-		// Continue to parse the C-VLAN.
-		fallthrough
-	case EtherTypeVLAN:
-		vlan := new(VLAN)
-		if err := vlan.UnmarshalBinary(b[n : n+2]); err != nil {
-			return 0, err
-		}
+	// Parse the C-VLAN.
+	if err := cvlan.UnmarshalBinary(b[n : n+2]); err != nil {
+		return 0, &FieldError{Field: "VLAN.ID", Offset: 14 + n, Err: err}
+	}
 
-		f.VLAN = vlan
-		f.EtherType = EtherType(binary.BigEndian.Uint16(b[n+2 : n+4]))
-		n += 4
+	f.VLAN = cvlan
+	f.EtherType = EtherType(binary.BigEndian.Uint16(b[n+2 : n+4]))
+	n += 4
+
+	return n, nil
+}
+
+// llcLength calculates the number of bytes required to store a Frame's LLC
+// and SNAP headers.
+func (f *Frame) llcLength() int {
+	var n int
+	if f.LLC != nil {
+		n += f.LLC.length()
+	}
+	if f.SNAP != nil {
+		n += snapLen
+	}
+
+	return n
+}
+
+// unmarshalLLC unmarshals an LLC header and an optional SNAP header, and
+// returns the number of bytes consumed.  off is the offset of b within the
+// frame.
+func (f *Frame) unmarshalLLC(off int, b []byte) (int, error) {
+	llc := new(LLC)
+	n, err := llc.unmarshal(b)
+	if err != nil {
+		return 0, &FieldError{Field: "LLC", Offset: off, Err: err}
+	}
+	f.LLC = llc
+	f.Encapsulation = EncapsulationLLC
+
+	if !llc.isSNAP() {
+		return n, nil
+	}
+
+	// 5 bytes must remain for a SNAP header.
+	if len(b[n:]) < snapLen {
+		return 0, &FieldError{Field: "SNAP", Offset: off + n, Err: io.ErrUnexpectedEOF}
+	}
+
+	snap := new(SNAP)
+	if err := snap.UnmarshalBinary(b[n : n+snapLen]); err != nil {
+		return 0, err
+	}
+	f.SNAP = snap
+	f.Encapsulation = EncapsulationSNAP
+
+	return n + snapLen, nil
+}
+
+// headerError returns a *FieldError for the first header field which is
+// truncated in a frame of length n.
+func headerError(n int) error {
+	fe := &FieldError{Err: io.ErrUnexpectedEOF}
+	switch {
+	case n < 6:
+		fe.Field = "Destination"
+	case n < 12:
+		fe.Field, fe.Offset = "Source", 6
 	default:
-		panic(fmt.Sprintf("unknown VLAN TPID: %04x", tpid))
+		fe.Field, fe.Offset = "EtherType", 12
 	}
 
-	return n, nil
+	return fe
 }
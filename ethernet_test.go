@@ -2,6 +2,9 @@ package ethernet
 
 import (
 	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
 	"io"
 	"net"
 	"reflect"
@@ -103,6 +106,207 @@ func TestFrameMarshalBinary(t *testing.T) {
 	}
 }
 
+func TestFrameMarshalTo(t *testing.T) {
+	f := &Frame{
+		Destination: Broadcast,
+		Source:      net.HardwareAddr{0, 1, 0, 1, 0, 1},
+		VLAN:        &VLAN{ID: 10},
+		EtherType:   EtherTypeARP,
+		Payload:     bytes.Repeat([]byte{1}, 28),
+	}
+
+	want, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	if _, err := f.MarshalTo(make([]byte, len(want)-1)); err != io.ErrShortBuffer {
+		t.Fatalf("unexpected error: %v != %v", io.ErrShortBuffer, err)
+	}
+
+	// Reuse a dirty buffer to ensure padding is zeroed.
+	b := bytes.Repeat([]byte{0xff}, 1500)
+	n, err := f.MarshalTo(b)
+	if err != nil {
+		t.Fatalf("failed to marshal to buffer: %v", err)
+	}
+
+	if got := b[:n]; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected Frame bytes:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestFrameAppendBinary(t *testing.T) {
+	f := &Frame{
+		Destination: Broadcast,
+		Source:      net.HardwareAddr{0, 1, 0, 1, 0, 1},
+		VLAN:        &VLAN{ID: 10},
+		EtherType:   EtherTypeARP,
+		Payload:     bytes.Repeat([]byte{1}, 28),
+	}
+
+	want, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	// Append to a prefix with sufficient capacity, so no allocation occurs.
+	prefix := []byte{0xde, 0xad}
+	buf := make([]byte, len(prefix), 128)
+	copy(buf, prefix)
+
+	b, err := f.AppendBinary(buf)
+	if err != nil {
+		t.Fatalf("failed to append: %v", err)
+	}
+
+	if got := b[:len(prefix)]; !bytes.Equal(prefix, got) {
+		t.Fatalf("unexpected prefix bytes:\n- want: %v\n-  got: %v", prefix, got)
+	}
+	if got := b[len(prefix):]; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected Frame bytes:\n- want: %v\n-  got: %v", want, got)
+	}
+	if &b[0] != &buf[0] {
+		t.Fatal("expected AppendBinary to reuse buffer")
+	}
+
+	// Errors leave the input unmodified.
+	f.ServiceVLAN = &VLAN{}
+	f.VLAN = nil
+	b, err = f.AppendBinary(prefix)
+	if err != ErrInvalidVLAN {
+		t.Fatalf("unexpected error: %v != %v", ErrInvalidVLAN, err)
+	}
+	if !bytes.Equal(prefix, b) {
+		t.Fatalf("unexpected bytes after error:\n- want: %v\n-  got: %v", prefix, b)
+	}
+}
+
+func TestFrameLength(t *testing.T) {
+	tests := []struct {
+		desc string
+		f    *Frame
+		n    int
+	}{
+		{
+			desc: "empty",
+			f:    &Frame{},
+			n:    60,
+		},
+		{
+			desc: "S/C-VLAN, short payload",
+			f: &Frame{
+				ServiceVLAN: &VLAN{},
+				VLAN:        &VLAN{},
+				Payload:     make([]byte, 10),
+			},
+			n: 68,
+		},
+		{
+			desc: "C-VLAN, large payload",
+			f: &Frame{
+				VLAN:    &VLAN{},
+				Payload: make([]byte, 1500),
+			},
+			n: 1518,
+		},
+		{
+			desc: "SNAP, payload and padding",
+			f: &Frame{
+				LLC:     &LLC{DSAP: 0xaa, SSAP: 0xaa, Control: 0x03},
+				SNAP:    &SNAP{},
+				Payload: make([]byte, 100),
+				Padding: make([]byte, 2),
+			},
+			n: 124,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if want, got := tt.n, tt.f.Length(); want != got {
+				t.Fatalf("unexpected length: %d != %d", want, got)
+			}
+
+			b, err := tt.f.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			if want, got := tt.n, len(b); want != got {
+				t.Fatalf("unexpected marshaled length: %d != %d", want, got)
+			}
+
+			fcs, err := tt.f.MarshalFCS()
+			if err != nil {
+				t.Fatalf("failed to marshal with FCS: %v", err)
+			}
+
+			if want, got := tt.n+4, len(fcs); want != got {
+				t.Fatalf("unexpected marshaled length with FCS: %d != %d", want, got)
+			}
+		})
+	}
+}
+
+func TestFrameMarshalMinimumLength(t *testing.T) {
+	tests := []struct {
+		desc string
+		f    *Frame
+	}{
+		{
+			desc: "no payload",
+			f:    &Frame{EtherType: EtherTypeIPv4},
+		},
+		{
+			desc: "short payload",
+			f: &Frame{
+				EtherType: EtherTypeARP,
+				Payload:   bytes.Repeat([]byte{0xff}, 28),
+			},
+		},
+		{
+			desc: "short payload, C-VLAN",
+			f: &Frame{
+				VLAN:      &VLAN{ID: 10},
+				EtherType: EtherTypeARP,
+				Payload:   bytes.Repeat([]byte{0xff}, 28),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := tt.f.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			if len(b) < 60 {
+				t.Fatalf("frame shorter than 60 bytes: %d", len(b))
+			}
+
+			// Padding must be zeros.
+			n := 14 + len(tt.f.Payload)
+			if tt.f.VLAN != nil {
+				n += 4
+			}
+			if want, got := make([]byte, len(b)-n), b[n:]; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected padding:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			fcs, err := tt.f.MarshalFCS()
+			if err != nil {
+				t.Fatalf("failed to marshal with FCS: %v", err)
+			}
+
+			if len(fcs) < 64 {
+				t.Fatalf("frame with FCS shorter than 64 bytes: %d", len(fcs))
+			}
+		})
+	}
+}
+
 func TestFrameMarshalFCS(t *testing.T) {
 	tests := []struct {
 		desc string
@@ -150,6 +354,34 @@ func TestFrameMarshalFCS(t *testing.T) {
 	}
 }
 
+func TestFrameMarshalWire(t *testing.T) {
+	f := &Frame{
+		Destination: net.HardwareAddr{0, 1, 0, 1, 0, 1},
+		Source:      net.HardwareAddr{1, 0, 1, 0, 1, 0},
+		EtherType:   EtherTypeIPv4,
+		Payload:     bytes.Repeat([]byte{0}, 50),
+	}
+
+	fcs, err := f.MarshalFCS()
+	if err != nil {
+		t.Fatalf("failed to marshal with FCS: %v", err)
+	}
+
+	b, err := f.MarshalWire()
+	if err != nil {
+		t.Fatalf("failed to marshal wire format: %v", err)
+	}
+
+	want := append([]byte{0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0x55, 0xd5}, fcs...)
+	if got := b; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected wire bytes:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	if _, err := (&Frame{ServiceVLAN: &VLAN{}}).MarshalWire(); err != ErrInvalidVLAN {
+		t.Fatalf("unexpected error: %v != %v", ErrInvalidVLAN, err)
+	}
+}
+
 func TestFrameUnmarshalBinary(t *testing.T) {
 	tests := []struct {
 		desc string
@@ -226,6 +458,25 @@ func TestFrameUnmarshalBinary(t *testing.T) {
 			b:    []byte("190734863281\x81\x0032"),
 			err:  io.ErrUnexpectedEOF,
 		},
+		{
+			desc: "unpadded ARP, C-VLAN: (PRI 0, ID 10)",
+			b: append([]byte{
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0, 1, 0, 1, 0, 1,
+				0x81, 0x00,
+				0x00, 0x0a,
+				0x08, 0x06,
+			}, bytes.Repeat([]byte{1}, 28)...),
+			f: &Frame{
+				Destination: Broadcast,
+				Source:      net.HardwareAddr{0, 1, 0, 1, 0, 1},
+				VLAN: &VLAN{
+					ID: 10,
+				},
+				EtherType: EtherTypeARP,
+				Payload:   bytes.Repeat([]byte{1}, 28),
+			},
+		},
 		{
 			desc: "0 VLANs detected, but 1 may have been present",
 			b:    bytes.Repeat([]byte{0}, 56),
@@ -301,7 +552,7 @@ func TestFrameUnmarshalBinary(t *testing.T) {
 		t.Run(tt.desc, func(t *testing.T) {
 			f := new(Frame)
 			if err := f.UnmarshalBinary(tt.b); err != nil {
-				if want, got := tt.err, err; want != got {
+				if want, got := tt.err, err; !errors.Is(got, want) {
 					t.Fatalf("unexpected error: %v != %v", want, got)
 				}
 
@@ -315,6 +566,225 @@ func TestFrameUnmarshalBinary(t *testing.T) {
 	}
 }
 
+func TestUnmarshalOptionsServiceVLANTPIDs(t *testing.T) {
+	b := func(tpid ...byte) []byte {
+		return append([]byte{
+			0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+			0, 1, 0, 1, 0, 1,
+			tpid[0], tpid[1],
+			0x00, 0x64,
+			0x81, 0x00,
+			0x00, 0x65,
+			0x08, 0x06,
+		}, bytes.Repeat([]byte{0}, 50)...)
+	}
+
+	want := &Frame{
+		Destination: Broadcast,
+		Source:      net.HardwareAddr{0, 1, 0, 1, 0, 1},
+		ServiceVLAN: &VLAN{ID: 100},
+		VLAN:        &VLAN{ID: 101},
+		EtherType:   EtherTypeARP,
+		Payload:     bytes.Repeat([]byte{0}, 50),
+	}
+
+	withTPID := func(tpid EtherType) *Frame {
+		f := *want
+		f.ServiceVLAN = &VLAN{ID: 100, TPID: tpid}
+		return &f
+	}
+
+	o := UnmarshalOptions{
+		ServiceVLANTPIDs: []EtherType{0x9100, 0x9200},
+	}
+
+	tests := []struct {
+		desc string
+		o    UnmarshalOptions
+		b    []byte
+		f    *Frame
+	}{
+		{
+			desc: "0x9100 not recognized",
+			b:    b(0x91, 0x00),
+			f: &Frame{
+				Destination: Broadcast,
+				Source:      net.HardwareAddr{0, 1, 0, 1, 0, 1},
+				EtherType:   0x9100,
+				Payload: append([]byte{
+					0x00, 0x64,
+					0x81, 0x00,
+					0x00, 0x65,
+					0x08, 0x06,
+				}, bytes.Repeat([]byte{0}, 50)...),
+			},
+		},
+		{
+			desc: "0x9100",
+			o:    o,
+			b:    b(0x91, 0x00),
+			f:    withTPID(0x9100),
+		},
+		{
+			desc: "0x9200",
+			o:    o,
+			b:    b(0x92, 0x00),
+			f:    withTPID(0x9200),
+		},
+		{
+			desc: "0x9100 single tag",
+			o:    o,
+			b: append([]byte{
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0, 1, 0, 1, 0, 1,
+				0x91, 0x00,
+				0x00, 0x65,
+				0x08, 0x06,
+			}, bytes.Repeat([]byte{0}, 50)...),
+			f: &Frame{
+				Destination: Broadcast,
+				Source:      net.HardwareAddr{0, 1, 0, 1, 0, 1},
+				VLAN:        &VLAN{ID: 101, TPID: 0x9100},
+				EtherType:   EtherTypeARP,
+				Payload:     bytes.Repeat([]byte{0}, 50),
+			},
+		},
+		{
+			desc: "0x88a8 always recognized",
+			o:    o,
+			b:    b(0x88, 0xa8),
+			f:    want,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			f := new(Frame)
+			if err := tt.o.Unmarshal(tt.b, f); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+
+			if want, got := tt.f, f; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected Frame:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			// TPIDs are preserved when marshaling.
+			b, err := f.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			if want, got := tt.b, b; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected Frame bytes:\n- want: %v\n-  got: %v", want, got)
+			}
+		})
+	}
+}
+
+func TestUnmarshalOptionsMaxVLANTags(t *testing.T) {
+	// Three stacked VLAN tags.
+	b := append([]byte{
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0, 1, 0, 1, 0, 1,
+		0x88, 0xa8, 0x00, 0x64,
+		0x81, 0x00, 0x00, 0x65,
+		0x81, 0x00, 0x00, 0x66,
+		0x08, 0x00,
+	}, make([]byte, 46)...)
+
+	tests := []struct {
+		desc   string
+		max    int
+		b      []byte
+		offset int
+		ok     bool
+	}{
+		{
+			desc: "no limit",
+			max:  -1,
+			b:    b,
+			ok:   true,
+		},
+		{
+			desc: "nested tag within default limit",
+			b:    b,
+			ok:   true,
+		},
+		{
+			desc: "Q-in-Q within default limit",
+			b:    append(append([]byte(nil), b[:20]...), b[24:]...),
+			ok:   true,
+		},
+		{
+			desc:   "nested tag exceeds limit",
+			max:    2,
+			b:      b,
+			offset: 20,
+		},
+		{
+			desc: "Q-in-Q within limit",
+			max:  2,
+			b:    append(append([]byte(nil), b[:20]...), b[24:]...),
+			ok:   true,
+		},
+		{
+			desc:   "Q-in-Q exceeds limit",
+			max:    1,
+			b:      append(append([]byte(nil), b[:20]...), b[24:]...),
+			offset: 16,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			err := UnmarshalOptions{MaxVLANTags: tt.max}.Unmarshal(tt.b, new(Frame))
+			if tt.ok {
+				if err != nil {
+					t.Fatalf("failed to unmarshal: %v", err)
+				}
+
+				return
+			}
+
+			var fe *FieldError
+			if !errors.As(err, &fe) || !errors.Is(err, ErrTooManyVLANs) {
+				t.Fatalf("expected too many VLANs *FieldError, but got: %#v", err)
+			}
+			if want, got := tt.offset, fe.Offset; want != got {
+				t.Fatalf("unexpected offset: %d != %d", want, got)
+			}
+		})
+	}
+}
+
+func TestFrameUnmarshalBinaryTripleTagged(t *testing.T) {
+	b := append([]byte{
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0, 1, 0, 1, 0, 1,
+		0x88, 0xa8, 0x00, 0x64,
+		0x81, 0x00, 0x00, 0x65,
+		0x81, 0x00, 0x00, 0x66,
+		0x08, 0x00,
+	}, make([]byte, 46)...)
+
+	f := new(Frame)
+	if err := f.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	want := &Frame{
+		Destination: Broadcast,
+		Source:      net.HardwareAddr{0, 1, 0, 1, 0, 1},
+		ServiceVLAN: &VLAN{ID: 100},
+		VLAN:        &VLAN{ID: 101},
+		EtherType:   EtherTypeVLAN,
+		Payload:     b[22:],
+	}
+	if got := f; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected Frame:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
 func TestFrameUnmarshalFCS(t *testing.T) {
 	tests := []struct {
 		desc string
@@ -358,7 +828,7 @@ func TestFrameUnmarshalFCS(t *testing.T) {
 		t.Run(tt.desc, func(t *testing.T) {
 			f := new(Frame)
 			if err := f.UnmarshalFCS(tt.b); err != nil {
-				if want, got := tt.err, err; want != got {
+				if want, got := tt.err, err; !errors.Is(got, want) {
 					t.Fatalf("unexpected error: %v != %v", want, got)
 				}
 
@@ -372,6 +842,103 @@ func TestFrameUnmarshalFCS(t *testing.T) {
 	}
 }
 
+func TestFrameUnmarshalFCSChecksumError(t *testing.T) {
+	b, err := (&Frame{EtherType: EtherTypeIPv4}).MarshalFCS()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	// Corrupt the final byte of the FCS.
+	want := binary.BigEndian.Uint32(b[len(b)-4:])
+	b[len(b)-1] ^= 0x01
+
+	err = new(Frame).UnmarshalFCS(b)
+
+	var cerr *ChecksumError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("expected *ChecksumError, but got: %#v", err)
+	}
+
+	if !errors.Is(err, ErrInvalidFCS) {
+		t.Fatalf("expected %v in %v", ErrInvalidFCS, err)
+	}
+
+	if want, got := (ChecksumError{Want: want ^ 0x01, Got: want}), *cerr; want != got {
+		t.Fatalf("unexpected checksums: %#v != %#v", want, got)
+	}
+}
+
+func TestMaxLength(t *testing.T) {
+	jumbo := &Frame{
+		Destination: Broadcast,
+		Source:      net.HardwareAddr{0, 1, 0, 1, 0, 1},
+		EtherType:   EtherTypeIPv4,
+		Payload:     make([]byte, 9000),
+	}
+
+	tests := []struct {
+		desc string
+		max  int
+		ok   bool
+	}{
+		{
+			desc: "no maximum",
+			ok:   true,
+		},
+		{
+			desc: "standard",
+			max:  MaxFrameLength,
+		},
+		{
+			desc: "jumbo",
+			max:  MaxJumboFrameLength,
+			ok:   true,
+		},
+		{
+			desc: "exact",
+			max:  9014,
+			ok:   true,
+		},
+		{
+			desc: "one byte short",
+			max:  9013,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			want := ErrFrameTooLarge
+			if tt.ok {
+				want = nil
+			}
+
+			b, err := jumbo.Marshal(WithMaxLength(tt.max))
+			if got := err; want != got {
+				t.Fatalf("unexpected marshal error: %v != %v", want, got)
+			}
+
+			_, err = jumbo.Marshal(WithMaxLength(tt.max), WithFCS())
+			if got := err; want != got {
+				t.Fatalf("unexpected marshal FCS error: %v != %v", want, got)
+			}
+
+			if want, got := !tt.ok, errors.Is(jumbo.Validate(WithMaxLength(tt.max)), ErrFrameTooLarge); want != got {
+				t.Fatalf("unexpected frame too large validation error: %v != %v", want, got)
+			}
+
+			if b == nil {
+				// Marshal without restrictions to test unmarshaling.
+				b, _ = jumbo.MarshalBinary()
+			}
+
+			o := UnmarshalOptions{MaxLength: tt.max}
+			if got := o.Unmarshal(b, new(Frame)); want != got {
+				t.Fatalf("unexpected unmarshal error: %v != %v", want, got)
+			}
+		})
+	}
+}
+
 // Benchmarks for Frame.MarshalBinary with varying VLAN tags and payloads
 
 func BenchmarkFrameMarshalBinary(b *testing.B) {
@@ -431,6 +998,24 @@ func benchmarkFrameMarshalBinary(b *testing.B, f *Frame) {
 	}
 }
 
+func BenchmarkFrameMarshalTo(b *testing.B) {
+	f := &Frame{
+		Destination: net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+		Source:      net.HardwareAddr{0xad, 0xbe, 0xef, 0xde, 0xad, 0xde},
+		Payload:     []byte{0, 1, 2, 3, 4},
+	}
+
+	buf := make([]byte, 1514)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := f.MarshalTo(buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 // Benchmarks for Frame.MarshalFCS
 
 func BenchmarkFrameMarshalFCS(b *testing.B) {
@@ -519,6 +1104,29 @@ func benchmarkFrameUnmarshalBinary(b *testing.B, f *Frame) {
 	}
 }
 
+func BenchmarkFrameUnmarshalZeroCopy(b *testing.B) {
+	f := &Frame{
+		Destination: net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+		Source:      net.HardwareAddr{0xad, 0xbe, 0xef, 0xde, 0xad, 0xde},
+		Payload:     []byte{0, 1, 2, 3, 4},
+	}
+
+	fb, err := f.MarshalBinary()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	o := UnmarshalOptions{ZeroCopy: true}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := o.Unmarshal(fb, f); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 // Benchmarks for Frame.UnmarshalFCS
 
 func BenchmarkFrameUnmarshalFCS(b *testing.B) {
@@ -546,3 +1154,408 @@ func benchmarkFrameUnmarshalFCS(b *testing.B, f *Frame) {
 		}
 	}
 }
+
+func TestUnmarshalOptionsZeroCopy(t *testing.T) {
+	b := append([]byte{
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0, 1, 0, 1, 0, 1,
+		0x08, 0x06,
+	}, bytes.Repeat([]byte{1}, 28)...)
+
+	f := new(Frame)
+	o := UnmarshalOptions{ZeroCopy: true}
+	if err := o.Unmarshal(b, f); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	want := &Frame{
+		Destination: Broadcast,
+		Source:      net.HardwareAddr{0, 1, 0, 1, 0, 1},
+		EtherType:   EtherTypeARP,
+		Payload:     bytes.Repeat([]byte{1}, 28),
+	}
+	if got := f; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected Frame:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	// Modifications to the input must be visible in the Frame.
+	b[0] = 0xfe
+	b[6] = 0xfe
+	b[14] = 0xfe
+
+	if f.Destination[0] != 0xfe || f.Source[0] != 0xfe || f.Payload[0] != 0xfe {
+		t.Fatalf("Frame does not alias input bytes: %v", f)
+	}
+
+	// Appending to a field must not overwrite the next.
+	_ = append(f.Destination, 0xff)
+	if want, got := byte(0xfe), b[6]; want != got {
+		t.Fatalf("append overwrote input bytes: %#x != %#x", want, got)
+	}
+}
+
+func TestFrameReset(t *testing.T) {
+	b := append([]byte{
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0, 1, 0, 1, 0, 1,
+		0x81, 0x00,
+		0x00, 0x0a,
+		0x08, 0x06,
+	}, bytes.Repeat([]byte{1}, 46)...)
+
+	f := new(Frame)
+	if err := f.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	dst := &f.Destination[0]
+
+	f.Reset()
+
+	if f.VLAN != nil || f.EtherType != 0 || len(f.Destination) != 0 || len(f.Payload) != 0 {
+		t.Fatalf("Frame was not reset: %v", f)
+	}
+
+	out, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	empty, err := (&Frame{}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal empty Frame: %v", err)
+	}
+
+	if want, got := empty, out; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected reset Frame bytes:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	// Unmarshal a shorter frame, which should reuse the existing memory.
+	b = b[:40]
+	b[12], b[13] = 0x08, 0x00
+	if err := f.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if dst != &f.Destination[0] {
+		t.Fatal("Frame memory was not reused")
+	}
+
+	want := &Frame{
+		Destination: Broadcast,
+		Source:      net.HardwareAddr{0, 1, 0, 1, 0, 1},
+		EtherType:   EtherTypeIPv4,
+		Payload:     b[14:],
+	}
+	if got := f; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected Frame:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestFrameUnmarshalBinaryReuse(t *testing.T) {
+	llc := append([]byte{
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0, 1, 0, 1, 0, 1,
+		0x81, 0x00,
+		0x00, 0x0a,
+		0x00, 0x35,
+		0x42, 0x42, 0x03,
+	}, bytes.Repeat([]byte{1}, 50)...)
+
+	ipv4 := append([]byte{
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0, 1, 0, 1, 0, 1,
+		0x08, 0x00,
+	}, bytes.Repeat([]byte{1}, 46)...)
+
+	f := new(Frame)
+	if err := f.UnmarshalBinary(llc); err != nil {
+		t.Fatalf("failed to unmarshal LLC frame: %v", err)
+	}
+	if f.LLC == nil || f.VLAN == nil {
+		t.Fatalf("expected LLC and VLAN headers: %v", f)
+	}
+
+	if err := f.UnmarshalBinary(ipv4); err != nil {
+		t.Fatalf("failed to unmarshal Ethernet II frame: %v", err)
+	}
+
+	want := &Frame{
+		Destination: Broadcast,
+		Source:      net.HardwareAddr{0, 1, 0, 1, 0, 1},
+		EtherType:   EtherTypeIPv4,
+		Payload:     ipv4[14:],
+	}
+	if got := f; !want.Equal(got) {
+		t.Fatalf("unexpected Frame:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	b, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	if want, got := ipv4, b; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected Frame bytes:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestFramePadding(t *testing.T) {
+	// An ARP request followed by non-zero padding, as transmitted by some
+	// devices.
+	arp := []byte{
+		0x00, 0x01, 0x08, 0x00, 0x06, 0x04, 0x00, 0x01,
+		0, 1, 0, 1, 0, 1, 192, 0, 2, 1,
+		0, 0, 0, 0, 0, 0, 192, 0, 2, 2,
+	}
+	pad := bytes.Repeat([]byte{0xaa}, 18)
+
+	b := append(append([]byte{
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0, 1, 0, 1, 0, 1,
+		0x08, 0x06,
+	}, arp...), pad...)
+
+	tests := []struct {
+		desc string
+		o    UnmarshalOptions
+		f    *Frame
+	}{
+		{
+			desc: "no payload length",
+			f: &Frame{
+				Destination: Broadcast,
+				Source:      net.HardwareAddr{0, 1, 0, 1, 0, 1},
+				EtherType:   EtherTypeARP,
+				Payload:     append(append([]byte(nil), arp...), pad...),
+			},
+		},
+		{
+			desc: "infer payload length",
+			o: UnmarshalOptions{
+				PayloadLength: InferPayloadLength,
+			},
+			f: &Frame{
+				Destination: Broadcast,
+				Source:      net.HardwareAddr{0, 1, 0, 1, 0, 1},
+				EtherType:   EtherTypeARP,
+				Payload:     arp,
+				Padding:     pad,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			f := new(Frame)
+			if err := tt.o.Unmarshal(b, f); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+
+			if want, got := tt.f, f; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected Frame:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			out, err := f.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			if want, got := b, out; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected Frame bytes:\n- want: %v\n-  got: %v", want, got)
+			}
+		})
+	}
+}
+
+func TestInferPayloadLength(t *testing.T) {
+	ipv4 := make([]byte, 46)
+	ipv4[3] = 28
+
+	ipv6 := make([]byte, 60)
+	ipv6[5] = 8
+
+	tests := []struct {
+		desc string
+		et   EtherType
+		b    []byte
+		n    int
+	}{
+		{
+			desc: "unknown EtherType",
+			et:   0x88b5,
+			b:    make([]byte, 46),
+			n:    -1,
+		},
+		{
+			desc: "short IPv4",
+			et:   EtherTypeIPv4,
+			b:    make([]byte, 19),
+			n:    -1,
+		},
+		{
+			desc: "IPv4",
+			et:   EtherTypeIPv4,
+			b:    ipv4,
+			n:    28,
+		},
+		{
+			desc: "IPv6",
+			et:   EtherTypeIPv6,
+			b:    ipv6,
+			n:    48,
+		},
+		{
+			desc: "ARP",
+			et:   EtherTypeARP,
+			b:    []byte{0x00, 0x01, 0x08, 0x00, 0x06, 0x04, 0x00, 0x01},
+			n:    28,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if want, got := tt.n, InferPayloadLength(tt.et, tt.b); want != got {
+				t.Fatalf("unexpected payload length: %d != %d", want, got)
+			}
+		})
+	}
+}
+
+func TestUnmarshalOptionsStrict(t *testing.T) {
+	frame := func(n int) []byte {
+		return append([]byte{
+			0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+			0, 1, 0, 1, 0, 1,
+			0x08, 0x06,
+		}, make([]byte, n-14)...)
+	}
+
+	withFCS := func(b []byte) []byte {
+		fcs := make([]byte, 4)
+		binary.BigEndian.PutUint32(fcs, crc32.ChecksumIEEE(b))
+		return append(b, fcs...)
+	}
+
+	tests := []struct {
+		desc   string
+		b      []byte
+		fcs    bool
+		strict bool
+		err    error
+	}{
+		{
+			desc: "runt, not strict",
+			b:    frame(42),
+		},
+		{
+			desc:   "runt, strict",
+			b:      frame(42),
+			strict: true,
+			err:    ErrRuntFrame,
+		},
+		{
+			desc:   "short header, strict",
+			b:      frame(14)[:13],
+			strict: true,
+			err:    ErrRuntFrame,
+		},
+		{
+			desc:   "minimum length, strict",
+			b:      frame(60),
+			strict: true,
+		},
+		{
+			desc:   "runt with FCS, strict",
+			b:      withFCS(frame(59)),
+			fcs:    true,
+			strict: true,
+			err:    ErrRuntFrame,
+		},
+		{
+			desc:   "minimum length with FCS, strict",
+			b:      withFCS(frame(60)),
+			fcs:    true,
+			strict: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			o := UnmarshalOptions{Strict: tt.strict}
+
+			unmarshal := o.Unmarshal
+			if tt.fcs {
+				unmarshal = o.UnmarshalFCS
+			}
+
+			if want, got := tt.err, unmarshal(tt.b, new(Frame)); want != got {
+				t.Fatalf("unexpected error: %v != %v", want, got)
+			}
+		})
+	}
+}
+
+func TestFrameUnmarshalFieldError(t *testing.T) {
+	tests := []struct {
+		desc string
+		b    []byte
+		fe   *FieldError
+	}{
+		{
+			desc: "short source",
+			b:    make([]byte, 8),
+			fe: &FieldError{
+				Field:  "Source",
+				Offset: 6,
+				Err:    io.ErrUnexpectedEOF,
+			},
+		},
+		{
+			desc: "C-VLAN ID too large after S-VLAN",
+			b: []byte{
+				0, 0, 0, 0, 0, 0,
+				0, 0, 0, 0, 0, 0,
+				0x88, 0xa8,
+				0x00, 0x64,
+				0x81, 0x00,
+				0x0f, 0xff,
+				0x08, 0x00,
+			},
+			fe: &FieldError{
+				Field:  "VLAN.ID",
+				Offset: 18,
+				Err:    ErrInvalidVLAN,
+			},
+		},
+		{
+			desc: "short LLC control",
+			b: []byte{
+				0, 0, 0, 0, 0, 0,
+				0, 0, 0, 0, 0, 0,
+				0x00, 0x03,
+				0xf0, 0xf0, 0x00,
+			},
+			fe: &FieldError{
+				Field:  "LLC",
+				Offset: 14,
+				Err:    io.ErrUnexpectedEOF,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			err := new(Frame).UnmarshalBinary(tt.b)
+
+			var fe *FieldError
+			if !errors.As(err, &fe) {
+				t.Fatalf("expected *FieldError, but got: %#v", err)
+			}
+
+			if want, got := tt.fe, fe; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected FieldError:\n- want: %v\n-  got: %v", want, got)
+			}
+		})
+	}
+}
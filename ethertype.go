@@ -0,0 +1,274 @@
+package ethernet
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Additional IANA-assigned EtherType values.  See
+// http://www.iana.org/assignments/ieee-802-numbers/ieee-802-numbers.xhtml.
+const (
+	EtherTypeWakeOnLAN      EtherType = 0x0842 // Wake-on-LAN magic packet
+	EtherTypeTRILL          EtherType = 0x22f3 // IETF TRILL
+	EtherTypeTEB            EtherType = 0x6558 // Transparent Ethernet Bridging, used with GRE
+	EtherTypeRARP           EtherType = 0x8035 // Reverse ARP
+	EtherTypeAppleTalk      EtherType = 0x809b // AppleTalk (EtherTalk)
+	EtherTypeIPX            EtherType = 0x8137 // Novell IPX
+	EtherTypeFlowControl    EtherType = 0x8808 // IEEE 802.3 MAC control, such as PAUSE and PFC
+	EtherTypeSlowProtocols  EtherType = 0x8809 // IEEE 802.3 slow protocols, such as LACP and OAM
+	EtherTypeMPLSUnicast    EtherType = 0x8847 // MPLS unicast
+	EtherTypeMPLSMulticast  EtherType = 0x8848 // MPLS multicast
+	EtherTypePPPoEDiscovery EtherType = 0x8863 // PPPoE discovery stage
+	EtherTypePPPoESession   EtherType = 0x8864 // PPPoE session stage
+	EtherTypeEAPOL          EtherType = 0x888e // IEEE 802.1X EAP over LAN
+	EtherTypePROFINET       EtherType = 0x8892 // PROFINET
+	EtherTypeEtherCAT       EtherType = 0x88a4 // EtherCAT
+	EtherTypeGOOSE          EtherType = 0x88b8 // IEC 61850 GOOSE
+	EtherTypeSampledValues  EtherType = 0x88ba // IEC 61850 Sampled Values
+	EtherTypeLLDP           EtherType = 0x88cc // IEEE 802.1AB Link Layer Discovery Protocol
+	EtherTypeMACsec         EtherType = 0x88e5 // IEEE 802.1AE MAC security
+	EtherTypePTP            EtherType = 0x88f7 // IEEE 1588 Precision Time Protocol
+	EtherTypeCFM            EtherType = 0x8902 // IEEE 802.1ag Connectivity Fault Management and ITU-T Y.1731
+	EtherTypeFCoE           EtherType = 0x8906 // Fibre Channel over Ethernet
+	EtherTypeFIP            EtherType = 0x8914 // FCoE Initialization Protocol
+	EtherTypeECTP           EtherType = 0x9000 // Ethernet Configuration Testing Protocol (loopback)
+	EtherTypeQinQ           EtherType = 0x9100 // Legacy VLAN stacking TPID
+)
+
+// namedEtherTypes are the EtherTypes whose stringer names are registered in
+// etherTypes.
+var namedEtherTypes = []EtherType{
+	EtherTypeIPv4,
+	EtherTypeARP,
+	EtherTypeIPv6,
+	EtherTypeVLAN,
+	EtherTypeServiceVLAN,
+	EtherTypePBB,
+	EtherTypeRTag,
+	EtherTypeHSR,
+	EtherTypeWakeOnLAN,
+	EtherTypeTRILL,
+	EtherTypeTEB,
+	EtherTypeRARP,
+	EtherTypeAppleTalk,
+	EtherTypeIPX,
+	EtherTypeFlowControl,
+	EtherTypeSlowProtocols,
+	EtherTypeMPLSUnicast,
+	EtherTypeMPLSMulticast,
+	EtherTypePPPoEDiscovery,
+	EtherTypePPPoESession,
+	EtherTypeEAPOL,
+	EtherTypePROFINET,
+	EtherTypeEtherCAT,
+	EtherTypeGOOSE,
+	EtherTypeSampledValues,
+	EtherTypeLLDP,
+	EtherTypeMACsec,
+	EtherTypePTP,
+	EtherTypeCFM,
+	EtherTypeFCoE,
+	EtherTypeFIP,
+	EtherTypeECTP,
+	EtherTypeQinQ,
+}
+
+// etherTypes is the registry of EtherType names.
+var etherTypes = newRegistry(namedEtherTypes)
+
+// ErrNoDecoder is returned by Frame.DecodePayload when no PayloadDecoder is
+// registered for a Frame's EtherType.
+var ErrNoDecoder = errors.New("no payload decoder registered")
+
+// A PayloadDecoder decodes the payload of a Frame with a registered
+// EtherType into a protocol-specific value.  A PayloadDecoder must be safe
+// for concurrent use.
+type PayloadDecoder func(payload []byte) (interface{}, error)
+
+// A registry maps EtherTypes to short names and payload decoders, and short
+// names to EtherTypes.  A registry is safe for concurrent use.
+type registry struct {
+	mu       sync.RWMutex
+	byType   map[EtherType]string
+	decoders map[EtherType]PayloadDecoder
+
+	// byName is keyed by lower case names.
+	byName map[string]EtherType
+}
+
+// newRegistry creates a registry containing ets, named using the short form
+// of their stringer names, such as "IPv4".
+func newRegistry(ets []EtherType) *registry {
+	r := &registry{
+		byType:   make(map[EtherType]string, len(ets)),
+		decoders: make(map[EtherType]PayloadDecoder),
+		byName:   make(map[string]EtherType, len(ets)),
+	}
+
+	for _, et := range ets {
+		name, _ := builtinName(et)
+		r.add(et, name)
+	}
+
+	return r
+}
+
+// add adds et to the registry with the specified name, replacing any
+// existing name for et.  The stringer names of EtherTypes declared by this
+// package are never removed, so that they always resolve.  The caller must
+// hold r.mu or have exclusive access to r.
+func (r *registry) add(et EtherType, name string) {
+	if old, ok := r.byType[et]; ok {
+		if builtin, ok := builtinName(et); !ok || old != builtin {
+			delete(r.byName, strings.ToLower(old))
+		}
+	}
+
+	r.byType[et] = name
+	r.byName[strings.ToLower(name)] = et
+}
+
+// RegisterEtherType registers a name and optional PayloadDecoder for an
+// EtherType, such as a local experimental EtherType like 0x88B5.  Registered
+// names are used by EtherType.String, Frame.String, MarshalText,
+// UnmarshalText, and EtherTypeByName.  If decode is not nil, it is used by
+// Frame.DecodePayload and Frame.Validate.
+//
+// Registering an EtherType which is already registered replaces its name
+// and decoder, although the names of EtherTypes declared by this package
+// are still accepted by UnmarshalText and EtherTypeByName.  It is an error
+// to register an EtherType which is an IEEE 802.3 length, or to register a
+// name which is used by another EtherType.
+func RegisterEtherType(et EtherType, name string, decode PayloadDecoder) error {
+	if et < minEtherType {
+		return ErrInvalidEtherType
+	}
+	if name == "" {
+		return fmt.Errorf("invalid EtherType name %q", name)
+	}
+
+	etherTypes.mu.Lock()
+	defer etherTypes.mu.Unlock()
+
+	if v, ok := etherTypes.byName[strings.ToLower(name)]; ok && v != et {
+		return fmt.Errorf("EtherType name %q already registered", name)
+	}
+
+	etherTypes.add(et, name)
+
+	delete(etherTypes.decoders, et)
+	if decode != nil {
+		etherTypes.decoders[et] = decode
+	}
+
+	return nil
+}
+
+// DecodePayload decodes a Frame's payload using the PayloadDecoder registered
+// for its EtherType.  If no PayloadDecoder is registered, ErrNoDecoder is
+// returned.
+func (f *Frame) DecodePayload() (interface{}, error) {
+	decode, ok := decoder(f.EtherType)
+	if !ok {
+		return nil, ErrNoDecoder
+	}
+
+	return decode(f.Payload)
+}
+
+// decoder returns the PayloadDecoder registered for et, if any.
+func decoder(et EtherType) (PayloadDecoder, bool) {
+	etherTypes.mu.RLock()
+	defer etherTypes.mu.RUnlock()
+
+	decode, ok := etherTypes.decoders[et]
+	return decode, ok
+}
+
+// EtherTypeByName returns the EtherType with the specified short name, such
+// as "IPv4" or "LLDP", without regard to case, and reports whether a
+// matching EtherType was found.
+func EtherTypeByName(name string) (EtherType, bool) {
+	etherTypes.mu.RLock()
+	defer etherTypes.mu.RUnlock()
+
+	et, ok := etherTypes.byName[strings.ToLower(name)]
+	return et, ok
+}
+
+// String returns the name of an EtherType.  EtherTypes declared by this
+// package use their stringer names, such as "EtherTypeIPv4".  Other
+// EtherTypes which are registered using RegisterEtherType are named in the
+// same way, such as "EtherTypeExperimental", and all others are formatted
+// as "EtherType(n)".
+func (et EtherType) String() string {
+	if _, ok := builtinName(et); !ok {
+		if name, ok := etherTypeName(et); ok {
+			return "EtherType" + name
+		}
+	}
+
+	return et.builtinString()
+}
+
+// builtinName returns the short form of the stringer name of an EtherType
+// declared by this package, such as "IPv4", and reports whether et is
+// declared by this package.
+func builtinName(et EtherType) (string, bool) {
+	s := et.builtinString()
+	if strings.HasPrefix(s, "EtherType(") {
+		return "", false
+	}
+
+	return strings.TrimPrefix(s, "EtherType"), true
+}
+
+// Name returns the short name of an EtherType, such as "IPv4" or "LLDP".  If
+// the EtherType has no name, Name returns the empty string.
+func (et EtherType) Name() string {
+	name, _ := etherTypeName(et)
+	return name
+}
+
+// MarshalText implements encoding.TextMarshaler.  Named EtherTypes are
+// marshaled using their short names, such as "IPv4" or "ARP", and all
+// others are marshaled as hexadecimal values, such as "0x88B5".
+func (et EtherType) MarshalText() ([]byte, error) {
+	if name, ok := etherTypeName(et); ok {
+		return []byte(name), nil
+	}
+
+	return []byte(fmt.Sprintf("0x%04X", uint16(et))), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.  UnmarshalText accepts
+// the short names produced by MarshalText, without regard to case, as well
+// as decimal and hexadecimal values with a "0x" prefix.
+func (et *EtherType) UnmarshalText(b []byte) error {
+	s := string(b)
+	if v, ok := EtherTypeByName(s); ok {
+		*et = v
+		return nil
+	}
+
+	v, err := strconv.ParseUint(s, 0, 16)
+	if err != nil {
+		return fmt.Errorf("invalid EtherType %q", s)
+	}
+
+	*et = EtherType(v)
+	return nil
+}
+
+// etherTypeName returns a short name for et, such as "IPv4", and reports
+// whether et has a name.
+func etherTypeName(et EtherType) (string, bool) {
+	etherTypes.mu.RLock()
+	defer etherTypes.mu.RUnlock()
+
+	name, ok := etherTypes.byType[et]
+	return name, ok
+}
@@ -0,0 +1,245 @@
+package ethernet
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestEtherTypeMarshalText(t *testing.T) {
+	tests := []struct {
+		et EtherType
+		s  string
+	}{
+		{et: EtherTypeIPv4, s: "IPv4"},
+		{et: EtherTypeARP, s: "ARP"},
+		{et: EtherTypeServiceVLAN, s: "ServiceVLAN"},
+		{et: EtherTypeLLDP, s: "LLDP"},
+		{et: 0x88b5, s: "0x88B5"},
+		{et: 0x0026, s: "0x0026"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			b, err := tt.et.MarshalText()
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			if want, got := tt.s, string(b); want != got {
+				t.Fatalf("unexpected text: %q != %q", want, got)
+			}
+
+			var et EtherType
+			if err := et.UnmarshalText(b); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+
+			if want, got := tt.et, et; want != got {
+				t.Fatalf("unexpected EtherType: %v != %v", want, got)
+			}
+		})
+	}
+}
+
+func TestEtherTypeUnmarshalText(t *testing.T) {
+	tests := []struct {
+		s  string
+		et EtherType
+		ok bool
+	}{
+		{s: "ipv6", et: EtherTypeIPv6, ok: true},
+		{s: "VLAN", et: EtherTypeVLAN, ok: true},
+		{s: "0x88cc", et: 0x88cc, ok: true},
+		{s: "2048", et: EtherTypeIPv4, ok: true},
+		{s: ""},
+		{s: "foo"},
+		{s: "0x10000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			var et EtherType
+			err := et.UnmarshalText([]byte(tt.s))
+			if tt.ok && err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+			if !tt.ok {
+				if err == nil {
+					t.Fatal("expected an error, but none occurred")
+				}
+
+				return
+			}
+
+			if want, got := tt.et, et; want != got {
+				t.Fatalf("unexpected EtherType: %v != %v", want, got)
+			}
+		})
+	}
+}
+
+func TestEtherTypeJSON(t *testing.T) {
+	type config struct {
+		EtherTypes []EtherType `json:"ethertypes"`
+	}
+
+	var c config
+	if err := json.Unmarshal([]byte(`{"ethertypes":["arp","0x88b5"]}`), &c); err != nil {
+		t.Fatalf("failed to unmarshal JSON: %v", err)
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatalf("failed to marshal JSON: %v", err)
+	}
+
+	if want, got := `{"ethertypes":["ARP","0x88B5"]}`, string(b); want != got {
+		t.Fatalf("unexpected JSON: %s != %s", want, got)
+	}
+}
+
+func TestNamedEtherTypes(t *testing.T) {
+	for _, et := range namedEtherTypes {
+		name, ok := etherTypeName(et)
+		if !ok {
+			t.Fatalf("EtherType 0x%04x has no name", uint16(et))
+		}
+
+		var got EtherType
+		if err := got.UnmarshalText([]byte(name)); err != nil {
+			t.Fatalf("failed to unmarshal %q: %v", name, err)
+		}
+
+		if want := et; want != got {
+			t.Fatalf("unexpected EtherType for %q: %v != %v", name, want, got)
+		}
+	}
+
+	if want, got := "EtherTypeLLDP", EtherTypeLLDP.String(); want != got {
+		t.Fatalf("unexpected string: %q != %q", want, got)
+	}
+}
+
+func TestRegistryReplaceBuiltin(t *testing.T) {
+	r := newRegistry(namedEtherTypes)
+	r.add(EtherTypeLLDP, "Discovery")
+	r.add(EtherTypeLLDP, "Discovery2")
+
+	for _, tt := range []struct {
+		name string
+		ok   bool
+	}{
+		{name: "lldp", ok: true},
+		{name: "discovery"},
+		{name: "discovery2", ok: true},
+	} {
+		et, ok := r.byName[tt.name]
+		if want, got := tt.ok, ok; want != got {
+			t.Fatalf("unexpected lookup for %q: %v != %v", tt.name, want, got)
+		}
+		if ok && et != EtherTypeLLDP {
+			t.Fatalf("unexpected EtherType for %q: %v", tt.name, et)
+		}
+	}
+}
+
+func TestEtherTypeByName(t *testing.T) {
+	tests := []struct {
+		name string
+		et   EtherType
+		ok   bool
+	}{
+		{name: "lldp", et: EtherTypeLLDP, ok: true},
+		{name: "MACsec", et: EtherTypeMACsec, ok: true},
+		{name: "PPPOESESSION", et: EtherTypePPPoESession, ok: true},
+		{name: ""},
+		{name: "0x0800"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			et, ok := EtherTypeByName(tt.name)
+			if want, got := tt.ok, ok; want != got {
+				t.Fatalf("unexpected lookup result: %v != %v", want, got)
+			}
+
+			if want, got := tt.et, et; want != got {
+				t.Fatalf("unexpected EtherType: %v != %v", want, got)
+			}
+		})
+	}
+
+	if want, got := "PTP", EtherTypePTP.Name(); want != got {
+		t.Fatalf("unexpected name: %q != %q", want, got)
+	}
+	if want, got := "", EtherType(0x88b5).Name(); want != got {
+		t.Fatalf("unexpected name: %q != %q", want, got)
+	}
+}
+
+func TestRegisterEtherType(t *testing.T) {
+	const et EtherType = 0x88b6
+	errShort := errors.New("short payload")
+
+	decode := func(b []byte) (interface{}, error) {
+		if len(b) < 2 {
+			return nil, errShort
+		}
+
+		return binary.BigEndian.Uint16(b[:2]), nil
+	}
+
+	if err := RegisterEtherType(et, "Experimental2", decode); err != nil {
+		t.Fatalf("failed to register: %v", err)
+	}
+
+	// Errors for invalid registrations.
+	if err := RegisterEtherType(0x0100, "Length", nil); err != ErrInvalidEtherType {
+		t.Fatalf("unexpected error: %v != %v", ErrInvalidEtherType, err)
+	}
+	if err := RegisterEtherType(0x88b7, "ipv4", nil); err == nil {
+		t.Fatal("expected an error for a duplicate name, but none occurred")
+	}
+
+	if got, ok := EtherTypeByName("experimental2"); !ok || got != et {
+		t.Fatalf("unexpected lookup result: %v, %v", got, ok)
+	}
+
+	if want, got := "EtherTypeExperimental2", et.String(); want != got {
+		t.Fatalf("unexpected EtherType string: %q != %q", want, got)
+	}
+	if want, got := "EtherType(34999)", EtherType(0x88b7).String(); want != got {
+		t.Fatalf("unexpected EtherType string: %q != %q", want, got)
+	}
+
+	f := &Frame{
+		Destination: Broadcast,
+		Source:      []byte{0, 1, 0, 1, 0, 1},
+		EtherType:   et,
+		Payload:     []byte{0x01, 0x02},
+	}
+
+	if want, got := "00:01:00:01:00:01 > ff:ff:ff:ff:ff:ff, Experimental2, 60 bytes", f.String(); want != got {
+		t.Fatalf("unexpected string:\n- want: %q\n-  got: %q", want, got)
+	}
+
+	v, err := f.DecodePayload()
+	if err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+	if want, got := uint16(0x0102), v.(uint16); want != got {
+		t.Fatalf("unexpected decoded value: %v != %v", want, got)
+	}
+
+	f.Payload = f.Payload[:1]
+	if err := f.Validate(); !errors.Is(err, errShort) {
+		t.Fatalf("expected %v in %v", errShort, err)
+	}
+
+	f.EtherType = 0x88b5
+	if _, err := f.DecodePayload(); err != ErrNoDecoder {
+		t.Fatalf("unexpected error: %v != %v", ErrNoDecoder, err)
+	}
+}
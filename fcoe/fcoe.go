@@ -0,0 +1,272 @@
+// Package fcoe implements marshaling and unmarshaling of Fibre Channel over
+// Ethernet frames, and of FCoE Initialization Protocol (FIP) frames, as
+// described in FC-BB-5.
+package fcoe
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// EtherType is the EtherType which indicates an FCoE frame in a Frame.
+const EtherType = ethernet.EtherTypeFCoE
+
+const (
+	// headerLen is the length of an FCoE header: version, reserved bits,
+	// and the SOF delimiter.
+	headerLen = 14
+
+	// trailerLen is the length of an FCoE trailer: the EOF delimiter and
+	// reserved bytes.
+	trailerLen = 4
+
+	// fcHeaderLen is the length of a Fibre Channel frame header.
+	fcHeaderLen = 24
+
+	// crcLen is the length of a Fibre Channel frame CRC.
+	crcLen = 4
+)
+
+var (
+	// ErrInvalidVersion is returned when an FCoE or FIP header has an
+	// unsupported version.
+	ErrInvalidVersion = errors.New("fcoe: invalid version")
+
+	// ErrInvalidCRC is returned when an encapsulated Fibre Channel frame has
+	// an incorrect CRC.
+	ErrInvalidCRC = errors.New("fcoe: invalid Fibre Channel CRC")
+)
+
+// A SOF is a Fibre Channel start-of-frame delimiter.
+type SOF uint8
+
+// Possible SOF values.
+const (
+	SOFf  SOF = 0x28
+	SOFi4 SOF = 0x29
+	SOFi2 SOF = 0x2d
+	SOFi3 SOF = 0x2e
+	SOFn4 SOF = 0x31
+	SOFn2 SOF = 0x35
+	SOFn3 SOF = 0x36
+	SOFc4 SOF = 0x39
+)
+
+// String returns the name of a SOF.
+func (s SOF) String() string {
+	switch s {
+	case SOFf:
+		return "SOFf"
+	case SOFi4:
+		return "SOFi4"
+	case SOFi2:
+		return "SOFi2"
+	case SOFi3:
+		return "SOFi3"
+	case SOFn4:
+		return "SOFn4"
+	case SOFn2:
+		return "SOFn2"
+	case SOFn3:
+		return "SOFn3"
+	case SOFc4:
+		return "SOFc4"
+	default:
+		return fmt.Sprintf("SOF(%d)", uint8(s))
+	}
+}
+
+// An EOF is a Fibre Channel end-of-frame delimiter.
+type EOF uint8
+
+// Possible EOF values.
+const (
+	EOFn   EOF = 0x41
+	EOFt   EOF = 0x42
+	EOFrt  EOF = 0x44
+	EOFdt  EOF = 0x46
+	EOFni  EOF = 0x49
+	EOFdti EOF = 0x4e
+	EOFrti EOF = 0x4f
+	EOFa   EOF = 0x50
+)
+
+// String returns the name of an EOF.
+func (e EOF) String() string {
+	switch e {
+	case EOFn:
+		return "EOFn"
+	case EOFt:
+		return "EOFt"
+	case EOFrt:
+		return "EOFrt"
+	case EOFdt:
+		return "EOFdt"
+	case EOFni:
+		return "EOFni"
+	case EOFdti:
+		return "EOFdti"
+	case EOFrti:
+		return "EOFrti"
+	case EOFa:
+		return "EOFa"
+	default:
+		return fmt.Sprintf("EOF(%d)", uint8(e))
+	}
+}
+
+// A Header is a Fibre Channel frame header.  Fibre Channel addresses are
+// 24 bit values.
+type Header struct {
+	RCTL      uint8
+	DestID    uint32
+	CSCTL     uint8
+	SourceID  uint32
+	Type      uint8
+	FCTL      uint32
+	SeqID     uint8
+	DFCTL     uint8
+	SeqCount  uint16
+	OXID      uint16
+	RXID      uint16
+	Parameter uint32
+}
+
+// A Packet is an FCoE frame, carrying a single Fibre Channel frame between
+// its SOF and EOF delimiters.
+type Packet struct {
+	// Version specifies the FCoE version, which is always 0.
+	Version uint8
+
+	// SOF and EOF specify the delimiters of the Fibre Channel frame.
+	SOF SOF
+	EOF EOF
+
+	// Header and Payload specify the Fibre Channel frame.  Its CRC is
+	// computed automatically on marshal and verified on unmarshal.
+	Header  Header
+	Payload []byte
+}
+
+// Parse unmarshals the FCoE frame carried in the payload of Frame f.  If
+// f's EtherType is not EtherType, ethernet.ErrInvalidEtherType is returned.
+func Parse(f *ethernet.Frame) (*Packet, error) {
+	if f.EtherType != EtherType {
+		return nil, ethernet.ErrInvalidEtherType
+	}
+
+	p := new(Packet)
+	if err := p.UnmarshalBinary(f.Payload); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Frame marshals a Packet into the payload of an Ethernet frame sent from
+// hardware address src to hardware address dst.
+func (p *Packet) Frame(dst, src net.HardwareAddr) (*ethernet.Frame, error) {
+	b, err := p.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ethernet.Frame{
+		Destination: dst,
+		Source:      src,
+		EtherType:   EtherType,
+		Payload:     b,
+	}, nil
+}
+
+// MarshalBinary allocates a byte slice and marshals a Packet into binary
+// form.
+func (p *Packet) MarshalBinary() ([]byte, error) {
+	if p.Version != 0 {
+		return nil, ErrInvalidVersion
+	}
+
+	n := headerLen + fcHeaderLen + len(p.Payload)
+	b := make([]byte, n+crcLen+trailerLen)
+
+	b[0] = p.Version << 4
+	b[13] = uint8(p.SOF)
+
+	p.Header.marshal(b[headerLen : headerLen+fcHeaderLen])
+	copy(b[headerLen+fcHeaderLen:n], p.Payload)
+
+	binary.LittleEndian.PutUint32(b[n:n+crcLen], crc32.ChecksumIEEE(b[headerLen:n]))
+	b[n+crcLen] = uint8(p.EOF)
+
+	return b, nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into a Packet.  The Fibre Channel
+// frame is delimited by the FCoE trailer in the final bytes of b.
+func (p *Packet) UnmarshalBinary(b []byte) error {
+	if len(b) < headerLen+fcHeaderLen+crcLen+trailerLen {
+		return io.ErrUnexpectedEOF
+	}
+
+	if v := b[0] >> 4; v != 0 {
+		return ErrInvalidVersion
+	}
+
+	n := len(b) - trailerLen - crcLen
+	if crc32.ChecksumIEEE(b[headerLen:n]) != binary.LittleEndian.Uint32(b[n:n+crcLen]) {
+		return ErrInvalidCRC
+	}
+
+	payload := make([]byte, n-headerLen-fcHeaderLen)
+	copy(payload, b[headerLen+fcHeaderLen:n])
+
+	*p = Packet{
+		SOF:     SOF(b[13]),
+		EOF:     EOF(b[n+crcLen]),
+		Payload: payload,
+	}
+	p.Header.unmarshal(b[headerLen : headerLen+fcHeaderLen])
+
+	return nil
+}
+
+// marshal packs a Header into b, which must be fcHeaderLen bytes.
+func (h *Header) marshal(b []byte) {
+	binary.BigEndian.PutUint32(b[0:4], uint32(h.RCTL)<<24|h.DestID&0x00ffffff)
+	binary.BigEndian.PutUint32(b[4:8], uint32(h.CSCTL)<<24|h.SourceID&0x00ffffff)
+	binary.BigEndian.PutUint32(b[8:12], uint32(h.Type)<<24|h.FCTL&0x00ffffff)
+	b[12] = h.SeqID
+	b[13] = h.DFCTL
+	binary.BigEndian.PutUint16(b[14:16], h.SeqCount)
+	binary.BigEndian.PutUint16(b[16:18], h.OXID)
+	binary.BigEndian.PutUint16(b[18:20], h.RXID)
+	binary.BigEndian.PutUint32(b[20:24], h.Parameter)
+}
+
+// unmarshal unpacks a Header from b, which must be fcHeaderLen bytes.
+func (h *Header) unmarshal(b []byte) {
+	w0 := binary.BigEndian.Uint32(b[0:4])
+	w1 := binary.BigEndian.Uint32(b[4:8])
+	w2 := binary.BigEndian.Uint32(b[8:12])
+
+	*h = Header{
+		RCTL:      uint8(w0 >> 24),
+		DestID:    w0 & 0x00ffffff,
+		CSCTL:     uint8(w1 >> 24),
+		SourceID:  w1 & 0x00ffffff,
+		Type:      uint8(w2 >> 24),
+		FCTL:      w2 & 0x00ffffff,
+		SeqID:     b[12],
+		DFCTL:     b[13],
+		SeqCount:  binary.BigEndian.Uint16(b[14:16]),
+		OXID:      binary.BigEndian.Uint16(b[16:18]),
+		RXID:      binary.BigEndian.Uint16(b[18:20]),
+		Parameter: binary.BigEndian.Uint32(b[20:24]),
+	}
+}
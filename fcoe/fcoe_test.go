@@ -0,0 +1,150 @@
+package fcoe
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestPacketMarshalUnmarshal(t *testing.T) {
+	p := &Packet{
+		SOF: SOFi3,
+		EOF: EOFt,
+		Header: Header{
+			RCTL:      0x22,
+			DestID:    0xfffffe,
+			SourceID:  0x010203,
+			Type:      0x01,
+			FCTL:      0x290000,
+			SeqCount:  1,
+			OXID:      0x1234,
+			RXID:      0xffff,
+			Parameter: 0xdeadbeef,
+		},
+		Payload: []byte{0x04, 0x00, 0x00, 0x00},
+	}
+
+	b, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	want := []byte{
+		// FCoE header: version 0, reserved, SOFi3.
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x2e,
+		// Fibre Channel header.
+		0x22, 0xff, 0xff, 0xfe,
+		0x00, 0x01, 0x02, 0x03,
+		0x01, 0x29, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x01,
+		0x12, 0x34, 0xff, 0xff,
+		0xde, 0xad, 0xbe, 0xef,
+		// Payload.
+		0x04, 0x00, 0x00, 0x00,
+	}
+
+	if want, got := want, b[:len(want)]; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected bytes:\n- want: %v\n-  got: %v", want, got)
+	}
+	if want, got := []byte{0x42, 0x00, 0x00, 0x00}, b[len(b)-trailerLen:]; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected trailer: %v != %v", want, got)
+	}
+
+	p2 := new(Packet)
+	if err := p2.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if !reflect.DeepEqual(p, p2) {
+		t.Fatalf("unexpected packet:\n- want: %#v\n-  got: %#v", p, p2)
+	}
+
+	// Any corruption of the Fibre Channel frame is detected.
+	b[headerLen+fcHeaderLen] ^= 0xff
+	if err := new(Packet).UnmarshalBinary(b); err != ErrInvalidCRC {
+		t.Fatalf("expected ErrInvalidCRC, but got: %v", err)
+	}
+}
+
+func TestPacketUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		b    []byte
+		err  error
+	}{
+		{
+			desc: "short",
+			b:    make([]byte, headerLen+fcHeaderLen+crcLen+trailerLen-1),
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "bad version",
+			b:    append([]byte{0x10}, make([]byte, headerLen+fcHeaderLen+crcLen+trailerLen-1)...),
+			err:  ErrInvalidVersion,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := new(Packet).UnmarshalBinary(tt.b); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestPacketFrameParse(t *testing.T) {
+	src := net.HardwareAddr{0x0e, 0xfc, 0x00, 0x01, 0x02, 0x03}
+	dst := net.HardwareAddr{0x0e, 0xfc, 0x00, 0xff, 0xff, 0xfe}
+
+	p := &Packet{
+		SOF:     SOFn3,
+		EOF:     EOFn,
+		Header:  Header{RCTL: 0x01},
+		Payload: []byte{0xaa, 0xbb, 0xcc, 0xdd},
+	}
+
+	f, err := p.Frame(dst, src)
+	if err != nil {
+		t.Fatalf("failed to create frame: %v", err)
+	}
+
+	// FCoE frames are never padded, so the trailer survives a round trip
+	// through the Ethernet frame.
+	b, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal frame: %v", err)
+	}
+
+	f2 := new(ethernet.Frame)
+	if err := f2.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal frame: %v", err)
+	}
+
+	p2, err := Parse(f2)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if !reflect.DeepEqual(p, p2) {
+		t.Fatalf("unexpected packet:\n- want: %#v\n-  got: %#v", p, p2)
+	}
+
+	if _, err := Parse(&ethernet.Frame{EtherType: EtherTypeFIP}); err != ethernet.ErrInvalidEtherType {
+		t.Fatalf("expected ErrInvalidEtherType, but got: %v", err)
+	}
+}
+
+func TestDelimiterString(t *testing.T) {
+	if want, got := "SOFi3", SOFi3.String(); want != got {
+		t.Fatalf("unexpected string: %q != %q", want, got)
+	}
+	if want, got := "EOF(1)", EOF(1).String(); want != got {
+		t.Fatalf("unexpected string: %q != %q", want, got)
+	}
+}
@@ -0,0 +1,250 @@
+package fcoe
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// EtherTypeFIP is the EtherType which indicates a FIP frame in a Frame.
+const EtherTypeFIP = ethernet.EtherTypeFIP
+
+// Multicast destinations of FIP frames.
+var (
+	// AllFCFMACs is the destination of FIP frames sent by ENodes to all
+	// Fibre Channel Forwarders.
+	AllFCFMACs = net.HardwareAddr{0x01, 0x10, 0x18, 0x01, 0x00, 0x02}
+
+	// AllENodeMACs is the destination of FIP frames sent by Fibre Channel
+	// Forwarders to all ENodes.
+	AllENodeMACs = net.HardwareAddr{0x01, 0x10, 0x18, 0x01, 0x00, 0x01}
+)
+
+const (
+	// fipHeaderLen is the length of a FIP header.
+	fipHeaderLen = 10
+
+	// descHeaderLen is the length of a FIP descriptor's type and length
+	// fields.
+	descHeaderLen = 2
+
+	// wordLen is the unit of FIP length fields.
+	wordLen = 4
+)
+
+// ErrInvalidDescriptor is returned when a FIP descriptor's length is not a
+// whole number of 32 bit words, or is too long.
+var ErrInvalidDescriptor = errors.New("fcoe: invalid FIP descriptor")
+
+// An Op is a FIP protocol code.
+type Op uint16
+
+// Possible Op values.
+const (
+	OpDiscovery   Op = 0x0001
+	OpLinkService Op = 0x0002
+	OpControl     Op = 0x0003
+	OpVLAN        Op = 0x0004
+)
+
+// String returns the name of an Op.
+func (o Op) String() string {
+	switch o {
+	case OpDiscovery:
+		return "Discovery"
+	case OpLinkService:
+		return "LinkService"
+	case OpControl:
+		return "Control"
+	case OpVLAN:
+		return "VLAN"
+	default:
+		return fmt.Sprintf("Op(%d)", uint16(o))
+	}
+}
+
+// FIPFlags are flags carried in a FIP header.
+type FIPFlags uint16
+
+// Possible FIPFlags values.
+const (
+	FlagFPMA      FIPFlags = 1 << 15
+	FlagSPMA      FIPFlags = 1 << 14
+	FlagAvailable FIPFlags = 1 << 2
+	FlagSolicited FIPFlags = 1 << 1
+	FlagFCFVNPort FIPFlags = 1 << 0
+)
+
+// A DescriptorType is the type of a FIP descriptor.
+type DescriptorType uint8
+
+// Possible DescriptorType values.
+const (
+	DescriptorPriority       DescriptorType = 1
+	DescriptorMACAddress     DescriptorType = 2
+	DescriptorFCMAP          DescriptorType = 3
+	DescriptorNameIdentifier DescriptorType = 4
+	DescriptorFabric         DescriptorType = 5
+	DescriptorMaxFCoESize    DescriptorType = 6
+	DescriptorFLOGI          DescriptorType = 7
+	DescriptorFDISC          DescriptorType = 8
+	DescriptorLOGO           DescriptorType = 9
+	DescriptorELP            DescriptorType = 10
+	DescriptorVxPortID       DescriptorType = 11
+	DescriptorFKAADPeriod    DescriptorType = 12
+	DescriptorVendorID       DescriptorType = 13
+	DescriptorVLAN           DescriptorType = 14
+)
+
+// A Descriptor is a FIP descriptor.  The length of Value plus 2 must be a
+// multiple of 4 bytes.
+type Descriptor struct {
+	Type  DescriptorType
+	Value []byte
+}
+
+// A FIP is a FCoE Initialization Protocol frame.
+type FIP struct {
+	// Version specifies the FIP version, which is always 1.
+	Version uint8
+
+	// Op and Subcode specify the operation of the frame.
+	Op      Op
+	Subcode uint8
+
+	// Flags specifies the FIP flags of the frame.
+	Flags FIPFlags
+
+	// Descriptors specifies the descriptors carried by the frame.
+	Descriptors []Descriptor
+}
+
+// ParseFIP unmarshals the FIP frame carried in the payload of Frame f.  If
+// f's EtherType is not EtherTypeFIP, ethernet.ErrInvalidEtherType is
+// returned.
+func ParseFIP(f *ethernet.Frame) (*FIP, error) {
+	if f.EtherType != EtherTypeFIP {
+		return nil, ethernet.ErrInvalidEtherType
+	}
+
+	p := new(FIP)
+	if err := p.UnmarshalBinary(f.Payload); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Frame marshals a FIP into the payload of an Ethernet frame sent from
+// hardware address src to hardware address dst.
+func (p *FIP) Frame(dst, src net.HardwareAddr) (*ethernet.Frame, error) {
+	b, err := p.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ethernet.Frame{
+		Destination: dst,
+		Source:      src,
+		EtherType:   EtherTypeFIP,
+		Payload:     b,
+	}, nil
+}
+
+// Descriptor returns the value of the first descriptor of type t, and
+// reports whether it was found.
+func (p *FIP) Descriptor(t DescriptorType) ([]byte, bool) {
+	for _, d := range p.Descriptors {
+		if d.Type == t {
+			return d.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// MarshalBinary allocates a byte slice and marshals a FIP into binary form.
+func (p *FIP) MarshalBinary() ([]byte, error) {
+	if p.Version != 1 {
+		return nil, ErrInvalidVersion
+	}
+
+	b := make([]byte, fipHeaderLen)
+	b[0] = p.Version << 4
+	binary.BigEndian.PutUint16(b[2:4], uint16(p.Op))
+	b[5] = p.Subcode
+	binary.BigEndian.PutUint16(b[8:10], uint16(p.Flags))
+
+	for _, d := range p.Descriptors {
+		n := descHeaderLen + len(d.Value)
+		if n%wordLen != 0 || n/wordLen > 0xff {
+			return nil, ErrInvalidDescriptor
+		}
+
+		b = append(b, uint8(d.Type), uint8(n/wordLen))
+		b = append(b, d.Value...)
+	}
+
+	n := (len(b) - fipHeaderLen) / wordLen
+	if n > 0xffff {
+		return nil, ErrInvalidDescriptor
+	}
+	binary.BigEndian.PutUint16(b[6:8], uint16(n))
+
+	return b, nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into a FIP.  Bytes beyond the
+// descriptor list length, such as Ethernet padding, are ignored.
+func (p *FIP) UnmarshalBinary(b []byte) error {
+	if len(b) < fipHeaderLen {
+		return io.ErrUnexpectedEOF
+	}
+
+	if v := b[0] >> 4; v != 1 {
+		return ErrInvalidVersion
+	}
+
+	n := int(binary.BigEndian.Uint16(b[6:8])) * wordLen
+	if len(b[fipHeaderLen:]) < n {
+		return io.ErrUnexpectedEOF
+	}
+
+	out := FIP{
+		Version: 1,
+		Op:      Op(binary.BigEndian.Uint16(b[2:4])),
+		Subcode: b[5],
+		Flags:   FIPFlags(binary.BigEndian.Uint16(b[8:10])),
+	}
+
+	b = b[fipHeaderLen : fipHeaderLen+n]
+	for len(b) > 0 {
+		if len(b) < descHeaderLen {
+			return io.ErrUnexpectedEOF
+		}
+
+		l := int(b[1]) * wordLen
+		if l < descHeaderLen {
+			return ErrInvalidDescriptor
+		}
+		if len(b) < l {
+			return io.ErrUnexpectedEOF
+		}
+
+		v := make([]byte, l-descHeaderLen)
+		copy(v, b[descHeaderLen:l])
+		out.Descriptors = append(out.Descriptors, Descriptor{
+			Type:  DescriptorType(b[0]),
+			Value: v,
+		})
+
+		b = b[l:]
+	}
+
+	*p = out
+	return nil
+}
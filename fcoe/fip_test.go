@@ -0,0 +1,158 @@
+package fcoe
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestFIPMarshalUnmarshal(t *testing.T) {
+	mac := net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}
+
+	p := &FIP{
+		Version: 1,
+		Op:      OpDiscovery,
+		Subcode: 0x01,
+		Flags:   FlagFPMA,
+		Descriptors: []Descriptor{
+			{Type: DescriptorMACAddress, Value: mac},
+			{Type: DescriptorMaxFCoESize, Value: []byte{0x08, 0x7e}},
+		},
+	}
+
+	b, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	want := []byte{
+		0x10, 0x00, 0x00, 0x01,
+		0x00, 0x01, 0x00, 0x03,
+		0x80, 0x00,
+		0x02, 0x02, 0xde, 0xad, 0xbe, 0xef, 0xde, 0xad,
+		0x06, 0x01, 0x08, 0x7e,
+	}
+
+	if !bytes.Equal(want, b) {
+		t.Fatalf("unexpected bytes:\n- want: %v\n-  got: %v", want, b)
+	}
+
+	// Trailing padding is ignored.
+	p2 := new(FIP)
+	if err := p2.UnmarshalBinary(append(b, 0x00, 0x00)); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if !reflect.DeepEqual(p, p2) {
+		t.Fatalf("unexpected FIP:\n- want: %#v\n-  got: %#v", p, p2)
+	}
+
+	if v, ok := p2.Descriptor(DescriptorMACAddress); !ok || !bytes.Equal(mac, v) {
+		t.Fatalf("unexpected MAC address descriptor: %v, %v", v, ok)
+	}
+}
+
+func TestFIPMarshalErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		p    *FIP
+		err  error
+	}{
+		{
+			desc: "bad version",
+			p:    &FIP{},
+			err:  ErrInvalidVersion,
+		},
+		{
+			desc: "unaligned descriptor",
+			p: &FIP{
+				Version:     1,
+				Descriptors: []Descriptor{{Type: DescriptorPriority, Value: []byte{0x80}}},
+			},
+			err: ErrInvalidDescriptor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if _, err := tt.p.MarshalBinary(); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestFIPUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		b    []byte
+		err  error
+	}{
+		{
+			desc: "short header",
+			b:    make([]byte, fipHeaderLen-1),
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "bad version",
+			b:    make([]byte, fipHeaderLen),
+			err:  ErrInvalidVersion,
+		},
+		{
+			desc: "short descriptor list",
+			b:    []byte{0x10, 0x00, 0x00, 0x01, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "zero length descriptor",
+			b: []byte{
+				0x10, 0x00, 0x00, 0x01, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00,
+				0x01, 0x00, 0x00, 0x00,
+			},
+			err: ErrInvalidDescriptor,
+		},
+		{
+			desc: "long descriptor",
+			b: []byte{
+				0x10, 0x00, 0x00, 0x01, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00,
+				0x01, 0x02, 0x00, 0x00,
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := new(FIP).UnmarshalBinary(tt.b); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestFIPFrameParse(t *testing.T) {
+	src := net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}
+	p := &FIP{Version: 1, Op: OpVLAN, Subcode: 0x01}
+
+	f, err := p.Frame(AllFCFMACs, src)
+	if err != nil {
+		t.Fatalf("failed to create frame: %v", err)
+	}
+
+	p2, err := ParseFIP(f)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if !reflect.DeepEqual(p, p2) {
+		t.Fatalf("unexpected FIP:\n- want: %#v\n-  got: %#v", p, p2)
+	}
+
+	if _, err := ParseFIP(&ethernet.Frame{EtherType: EtherType}); err != ethernet.ErrInvalidEtherType {
+		t.Fatalf("expected ErrInvalidEtherType, but got: %v", err)
+	}
+}
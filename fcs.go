@@ -0,0 +1,67 @@
+package ethernet
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/crc32"
+)
+
+// NewFCS returns a hash.Hash32 which computes the IEEE CRC32 frame check
+// sequence of an Ethernet frame.  Data may be written to the hash
+// incrementally, such as while copying a large payload, so that the frame
+// check sequence can be computed without a second pass over the frame.
+// MarshalFCS computes its frame check sequence in the same way.
+//
+// The Sum method of the returned hash appends the frame check sequence in
+// the same byte order used by MarshalFCS and UnmarshalFCS.
+func NewFCS() hash.Hash32 {
+	// crc32.NewIEEE reuses the same precomputed table for every hash.
+	return crc32.NewIEEE()
+}
+
+// fcsChunk is the number of bytes copied before they are added to a frame
+// check sequence, so that the bytes are hashed while still in cache.
+const fcsChunk = 1024
+
+// readFCS reads a Frame into all but the final 4 bytes of b, as with read,
+// and stores its frame check sequence in the final 4 bytes of b.  The frame
+// check sequence is computed as the Frame is written, rather than with a
+// second pass over b.
+func (f *Frame) readFCS(b []byte) error {
+	fb := b[:len(b)-4]
+
+	n, err := f.readHeader(fb)
+	if err != nil {
+		return err
+	}
+	crc := crc32.Update(0, crc32.IEEETable, fb[:n])
+
+	for _, src := range [][]byte{f.Payload, f.Padding} {
+		for len(src) > 0 && n < len(fb) {
+			c := copy(fb[n:], src[:minInt(len(src), fcsChunk)])
+			crc = crc32.Update(crc, crc32.IEEETable, fb[n:n+c])
+
+			src = src[c:]
+			n += c
+		}
+	}
+
+	// Zero-pad the remainder, in case b is being reused.
+	zero := fb[n:]
+	for i := range zero {
+		zero[i] = 0
+	}
+	crc = crc32.Update(crc, crc32.IEEETable, zero)
+
+	binary.BigEndian.PutUint32(b[len(fb):], crc)
+	return nil
+}
+
+// minInt returns the smaller of a and b.
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,100 @@
+package ethernet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"testing"
+)
+
+func TestNewFCS(t *testing.T) {
+	f := &Frame{
+		Destination: Broadcast,
+		Source:      []byte{0, 1, 0, 1, 0, 1},
+		EtherType:   EtherTypeIPv6,
+		Payload:     bytes.Repeat([]byte{0xaa}, 1500),
+	}
+
+	b, err := f.MarshalFCS()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	// Write the frame in small chunks to simulate incremental computation.
+	h := NewFCS()
+	frame := b[:len(b)-4]
+	for len(frame) > 0 {
+		n := 100
+		if n > len(frame) {
+			n = len(frame)
+		}
+
+		_, _ = h.Write(frame[:n])
+		frame = frame[n:]
+	}
+
+	if want, got := b[len(b)-4:], h.Sum(nil); !bytes.Equal(want, got) {
+		t.Fatalf("unexpected FCS:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestFrameReadFCS(t *testing.T) {
+	tests := []struct {
+		desc string
+		f    *Frame
+	}{
+		{
+			desc: "padded",
+			f: &Frame{
+				Destination: Broadcast,
+				Source:      []byte{0, 1, 0, 1, 0, 1},
+				VLAN:        &VLAN{ID: 10},
+				EtherType:   EtherTypeARP,
+				Payload:     []byte{0xaa},
+			},
+		},
+		{
+			desc: "jumbo",
+			f: &Frame{
+				Destination: Broadcast,
+				Source:      []byte{0, 1, 0, 1, 0, 1},
+				EtherType:   EtherTypeIPv4,
+				Payload:     bytes.Repeat([]byte{0xaa, 0xbb, 0xcc}, 3000),
+			},
+		},
+		{
+			desc: "LLC with padding",
+			f: &Frame{
+				Destination: Broadcast,
+				Source:      []byte{0, 1, 0, 1, 0, 1},
+				LLC:         &LLC{DSAP: SAPSTP, SSAP: SAPSTP, Control: 0x03},
+				Payload:     []byte{0xaa},
+				Padding:     []byte{0xff, 0xff},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			// Reuse a dirty buffer to verify that it is zero-padded.
+			b := bytes.Repeat([]byte{0xff}, tt.f.Length()+4)
+			if err := tt.f.readFCS(b); err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			n := len(b) - 4
+			if want, got := crc32.ChecksumIEEE(b[:n]), binary.BigEndian.Uint32(b[n:]); want != got {
+				t.Fatalf("unexpected FCS: %#08x != %#08x", want, got)
+			}
+
+			bin, err := tt.f.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			if want, got := bin, b[:n]; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected frame bytes:\n- want: %v\n-  got: %v", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package flow
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// A jsonRecord is the JSON representation of a Record.
+type jsonRecord struct {
+	Source      string    `json:"source"`
+	Destination string    `json:"destination"`
+	VLAN        uint16    `json:"vlan"`
+	EtherType   uint16    `json:"ether_type"`
+	Frames      uint64    `json:"frames"`
+	Bytes       uint64    `json:"bytes"`
+	First       time.Time `json:"first"`
+	Last        time.Time `json:"last"`
+}
+
+// MarshalJSON implements json.Marshaler.  Hardware addresses are encoded in
+// their canonical string form.
+func (r Record) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonRecord{
+		Source:      r.Source.String(),
+		Destination: r.Destination.String(),
+		VLAN:        r.VLAN,
+		EtherType:   uint16(r.EtherType),
+		Frames:      r.Frames,
+		Bytes:       r.Bytes,
+		First:       r.First,
+		Last:        r.Last,
+	})
+}
+
+// JSONExporter returns an Exporter which writes each Record to w as a line
+// of JSON.
+func JSONExporter(w io.Writer) Exporter {
+	return &jsonExporter{enc: json.NewEncoder(w)}
+}
+
+type jsonExporter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func (e *jsonExporter) Export(rs []Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, r := range rs {
+		if err := e.enc.Encode(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IPFIX constants, as defined in RFC 7011 and the IANA IPFIX Information
+// Elements registry.
+const (
+	ipfixVersion    = 10
+	ipfixTemplateID = 256
+
+	ipfixHeaderLen     = 16
+	ipfixSetHeaderLen  = 4
+	ipfixDataRecordLen = 6 + 6 + 2 + 2 + 8 + 8 + 8 + 8
+	ipfixMaxRecords    = (65535 - ipfixHeaderLen - ipfixSetHeaderLen*2 - 4 - 4*len(ipfixFields)) / ipfixDataRecordLen
+)
+
+// ipfixFields are the Information Elements and their lengths exported for
+// each Record.
+var ipfixFields = [...][2]uint16{
+	{56, 6},  // sourceMacAddress
+	{80, 6},  // destinationMacAddress
+	{243, 2}, // dot1qVlanId
+	{256, 2}, // ethernetType
+	{2, 8},   // packetDeltaCount
+	{1, 8},   // octetDeltaCount
+	{152, 8}, // flowStartMilliseconds
+	{153, 8}, // flowEndMilliseconds
+}
+
+// IPFIXExporter returns an Exporter which writes Records to w as IPFIX
+// messages for the specified observation domain.  Each message contains a
+// template set describing its records, so that the output is self-describing
+// when written to a file or stream.
+func IPFIXExporter(w io.Writer, domain uint32) Exporter {
+	return &ipfixExporter{
+		w:      w,
+		domain: domain,
+		now:    time.Now,
+	}
+}
+
+type ipfixExporter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	domain uint32
+	seq    uint32
+	now    func() time.Time
+}
+
+func (e *ipfixExporter) Export(rs []Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for len(rs) > 0 {
+		n := len(rs)
+		if n > ipfixMaxRecords {
+			n = ipfixMaxRecords
+		}
+
+		if _, err := e.w.Write(e.message(rs[:n])); err != nil {
+			return err
+		}
+
+		rs = rs[n:]
+	}
+
+	return nil
+}
+
+// message builds a single IPFIX message containing rs.
+func (e *ipfixExporter) message(rs []Record) []byte {
+	var (
+		tmplLen = ipfixSetHeaderLen + 4 + 4*len(ipfixFields)
+		dataLen = ipfixSetHeaderLen + ipfixDataRecordLen*len(rs)
+		b       = make([]byte, ipfixHeaderLen+tmplLen+dataLen)
+	)
+
+	// Message header.
+	binary.BigEndian.PutUint16(b[0:2], ipfixVersion)
+	binary.BigEndian.PutUint16(b[2:4], uint16(len(b)))
+	binary.BigEndian.PutUint32(b[4:8], uint32(e.now().Unix()))
+	binary.BigEndian.PutUint32(b[8:12], e.seq)
+	binary.BigEndian.PutUint32(b[12:16], e.domain)
+	e.seq += uint32(len(rs))
+
+	// Template set.
+	n := ipfixHeaderLen
+	binary.BigEndian.PutUint16(b[n:n+2], 2)
+	binary.BigEndian.PutUint16(b[n+2:n+4], uint16(tmplLen))
+	binary.BigEndian.PutUint16(b[n+4:n+6], ipfixTemplateID)
+	binary.BigEndian.PutUint16(b[n+6:n+8], uint16(len(ipfixFields)))
+	n += 8
+	for _, f := range ipfixFields {
+		binary.BigEndian.PutUint16(b[n:n+2], f[0])
+		binary.BigEndian.PutUint16(b[n+2:n+4], f[1])
+		n += 4
+	}
+
+	// Data set.
+	binary.BigEndian.PutUint16(b[n:n+2], ipfixTemplateID)
+	binary.BigEndian.PutUint16(b[n+2:n+4], uint16(dataLen))
+	n += 4
+	for _, r := range rs {
+		copy(b[n:n+6], r.Source)
+		copy(b[n+6:n+12], r.Destination)
+		binary.BigEndian.PutUint16(b[n+12:n+14], r.VLAN)
+		binary.BigEndian.PutUint16(b[n+14:n+16], uint16(r.EtherType))
+		binary.BigEndian.PutUint64(b[n+16:n+24], r.Frames)
+		binary.BigEndian.PutUint64(b[n+24:n+32], r.Bytes)
+		binary.BigEndian.PutUint64(b[n+32:n+40], uint64(unixMilli(r.First)))
+		binary.BigEndian.PutUint64(b[n+40:n+48], uint64(unixMilli(r.Last)))
+		n += ipfixDataRecordLen
+	}
+
+	return b
+}
+
+// unixMilli returns t as milliseconds since the Unix epoch.
+func unixMilli(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,105 @@
+package flow
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+var testRecord = Record{
+	Source:      macA,
+	Destination: macB,
+	VLAN:        10,
+	EtherType:   ethernet.EtherTypeIPv4,
+	Frames:      2,
+	Bytes:       128,
+	First:       time.Unix(1, 0).UTC(),
+	Last:        time.Unix(2, 0).UTC(),
+}
+
+func TestJSONExporter(t *testing.T) {
+	var buf bytes.Buffer
+	if err := JSONExporter(&buf).Export([]Record{testRecord, testRecord}); err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+
+	line := `{"source":"de:ad:be:ef:00:0a","destination":"de:ad:be:ef:00:0b","vlan":10,` +
+		`"ether_type":2048,"frames":2,"bytes":128,` +
+		`"first":"1970-01-01T00:00:01Z","last":"1970-01-01T00:00:02Z"}` + "\n"
+
+	if want, got := line+line, buf.String(); want != got {
+		t.Fatalf("unexpected JSON:\n- want: %s\n-  got: %s", want, got)
+	}
+}
+
+func TestIPFIXExporter(t *testing.T) {
+	var buf bytes.Buffer
+	e := IPFIXExporter(&buf, 1).(*ipfixExporter)
+	e.now = func() time.Time { return time.Unix(3, 0) }
+
+	for i := 0; i < 2; i++ {
+		if err := e.Export([]Record{testRecord}); err != nil {
+			t.Fatalf("failed to export: %v", err)
+		}
+	}
+
+	msg := func(seq byte) []byte {
+		return []byte{
+			// Header: version 10, length 108, export time, sequence, domain.
+			0x00, 0x0a, 0x00, 0x6c,
+			0x00, 0x00, 0x00, 0x03,
+			0x00, 0x00, 0x00, seq,
+			0x00, 0x00, 0x00, 0x01,
+			// Template set: ID 2, length 40, template 256 with 8 fields.
+			0x00, 0x02, 0x00, 0x28,
+			0x01, 0x00, 0x00, 0x08,
+			0x00, 0x38, 0x00, 0x06,
+			0x00, 0x50, 0x00, 0x06,
+			0x00, 0xf3, 0x00, 0x02,
+			0x01, 0x00, 0x00, 0x02,
+			0x00, 0x02, 0x00, 0x08,
+			0x00, 0x01, 0x00, 0x08,
+			0x00, 0x98, 0x00, 0x08,
+			0x00, 0x99, 0x00, 0x08,
+			// Data set: ID 256, length 52.
+			0x01, 0x00, 0x00, 0x34,
+			0xde, 0xad, 0xbe, 0xef, 0x00, 0x0a,
+			0xde, 0xad, 0xbe, 0xef, 0x00, 0x0b,
+			0x00, 0x0a,
+			0x08, 0x00,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x80,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0xe8,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x07, 0xd0,
+		}
+	}
+
+	if want, got := append(msg(0), msg(1)...), buf.Bytes(); !bytes.Equal(want, got) {
+		t.Fatalf("unexpected IPFIX messages:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestIPFIXExporterSplitsMessages(t *testing.T) {
+	var buf bytes.Buffer
+	e := IPFIXExporter(&buf, 1)
+
+	rs := make([]Record, ipfixMaxRecords+1)
+	if err := e.Export(rs); err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+
+	// Walk each message using its length field.
+	var msgs int
+	b := buf.Bytes()
+	for len(b) > 0 {
+		n := int(b[2])<<8 | int(b[3])
+		b = b[n:]
+		msgs++
+	}
+
+	if want, got := 2, msgs; want != got {
+		t.Fatalf("unexpected number of messages: %v != %v", want, got)
+	}
+}
@@ -0,0 +1,305 @@
+// Package flow implements Ethernet flow tracking and export, and detection of
+// hardware addresses which move between ports, for lightweight Layer 2
+// traffic visibility without full packet capture.
+package flow
+
+import (
+	"context"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// defaultMaxFlows is the default maximum number of flows tracked by a
+// Tracker.
+const defaultMaxFlows = 65536
+
+// A Key uniquely identifies a flow.
+type Key struct {
+	// Source and Destination are the hardware addresses of a flow.
+	Source, Destination [6]byte
+
+	// VLAN is the customer VLAN ID of a flow.  Untagged and priority tagged
+	// frames use ethernet.VLANNone.
+	VLAN uint16
+
+	// EtherType is the EtherType of a flow.  IEEE 802.3 frames, which carry
+	// a length in place of an EtherType, use the EtherType in their SNAP
+	// header if present, or zero otherwise, so that frames of varying
+	// lengths are accounted into a single flow.
+	EtherType ethernet.EtherType
+}
+
+// A Record contains accounting information for a single flow.
+type Record struct {
+	// Source, Destination, VLAN, and EtherType identify the flow, as in Key.
+	Source, Destination net.HardwareAddr
+	VLAN                uint16
+	EtherType           ethernet.EtherType
+
+	// Frames and Bytes are the number of frames and bytes observed for the
+	// flow since it was last exported.
+	Frames, Bytes uint64
+
+	// First and Last are the times at which the first and last frames were
+	// observed for the flow since it was last exported.
+	First, Last time.Time
+}
+
+// Key returns the Key for a Record.
+func (r *Record) Key() Key {
+	k := Key{
+		VLAN:      r.VLAN,
+		EtherType: r.EtherType,
+	}
+	copy(k.Source[:], r.Source)
+	copy(k.Destination[:], r.Destination)
+
+	return k
+}
+
+// TrackerConfig specifies configuration for a Tracker.
+type TrackerConfig struct {
+	// MaxFlows specifies the maximum number of flows tracked between
+	// exports.  Frames belonging to new flows beyond this limit are counted
+	// as dropped.  If zero, a default of 65536 is used.
+	MaxFlows int
+}
+
+// A Tracker accounts frames into flows.  A Tracker is safe for concurrent
+// use.
+type Tracker struct {
+	max int
+
+	mu      sync.Mutex
+	flows   map[Key]*Record
+	totals  map[totalKey]*Total
+	dropped uint64
+
+	// now allows tests to control the passage of time.
+	now func() time.Time
+}
+
+// NewTracker creates a Tracker using the input configuration.  If cfg is
+// nil, a default configuration is used.
+func NewTracker(cfg *TrackerConfig) *Tracker {
+	if cfg == nil {
+		cfg = &TrackerConfig{}
+	}
+
+	max := cfg.MaxFlows
+	if max == 0 {
+		max = defaultMaxFlows
+	}
+
+	return &Tracker{
+		max:    max,
+		flows:  make(map[Key]*Record),
+		totals: make(map[totalKey]*Total),
+		now:    time.Now,
+	}
+}
+
+// Observe accounts Frame f, which occupied n bytes on the wire, into its
+// flow.
+func (t *Tracker) Observe(f *ethernet.Frame, n int) {
+	k := Key{EtherType: etherType(f)}
+	copy(k.Source[:], f.Source)
+	copy(k.Destination[:], f.Destination)
+	if f.VLAN != nil {
+		k.VLAN = f.VLAN.ID
+	}
+
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r, ok := t.flows[k]
+	if !ok {
+		if len(t.flows) >= t.max {
+			t.dropped++
+			return
+		}
+
+		r = &Record{
+			Source:      net.HardwareAddr(append([]byte(nil), k.Source[:]...)),
+			Destination: net.HardwareAddr(append([]byte(nil), k.Destination[:]...)),
+			VLAN:        k.VLAN,
+			EtherType:   k.EtherType,
+			First:       now,
+		}
+		t.flows[k] = r
+	}
+
+	r.Frames++
+	r.Bytes += uint64(n)
+	r.Last = now
+
+	tk := totalKey{VLAN: k.VLAN, EtherType: k.EtherType}
+	tot, ok := t.totals[tk]
+	if !ok {
+		if len(t.totals) >= t.max {
+			return
+		}
+
+		tot = &Total{VLAN: k.VLAN, EtherType: k.EtherType}
+		t.totals[tk] = tot
+	}
+
+	tot.Frames++
+	tot.Bytes += uint64(n)
+}
+
+// etherType returns the EtherType used to identify the flow of Frame f, as
+// described by Key.
+func etherType(f *ethernet.Frame) ethernet.EtherType {
+	if _, ok := f.LengthField(); !ok {
+		return f.EtherType
+	}
+
+	if f.SNAP != nil {
+		if et, ok := f.SNAP.EtherType(); ok {
+			return et
+		}
+	}
+
+	return 0
+}
+
+// ObserveBinary unmarshals b into a Frame and accounts it into its flow.
+func (t *Tracker) ObserveBinary(b []byte) error {
+	var f ethernet.Frame
+	if err := f.UnmarshalBinary(b); err != nil {
+		return err
+	}
+
+	t.Observe(&f, len(b))
+	return nil
+}
+
+// Records returns a snapshot of all flows, sorted by Key.
+func (t *Tracker) Records() []Record {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.records()
+}
+
+// Flush returns all flows, sorted by Key, and resets the Tracker so that
+// subsequent Records contain only new activity.
+func (t *Tracker) Flush() []Record {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	rs := t.records()
+	t.flows = make(map[Key]*Record)
+	return rs
+}
+
+// Dropped returns the number of frames which were not tracked because the
+// maximum number of flows was reached.
+func (t *Tracker) Dropped() uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.dropped
+}
+
+// A Total contains cumulative accounting information for all flows with a
+// given VLAN and EtherType.
+type Total struct {
+	VLAN          uint16
+	EtherType     ethernet.EtherType
+	Frames, Bytes uint64
+}
+
+// A totalKey uniquely identifies a Total.
+type totalKey struct {
+	VLAN      uint16
+	EtherType ethernet.EtherType
+}
+
+// Totals returns cumulative accounting information for all tracked frames,
+// grouped by VLAN and EtherType and sorted by VLAN and then EtherType.
+// Unlike Records, Totals are never reset by Flush.
+func (t *Tracker) Totals() []Total {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ts := make([]Total, 0, len(t.totals))
+	for _, tot := range t.totals {
+		ts = append(ts, *tot)
+	}
+
+	sort.Slice(ts, func(i, j int) bool {
+		if ts[i].VLAN != ts[j].VLAN {
+			return ts[i].VLAN < ts[j].VLAN
+		}
+
+		return ts[i].EtherType < ts[j].EtherType
+	})
+
+	return ts
+}
+
+// records returns all flows sorted by Key.  t.mu must be held when calling
+// records.
+func (t *Tracker) records() []Record {
+	rs := make([]Record, 0, len(t.flows))
+	for _, r := range t.flows {
+		rs = append(rs, *r)
+	}
+
+	sort.Slice(rs, func(i, j int) bool {
+		return less(rs[i].Key(), rs[j].Key())
+	})
+
+	return rs
+}
+
+// An Exporter exports flow Records.
+type Exporter interface {
+	Export(rs []Record) error
+}
+
+// Run flushes t and exports its Records using e at regular intervals, until
+// ctx is canceled or e returns an error.  When ctx is canceled, any remaining
+// Records are exported before Run returns.
+func (t *Tracker) Run(ctx context.Context, interval time.Duration, e Exporter) error {
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-tick.C:
+			if err := e.Export(t.Flush()); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			if err := e.Export(t.Flush()); err != nil {
+				return err
+			}
+
+			return ctx.Err()
+		}
+	}
+}
+
+// less reports whether Key a sorts before Key b.
+func less(a, b Key) bool {
+	if a.Source != b.Source {
+		return string(a.Source[:]) < string(b.Source[:])
+	}
+	if a.Destination != b.Destination {
+		return string(a.Destination[:]) < string(b.Destination[:])
+	}
+	if a.VLAN != b.VLAN {
+		return a.VLAN < b.VLAN
+	}
+
+	return a.EtherType < b.EtherType
+}
@@ -0,0 +1,249 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+var (
+	macA = net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0x00, 0x0a}
+	macB = net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0x00, 0x0b}
+)
+
+func TestTrackerObserve(t *testing.T) {
+	var (
+		t0 = time.Unix(1, 0)
+		t1 = time.Unix(2, 0)
+		t2 = time.Unix(3, 0)
+	)
+
+	tr := NewTracker(nil)
+
+	steps := []struct {
+		now time.Time
+		f   *ethernet.Frame
+		n   int
+	}{
+		{
+			now: t0,
+			f: &ethernet.Frame{
+				Destination: macB,
+				Source:      macA,
+				EtherType:   ethernet.EtherTypeIPv4,
+			},
+			n: 64,
+		},
+		{
+			now: t1,
+			f: &ethernet.Frame{
+				Destination: macA,
+				Source:      macB,
+				VLAN:        &ethernet.VLAN{ID: 10},
+				EtherType:   ethernet.EtherTypeARP,
+			},
+			n: 60,
+		},
+		{
+			now: t2,
+			f: &ethernet.Frame{
+				Destination: macB,
+				Source:      macA,
+				EtherType:   ethernet.EtherTypeIPv4,
+			},
+			n: 1514,
+		},
+	}
+
+	for _, s := range steps {
+		now := s.now
+		tr.now = func() time.Time { return now }
+		tr.Observe(s.f, s.n)
+	}
+
+	want := []Record{
+		{
+			Source:      macA,
+			Destination: macB,
+			EtherType:   ethernet.EtherTypeIPv4,
+			Frames:      2,
+			Bytes:       64 + 1514,
+			First:       t0,
+			Last:        t2,
+		},
+		{
+			Source:      macB,
+			Destination: macA,
+			VLAN:        10,
+			EtherType:   ethernet.EtherTypeARP,
+			Frames:      1,
+			Bytes:       60,
+			First:       t1,
+			Last:        t1,
+		},
+	}
+
+	if got := tr.Records(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected records:\n- want: %+v\n-  got: %+v", want, got)
+	}
+
+	if got := tr.Flush(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected flushed records:\n- want: %+v\n-  got: %+v", want, got)
+	}
+
+	if got := tr.Records(); len(got) != 0 {
+		t.Fatalf("expected no records after flush, but got: %+v", got)
+	}
+
+	totals := []Total{
+		{
+			EtherType: ethernet.EtherTypeIPv4,
+			Frames:    2,
+			Bytes:     64 + 1514,
+		},
+		{
+			VLAN:      10,
+			EtherType: ethernet.EtherTypeARP,
+			Frames:    1,
+			Bytes:     60,
+		},
+	}
+
+	if want, got := totals, tr.Totals(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected totals:\n- want: %+v\n-  got: %+v", want, got)
+	}
+}
+
+func TestTrackerObserveLengthField(t *testing.T) {
+	tr := NewTracker(nil)
+
+	llc := &ethernet.LLC{DSAP: 0x42, SSAP: 0x42, Control: 0x03}
+	snap := &ethernet.LLC{DSAP: 0xaa, SSAP: 0xaa, Control: 0x03}
+
+	for _, f := range []*ethernet.Frame{
+		{LLC: llc, EtherType: 38},
+		{LLC: llc, EtherType: 120},
+		{LLC: snap, SNAP: &ethernet.SNAP{ProtocolID: 0x0800}, EtherType: 46},
+		{LLC: snap, SNAP: &ethernet.SNAP{ProtocolID: 0x0800}, EtherType: 1500},
+	} {
+		f.Destination = macB
+		f.Source = macA
+		tr.Observe(f, 60)
+	}
+
+	want := []Total{
+		{
+			Frames: 2,
+			Bytes:  120,
+		},
+		{
+			EtherType: ethernet.EtherTypeIPv4,
+			Frames:    2,
+			Bytes:     120,
+		},
+	}
+
+	if got := tr.Totals(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected totals:\n- want: %+v\n-  got: %+v", want, got)
+	}
+}
+
+func TestTrackerMaxFlows(t *testing.T) {
+	tr := NewTracker(&TrackerConfig{MaxFlows: 1})
+
+	for _, src := range []net.HardwareAddr{macA, macB, macA} {
+		tr.Observe(&ethernet.Frame{
+			Destination: ethernet.Broadcast,
+			Source:      src,
+		}, 60)
+	}
+
+	rs := tr.Records()
+	if want, got := 1, len(rs); want != got {
+		t.Fatalf("unexpected number of records: %v != %v", want, got)
+	}
+	if want, got := uint64(2), rs[0].Frames; want != got {
+		t.Fatalf("unexpected number of frames: %v != %v", want, got)
+	}
+	if want, got := uint64(1), tr.Dropped(); want != got {
+		t.Fatalf("unexpected number of dropped frames: %v != %v", want, got)
+	}
+}
+
+func TestTrackerObserveBinary(t *testing.T) {
+	tr := NewTracker(nil)
+
+	if err := tr.ObserveBinary([]byte{0}); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("unexpected error: %v != %v", io.ErrUnexpectedEOF, err)
+	}
+
+	f := &ethernet.Frame{
+		Destination: macB,
+		Source:      macA,
+		EtherType:   ethernet.EtherTypeIPv6,
+	}
+
+	b, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal frame: %v", err)
+	}
+
+	if err := tr.ObserveBinary(b); err != nil {
+		t.Fatalf("failed to observe frame: %v", err)
+	}
+
+	rs := tr.Records()
+	if want, got := 1, len(rs); want != got {
+		t.Fatalf("unexpected number of records: %v != %v", want, got)
+	}
+	if want, got := uint64(len(b)), rs[0].Bytes; want != got {
+		t.Fatalf("unexpected number of bytes: %v != %v", want, got)
+	}
+}
+
+func TestTrackerRun(t *testing.T) {
+	tr := NewTracker(nil)
+	tr.Observe(&ethernet.Frame{
+		Destination: macB,
+		Source:      macA,
+	}, 60)
+
+	e := &testExporter{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := tr.Run(ctx, time.Hour, e); err != context.Canceled {
+		t.Fatalf("unexpected error: %v != %v", context.Canceled, err)
+	}
+
+	if want, got := 1, len(e.records()); want != got {
+		t.Fatalf("unexpected number of exported records: %v != %v", want, got)
+	}
+}
+
+type testExporter struct {
+	mu sync.Mutex
+	rs []Record
+}
+
+func (e *testExporter) Export(rs []Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.rs = append(e.rs, rs...)
+	return nil
+}
+
+func (e *testExporter) records() []Record {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.rs
+}
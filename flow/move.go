@@ -0,0 +1,266 @@
+package flow
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// Defaults for MoveDetectorConfig.
+const (
+	defaultDuplicateWindow = time.Second
+	defaultFlapThreshold   = 3
+	defaultFlapWindow      = 10 * time.Second
+	defaultMaxAddresses    = 65536
+)
+
+// An AlertKind is the kind of condition reported by a MoveDetector.
+type AlertKind int
+
+// Possible AlertKind values.
+const (
+	// AlertDuplicate indicates that a source address was seen on a port
+	// while it was still active on another port.
+	AlertDuplicate AlertKind = iota
+
+	// AlertFlapping indicates that a source address moved between ports
+	// too often.
+	AlertFlapping
+)
+
+// String returns the string representation of an AlertKind.
+func (k AlertKind) String() string {
+	switch k {
+	case AlertDuplicate:
+		return "duplicate"
+	case AlertFlapping:
+		return "flapping"
+	default:
+		return "unknown"
+	}
+}
+
+// An Alert is raised by a MoveDetector when a source address is seen on
+// multiple ports.
+type Alert struct {
+	// Kind is the condition detected.
+	Kind AlertKind
+
+	// Address is the source hardware address which triggered the Alert.
+	Address net.HardwareAddr
+
+	// From and To are the ports on which Address was previously and most
+	// recently seen.
+	From, To string
+
+	// Moves is the number of port changes for Address within the flap
+	// window, including this one.
+	Moves int
+
+	// Time is the time at which the Alert was raised.
+	Time time.Time
+}
+
+// MoveDetectorConfig specifies configuration for a MoveDetector.
+type MoveDetectorConfig struct {
+	// DuplicateWindow specifies the interval after an address was last seen
+	// on a port during which seeing it on another port is reported as a
+	// duplicate, rather than treated as a station move.  If zero, a default
+	// of 1 second is used.
+	DuplicateWindow time.Duration
+
+	// FlapThreshold and FlapWindow specify the number of port changes within
+	// an interval at which an address is reported as flapping.  If zero,
+	// defaults of 3 changes within 10 seconds are used.
+	FlapThreshold int
+	FlapWindow    time.Duration
+
+	// MaxAddresses specifies the maximum number of addresses tracked.
+	// Addresses beyond this limit are ignored until others expire.  If zero,
+	// a default of 65536 is used.
+	MaxAddresses int
+
+	// Alert, if not nil, is invoked synchronously for each Alert.  Alert
+	// must not call back into the MoveDetector.
+	Alert func(a Alert)
+}
+
+// A MoveDetector detects source hardware addresses which are seen on
+// multiple ports, either simultaneously or by moving back and forth between
+// them.  A MoveDetector may be used standalone, fed by frames read from
+// several net.PacketConns.  A MoveDetector is safe for concurrent use.
+type MoveDetector struct {
+	dup       time.Duration
+	threshold int
+	window    time.Duration
+	max       int
+	alert     func(a Alert)
+
+	mu      sync.Mutex
+	entries map[[6]byte]*moveEntry
+	alerts  map[AlertKind]uint64
+
+	// now allows tests to control the passage of time.
+	now func() time.Time
+}
+
+// A moveEntry tracks the location of a single address.
+type moveEntry struct {
+	port  string
+	last  time.Time
+	moves []time.Time
+}
+
+// NewMoveDetector creates a MoveDetector using the input configuration.  If
+// cfg is nil, a default configuration is used.
+func NewMoveDetector(cfg *MoveDetectorConfig) *MoveDetector {
+	if cfg == nil {
+		cfg = &MoveDetectorConfig{}
+	}
+
+	d := &MoveDetector{
+		dup:       cfg.DuplicateWindow,
+		threshold: cfg.FlapThreshold,
+		window:    cfg.FlapWindow,
+		max:       cfg.MaxAddresses,
+		alert:     cfg.Alert,
+		entries:   make(map[[6]byte]*moveEntry),
+		alerts:    make(map[AlertKind]uint64),
+		now:       time.Now,
+	}
+
+	if d.dup == 0 {
+		d.dup = defaultDuplicateWindow
+	}
+	if d.threshold == 0 {
+		d.threshold = defaultFlapThreshold
+	}
+	if d.window == 0 {
+		d.window = defaultFlapWindow
+	}
+	if d.max == 0 {
+		d.max = defaultMaxAddresses
+	}
+
+	return d
+}
+
+// Observe records that source address addr was seen on port, and raises
+// any resulting Alerts.  The Alerts are also returned.
+func (d *MoveDetector) Observe(port string, addr net.HardwareAddr) []Alert {
+	var k [6]byte
+	copy(k[:], addr)
+
+	now := d.now()
+
+	d.mu.Lock()
+	alerts := d.observe(k, port, now)
+	for _, a := range alerts {
+		d.alerts[a.Kind]++
+	}
+	d.mu.Unlock()
+
+	if d.alert != nil {
+		for _, a := range alerts {
+			d.alert(a)
+		}
+	}
+
+	return alerts
+}
+
+// observe updates the entry for k.  d.mu must be held when calling observe.
+func (d *MoveDetector) observe(k [6]byte, port string, now time.Time) []Alert {
+	e, ok := d.entries[k]
+	if !ok {
+		if len(d.entries) >= d.max {
+			d.expire(now)
+			if len(d.entries) >= d.max {
+				return nil
+			}
+		}
+
+		d.entries[k] = &moveEntry{port: port, last: now}
+		return nil
+	}
+
+	if e.port == port {
+		e.last = now
+		return nil
+	}
+
+	// Address has changed ports: forget moves outside the flap window and
+	// record this one.
+	cutoff := now.Add(-d.window)
+	moves := e.moves[:0]
+	for _, t := range e.moves {
+		if t.After(cutoff) {
+			moves = append(moves, t)
+		}
+	}
+	e.moves = append(moves, now)
+
+	newAlert := func(kind AlertKind) Alert {
+		return Alert{
+			Kind:    kind,
+			Address: net.HardwareAddr(append([]byte(nil), k[:]...)),
+			From:    e.port,
+			To:      port,
+			Moves:   len(e.moves),
+			Time:    now,
+		}
+	}
+
+	var alerts []Alert
+	if now.Sub(e.last) < d.dup {
+		alerts = append(alerts, newAlert(AlertDuplicate))
+	}
+	if len(e.moves) >= d.threshold {
+		alerts = append(alerts, newAlert(AlertFlapping))
+	}
+
+	e.port = port
+	e.last = now
+
+	return alerts
+}
+
+// expire removes entries which have been idle for longer than the flap
+// window.  d.mu must be held when calling expire.
+func (d *MoveDetector) expire(now time.Time) {
+	for k, e := range d.entries {
+		if now.Sub(e.last) > d.window {
+			delete(d.entries, k)
+		}
+	}
+}
+
+// Port returns the port on which addr was most recently seen, and whether
+// addr is known to d.
+func (d *MoveDetector) Port(addr net.HardwareAddr) (string, bool) {
+	var k [6]byte
+	copy(k[:], addr)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	e, ok := d.entries[k]
+	if !ok {
+		return "", false
+	}
+
+	return e.port, true
+}
+
+// Alerts returns the number of Alerts raised by d, by AlertKind.
+func (d *MoveDetector) Alerts() map[AlertKind]uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	alerts := make(map[AlertKind]uint64, len(d.alerts))
+	for k, v := range d.alerts {
+		alerts[k] = v
+	}
+
+	return alerts
+}
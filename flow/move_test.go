@@ -0,0 +1,134 @@
+package flow
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMoveDetector(t *testing.T) {
+	type step struct {
+		after time.Duration
+		port  string
+		kinds []AlertKind
+	}
+
+	tests := []struct {
+		desc  string
+		cfg   *MoveDetectorConfig
+		steps []step
+	}{
+		{
+			desc: "same port",
+			steps: []step{
+				{port: "eth0"},
+				{after: time.Millisecond, port: "eth0"},
+			},
+		},
+		{
+			desc: "station move",
+			steps: []step{
+				{port: "eth0"},
+				{after: 2 * time.Second, port: "eth1"},
+			},
+		},
+		{
+			desc: "duplicate",
+			steps: []step{
+				{port: "eth0"},
+				{after: time.Millisecond, port: "eth1", kinds: []AlertKind{AlertDuplicate}},
+			},
+		},
+		{
+			desc: "flapping",
+			cfg: &MoveDetectorConfig{
+				DuplicateWindow: time.Nanosecond,
+				FlapThreshold:   2,
+			},
+			steps: []step{
+				{port: "eth0"},
+				{after: time.Second, port: "eth1"},
+				{after: time.Second, port: "eth0", kinds: []AlertKind{AlertFlapping}},
+			},
+		},
+		{
+			desc: "moves outside flap window",
+			cfg: &MoveDetectorConfig{
+				DuplicateWindow: time.Nanosecond,
+				FlapThreshold:   2,
+				FlapWindow:      time.Second,
+			},
+			steps: []step{
+				{port: "eth0"},
+				{after: 2 * time.Second, port: "eth1"},
+				{after: 2 * time.Second, port: "eth0"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var alerted []AlertKind
+			cfg := tt.cfg
+			if cfg == nil {
+				cfg = &MoveDetectorConfig{}
+			}
+			cfg.Alert = func(a Alert) {
+				alerted = append(alerted, a.Kind)
+			}
+
+			d := NewMoveDetector(cfg)
+
+			now := time.Unix(0, 0)
+			d.now = func() time.Time { return now }
+
+			var want []AlertKind
+			for i, s := range tt.steps {
+				now = now.Add(s.after)
+
+				var got []AlertKind
+				for _, a := range d.Observe(s.port, macA) {
+					got = append(got, a.Kind)
+				}
+
+				if !reflect.DeepEqual(s.kinds, got) {
+					t.Fatalf("unexpected alerts at step %d: %v != %v", i, s.kinds, got)
+				}
+
+				want = append(want, s.kinds...)
+			}
+
+			if !reflect.DeepEqual(want, alerted) {
+				t.Fatalf("unexpected alert callbacks: %v != %v", want, alerted)
+			}
+
+			port, ok := d.Port(macA)
+			if !ok {
+				t.Fatal("address is not known to detector")
+			}
+			if want, got := tt.steps[len(tt.steps)-1].port, port; want != got {
+				t.Fatalf("unexpected port: %q != %q", want, got)
+			}
+		})
+	}
+}
+
+func TestMoveDetectorMaxAddresses(t *testing.T) {
+	d := NewMoveDetector(&MoveDetectorConfig{MaxAddresses: 1})
+
+	now := time.Unix(0, 0)
+	d.now = func() time.Time { return now }
+
+	d.Observe("eth0", macA)
+	d.Observe("eth0", macB)
+	if _, ok := d.Port(macB); ok {
+		t.Fatal("address beyond limit should not be tracked")
+	}
+
+	// Once the first address expires, the second can be tracked.
+	now = now.Add(time.Minute)
+	d.Observe("eth0", macB)
+	if _, ok := d.Port(macB); !ok {
+		t.Fatal("address should be tracked after expiry")
+	}
+}
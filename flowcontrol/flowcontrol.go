@@ -0,0 +1,215 @@
+// Package flowcontrol implements marshaling and unmarshaling of IEEE 802.3x
+// PAUSE and IEEE 802.1Qbb Priority-based Flow Control MAC control frames.
+package flowcontrol
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// EtherType is the EtherType which indicates a MAC control frame in a Frame.
+const EtherType = ethernet.EtherTypeFlowControl
+
+// Destination is the hardware address to which PAUSE and PFC frames are
+// sent.
+var Destination = net.HardwareAddr{0x01, 0x80, 0xc2, 0x00, 0x00, 0x01}
+
+const (
+	// pauseLen and pfcLen are the lengths of PAUSE and PFC MAC control
+	// frames, including the opcode.
+	pauseLen = 4
+	pfcLen   = 20
+
+	// quantumBits is the number of bit times in a pause quantum.
+	quantumBits = 512
+)
+
+// ErrInvalidOpcode is returned when a MAC control frame's opcode does not
+// match the frame being unmarshaled.
+var ErrInvalidOpcode = errors.New("flowcontrol: invalid MAC control opcode")
+
+// An Opcode is a MAC control opcode.
+type Opcode uint16
+
+// Possible Opcode values.
+const (
+	OpcodePause Opcode = 0x0001
+	OpcodePFC   Opcode = 0x0101
+)
+
+// String returns the name of an Opcode.
+func (o Opcode) String() string {
+	switch o {
+	case OpcodePause:
+		return "PAUSE"
+	case OpcodePFC:
+		return "PFC"
+	default:
+		return fmt.Sprintf("Opcode(%d)", uint16(o))
+	}
+}
+
+// QuantaDuration converts a number of pause quanta into the duration of a
+// pause on a link with the specified speed in bits per second.
+func QuantaDuration(quanta uint16, bitsPerSecond uint64) time.Duration {
+	if bitsPerSecond == 0 {
+		return 0
+	}
+
+	return time.Duration(uint64(quanta) * quantumBits * uint64(time.Second) / bitsPerSecond)
+}
+
+// A Pause is a PAUSE MAC control frame, which requests that the receiver
+// stop transmitting for a period of time.
+type Pause struct {
+	// Quanta specifies the pause time in units of 512 bit times.  Zero
+	// requests that the receiver resume transmitting.
+	Quanta uint16
+}
+
+// ParsePause unmarshals the PAUSE frame carried in the payload of Frame f.
+// If f's EtherType is not EtherType, ethernet.ErrInvalidEtherType is
+// returned.
+func ParsePause(f *ethernet.Frame) (*Pause, error) {
+	if f.EtherType != EtherType {
+		return nil, ethernet.ErrInvalidEtherType
+	}
+
+	p := new(Pause)
+	if err := p.UnmarshalBinary(f.Payload); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Frame marshals a Pause into the payload of an Ethernet frame sent from
+// hardware address src to Destination.
+func (p *Pause) Frame(src net.HardwareAddr) (*ethernet.Frame, error) {
+	b, err := p.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return newFrame(src, b), nil
+}
+
+// MarshalBinary allocates a byte slice and marshals a Pause into binary
+// form.
+func (p *Pause) MarshalBinary() ([]byte, error) {
+	b := make([]byte, pauseLen)
+	binary.BigEndian.PutUint16(b[0:2], uint16(OpcodePause))
+	binary.BigEndian.PutUint16(b[2:4], p.Quanta)
+	return b, nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into a Pause.  Trailing bytes,
+// such as Ethernet padding, are ignored.
+func (p *Pause) UnmarshalBinary(b []byte) error {
+	if err := checkOpcode(b, OpcodePause, pauseLen); err != nil {
+		return err
+	}
+
+	p.Quanta = binary.BigEndian.Uint16(b[2:4])
+	return nil
+}
+
+// A PFC is a Priority-based Flow Control MAC control frame, which requests
+// that the receiver stop transmitting frames of certain priorities for a
+// period of time.
+type PFC struct {
+	// Enabled specifies the priority enable vector.  If bit i is set, the
+	// receiver must act on Quanta[i].
+	Enabled uint8
+
+	// Quanta specifies the pause time of each priority in units of 512 bit
+	// times.
+	Quanta [8]uint16
+}
+
+// ParsePFC unmarshals the PFC frame carried in the payload of Frame f.  If
+// f's EtherType is not EtherType, ethernet.ErrInvalidEtherType is returned.
+func ParsePFC(f *ethernet.Frame) (*PFC, error) {
+	if f.EtherType != EtherType {
+		return nil, ethernet.ErrInvalidEtherType
+	}
+
+	p := new(PFC)
+	if err := p.UnmarshalBinary(f.Payload); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Frame marshals a PFC into the payload of an Ethernet frame sent from
+// hardware address src to Destination.
+func (p *PFC) Frame(src net.HardwareAddr) (*ethernet.Frame, error) {
+	b, err := p.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return newFrame(src, b), nil
+}
+
+// MarshalBinary allocates a byte slice and marshals a PFC into binary form.
+func (p *PFC) MarshalBinary() ([]byte, error) {
+	b := make([]byte, pfcLen)
+	binary.BigEndian.PutUint16(b[0:2], uint16(OpcodePFC))
+	binary.BigEndian.PutUint16(b[2:4], uint16(p.Enabled))
+
+	for i, q := range p.Quanta {
+		binary.BigEndian.PutUint16(b[4+i*2:6+i*2], q)
+	}
+
+	return b, nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into a PFC.  Trailing bytes, such
+// as Ethernet padding, are ignored.
+func (p *PFC) UnmarshalBinary(b []byte) error {
+	if err := checkOpcode(b, OpcodePFC, pfcLen); err != nil {
+		return err
+	}
+
+	// The high 8 bits of the enable vector are reserved.
+	p.Enabled = b[3]
+	for i := range p.Quanta {
+		p.Quanta[i] = binary.BigEndian.Uint16(b[4+i*2 : 6+i*2])
+	}
+
+	return nil
+}
+
+// checkOpcode verifies that b begins with opcode op and is at least n bytes.
+func checkOpcode(b []byte, op Opcode, n int) error {
+	if len(b) < 2 {
+		return io.ErrUnexpectedEOF
+	}
+	if Opcode(binary.BigEndian.Uint16(b[0:2])) != op {
+		return ErrInvalidOpcode
+	}
+	if len(b) < n {
+		return io.ErrUnexpectedEOF
+	}
+
+	return nil
+}
+
+// newFrame creates an Ethernet frame which carries MAC control frame b from
+// hardware address src to Destination.
+func newFrame(src net.HardwareAddr, b []byte) *ethernet.Frame {
+	return &ethernet.Frame{
+		Destination: append(net.HardwareAddr(nil), Destination...),
+		Source:      src,
+		EtherType:   EtherType,
+		Payload:     b,
+	}
+}
@@ -0,0 +1,188 @@
+package flowcontrol
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestPauseMarshalUnmarshal(t *testing.T) {
+	p := &Pause{Quanta: 0xffff}
+
+	b, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	if want, got := []byte{0x00, 0x01, 0xff, 0xff}, b; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected bytes: %v != %v", want, got)
+	}
+
+	got := new(Pause)
+	if err := got.UnmarshalBinary(append(b, make([]byte, 42)...)); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if !reflect.DeepEqual(p, got) {
+		t.Fatalf("unexpected Pause:\n- want: %#v\n-  got: %#v", p, got)
+	}
+}
+
+func TestPFCMarshalUnmarshal(t *testing.T) {
+	p := &PFC{
+		Enabled: 0x09,
+		Quanta:  [8]uint16{0x0100, 0, 0, 0xffff},
+	}
+
+	b, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	want := []byte{
+		0x01, 0x01, 0x00, 0x09,
+		0x01, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0xff, 0xff,
+		0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00,
+	}
+	if !bytes.Equal(want, b) {
+		t.Fatalf("unexpected bytes:\n- want: %v\n-  got: %v", want, b)
+	}
+
+	got := new(PFC)
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if !reflect.DeepEqual(p, got) {
+		t.Fatalf("unexpected PFC:\n- want: %#v\n-  got: %#v", p, got)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		v    interface{ UnmarshalBinary([]byte) error }
+		b    []byte
+		err  error
+	}{
+		{
+			desc: "Pause empty",
+			v:    new(Pause),
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "Pause with PFC opcode",
+			v:    new(Pause),
+			b:    []byte{0x01, 0x01, 0x00, 0x00},
+			err:  ErrInvalidOpcode,
+		},
+		{
+			desc: "Pause short",
+			v:    new(Pause),
+			b:    []byte{0x00, 0x01, 0x00},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "PFC with PAUSE opcode",
+			v:    new(PFC),
+			b:    []byte{0x00, 0x01, 0x00, 0x00},
+			err:  ErrInvalidOpcode,
+		},
+		{
+			desc: "PFC short",
+			v:    new(PFC),
+			b:    []byte{0x01, 0x01, 0x00, 0x01},
+			err:  io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := tt.v.UnmarshalBinary(tt.b); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestFrameParse(t *testing.T) {
+	src := net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}
+	p := &PFC{Enabled: 0x01, Quanta: [8]uint16{10}}
+
+	f, err := p.Frame(src)
+	if err != nil {
+		t.Fatalf("failed to create frame: %v", err)
+	}
+
+	// Round trip through binary form to add Ethernet padding.
+	b, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal frame: %v", err)
+	}
+
+	f = new(ethernet.Frame)
+	if err := f.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal frame: %v", err)
+	}
+
+	if want, got := Destination, f.Destination; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected destination: %v != %v", want, got)
+	}
+
+	got, err := ParsePFC(f)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if !reflect.DeepEqual(p, got) {
+		t.Fatalf("unexpected PFC:\n- want: %#v\n-  got: %#v", p, got)
+	}
+
+	if _, err := ParsePause(f); err != ErrInvalidOpcode {
+		t.Fatalf("expected ErrInvalidOpcode, but got: %v", err)
+	}
+	if _, err := ParsePause(&ethernet.Frame{EtherType: ethernet.EtherTypeIPv4}); err != ethernet.ErrInvalidEtherType {
+		t.Fatalf("expected ErrInvalidEtherType, but got: %v", err)
+	}
+}
+
+func TestQuantaDuration(t *testing.T) {
+	tests := []struct {
+		desc   string
+		quanta uint16
+		speed  uint64
+		d      time.Duration
+	}{
+		{
+			desc:   "zero speed",
+			quanta: 1,
+		},
+		{
+			desc:   "1 Gbps",
+			quanta: 1,
+			speed:  1e9,
+			d:      512 * time.Nanosecond,
+		},
+		{
+			desc:   "10 Gbps maximum",
+			quanta: 0xffff,
+			speed:  10e9,
+			d:      3355392 * time.Nanosecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if want, got := tt.d, QuantaDuration(tt.quanta, tt.speed); want != got {
+				t.Fatalf("unexpected duration: %v != %v", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package ethernet
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// String returns a human-readable summary of a Frame in the style of
+// tcpdump, such as:
+//
+//	00:11:22:33:44:55 > ff:ff:ff:ff:ff:ff, 802.1Q vid 101 pri 1, IPv4, 64 bytes
+//
+// The length is the number of bytes produced by MarshalBinary.
+func (f *Frame) String() string {
+	if f == nil {
+		return "<nil>"
+	}
+
+	ss := []string{fmt.Sprintf("%s > %s", macString(f.Source), macString(f.Destination))}
+
+	for _, v := range []struct {
+		name string
+		vlan *VLAN
+	}{
+		{name: "802.1ad", vlan: f.ServiceVLAN},
+		{name: "802.1Q", vlan: f.VLAN},
+	} {
+		if v.vlan == nil {
+			continue
+		}
+
+		ss = append(ss, v.name+" "+vlanString(v.vlan))
+	}
+
+	switch {
+	case f.LLC != nil:
+		ss = append(ss, fmt.Sprintf("802.3 length %d", f.llcLength()+len(f.Payload)))
+
+		l := f.LLC
+		ss = append(ss, fmt.Sprintf("LLC dsap 0x%02x ssap 0x%02x ctrl 0x%02x", l.DSAP, l.SSAP, l.Control))
+
+		if s := f.SNAP; s != nil {
+			ss = append(ss, fmt.Sprintf("SNAP oui 0x%02x%02x%02x pid 0x%04x",
+				s.OUI[0], s.OUI[1], s.OUI[2], s.ProtocolID))
+		}
+	case f.Encapsulation == EncapsulationNovellRaw:
+		ss = append(ss, fmt.Sprintf("802.3 length %d", len(f.Payload)), "Novell raw")
+	case f.EtherType < minEtherType:
+		ss = append(ss, fmt.Sprintf("802.3 length %d", f.EtherType))
+	default:
+		if name, ok := etherTypeName(f.EtherType); ok {
+			ss = append(ss, name)
+		} else {
+			ss = append(ss, fmt.Sprintf("ethertype 0x%04x", uint16(f.EtherType)))
+		}
+	}
+
+	ss = append(ss, fmt.Sprintf("%d bytes", f.Length()))
+
+	return strings.Join(ss, ", ")
+}
+
+// macString formats a hardware address, using "?" for an empty address.
+func macString(mac []byte) string {
+	if len(mac) == 0 {
+		return "?"
+	}
+
+	return net.HardwareAddr(mac).String()
+}
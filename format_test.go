@@ -0,0 +1,80 @@
+package ethernet
+
+import (
+	"net"
+	"testing"
+)
+
+func TestFrameString(t *testing.T) {
+	var (
+		dst = Broadcast
+		src = net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55}
+	)
+
+	tests := []struct {
+		desc string
+		f    *Frame
+		s    string
+	}{
+		{
+			desc: "nil",
+			s:    "<nil>",
+		},
+		{
+			desc: "empty",
+			f:    &Frame{EtherType: EtherTypeIPv6},
+			s:    "? > ?, IPv6, 60 bytes",
+		},
+		{
+			desc: "C-VLAN, IPv4",
+			f: &Frame{
+				Destination: dst,
+				Source:      src,
+				VLAN:        &VLAN{Priority: 1, ID: 101},
+				EtherType:   EtherTypeIPv4,
+			},
+			s: "00:11:22:33:44:55 > ff:ff:ff:ff:ff:ff, 802.1Q vid 101 pri 1, IPv4, 64 bytes",
+		},
+		{
+			desc: "S/C-VLAN, unknown EtherType",
+			f: &Frame{
+				Destination: dst,
+				Source:      src,
+				ServiceVLAN: &VLAN{ID: 100, DropEligible: true},
+				VLAN:        &VLAN{ID: 101},
+				EtherType:   0x88b5,
+				Payload:     make([]byte, 100),
+			},
+			s: "00:11:22:33:44:55 > ff:ff:ff:ff:ff:ff, 802.1ad vid 100 pri 0 dei, 802.1Q vid 101 pri 0, ethertype 0x88b5, 122 bytes",
+		},
+		{
+			desc: "SNAP",
+			f: &Frame{
+				Destination: dst,
+				Source:      src,
+				LLC:         &LLC{DSAP: 0xaa, SSAP: 0xaa, Control: 0x03},
+				SNAP:        &SNAP{OUI: [3]byte{0x00, 0x00, 0x0c}, ProtocolID: 0x2000},
+				Payload:     make([]byte, 10),
+			},
+			s: "00:11:22:33:44:55 > ff:ff:ff:ff:ff:ff, 802.3 length 18, LLC dsap 0xaa ssap 0xaa ctrl 0x03, SNAP oui 0x00000c pid 0x2000, 60 bytes",
+		},
+		{
+			desc: "Novell raw",
+			f: &Frame{
+				Destination:   dst,
+				Source:        src,
+				Payload:       []byte{0xff, 0xff},
+				Encapsulation: EncapsulationNovellRaw,
+			},
+			s: "00:11:22:33:44:55 > ff:ff:ff:ff:ff:ff, 802.3 length 2, Novell raw, 60 bytes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if want, got := tt.s, tt.f.String(); want != got {
+				t.Fatalf("unexpected string:\n- want: %q\n-  got: %q", want, got)
+			}
+		})
+	}
+}
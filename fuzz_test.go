@@ -0,0 +1,72 @@
+package ethernet
+
+import (
+	"bytes"
+	"testing"
+)
+
+func FuzzFrameUnmarshal(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		fr := new(Frame)
+		if err := fr.UnmarshalBinary(b); err != nil {
+			return
+		}
+
+		out, err := fr.MarshalBinary()
+		if err != nil {
+			t.Fatalf("failed to marshal unmarshaled frame: %v", err)
+		}
+
+		if want, got := fr.Length(), len(out); want != got {
+			t.Fatalf("unexpected marshaled length: %d != %d", want, got)
+		}
+	})
+}
+
+func FuzzVLAN(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		v := new(VLAN)
+		if err := v.UnmarshalBinary(b); err != nil {
+			return
+		}
+
+		out, err := v.MarshalBinary()
+		if err != nil {
+			t.Fatalf("failed to marshal unmarshaled VLAN: %v", err)
+		}
+
+		if !bytes.Equal(b, out) {
+			t.Fatalf("unexpected VLAN bytes:\n- want: %v\n-  got: %v", b, out)
+		}
+	})
+}
+
+func FuzzFCSRoundTrip(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {
+		fr := new(Frame)
+		if err := fr.UnmarshalBinary(b); err != nil {
+			return
+		}
+
+		// Once marshaled with an FCS, a frame must unmarshal successfully
+		// and produce identical bytes when marshaled again.
+		b1, err := fr.MarshalFCS()
+		if err != nil {
+			t.Fatalf("failed to marshal with FCS: %v", err)
+		}
+
+		fr2 := new(Frame)
+		if err := fr2.UnmarshalFCS(b1); err != nil {
+			t.Fatalf("failed to unmarshal with FCS: %v", err)
+		}
+
+		b2, err := fr2.MarshalFCS()
+		if err != nil {
+			t.Fatalf("failed to marshal with FCS again: %v", err)
+		}
+
+		if !bytes.Equal(b1, b2) {
+			t.Fatalf("unexpected round trip bytes:\n- want: %v\n-  got: %v", b1, b2)
+		}
+	})
+}
@@ -0,0 +1,348 @@
+// Package generator produces streams of Ethernet frames from declarative
+// traffic profiles, for load testing and lab traffic generation.
+package generator
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// Frame size bounds, excluding the frame check sequence.
+const (
+	minFrameSize = 60
+	maxFrameSize = 65535
+)
+
+var (
+	// errInvalidSize is returned when a Profile specifies an out of range
+	// frame size or a negative weight.
+	errInvalidSize = errors.New("generator: frame sizes must be between 60 and 65535 bytes with non-negative weights")
+
+	// errInvalidAddressRange is returned when a Profile specifies an
+	// AddressRange without a valid base address.
+	errInvalidAddressRange = errors.New("generator: address range requires a 6 byte base address")
+
+	// errInvalidVLANRange is returned when a Profile specifies an invalid
+	// VLANRange.
+	errInvalidVLANRange = errors.New("generator: VLAN range must satisfy 1 <= Min <= Max < 4095")
+
+	// errInvalidRate is returned when a Profile specifies a negative rate.
+	errInvalidRate = errors.New("generator: rates must not be negative")
+)
+
+// A Size is a frame size and its relative weight within a distribution.
+type Size struct {
+	// Length is the length of a frame in bytes, excluding the frame check
+	// sequence.
+	Length int
+
+	// Weight is the relative frequency of Length.  A Size with Weight 0 is
+	// never chosen, unless all Sizes have Weight 0.
+	Weight int
+}
+
+// An AddressRange is a contiguous range of hardware addresses.
+type AddressRange struct {
+	// Base is the first address in the range.
+	Base net.HardwareAddr
+
+	// Count is the number of addresses in the range.  If zero or one, only
+	// Base is used.
+	Count int
+}
+
+// A VLANRange is an inclusive range of VLAN IDs.
+type VLANRange struct {
+	Min, Max uint16
+}
+
+// A Ramp specifies a transmit rate which changes linearly over time.
+type Ramp struct {
+	// Start and End are rates in frames per second.  If both are zero,
+	// frames are generated as fast as possible.
+	Start, End float64
+
+	// Duration is the time taken to change from Start to End.  If zero, End
+	// is used immediately.
+	Duration time.Duration
+}
+
+// A Profile declaratively describes a stream of frames.
+type Profile struct {
+	// Template is the frame from which all generated frames are derived.
+	// Its payload is ignored.
+	Template ethernet.Frame
+
+	// Sizes is the distribution of frame sizes.  If empty, all frames are
+	// the minimum size of 60 bytes.
+	Sizes []Size
+
+	// Source and Destination, if not nil, randomize the corresponding
+	// addresses of each frame within a range.
+	Source, Destination *AddressRange
+
+	// VLAN, if not nil, randomizes the VLAN ID of each frame within a range.
+	// If Template has no VLAN tag, one is added.
+	VLAN *VLANRange
+
+	// RandomPayload fills payloads with random bytes rather than zeros.
+	RandomPayload bool
+
+	// Rate specifies the transmit rate of the stream.
+	Rate Ramp
+
+	// Count and Duration, if not zero, stop the stream after the specified
+	// number of frames or interval has elapsed, whichever comes first.
+	Count    uint64
+	Duration time.Duration
+
+	// Seed seeds the random number generator used to produce frames, so
+	// that streams are reproducible.
+	Seed int64
+}
+
+// A Stream produces frames according to a Profile.  A Stream is not safe
+// for concurrent use.
+type Stream struct {
+	p     Profile
+	rng   *rand.Rand
+	total int
+}
+
+// NewStream creates a Stream from Profile p.
+func NewStream(p Profile) (*Stream, error) {
+	total := 0
+	for _, s := range p.Sizes {
+		if s.Length < minFrameSize || s.Length > maxFrameSize || s.Weight < 0 {
+			return nil, errInvalidSize
+		}
+
+		total += s.Weight
+	}
+
+	for _, r := range []*AddressRange{p.Source, p.Destination} {
+		if r != nil && len(r.Base) != 6 {
+			return nil, errInvalidAddressRange
+		}
+	}
+
+	if v := p.VLAN; v != nil && (v.Min == ethernet.VLANNone || v.Min > v.Max || v.Max >= ethernet.VLANMax) {
+		return nil, errInvalidVLANRange
+	}
+
+	if p.Rate.Start < 0 || p.Rate.End < 0 {
+		return nil, errInvalidRate
+	}
+
+	return &Stream{
+		p:     p,
+		rng:   rand.New(rand.NewSource(p.Seed)),
+		total: total,
+	}, nil
+}
+
+// Next generates the next Frame in the stream.
+func (s *Stream) Next() *ethernet.Frame {
+	f := s.p.Template
+
+	if r := s.p.Source; r != nil {
+		f.Source = s.address(r)
+	}
+	if r := s.p.Destination; r != nil {
+		f.Destination = s.address(r)
+	}
+
+	if r := s.p.VLAN; r != nil {
+		v := ethernet.VLAN{}
+		if f.VLAN != nil {
+			v = *f.VLAN
+		}
+
+		v.ID = r.Min + uint16(s.rng.Intn(int(r.Max-r.Min)+1))
+		f.VLAN = &v
+	}
+
+	header := 14
+	if f.ServiceVLAN != nil {
+		header += 4
+	}
+	if f.VLAN != nil {
+		header += 4
+	}
+
+	n := s.size() - header
+	if n < 0 {
+		n = 0
+	}
+
+	f.Payload = make([]byte, n)
+	if s.p.RandomPayload {
+		_, _ = s.rng.Read(f.Payload)
+	}
+
+	return &f
+}
+
+// Rate returns the stream's transmit rate in frames per second after
+// elapsed time has passed.
+func (s *Stream) Rate(elapsed time.Duration) float64 {
+	r := s.p.Rate
+	if elapsed >= r.Duration {
+		return r.End
+	}
+
+	return r.Start + (r.End-r.Start)*float64(elapsed)/float64(r.Duration)
+}
+
+// size chooses a frame size from the distribution.
+func (s *Stream) size() int {
+	switch {
+	case len(s.p.Sizes) == 0:
+		return minFrameSize
+	case s.total == 0:
+		return s.p.Sizes[s.rng.Intn(len(s.p.Sizes))].Length
+	}
+
+	n := s.rng.Intn(s.total)
+	for _, sz := range s.p.Sizes {
+		if n < sz.Weight {
+			return sz.Length
+		}
+
+		n -= sz.Weight
+	}
+
+	// Unreachable.
+	return minFrameSize
+}
+
+// address chooses an address from r.
+func (s *Stream) address(r *AddressRange) net.HardwareAddr {
+	var b [8]byte
+	copy(b[2:], r.Base)
+
+	n := binary.BigEndian.Uint64(b[:])
+	if r.Count > 1 {
+		n += uint64(s.rng.Intn(r.Count))
+	}
+	binary.BigEndian.PutUint64(b[:], n)
+
+	return net.HardwareAddr(append([]byte(nil), b[2:]...))
+}
+
+// Stats contains statistics about a single stream.
+type Stats struct {
+	// Frames and Bytes are the number of frames and bytes written.
+	Frames, Bytes uint64
+}
+
+// Run generates one stream for each of ps concurrently, writing frames to
+// c with address addr, until each stream stops, ctx is canceled, or a write
+// fails.  Stats are returned for each stream, in the same order as ps, even
+// if an error occurs.
+func Run(ctx context.Context, c net.PacketConn, addr net.Addr, ps []Profile) ([]Stats, error) {
+	streams := make([]*Stream, 0, len(ps))
+	for _, p := range ps {
+		s, err := NewStream(p)
+		if err != nil {
+			return nil, err
+		}
+
+		streams = append(streams, s)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		stats = make([]Stats, len(streams))
+		wg    sync.WaitGroup
+
+		once     sync.Once
+		firstErr error
+	)
+
+	wg.Add(len(streams))
+	for i, s := range streams {
+		go func(i int, s *Stream) {
+			defer wg.Done()
+
+			var err error
+			stats[i], err = s.run(ctx, c, addr)
+			if err != nil {
+				// Stop all other streams on the first error.
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(i, s)
+	}
+	wg.Wait()
+
+	return stats, firstErr
+}
+
+// run generates frames and writes them to c at the stream's rate.
+func (s *Stream) run(ctx context.Context, c net.PacketConn, addr net.Addr) (Stats, error) {
+	var (
+		stats Stats
+		paced = s.p.Rate.Start != 0 || s.p.Rate.End != 0
+		start = time.Now()
+		next  = start
+	)
+
+	for s.p.Count == 0 || stats.Frames < s.p.Count {
+		elapsed := next.Sub(start)
+		if s.p.Duration != 0 && elapsed >= s.p.Duration {
+			break
+		}
+
+		if d := time.Until(next); d > 0 {
+			timer := time.NewTimer(d)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return stats, ctx.Err()
+			}
+		} else if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+
+		if !paced {
+			next = time.Now()
+		} else if rate := s.Rate(elapsed); rate > 0 {
+			next = next.Add(time.Duration(float64(time.Second) / rate))
+		} else if elapsed >= s.p.Rate.Duration {
+			// The rate has ramped down to zero.
+			break
+		} else {
+			// The rate is ramping up from zero; check again shortly.
+			next = next.Add(time.Millisecond)
+			continue
+		}
+
+		b, err := s.Next().MarshalBinary()
+		if err != nil {
+			return stats, err
+		}
+
+		n, err := c.WriteTo(b, addr)
+		if err != nil {
+			return stats, err
+		}
+
+		stats.Frames++
+		stats.Bytes += uint64(n)
+	}
+
+	return stats, nil
+}
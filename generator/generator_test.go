@@ -0,0 +1,212 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+var (
+	srcMAC = net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0x00, 0x00}
+	dstMAC = net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xff, 0xff}
+)
+
+func TestNewStreamErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		p    Profile
+		err  error
+	}{
+		{
+			desc: "runt size",
+			p:    Profile{Sizes: []Size{{Length: 59, Weight: 1}}},
+			err:  errInvalidSize,
+		},
+		{
+			desc: "negative weight",
+			p:    Profile{Sizes: []Size{{Length: 64, Weight: -1}}},
+			err:  errInvalidSize,
+		},
+		{
+			desc: "short address",
+			p:    Profile{Source: &AddressRange{Base: srcMAC[:5]}},
+			err:  errInvalidAddressRange,
+		},
+		{
+			desc: "VLAN none",
+			p:    Profile{VLAN: &VLANRange{Max: 10}},
+			err:  errInvalidVLANRange,
+		},
+		{
+			desc: "VLAN inverted",
+			p:    Profile{VLAN: &VLANRange{Min: 10, Max: 5}},
+			err:  errInvalidVLANRange,
+		},
+		{
+			desc: "VLAN max",
+			p:    Profile{VLAN: &VLANRange{Min: 1, Max: ethernet.VLANMax}},
+			err:  errInvalidVLANRange,
+		},
+		{
+			desc: "negative rate",
+			p:    Profile{Rate: Ramp{End: -1}},
+			err:  errInvalidRate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if _, err := NewStream(tt.p); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestStreamNext(t *testing.T) {
+	s, err := NewStream(Profile{
+		Template: ethernet.Frame{
+			Destination: dstMAC,
+			VLAN:        &ethernet.VLAN{Priority: ethernet.PriorityVoice},
+			EtherType:   ethernet.EtherTypeIPv4,
+		},
+		Sizes: []Size{
+			{Length: 64, Weight: 1},
+			{Length: 1518, Weight: 3},
+			{Length: 9000},
+		},
+		Source:        &AddressRange{Base: srcMAC, Count: 16},
+		VLAN:          &VLANRange{Min: 100, Max: 103},
+		RandomPayload: true,
+		Seed:          1,
+	})
+	if err != nil {
+		t.Fatalf("failed to create stream: %v", err)
+	}
+
+	sizes := make(map[int]int)
+	for i := 0; i < 1000; i++ {
+		f := s.Next()
+
+		b, err := f.MarshalBinary()
+		if err != nil {
+			t.Fatalf("failed to marshal frame: %v", err)
+		}
+		sizes[len(b)]++
+
+		if !bytes.Equal(f.Source[:5], srcMAC[:5]) || f.Source[5] >= 16 {
+			t.Fatalf("source address out of range: %s", f.Source)
+		}
+		if want, got := dstMAC, f.Destination; !bytes.Equal(want, got) {
+			t.Fatalf("unexpected destination: %s != %s", want, got)
+		}
+		if f.VLAN.ID < 100 || f.VLAN.ID > 103 {
+			t.Fatalf("VLAN ID out of range: %d", f.VLAN.ID)
+		}
+		if want, got := ethernet.PriorityVoice, f.VLAN.Priority; want != got {
+			t.Fatalf("unexpected priority: %v != %v", want, got)
+		}
+	}
+
+	if n := sizes[9000]; n != 0 {
+		t.Fatalf("zero weight size chosen %d times", n)
+	}
+	if sizes[1518] <= sizes[64] {
+		t.Fatalf("size distribution does not respect weights: %v", sizes)
+	}
+}
+
+func TestStreamRate(t *testing.T) {
+	s, err := NewStream(Profile{
+		Rate: Ramp{Start: 100, End: 200, Duration: 10 * time.Second},
+	})
+	if err != nil {
+		t.Fatalf("failed to create stream: %v", err)
+	}
+
+	for _, tt := range []struct {
+		elapsed time.Duration
+		rate    float64
+	}{
+		{elapsed: 0, rate: 100},
+		{elapsed: 5 * time.Second, rate: 150},
+		{elapsed: 10 * time.Second, rate: 200},
+		{elapsed: time.Minute, rate: 200},
+	} {
+		if want, got := tt.rate, s.Rate(tt.elapsed); want != got {
+			t.Fatalf("unexpected rate at %v: %v != %v", tt.elapsed, want, got)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	c := &testConn{}
+	stats, err := Run(context.Background(), c, nil, []Profile{
+		{Count: 10},
+		{Count: 5, Sizes: []Size{{Length: 100}}},
+	})
+	if err != nil {
+		t.Fatalf("failed to run: %v", err)
+	}
+
+	want := []Stats{
+		{Frames: 10, Bytes: 600},
+		{Frames: 5, Bytes: 500},
+	}
+
+	for i := range want {
+		if want, got := want[i], stats[i]; want != got {
+			t.Fatalf("unexpected stats for stream %d: %+v != %+v", i, want, got)
+		}
+	}
+
+	if want, got := 15, c.count(); want != got {
+		t.Fatalf("unexpected number of writes: %v != %v", want, got)
+	}
+}
+
+func TestRunWriteError(t *testing.T) {
+	errWrite := errors.New("write error")
+
+	_, err := Run(context.Background(), &testConn{err: errWrite}, nil, []Profile{
+		{},
+		{Rate: Ramp{End: 1}},
+	})
+	if err != errWrite {
+		t.Fatalf("unexpected error: %v != %v", errWrite, err)
+	}
+}
+
+// testConn is a net.PacketConn which counts writes.
+type testConn struct {
+	net.PacketConn
+
+	mu     sync.Mutex
+	writes int
+	err    error
+}
+
+func (c *testConn) WriteTo(b []byte, _ net.Addr) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	c.writes++
+	return len(b), nil
+}
+
+func (c *testConn) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.writes
+}
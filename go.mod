@@ -1,8 +1,13 @@
 module github.com/mdlayher/ethernet
 
-go 1.12
+go 1.20
+
+require github.com/mdlayher/packet v1.0.0
 
 require (
-	github.com/mdlayher/packet v1.0.0
+	github.com/josharian/native v1.0.0 // indirect
+	github.com/mdlayher/socket v0.2.1 // indirect
 	golang.org/x/net v0.0.0-20190603091049-60506f45cf65 // indirect
+	golang.org/x/sync v0.0.0-20210220032951-036812b2e83c // indirect
+	golang.org/x/sys v0.0.0-20220209214540-3681064d5158 // indirect
 )
@@ -0,0 +1,214 @@
+// Package gre implements marshaling and unmarshaling of GRE headers, as
+// described in RFC 2784 and RFC 2890, and of Ethernet frames carried by GRE
+// using Transparent Ethernet Bridging.
+package gre
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// EtherTypeTEB is the GRE protocol type which indicates a bridged Ethernet
+// frame.
+const EtherTypeTEB = ethernet.EtherTypeTEB
+
+// GRE header flags.
+const (
+	flagChecksum = 0x8000
+	flagKey      = 0x2000
+	flagSequence = 0x1000
+
+	// versionMask masks the version bits, which must be zero.
+	versionMask = 0x0007
+)
+
+var (
+	// ErrInvalidChecksum is returned when a GRE packet's checksum is
+	// incorrect.
+	ErrInvalidChecksum = errors.New("gre: invalid checksum")
+
+	// ErrInvalidVersion is returned when a GRE header's version is not
+	// zero.
+	ErrInvalidVersion = errors.New("gre: invalid version")
+)
+
+// A Header is a GRE header.
+type Header struct {
+	// Checksum specifies whether the header carries a checksum of the GRE
+	// header and payload.  The checksum is computed when marshaling, and
+	// verified when unmarshaling.
+	Checksum bool
+
+	// KeyPresent specifies whether the header carries Key.
+	KeyPresent bool
+	Key        uint32
+
+	// SequencePresent specifies whether the header carries Sequence.
+	SequencePresent bool
+	Sequence        uint32
+
+	// Protocol specifies the EtherType of the payload.
+	Protocol ethernet.EtherType
+}
+
+// length returns the length of a Header in binary form.
+func (h *Header) length() int {
+	n := 4
+	if h.Checksum {
+		n += 4
+	}
+	if h.KeyPresent {
+		n += 4
+	}
+	if h.SequencePresent {
+		n += 4
+	}
+
+	return n
+}
+
+// Marshal allocates a byte slice and marshals a Header and payload into a
+// GRE packet.
+func (h *Header) Marshal(payload []byte) ([]byte, error) {
+	b := make([]byte, h.length()+len(payload))
+
+	var flags uint16
+	if h.Checksum {
+		flags |= flagChecksum
+	}
+	if h.KeyPresent {
+		flags |= flagKey
+	}
+	if h.SequencePresent {
+		flags |= flagSequence
+	}
+	binary.BigEndian.PutUint16(b[0:2], flags)
+	binary.BigEndian.PutUint16(b[2:4], uint16(h.Protocol))
+
+	// The checksum is filled in after the rest of the packet.
+	n := 4
+	if h.Checksum {
+		n += 4
+	}
+	if h.KeyPresent {
+		binary.BigEndian.PutUint32(b[n:n+4], h.Key)
+		n += 4
+	}
+	if h.SequencePresent {
+		binary.BigEndian.PutUint32(b[n:n+4], h.Sequence)
+		n += 4
+	}
+	copy(b[n:], payload)
+
+	if h.Checksum {
+		binary.BigEndian.PutUint16(b[4:6], checksum(b))
+	}
+
+	return b, nil
+}
+
+// Unmarshal unmarshals a GRE packet into a Header, returning the payload
+// which follows it.  The payload aliases b.
+func (h *Header) Unmarshal(b []byte) ([]byte, error) {
+	if len(b) < 4 {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	flags := binary.BigEndian.Uint16(b[0:2])
+	if flags&versionMask != 0 {
+		return nil, ErrInvalidVersion
+	}
+
+	hh := Header{
+		Checksum:        flags&flagChecksum != 0,
+		KeyPresent:      flags&flagKey != 0,
+		SequencePresent: flags&flagSequence != 0,
+		Protocol:        ethernet.EtherType(binary.BigEndian.Uint16(b[2:4])),
+	}
+
+	n := hh.length()
+	if len(b) < n {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	i := 4
+	if hh.Checksum {
+		if checksum(b) != 0 {
+			return nil, ErrInvalidChecksum
+		}
+		i += 4
+	}
+	if hh.KeyPresent {
+		hh.Key = binary.BigEndian.Uint32(b[i : i+4])
+		i += 4
+	}
+	if hh.SequencePresent {
+		hh.Sequence = binary.BigEndian.Uint32(b[i : i+4])
+	}
+
+	*h = hh
+	return b[n:], nil
+}
+
+// ParseTEB unmarshals a GRE packet carrying a bridged Ethernet frame, and
+// validates the frame using Frame.Validate.  If the GRE protocol type is not
+// EtherTypeTEB, ethernet.ErrInvalidEtherType is returned.
+func ParseTEB(b []byte) (*Header, *ethernet.Frame, error) {
+	h := new(Header)
+	payload, err := h.Unmarshal(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	if h.Protocol != EtherTypeTEB {
+		return nil, nil, ethernet.ErrInvalidEtherType
+	}
+
+	f := new(ethernet.Frame)
+	if err := f.UnmarshalBinary(payload); err != nil {
+		return nil, nil, err
+	}
+	if err := f.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	return h, f, nil
+}
+
+// MarshalTEB validates Frame f using Frame.Validate, and marshals it into a
+// GRE packet using the options specified in h.  h.Protocol is ignored, and
+// EtherTypeTEB is used instead.
+func MarshalTEB(h *Header, f *ethernet.Frame) ([]byte, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	fb, err := f.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	hh := *h
+	hh.Protocol = EtherTypeTEB
+
+	return hh.Marshal(fb)
+}
+
+// checksum computes the Internet checksum of b, as described in RFC 1071.
+func checksum(b []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(b); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(b[i : i+2]))
+	}
+	if len(b)%2 != 0 {
+		sum += uint32(b[len(b)-1]) << 8
+	}
+
+	for sum > 0xffff {
+		sum = sum>>16 + sum&0xffff
+	}
+
+	return ^uint16(sum)
+}
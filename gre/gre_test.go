@@ -0,0 +1,155 @@
+package gre
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestHeaderMarshalUnmarshal(t *testing.T) {
+	tests := []struct {
+		desc string
+		h    *Header
+		b    []byte
+	}{
+		{
+			desc: "minimal",
+			h:    &Header{Protocol: ethernet.EtherTypeIPv4},
+			b: []byte{
+				0x00, 0x00, 0x08, 0x00,
+				0xaa,
+			},
+		},
+		{
+			desc: "all options",
+			h: &Header{
+				Checksum:        true,
+				KeyPresent:      true,
+				Key:             0xdeadbeef,
+				SequencePresent: true,
+				Sequence:        1,
+				Protocol:        EtherTypeTEB,
+			},
+			b: []byte{
+				0xb0, 0x00, 0x65, 0x58,
+				0xa3, 0x07, 0x00, 0x00,
+				0xde, 0xad, 0xbe, 0xef,
+				0x00, 0x00, 0x00, 0x01,
+				0xaa,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := tt.h.Marshal([]byte{0xaa})
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			if want, got := tt.b, b; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected bytes:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			h := new(Header)
+			payload, err := h.Unmarshal(b)
+			if err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+
+			if !reflect.DeepEqual(tt.h, h) {
+				t.Fatalf("unexpected header:\n- want: %#v\n-  got: %#v", tt.h, h)
+			}
+			if want, got := []byte{0xaa}, payload; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected payload: %v != %v", want, got)
+			}
+		})
+	}
+}
+
+func TestHeaderUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		b    []byte
+		err  error
+	}{
+		{
+			desc: "short",
+			b:    []byte{0x00, 0x00, 0x65},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "short options",
+			b:    []byte{0x20, 0x00, 0x65, 0x58, 0x00},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "bad version",
+			b:    []byte{0x00, 0x01, 0x65, 0x58},
+			err:  ErrInvalidVersion,
+		},
+		{
+			desc: "bad checksum",
+			b: []byte{
+				0x80, 0x00, 0x65, 0x58,
+				0x00, 0x00, 0x00, 0x00,
+			},
+			err: ErrInvalidChecksum,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if _, err := new(Header).Unmarshal(tt.b); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestTEB(t *testing.T) {
+	f := &ethernet.Frame{
+		Destination: ethernet.Broadcast,
+		Source:      []byte{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+		EtherType:   ethernet.EtherTypeIPv4,
+		Payload:     bytes.Repeat([]byte{0xff}, 46),
+	}
+
+	b, err := MarshalTEB(&Header{KeyPresent: true, Key: 10}, f)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	h, got, err := ParseTEB(b)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	wantH := &Header{KeyPresent: true, Key: 10, Protocol: EtherTypeTEB}
+	if !reflect.DeepEqual(wantH, h) {
+		t.Fatalf("unexpected header:\n- want: %#v\n-  got: %#v", wantH, h)
+	}
+	if !reflect.DeepEqual(f, got) {
+		t.Fatalf("unexpected frame:\n- want: %#v\n-  got: %#v", f, got)
+	}
+}
+
+func TestTEBErrors(t *testing.T) {
+	// Invalid frames are rejected before marshaling.
+	_, err := MarshalTEB(&Header{}, &ethernet.Frame{})
+	if !errors.Is(err, ethernet.ErrInvalidHardwareAddr) {
+		t.Fatalf("expected ErrInvalidHardwareAddr, but got: %v", err)
+	}
+
+	b, err := (&Header{Protocol: ethernet.EtherTypeIPv4}).Marshal(nil)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	if _, _, err := ParseTEB(b); err != ethernet.ErrInvalidEtherType {
+		t.Fatalf("expected ErrInvalidEtherType, but got: %v", err)
+	}
+}
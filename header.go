@@ -0,0 +1,63 @@
+package ethernet
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+)
+
+// A Header describes the link-layer header of an Ethernet frame, as
+// reported by ParseHeader.
+type Header struct {
+	// Destination and Source alias the hardware addresses in the byte
+	// slice passed to ParseHeader.
+	Destination net.HardwareAddr
+	Source      net.HardwareAddr
+
+	// Tags is the number of 802.1Q VLAN tags which precede the EtherType.
+	Tags int
+
+	// EtherType is the EtherType or IEEE 802.3 length which follows any
+	// VLAN tags.
+	EtherType EtherType
+
+	// PayloadOffset is the offset of the first byte following the
+	// EtherType.
+	PayloadOffset int
+}
+
+// ParseHeader parses the link-layer header of the Ethernet frame in b,
+// without allocating or copying any data.  ParseHeader is intended for
+// classification of frames where only the header is of interest; use
+// Frame.UnmarshalBinary to fully parse a frame.
+//
+// Any number of stacked tags using EtherTypeVLAN or EtherTypeServiceVLAN
+// are counted.  If b is too short to contain the header, a *FieldError
+// wrapping io.ErrUnexpectedEOF is returned.
+func ParseHeader(b []byte) (Header, error) {
+	if len(b) < 14 {
+		return Header{}, headerError(len(b))
+	}
+
+	h := Header{
+		Destination: b[0:6:6],
+		Source:      b[6:12:12],
+	}
+
+	n := 14
+	et := EtherType(binary.BigEndian.Uint16(b[n-2 : n]))
+	for et == EtherTypeVLAN || et == EtherTypeServiceVLAN {
+		if len(b[n:]) < 4 {
+			return Header{}, &FieldError{Field: "VLAN", Offset: n - 2, Err: io.ErrUnexpectedEOF}
+		}
+
+		h.Tags++
+		n += 4
+		et = EtherType(binary.BigEndian.Uint16(b[n-2 : n]))
+	}
+
+	h.EtherType = et
+	h.PayloadOffset = n
+
+	return h, nil
+}
@@ -0,0 +1,132 @@
+package ethernet
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestParseHeader(t *testing.T) {
+	addrs := []byte{
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0xde, 0xad, 0xbe, 0xef, 0xde, 0xad,
+	}
+
+	tests := []struct {
+		desc   string
+		b      []byte
+		h      Header
+		field  string
+		offset int
+	}{
+		{
+			desc:  "short destination",
+			b:     addrs[:3],
+			field: "Destination",
+		},
+		{
+			desc:   "short EtherType",
+			b:      addrs,
+			field:  "EtherType",
+			offset: 12,
+		},
+		{
+			desc: "untagged",
+			b: append(append([]byte{}, addrs...),
+				0x08, 0x06,
+				0xde, 0xad,
+			),
+			h: Header{
+				EtherType:     EtherTypeARP,
+				PayloadOffset: 14,
+			},
+		},
+		{
+			desc: "truncated tag",
+			b: append(append([]byte{}, addrs...),
+				0x81, 0x00,
+				0x00, 0x01,
+			),
+			field:  "VLAN",
+			offset: 12,
+		},
+		{
+			desc: "Q-in-Q",
+			b: append(append([]byte{}, addrs...),
+				0x88, 0xa8,
+				0x00, 0x0a,
+				0x81, 0x00,
+				0x00, 0x14,
+				0x86, 0xdd,
+			),
+			h: Header{
+				Tags:          2,
+				EtherType:     EtherTypeIPv6,
+				PayloadOffset: 22,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			h, err := ParseHeader(tt.b)
+			if tt.field != "" {
+				var fe *FieldError
+				if !errors.As(err, &fe) {
+					t.Fatalf("expected *FieldError, but got: %v", err)
+				}
+
+				if want, got := tt.field, fe.Field; want != got {
+					t.Fatalf("unexpected field: %q != %q", want, got)
+				}
+				if want, got := tt.offset, fe.Offset; want != got {
+					t.Fatalf("unexpected offset: %v != %v", want, got)
+				}
+				if !errors.Is(err, io.ErrUnexpectedEOF) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to parse header: %v", err)
+			}
+
+			if want, got := addrs[0:6], h.Destination; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected destination: %v != %v", want, got)
+			}
+			if want, got := addrs[6:12], h.Source; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected source: %v != %v", want, got)
+			}
+
+			h.Destination, h.Source = nil, nil
+			if want, got := tt.h, h; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected header:\n- want: %+v\n-  got: %+v", want, got)
+			}
+		})
+	}
+}
+
+func TestParseHeaderAllocs(t *testing.T) {
+	b, err := (&Frame{
+		Destination: Broadcast,
+		Source:      Broadcast,
+		VLAN:        &VLAN{ID: 10},
+		EtherType:   EtherTypeIPv4,
+	}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := ParseHeader(b); err != nil {
+			t.Fatalf("failed to parse header: %v", err)
+		}
+	})
+
+	if allocs != 0 {
+		t.Fatalf("unexpected allocations: %v", allocs)
+	}
+}
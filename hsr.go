@@ -0,0 +1,94 @@
+package ethernet
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// EtherTypeHSR is the EtherType of an IEC 62439-3 High-availability Seamless
+// Redundancy (HSR) tag.
+const EtherTypeHSR EtherType = 0x892f
+
+// hsrLen is the length of an HSR tag, excluding its own EtherType but
+// including the EtherType of the encapsulated payload.
+const hsrLen = 6
+
+// ErrInvalidHSR is returned when an HSR tag's path ID does not fit in 4 bits
+// or its LSDU size does not fit in 12 bits.
+var ErrInvalidHSR = errors.New("invalid HSR tag")
+
+// An HSRTag is an IEC 62439-3 High-availability Seamless Redundancy tag,
+// used to detect duplicate frames circulating in a ring network.  An HSRTag
+// is carried at the beginning of the payload of a Frame whose EtherType is
+// EtherTypeHSR.
+type HSRTag struct {
+	// PathID specifies the 4 bit path identifier, which identifies the
+	// ring port on which a frame was sent.
+	PathID uint8
+
+	// LSDUSize specifies the 12 bit size of the link service data unit,
+	// which includes the HSR tag and the payload which follows it.
+	LSDUSize uint16
+
+	// SequenceNumber specifies the sequence number of the frame.
+	SequenceNumber uint16
+
+	// EtherType specifies the EtherType of the payload following the HSR
+	// tag.
+	EtherType EtherType
+}
+
+// MarshalBinary allocates a byte slice and marshals an HSRTag into binary
+// form.
+func (h *HSRTag) MarshalBinary() ([]byte, error) {
+	b := make([]byte, hsrLen)
+	_, err := h.read(b)
+	return b, err
+}
+
+// read reads data from an HSRTag into b.  read is used to marshal an HSRTag
+// into binary form, but does not allocate on its own.
+func (h *HSRTag) read(b []byte) (int, error) {
+	if h.PathID > 0x0f || h.LSDUSize > 0x0fff {
+		return 0, ErrInvalidHSR
+	}
+
+	//  4 bits: path ID
+	// 12 bits: LSDU size
+	binary.BigEndian.PutUint16(b[0:2], uint16(h.PathID)<<12|h.LSDUSize)
+	binary.BigEndian.PutUint16(b[2:4], h.SequenceNumber)
+	binary.BigEndian.PutUint16(b[4:6], uint16(h.EtherType))
+	return hsrLen, nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into an HSRTag.  Trailing bytes
+// after the HSR tag are ignored.
+func (h *HSRTag) UnmarshalBinary(b []byte) error {
+	if len(b) < hsrLen {
+		return io.ErrUnexpectedEOF
+	}
+
+	ub := binary.BigEndian.Uint16(b[0:2])
+	h.PathID = uint8(ub >> 12)
+	h.LSDUSize = ub & 0x0fff
+	h.SequenceNumber = binary.BigEndian.Uint16(b[2:4])
+	h.EtherType = EtherType(binary.BigEndian.Uint16(b[4:6]))
+	return nil
+}
+
+// HSRTag unmarshals the HSR tag at the beginning of a Frame's payload, and
+// returns it along with the remainder of the payload.  If the Frame's
+// EtherType is not EtherTypeHSR, ErrInvalidEtherType is returned.
+func (f *Frame) HSRTag() (*HSRTag, []byte, error) {
+	if f.EtherType != EtherTypeHSR {
+		return nil, nil, ErrInvalidEtherType
+	}
+
+	h := new(HSRTag)
+	if err := h.UnmarshalBinary(f.Payload); err != nil {
+		return nil, nil, err
+	}
+
+	return h, f.Payload[hsrLen:], nil
+}
@@ -0,0 +1,52 @@
+package ethernet
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestFrameHSRTag(t *testing.T) {
+	h := &HSRTag{
+		PathID:         1,
+		LSDUSize:       52,
+		SequenceNumber: 0xbeef,
+		EtherType:      EtherTypeIPv4,
+	}
+
+	hb, err := h.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	if want, got := []byte{0x10, 0x34, 0xbe, 0xef, 0x08, 0x00}, hb; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected HSR tag bytes:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	f := &Frame{
+		EtherType: EtherTypeHSR,
+		Payload:   append(hb, make([]byte, 46)...),
+	}
+
+	got, payload, err := f.HSRTag()
+	if err != nil {
+		t.Fatalf("failed to get HSR tag: %v", err)
+	}
+
+	if !reflect.DeepEqual(h, got) {
+		t.Fatalf("unexpected HSR tag:\n- want: %v\n-  got: %v", h, got)
+	}
+	if want, got := 46, len(payload); want != got {
+		t.Fatalf("unexpected payload length: %d != %d", want, got)
+	}
+
+	if _, err := (&HSRTag{PathID: 16}).MarshalBinary(); err != ErrInvalidHSR {
+		t.Fatalf("unexpected error: %v != %v", ErrInvalidHSR, err)
+	}
+
+	f.Payload = f.Payload[:5]
+	if _, _, err := f.HSRTag(); err != io.ErrUnexpectedEOF {
+		t.Fatalf("unexpected error: %v != %v", io.ErrUnexpectedEOF, err)
+	}
+}
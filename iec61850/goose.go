@@ -0,0 +1,199 @@
+package iec61850
+
+import (
+	"net"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// EtherTypeGOOSE is the EtherType which indicates a GOOSE frame in a Frame.
+const EtherTypeGOOSE = ethernet.EtherTypeGOOSE
+
+// BER tags of a goosePdu and its fields.
+const (
+	tagGoosePDU          = 0x61
+	tagGoCBRef           = 0x80
+	tagTimeAllowedToLive = 0x81
+	tagDatSet            = 0x82
+	tagGoID              = 0x83
+	tagT                 = 0x84
+	tagStNum             = 0x85
+	tagSqNum             = 0x86
+	tagSimulation        = 0x87
+	tagConfRev           = 0x88
+	tagNdsCom            = 0x89
+	tagNumDatSetEntries  = 0x8a
+	tagAllData           = 0xab
+)
+
+// GOOSEDestination returns the multicast destination of GOOSE frames with
+// address index i, in the range 01:0c:cd:01:00:00 to 01:0c:cd:01:01:ff
+// recommended by IEC 61850-8-1.
+func GOOSEDestination(i uint16) net.HardwareAddr {
+	return net.HardwareAddr{0x01, 0x0c, 0xcd, 0x01, uint8(i >> 8), uint8(i)}
+}
+
+// A GOOSE is a GOOSE frame, carrying a goosePdu.
+type GOOSE struct {
+	Header
+
+	// GoCBRef specifies the reference of the GOOSE control block.
+	GoCBRef string
+
+	// TimeAllowedToLive specifies the time in milliseconds before which
+	// the next frame is expected.
+	TimeAllowedToLive uint32
+
+	// DatSet specifies the reference of the dataset.
+	DatSet string
+
+	// GoID specifies the optional GOOSE identifier.  GoID is omitted when
+	// marshaled if it is empty.
+	GoID string
+
+	// T specifies the time of the last change in state.
+	T Timestamp
+
+	// StNum and SqNum specify the state and sequence numbers.
+	StNum uint32
+	SqNum uint32
+
+	// Simulation reports whether the frame was sent by a test device.
+	Simulation bool
+
+	// ConfRev specifies the configuration revision of the dataset.
+	ConfRev uint32
+
+	// NdsCom reports whether the GOOSE control block needs commissioning.
+	NdsCom bool
+
+	// NumDatSetEntries specifies the number of entries in AllData.
+	NumDatSetEntries uint32
+
+	// AllData specifies the BER encoded contents of the allData sequence.
+	AllData []byte
+}
+
+// ParseGOOSE unmarshals the GOOSE frame carried in the payload of Frame f.
+// If f's EtherType is not EtherTypeGOOSE, ethernet.ErrInvalidEtherType is
+// returned.
+func ParseGOOSE(f *ethernet.Frame) (*GOOSE, error) {
+	if f.EtherType != EtherTypeGOOSE {
+		return nil, ethernet.ErrInvalidEtherType
+	}
+
+	g := new(GOOSE)
+	if err := g.UnmarshalBinary(f.Payload); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// Frame marshals a GOOSE into the payload of an Ethernet frame sent from
+// hardware address src to hardware address dst.
+func (g *GOOSE) Frame(dst, src net.HardwareAddr) (*ethernet.Frame, error) {
+	b, err := g.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ethernet.Frame{
+		Destination: dst,
+		Source:      src,
+		EtherType:   EtherTypeGOOSE,
+		Payload:     b,
+	}, nil
+}
+
+// MarshalBinary allocates a byte slice and marshals a GOOSE into binary
+// form.
+func (g *GOOSE) MarshalBinary() ([]byte, error) {
+	var t [timestampLen]byte
+	g.T.put(t[:])
+
+	var pdu []byte
+	pdu = appendTLV(pdu, tagGoCBRef, []byte(g.GoCBRef))
+	pdu = appendUint(pdu, tagTimeAllowedToLive, g.TimeAllowedToLive)
+	pdu = appendTLV(pdu, tagDatSet, []byte(g.DatSet))
+	if g.GoID != "" {
+		pdu = appendTLV(pdu, tagGoID, []byte(g.GoID))
+	}
+	pdu = appendTLV(pdu, tagT, t[:])
+	pdu = appendUint(pdu, tagStNum, g.StNum)
+	pdu = appendUint(pdu, tagSqNum, g.SqNum)
+	pdu = appendBool(pdu, tagSimulation, g.Simulation)
+	pdu = appendUint(pdu, tagConfRev, g.ConfRev)
+	pdu = appendBool(pdu, tagNdsCom, g.NdsCom)
+	pdu = appendUint(pdu, tagNumDatSetEntries, g.NumDatSetEntries)
+	pdu = appendTLV(pdu, tagAllData, g.AllData)
+
+	b := appendTLV(make([]byte, headerLen), tagGoosePDU, pdu)
+	if err := g.Header.put(b, len(b)-headerLen); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into a GOOSE.  Unknown goosePdu
+// fields are ignored.
+func (g *GOOSE) UnmarshalBinary(b []byte) error {
+	h, apdu, err := parseHeader(b)
+	if err != nil {
+		return err
+	}
+
+	tag, pdu, _, err := parseTLV(apdu)
+	if err != nil {
+		return err
+	}
+	if tag != tagGoosePDU {
+		return ErrInvalidPDU
+	}
+
+	out := GOOSE{Header: h}
+	for len(pdu) > 0 {
+		var v []byte
+		tag, v, pdu, err = parseTLV(pdu)
+		if err != nil {
+			return err
+		}
+
+		switch tag {
+		case tagGoCBRef:
+			out.GoCBRef = string(v)
+		case tagTimeAllowedToLive:
+			out.TimeAllowedToLive, err = parseUint(v)
+		case tagDatSet:
+			out.DatSet = string(v)
+		case tagGoID:
+			out.GoID = string(v)
+		case tagT:
+			out.T, err = parseTimestamp(v)
+		case tagStNum:
+			out.StNum, err = parseUint(v)
+		case tagSqNum:
+			out.SqNum, err = parseUint(v)
+		case tagSimulation:
+			out.Simulation, err = parseBool(v)
+		case tagConfRev:
+			out.ConfRev, err = parseUint(v)
+		case tagNdsCom:
+			out.NdsCom, err = parseBool(v)
+		case tagNumDatSetEntries:
+			out.NumDatSetEntries, err = parseUint(v)
+		case tagAllData:
+			if len(v) > 0 {
+				out.AllData = make([]byte, len(v))
+				copy(out.AllData, v)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	*g = out
+	return nil
+}
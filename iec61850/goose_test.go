@@ -0,0 +1,158 @@
+package iec61850
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestGOOSEMarshalUnmarshal(t *testing.T) {
+	g := &GOOSE{
+		Header:            Header{APPID: 0x0001},
+		GoCBRef:           "IED/LLN0$GO$a",
+		TimeAllowedToLive: 2000,
+		DatSet:            "IED/LLN0$ds",
+		T:                 Timestamp{Seconds: 1, Fraction: 0x800000, Quality: 0x0a},
+		StNum:             1,
+		SqNum:             0x80,
+		ConfRev:           1,
+		NumDatSetEntries:  1,
+		// A single BOOLEAN true.
+		AllData: []byte{0x83, 0x01, 0x01},
+	}
+
+	b, err := g.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	want := []byte{
+		// Header.
+		0x00, 0x01, 0x00, 0x4c, 0x00, 0x00, 0x00, 0x00,
+		// goosePdu.
+		0x61, 0x42,
+		0x80, 0x0d, 'I', 'E', 'D', '/', 'L', 'L', 'N', '0', '$', 'G', 'O', '$', 'a',
+		0x81, 0x02, 0x07, 0xd0,
+		0x82, 0x0b, 'I', 'E', 'D', '/', 'L', 'L', 'N', '0', '$', 'd', 's',
+		0x84, 0x08, 0x00, 0x00, 0x00, 0x01, 0x80, 0x00, 0x00, 0x0a,
+		0x85, 0x01, 0x01,
+		0x86, 0x02, 0x00, 0x80,
+		0x87, 0x01, 0x00,
+		0x88, 0x01, 0x01,
+		0x89, 0x01, 0x00,
+		0x8a, 0x01, 0x01,
+		0xab, 0x03, 0x83, 0x01, 0x01,
+	}
+
+	if !bytes.Equal(want, b) {
+		t.Fatalf("unexpected bytes:\n- want: %v\n-  got: %v", want, b)
+	}
+
+	g2 := new(GOOSE)
+	if err := g2.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if !reflect.DeepEqual(g, g2) {
+		t.Fatalf("unexpected GOOSE:\n- want: %#v\n-  got: %#v", g, g2)
+	}
+}
+
+func TestGOOSEUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		b    []byte
+		err  error
+	}{
+		{
+			desc: "short header",
+			b:    []byte{0x00, 0x01},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "not a goosePdu",
+			b:    []byte{0x00, 0x01, 0x00, 0x0a, 0x00, 0x00, 0x00, 0x00, 0x60, 0x00},
+			err:  ErrInvalidPDU,
+		},
+		{
+			desc: "truncated goosePdu",
+			b:    []byte{0x00, 0x01, 0x00, 0x0a, 0x00, 0x00, 0x00, 0x00, 0x61, 0x02},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "bad stNum",
+			b: []byte{
+				0x00, 0x01, 0x00, 0x0d, 0x00, 0x00, 0x00, 0x00,
+				0x61, 0x03, 0x85, 0x01, 0xff,
+			},
+			err: ErrInvalidBER,
+		},
+		{
+			desc: "bad simulation",
+			b: []byte{
+				0x00, 0x01, 0x00, 0x0c, 0x00, 0x00, 0x00, 0x00,
+				0x61, 0x02, 0x87, 0x00,
+			},
+			err: ErrInvalidBER,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := new(GOOSE).UnmarshalBinary(tt.b); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestGOOSEFrameParse(t *testing.T) {
+	src := net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}
+	dst := GOOSEDestination(0x0102)
+
+	if want, got := (net.HardwareAddr{0x01, 0x0c, 0xcd, 0x01, 0x01, 0x02}), dst; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected destination: %v != %v", want, got)
+	}
+
+	g := &GOOSE{
+		Header:     Header{APPID: 0x3fff},
+		GoCBRef:    "a",
+		DatSet:     "b",
+		GoID:       "c",
+		Simulation: true,
+		NdsCom:     true,
+	}
+
+	f, err := g.Frame(dst, src)
+	if err != nil {
+		t.Fatalf("failed to create frame: %v", err)
+	}
+
+	// Ethernet padding is ignored.
+	b, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal frame: %v", err)
+	}
+
+	f2 := new(ethernet.Frame)
+	if err := f2.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal frame: %v", err)
+	}
+
+	g2, err := ParseGOOSE(f2)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if !reflect.DeepEqual(g, g2) {
+		t.Fatalf("unexpected GOOSE:\n- want: %#v\n-  got: %#v", g, g2)
+	}
+
+	if _, err := ParseGOOSE(&ethernet.Frame{EtherType: ethernet.EtherTypeIPv4}); err != ethernet.ErrInvalidEtherType {
+		t.Fatalf("expected ErrInvalidEtherType, but got: %v", err)
+	}
+}
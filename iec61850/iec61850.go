@@ -0,0 +1,243 @@
+// Package iec61850 implements marshaling and unmarshaling of the Ethernet
+// frames used by IEC 61850 substation automation: Generic Object Oriented
+// Substation Events (GOOSE), as described in IEC 61850-8-1, and Sampled
+// Values (SV), as described in IEC 61850-9-2.
+//
+// The dataset values and samples carried by these frames are encoded using
+// ASN.1 BER and are exposed as raw bytes, leaving their interpretation to
+// the caller.
+package iec61850
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"time"
+)
+
+const (
+	// headerLen is the length of the header shared by GOOSE and Sampled
+	// Values frames.
+	headerLen = 8
+
+	// timestampLen is the length of a BER encoded UtcTime value.
+	timestampLen = 8
+)
+
+var (
+	// ErrInvalidLength is returned when a frame's length field is shorter
+	// than its header or longer than its payload, or when a marshaled
+	// frame is too long for its length field.
+	ErrInvalidLength = errors.New("iec61850: invalid length")
+
+	// ErrInvalidBER is returned when a PDU contains a malformed or
+	// unsupported ASN.1 BER encoding.
+	ErrInvalidBER = errors.New("iec61850: invalid BER encoding")
+
+	// ErrInvalidPDU is returned when a frame does not carry the expected
+	// PDU.
+	ErrInvalidPDU = errors.New("iec61850: invalid PDU")
+)
+
+// A Header is the header shared by GOOSE and Sampled Values frames.  The
+// length field is computed automatically on marshal.
+type Header struct {
+	// APPID identifies the application which sent the frame.
+	APPID uint16
+
+	// Reserved1 and Reserved2 are the reserved header fields.  In IEC
+	// 61850 edition 2, the high bit of Reserved1 indicates simulated
+	// frames.
+	Reserved1 uint16
+	Reserved2 uint16
+}
+
+// put marshals h into b, along with the length of the APDU which
+// follows it.
+func (h *Header) put(b []byte, apduLen int) error {
+	n := headerLen + apduLen
+	if n > 0xffff {
+		return ErrInvalidLength
+	}
+
+	binary.BigEndian.PutUint16(b[0:2], h.APPID)
+	binary.BigEndian.PutUint16(b[2:4], uint16(n))
+	binary.BigEndian.PutUint16(b[4:6], h.Reserved1)
+	binary.BigEndian.PutUint16(b[6:8], h.Reserved2)
+
+	return nil
+}
+
+// parseHeader unmarshals a Header from b, returning the APDU which follows
+// it.  Bytes beyond the header's length field, such as Ethernet padding, are
+// not included in the APDU.
+func parseHeader(b []byte) (Header, []byte, error) {
+	if len(b) < headerLen {
+		return Header{}, nil, io.ErrUnexpectedEOF
+	}
+
+	n := int(binary.BigEndian.Uint16(b[2:4]))
+	if n < headerLen {
+		return Header{}, nil, ErrInvalidLength
+	}
+	if len(b) < n {
+		return Header{}, nil, io.ErrUnexpectedEOF
+	}
+
+	h := Header{
+		APPID:     binary.BigEndian.Uint16(b[0:2]),
+		Reserved1: binary.BigEndian.Uint16(b[4:6]),
+		Reserved2: binary.BigEndian.Uint16(b[6:8]),
+	}
+
+	return h, b[headerLen:n], nil
+}
+
+// A Timestamp is an IEC 61850 UtcTime value.
+type Timestamp struct {
+	// Seconds specifies the seconds since the Unix epoch.
+	Seconds uint32
+
+	// Fraction specifies the 24 bit fraction of a second, in units of
+	// 2^-24 seconds.
+	Fraction uint32
+
+	// Quality specifies the time quality flags and accuracy.
+	Quality uint8
+}
+
+// NewTimestamp creates a Timestamp from time t, with zero quality.
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{
+		Seconds:  uint32(t.Unix()),
+		Fraction: uint32((uint64(t.Nanosecond()) << 24) / uint64(time.Second)),
+	}
+}
+
+// Time converts a Timestamp into a time.Time.
+func (ts Timestamp) Time() time.Time {
+	ns := (uint64(ts.Fraction&0x00ffffff) * uint64(time.Second)) >> 24
+	return time.Unix(int64(ts.Seconds), int64(ns))
+}
+
+// put marshals a Timestamp into b.
+func (ts Timestamp) put(b []byte) {
+	binary.BigEndian.PutUint32(b[0:4], ts.Seconds)
+	binary.BigEndian.PutUint32(b[4:8], ts.Fraction<<8|uint32(ts.Quality))
+}
+
+// parseTimestamp unmarshals a Timestamp from b.
+func parseTimestamp(b []byte) (Timestamp, error) {
+	if len(b) != timestampLen {
+		return Timestamp{}, ErrInvalidBER
+	}
+
+	v := binary.BigEndian.Uint32(b[4:8])
+	return Timestamp{
+		Seconds:  binary.BigEndian.Uint32(b[0:4]),
+		Fraction: v >> 8,
+		Quality:  uint8(v),
+	}, nil
+}
+
+// appendTLV appends a BER encoded value with a single byte tag to b.
+func appendTLV(b []byte, tag uint8, v []byte) []byte {
+	b = append(b, tag)
+
+	switch n := len(v); {
+	case n < 0x80:
+		b = append(b, uint8(n))
+	case n <= 0xff:
+		b = append(b, 0x81, uint8(n))
+	default:
+		b = append(b, 0x82, uint8(n>>8), uint8(n))
+	}
+
+	return append(b, v...)
+}
+
+// appendUint appends a BER encoded non-negative INTEGER to b.
+func appendUint(b []byte, tag uint8, v uint32) []byte {
+	var buf [5]byte
+	binary.BigEndian.PutUint32(buf[1:], v)
+
+	// Use the shortest encoding which keeps the sign bit clear.
+	i := 0
+	for i < len(buf)-1 && buf[i] == 0 && buf[i+1]&0x80 == 0 {
+		i++
+	}
+
+	return appendTLV(b, tag, buf[i:])
+}
+
+// appendBool appends a BER encoded BOOLEAN to b.
+func appendBool(b []byte, tag uint8, v bool) []byte {
+	if v {
+		return appendTLV(b, tag, []byte{0xff})
+	}
+
+	return appendTLV(b, tag, []byte{0x00})
+}
+
+// parseTLV parses a BER encoded value with a single byte tag from b,
+// returning its tag, its contents, and the bytes which follow it.  The
+// returned slices alias b.
+func parseTLV(b []byte) (uint8, []byte, []byte, error) {
+	if len(b) < 2 {
+		return 0, nil, nil, io.ErrUnexpectedEOF
+	}
+
+	tag := b[0]
+	if tag&0x1f == 0x1f {
+		// Multi-byte tags are not used by GOOSE or Sampled Values.
+		return 0, nil, nil, ErrInvalidBER
+	}
+
+	n := int(b[1])
+	b = b[2:]
+	if n&0x80 != 0 {
+		// Long form: the low bits specify the number of length bytes.
+		l := n &^ 0x80
+		if l == 0 || l > 2 {
+			return 0, nil, nil, ErrInvalidBER
+		}
+		if len(b) < l {
+			return 0, nil, nil, io.ErrUnexpectedEOF
+		}
+
+		n = 0
+		for _, c := range b[:l] {
+			n = n<<8 | int(c)
+		}
+		b = b[l:]
+	}
+
+	if len(b) < n {
+		return 0, nil, nil, io.ErrUnexpectedEOF
+	}
+
+	return tag, b[:n:n], b[n:], nil
+}
+
+// parseUint parses the contents of a BER encoded non-negative INTEGER.
+func parseUint(b []byte) (uint32, error) {
+	if len(b) == 0 || len(b) > 5 || b[0]&0x80 != 0 || (len(b) == 5 && b[0] != 0) {
+		return 0, ErrInvalidBER
+	}
+
+	var v uint32
+	for _, c := range b {
+		v = v<<8 | uint32(c)
+	}
+
+	return v, nil
+}
+
+// parseBool parses the contents of a BER encoded BOOLEAN.
+func parseBool(b []byte) (bool, error) {
+	if len(b) != 1 {
+		return false, ErrInvalidBER
+	}
+
+	return b[0] != 0, nil
+}
@@ -0,0 +1,165 @@
+package iec61850
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestParseHeader(t *testing.T) {
+	tests := []struct {
+		desc string
+		b    []byte
+		h    Header
+		apdu []byte
+		err  error
+	}{
+		{
+			desc: "short",
+			b:    make([]byte, headerLen-1),
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "length too short",
+			b:    []byte{0x00, 0x01, 0x00, 0x07, 0x00, 0x00, 0x00, 0x00},
+			err:  ErrInvalidLength,
+		},
+		{
+			desc: "length too long",
+			b:    []byte{0x00, 0x01, 0x00, 0x09, 0x00, 0x00, 0x00, 0x00},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "OK with padding",
+			b: []byte{
+				0x40, 0x01, 0x00, 0x09, 0x80, 0x00, 0x00, 0x00,
+				0xaa, 0x00, 0x00,
+			},
+			h: Header{
+				APPID:     0x4001,
+				Reserved1: 0x8000,
+			},
+			apdu: []byte{0xaa},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			h, apdu, err := parseHeader(tt.b)
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error: %v != %v", want, got)
+			}
+
+			if want, got := tt.h, h; want != got {
+				t.Fatalf("unexpected header: %#v != %#v", want, got)
+			}
+			if want, got := tt.apdu, apdu; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected APDU: %v != %v", want, got)
+			}
+		})
+	}
+}
+
+func TestBERUint(t *testing.T) {
+	tests := []struct {
+		v uint32
+		b []byte
+	}{
+		{v: 0, b: []byte{0x85, 0x01, 0x00}},
+		{v: 0x7f, b: []byte{0x85, 0x01, 0x7f}},
+		{v: 0x80, b: []byte{0x85, 0x02, 0x00, 0x80}},
+		{v: 0x1234, b: []byte{0x85, 0x02, 0x12, 0x34}},
+		{v: 0xffffffff, b: []byte{0x85, 0x05, 0x00, 0xff, 0xff, 0xff, 0xff}},
+	}
+
+	for _, tt := range tests {
+		b := appendUint(nil, tagStNum, tt.v)
+		if want, got := tt.b, b; !bytes.Equal(want, got) {
+			t.Fatalf("unexpected encoding of %d: %v != %v", tt.v, want, got)
+		}
+
+		_, c, _, err := parseTLV(b)
+		if err != nil {
+			t.Fatalf("failed to parse TLV: %v", err)
+		}
+
+		v, err := parseUint(c)
+		if err != nil {
+			t.Fatalf("failed to parse integer: %v", err)
+		}
+		if want, got := tt.v, v; want != got {
+			t.Fatalf("unexpected integer: %v != %v", want, got)
+		}
+	}
+
+	for _, b := range [][]byte{nil, {0x80}, {0x01, 0x00, 0x00, 0x00, 0x00}} {
+		if _, err := parseUint(b); err != ErrInvalidBER {
+			t.Fatalf("expected ErrInvalidBER for %v, but got: %v", b, err)
+		}
+	}
+}
+
+func TestBERLongLength(t *testing.T) {
+	v := bytes.Repeat([]byte{0xaa}, 300)
+
+	b := appendTLV(nil, tagAllData, v)
+	if want, got := []byte{0xab, 0x82, 0x01, 0x2c}, b[:4]; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected TLV header: %v != %v", want, got)
+	}
+
+	tag, c, rest, err := parseTLV(append(b, 0xff))
+	if err != nil {
+		t.Fatalf("failed to parse TLV: %v", err)
+	}
+
+	if want, got := uint8(tagAllData), tag; want != got {
+		t.Fatalf("unexpected tag: %v != %v", want, got)
+	}
+	if !bytes.Equal(v, c) {
+		t.Fatal("unexpected TLV contents")
+	}
+	if want, got := []byte{0xff}, rest; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected trailing bytes: %v != %v", want, got)
+	}
+
+	for _, b := range [][]byte{{0x80}, {0x80, 0x02, 0x00}, {0x80, 0x81}, {0x80, 0x85, 0x00}, {0x1f, 0x00}} {
+		if _, _, _, err := parseTLV(b); err == nil {
+			t.Fatalf("expected an error for %v", b)
+		}
+	}
+}
+
+func TestTimestamp(t *testing.T) {
+	tm := time.Unix(1500000000, 500000000)
+
+	ts := NewTimestamp(tm)
+	if want, got := (Timestamp{Seconds: 1500000000, Fraction: 0x800000}), ts; want != got {
+		t.Fatalf("unexpected timestamp: %#v != %#v", want, got)
+	}
+	if !tm.Equal(ts.Time()) {
+		t.Fatalf("unexpected time: %v != %v", tm, ts.Time())
+	}
+
+	ts.Quality = 0x0a
+
+	var b [timestampLen]byte
+	ts.put(b[:])
+
+	want := []byte{0x59, 0x68, 0x2f, 0x00, 0x80, 0x00, 0x00, 0x0a}
+	if !bytes.Equal(want, b[:]) {
+		t.Fatalf("unexpected bytes: %v != %v", want, b)
+	}
+
+	got, err := parseTimestamp(b[:])
+	if err != nil {
+		t.Fatalf("failed to parse timestamp: %v", err)
+	}
+	if ts != got {
+		t.Fatalf("unexpected timestamp: %#v != %#v", ts, got)
+	}
+
+	if _, err := parseTimestamp(b[:7]); err != ErrInvalidBER {
+		t.Fatalf("expected ErrInvalidBER, but got: %v", err)
+	}
+}
@@ -0,0 +1,253 @@
+package iec61850
+
+import (
+	"encoding/binary"
+	"net"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// EtherTypeSampledValues is the EtherType which indicates a Sampled Values
+// frame in a Frame.
+const EtherTypeSampledValues = ethernet.EtherTypeSampledValues
+
+// BER tags of a savPdu, its ASDUs, and their fields.
+const (
+	tagSavPDU    = 0x60
+	tagNoASDU    = 0x80
+	tagSeqASDU   = 0xa2
+	tagASDU      = 0x30
+	tagSvID      = 0x80
+	tagSVDatSet  = 0x81
+	tagSmpCnt    = 0x82
+	tagSVConfRev = 0x83
+	tagRefrTm    = 0x84
+	tagSmpSynch  = 0x85
+	tagSmpRate   = 0x86
+	tagSample    = 0x87
+)
+
+// SVDestination returns the multicast destination of Sampled Values frames
+// with address index i, in the range 01:0c:cd:04:00:00 to 01:0c:cd:04:01:ff
+// recommended by IEC 61850-9-2.
+func SVDestination(i uint16) net.HardwareAddr {
+	return net.HardwareAddr{0x01, 0x0c, 0xcd, 0x04, uint8(i >> 8), uint8(i)}
+}
+
+// A SampledValues is a Sampled Values frame, carrying a savPdu.  The
+// number of ASDUs is computed automatically on marshal.
+type SampledValues struct {
+	Header
+	ASDUs []ASDU
+}
+
+// An ASDU is a single set of samples in a savPdu.  The optional smpMod
+// field is ignored when unmarshaled.
+type ASDU struct {
+	// SvID specifies the identifier of the sampled value control block.
+	SvID string
+
+	// DatSet specifies the optional reference of the dataset.  DatSet is
+	// omitted when marshaled if it is empty.
+	DatSet string
+
+	// SmpCnt specifies the sample counter.
+	SmpCnt uint16
+
+	// ConfRev specifies the configuration revision of the dataset.
+	ConfRev uint32
+
+	// RefrTm specifies the optional refresh time of the samples.
+	RefrTm *Timestamp
+
+	// SmpSynch specifies the time synchronization source of the samples.
+	SmpSynch uint8
+
+	// SmpRate specifies the optional sample rate.  SmpRate is omitted when
+	// marshaled if it is zero.
+	SmpRate uint16
+
+	// Sample specifies the encoded samples of the dataset.
+	Sample []byte
+}
+
+// ParseSampledValues unmarshals the Sampled Values frame carried in the
+// payload of Frame f.  If f's EtherType is not EtherTypeSampledValues,
+// ethernet.ErrInvalidEtherType is returned.
+func ParseSampledValues(f *ethernet.Frame) (*SampledValues, error) {
+	if f.EtherType != EtherTypeSampledValues {
+		return nil, ethernet.ErrInvalidEtherType
+	}
+
+	sv := new(SampledValues)
+	if err := sv.UnmarshalBinary(f.Payload); err != nil {
+		return nil, err
+	}
+
+	return sv, nil
+}
+
+// Frame marshals a SampledValues into the payload of an Ethernet frame sent
+// from hardware address src to hardware address dst.
+func (sv *SampledValues) Frame(dst, src net.HardwareAddr) (*ethernet.Frame, error) {
+	b, err := sv.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ethernet.Frame{
+		Destination: dst,
+		Source:      src,
+		EtherType:   EtherTypeSampledValues,
+		Payload:     b,
+	}, nil
+}
+
+// MarshalBinary allocates a byte slice and marshals a SampledValues into
+// binary form.
+func (sv *SampledValues) MarshalBinary() ([]byte, error) {
+	var seq []byte
+	for _, a := range sv.ASDUs {
+		seq = appendTLV(seq, tagASDU, a.marshal())
+	}
+
+	var pdu []byte
+	pdu = appendUint(pdu, tagNoASDU, uint32(len(sv.ASDUs)))
+	pdu = appendTLV(pdu, tagSeqASDU, seq)
+
+	b := appendTLV(make([]byte, headerLen), tagSavPDU, pdu)
+	if err := sv.Header.put(b, len(b)-headerLen); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into a SampledValues.  Unknown
+// savPdu and ASDU fields are ignored.
+func (sv *SampledValues) UnmarshalBinary(b []byte) error {
+	h, apdu, err := parseHeader(b)
+	if err != nil {
+		return err
+	}
+
+	tag, pdu, _, err := parseTLV(apdu)
+	if err != nil {
+		return err
+	}
+	if tag != tagSavPDU {
+		return ErrInvalidPDU
+	}
+
+	out := SampledValues{Header: h}
+	for len(pdu) > 0 {
+		var v []byte
+		tag, v, pdu, err = parseTLV(pdu)
+		if err != nil {
+			return err
+		}
+		if tag != tagSeqASDU {
+			continue
+		}
+
+		for len(v) > 0 {
+			var c []byte
+			tag, c, v, err = parseTLV(v)
+			if err != nil {
+				return err
+			}
+			if tag != tagASDU {
+				return ErrInvalidPDU
+			}
+
+			var a ASDU
+			if err := a.unmarshal(c); err != nil {
+				return err
+			}
+			out.ASDUs = append(out.ASDUs, a)
+		}
+	}
+
+	*sv = out
+	return nil
+}
+
+// marshal marshals the fields of an ASDU.  Integer fields use the fixed
+// lengths specified by IEC 61850-9-2, rather than minimal BER encodings.
+func (a *ASDU) marshal() []byte {
+	var b []byte
+	b = appendTLV(b, tagSvID, []byte(a.SvID))
+	if a.DatSet != "" {
+		b = appendTLV(b, tagSVDatSet, []byte(a.DatSet))
+	}
+
+	var buf [timestampLen]byte
+	binary.BigEndian.PutUint16(buf[:2], a.SmpCnt)
+	b = appendTLV(b, tagSmpCnt, buf[:2])
+	binary.BigEndian.PutUint32(buf[:4], a.ConfRev)
+	b = appendTLV(b, tagSVConfRev, buf[:4])
+
+	if a.RefrTm != nil {
+		a.RefrTm.put(buf[:])
+		b = appendTLV(b, tagRefrTm, buf[:])
+	}
+
+	b = appendTLV(b, tagSmpSynch, []byte{a.SmpSynch})
+	if a.SmpRate != 0 {
+		binary.BigEndian.PutUint16(buf[:2], a.SmpRate)
+		b = appendTLV(b, tagSmpRate, buf[:2])
+	}
+
+	return appendTLV(b, tagSample, a.Sample)
+}
+
+// unmarshal unmarshals the fields of an ASDU from b.
+func (a *ASDU) unmarshal(b []byte) error {
+	for len(b) > 0 {
+		tag, v, rest, err := parseTLV(b)
+		if err != nil {
+			return err
+		}
+		b = rest
+
+		switch tag {
+		case tagSvID:
+			a.SvID = string(v)
+		case tagSVDatSet:
+			a.DatSet = string(v)
+		case tagSmpCnt:
+			if len(v) != 2 {
+				return ErrInvalidBER
+			}
+			a.SmpCnt = binary.BigEndian.Uint16(v)
+		case tagSVConfRev:
+			if len(v) != 4 {
+				return ErrInvalidBER
+			}
+			a.ConfRev = binary.BigEndian.Uint32(v)
+		case tagRefrTm:
+			ts, err := parseTimestamp(v)
+			if err != nil {
+				return err
+			}
+			a.RefrTm = &ts
+		case tagSmpSynch:
+			if len(v) != 1 {
+				return ErrInvalidBER
+			}
+			a.SmpSynch = v[0]
+		case tagSmpRate:
+			if len(v) != 2 {
+				return ErrInvalidBER
+			}
+			a.SmpRate = binary.BigEndian.Uint16(v)
+		case tagSample:
+			if len(v) > 0 {
+				a.Sample = make([]byte, len(v))
+				copy(a.Sample, v)
+			}
+		}
+	}
+
+	return nil
+}
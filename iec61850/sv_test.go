@@ -0,0 +1,170 @@
+package iec61850
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestSampledValuesMarshalUnmarshal(t *testing.T) {
+	sv := &SampledValues{
+		Header: Header{APPID: 0x4000},
+		ASDUs: []ASDU{
+			{
+				SvID:     "MU01",
+				SmpCnt:   0x0102,
+				ConfRev:  1,
+				SmpSynch: 2,
+				Sample:   []byte{0x00, 0x00, 0x00, 0x64, 0x00, 0x00, 0x00, 0x00},
+			},
+			{
+				SvID:    "MU02",
+				DatSet:  "ds",
+				RefrTm:  &Timestamp{Seconds: 1},
+				SmpRate: 4000,
+			},
+		},
+	}
+
+	b, err := sv.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	want := []byte{
+		// Header.
+		0x40, 0x00, 0x00, 0x57, 0x00, 0x00, 0x00, 0x00,
+		// savPdu, noASDU, seqASDU.
+		0x60, 0x4d,
+		0x80, 0x01, 0x02,
+		0xa2, 0x48,
+		// First ASDU.
+		0x30, 0x1d,
+		0x80, 0x04, 'M', 'U', '0', '1',
+		0x82, 0x02, 0x01, 0x02,
+		0x83, 0x04, 0x00, 0x00, 0x00, 0x01,
+		0x85, 0x01, 0x02,
+		0x87, 0x08, 0x00, 0x00, 0x00, 0x64, 0x00, 0x00, 0x00, 0x00,
+		// Second ASDU.
+		0x30, 0x27,
+		0x80, 0x04, 'M', 'U', '0', '2',
+		0x81, 0x02, 'd', 's',
+		0x82, 0x02, 0x00, 0x00,
+		0x83, 0x04, 0x00, 0x00, 0x00, 0x00,
+		0x84, 0x08, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00,
+		0x85, 0x01, 0x00,
+		0x86, 0x02, 0x0f, 0xa0,
+		0x87, 0x00,
+	}
+
+	if !bytes.Equal(want, b) {
+		t.Fatalf("unexpected bytes:\n- want: %v\n-  got: %v", want, b)
+	}
+
+	sv2 := new(SampledValues)
+	if err := sv2.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if !reflect.DeepEqual(sv, sv2) {
+		t.Fatalf("unexpected SampledValues:\n- want: %#v\n-  got: %#v", sv, sv2)
+	}
+}
+
+func TestSampledValuesUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		b    []byte
+		err  error
+	}{
+		{
+			desc: "short header",
+			b:    []byte{0x40, 0x00},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "not a savPdu",
+			b:    []byte{0x40, 0x00, 0x00, 0x0a, 0x00, 0x00, 0x00, 0x00, 0x61, 0x00},
+			err:  ErrInvalidPDU,
+		},
+		{
+			desc: "not an ASDU",
+			b: []byte{
+				0x40, 0x00, 0x00, 0x0e, 0x00, 0x00, 0x00, 0x00,
+				0x60, 0x04, 0xa2, 0x02, 0x31, 0x00,
+			},
+			err: ErrInvalidPDU,
+		},
+		{
+			desc: "bad smpCnt",
+			b: []byte{
+				0x40, 0x00, 0x00, 0x11, 0x00, 0x00, 0x00, 0x00,
+				0x60, 0x07, 0xa2, 0x05, 0x30, 0x03, 0x82, 0x01, 0x01,
+			},
+			err: ErrInvalidBER,
+		},
+		{
+			desc: "truncated ASDU",
+			b: []byte{
+				0x40, 0x00, 0x00, 0x0f, 0x00, 0x00, 0x00, 0x00,
+				0x60, 0x05, 0xa2, 0x03, 0x30, 0x01, 0x80,
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := new(SampledValues).UnmarshalBinary(tt.b); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestSampledValuesFrameParse(t *testing.T) {
+	src := net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}
+	dst := SVDestination(1)
+
+	if want, got := (net.HardwareAddr{0x01, 0x0c, 0xcd, 0x04, 0x00, 0x01}), dst; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected destination: %v != %v", want, got)
+	}
+
+	sv := &SampledValues{
+		Header: Header{APPID: 0x4001},
+		ASDUs:  []ASDU{{SvID: "a", SmpCnt: 1}},
+	}
+
+	f, err := sv.Frame(dst, src)
+	if err != nil {
+		t.Fatalf("failed to create frame: %v", err)
+	}
+
+	// Ethernet padding is ignored.
+	b, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal frame: %v", err)
+	}
+
+	f2 := new(ethernet.Frame)
+	if err := f2.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal frame: %v", err)
+	}
+
+	sv2, err := ParseSampledValues(f2)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if !reflect.DeepEqual(sv, sv2) {
+		t.Fatalf("unexpected SampledValues:\n- want: %#v\n-  got: %#v", sv, sv2)
+	}
+
+	if _, err := ParseSampledValues(&ethernet.Frame{EtherType: EtherTypeGOOSE}); err != ethernet.ErrInvalidEtherType {
+		t.Fatalf("expected ErrInvalidEtherType, but got: %v", err)
+	}
+}
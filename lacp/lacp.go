@@ -0,0 +1,241 @@
+// Package lacp implements marshaling and unmarshaling of IEEE 802.3ad Link
+// Aggregation Control Protocol and Marker Protocol data units carried over
+// the slow protocols EtherType.
+package lacp
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// EtherType is the EtherType which indicates a slow protocols PDU in a Frame.
+const EtherType = ethernet.EtherTypeSlowProtocols
+
+// Destination is the slow protocols multicast hardware address to which
+// LACPDUs and Marker PDUs are sent.
+var Destination = net.HardwareAddr{0x01, 0x80, 0xc2, 0x00, 0x00, 0x02}
+
+const (
+	// subtypeLACP and subtypeMarker are the slow protocols subtypes of an
+	// LACPDU and a Marker PDU.
+	subtypeLACP   = 0x01
+	subtypeMarker = 0x02
+
+	// version is the LACP and Marker Protocol version implemented by this
+	// package.
+	version = 0x01
+
+	// lacpduLen and markerLen are the lengths of an LACPDU and a Marker
+	// PDU.
+	lacpduLen = 110
+	markerLen = 110
+
+	// infoLen and collectorLen are the lengths of the actor and partner
+	// information TLVs and the collector information TLV.
+	infoLen      = 20
+	collectorLen = 16
+)
+
+// LACPDU TLV types.
+const (
+	tlvActor     = 0x01
+	tlvPartner   = 0x02
+	tlvCollector = 0x03
+)
+
+var (
+	// ErrInvalidSubtype is returned when a slow protocols PDU's subtype does
+	// not match the PDU being unmarshaled.
+	ErrInvalidSubtype = errors.New("lacp: invalid slow protocols subtype")
+
+	// ErrInvalidTLV is returned when a PDU's TLVs are missing, out of
+	// order, or have an invalid length.
+	ErrInvalidTLV = errors.New("lacp: invalid TLV")
+
+	// ErrInvalidSystem is returned when a system identifier is not a 6 byte
+	// hardware address.
+	ErrInvalidSystem = errors.New("lacp: invalid system identifier")
+)
+
+// State is the state of an actor or partner port.
+type State uint8
+
+// Possible State values.
+const (
+	StateActivity        State = 0x01
+	StateTimeout         State = 0x02
+	StateAggregation     State = 0x04
+	StateSynchronization State = 0x08
+	StateCollecting      State = 0x10
+	StateDistributing    State = 0x20
+	StateDefaulted       State = 0x40
+	StateExpired         State = 0x80
+)
+
+// Info is the actor or partner information carried in an LACPDU.
+type Info struct {
+	// SystemPriority and System specify the system identifier.
+	SystemPriority uint16
+	System         net.HardwareAddr
+
+	// Key specifies the operational key.
+	Key uint16
+
+	// PortPriority and Port specify the port identifier.
+	PortPriority uint16
+	Port         uint16
+
+	// State specifies the port state.
+	State State
+}
+
+// read marshals an Info into b as a TLV of type t.
+func (i Info) read(b []byte, t uint8) error {
+	if len(i.System) != 6 {
+		return ErrInvalidSystem
+	}
+
+	b[0] = t
+	b[1] = infoLen
+	binary.BigEndian.PutUint16(b[2:4], i.SystemPriority)
+	copy(b[4:10], i.System)
+	binary.BigEndian.PutUint16(b[10:12], i.Key)
+	binary.BigEndian.PutUint16(b[12:14], i.PortPriority)
+	binary.BigEndian.PutUint16(b[14:16], i.Port)
+	b[16] = uint8(i.State)
+
+	return nil
+}
+
+// parseInfo unmarshals an Info from b, which must be a TLV of type t.
+func parseInfo(b []byte, t uint8) (Info, error) {
+	if b[0] != t || b[1] != infoLen {
+		return Info{}, ErrInvalidTLV
+	}
+
+	system := make(net.HardwareAddr, 6)
+	copy(system, b[4:10])
+
+	return Info{
+		SystemPriority: binary.BigEndian.Uint16(b[2:4]),
+		System:         system,
+		Key:            binary.BigEndian.Uint16(b[10:12]),
+		PortPriority:   binary.BigEndian.Uint16(b[12:14]),
+		Port:           binary.BigEndian.Uint16(b[14:16]),
+		State:          State(b[16]),
+	}, nil
+}
+
+// An LACPDU is a Link Aggregation Control Protocol data unit.
+type LACPDU struct {
+	// Actor and Partner specify the information of the transmitting port
+	// and of its partner.
+	Actor   Info
+	Partner Info
+
+	// CollectorMaxDelay specifies the maximum delay of the frame collector,
+	// which is transmitted in units of 10 microseconds.
+	CollectorMaxDelay time.Duration
+}
+
+// Parse unmarshals the LACPDU carried in the payload of Frame f.  If f's
+// EtherType is not EtherType, ethernet.ErrInvalidEtherType is returned.
+func Parse(f *ethernet.Frame) (*LACPDU, error) {
+	if f.EtherType != EtherType {
+		return nil, ethernet.ErrInvalidEtherType
+	}
+
+	l := new(LACPDU)
+	if err := l.UnmarshalBinary(f.Payload); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Frame marshals an LACPDU into the payload of an Ethernet frame sent from
+// hardware address src to Destination.
+func (l *LACPDU) Frame(src net.HardwareAddr) (*ethernet.Frame, error) {
+	b, err := l.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return newFrame(src, b), nil
+}
+
+// newFrame creates an Ethernet frame which carries slow protocols PDU b from
+// hardware address src to Destination.
+func newFrame(src net.HardwareAddr, b []byte) *ethernet.Frame {
+	return &ethernet.Frame{
+		Destination: append(net.HardwareAddr(nil), Destination...),
+		Source:      src,
+		EtherType:   EtherType,
+		Payload:     b,
+	}
+}
+
+// MarshalBinary allocates a byte slice and marshals an LACPDU into binary
+// form.
+func (l *LACPDU) MarshalBinary() ([]byte, error) {
+	b := make([]byte, lacpduLen)
+	b[0] = subtypeLACP
+	b[1] = version
+
+	if err := l.Actor.read(b[2:22], tlvActor); err != nil {
+		return nil, err
+	}
+	if err := l.Partner.read(b[22:42], tlvPartner); err != nil {
+		return nil, err
+	}
+
+	b[42] = tlvCollector
+	b[43] = collectorLen
+	binary.BigEndian.PutUint16(b[44:46], uint16(l.CollectorMaxDelay/(10*time.Microsecond)))
+
+	// The terminator TLV and trailing reserved bytes are zero.
+	return b, nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into an LACPDU.  Any version of
+// LACPDU is accepted, but only the version 1 fields are parsed.
+func (l *LACPDU) UnmarshalBinary(b []byte) error {
+	if len(b) < 2 {
+		return io.ErrUnexpectedEOF
+	}
+	if b[0] != subtypeLACP {
+		return ErrInvalidSubtype
+	}
+
+	// The collector information TLV is the last TLV with meaningful
+	// contents.
+	if len(b) < 42+collectorLen {
+		return io.ErrUnexpectedEOF
+	}
+
+	actor, err := parseInfo(b[2:22], tlvActor)
+	if err != nil {
+		return err
+	}
+	partner, err := parseInfo(b[22:42], tlvPartner)
+	if err != nil {
+		return err
+	}
+
+	if b[42] != tlvCollector || b[43] != collectorLen {
+		return ErrInvalidTLV
+	}
+
+	*l = LACPDU{
+		Actor:             actor,
+		Partner:           partner,
+		CollectorMaxDelay: time.Duration(binary.BigEndian.Uint16(b[44:46])) * 10 * time.Microsecond,
+	}
+
+	return nil
+}
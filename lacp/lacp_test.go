@@ -0,0 +1,165 @@
+package lacp
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestLACPDUMarshalUnmarshal(t *testing.T) {
+	l := &LACPDU{
+		Actor: Info{
+			SystemPriority: 0x8000,
+			System:         net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+			Key:            0x000d,
+			PortPriority:   0x00ff,
+			Port:           0x0002,
+			State:          StateActivity | StateAggregation | StateSynchronization,
+		},
+		Partner: Info{
+			SystemPriority: 0xffff,
+			System:         net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55},
+			Key:            0x0001,
+			PortPriority:   0x0001,
+			Port:           0x0010,
+			State:          StateDefaulted | StateExpired,
+		},
+		CollectorMaxDelay: 50 * time.Microsecond,
+	}
+
+	b, err := l.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	want := []byte{
+		0x01, 0x01,
+		// Actor.
+		0x01, 0x14,
+		0x80, 0x00, 0xde, 0xad, 0xbe, 0xef, 0xde, 0xad,
+		0x00, 0x0d, 0x00, 0xff, 0x00, 0x02,
+		0x0d, 0x00, 0x00, 0x00,
+		// Partner.
+		0x02, 0x14,
+		0xff, 0xff, 0x00, 0x11, 0x22, 0x33, 0x44, 0x55,
+		0x00, 0x01, 0x00, 0x01, 0x00, 0x10,
+		0xc0, 0x00, 0x00, 0x00,
+		// Collector.
+		0x03, 0x10,
+		0x00, 0x05,
+	}
+	want = append(want, make([]byte, lacpduLen-len(want))...)
+
+	if !bytes.Equal(want, b) {
+		t.Fatalf("unexpected bytes:\n- want: %v\n-  got: %v", want, b)
+	}
+
+	got := new(LACPDU)
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if !reflect.DeepEqual(l, got) {
+		t.Fatalf("unexpected LACPDU:\n- want: %#v\n-  got: %#v", l, got)
+	}
+}
+
+func TestLACPDUUnmarshalErrors(t *testing.T) {
+	valid, err := (&LACPDU{
+		Actor:   Info{System: make(net.HardwareAddr, 6)},
+		Partner: Info{System: make(net.HardwareAddr, 6)},
+	}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	modify := func(i int, v byte) []byte {
+		b := append([]byte(nil), valid...)
+		b[i] = v
+		return b
+	}
+
+	tests := []struct {
+		desc string
+		b    []byte
+		err  error
+	}{
+		{
+			desc: "empty",
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "bad subtype",
+			b:    modify(0, 0x02),
+			err:  ErrInvalidSubtype,
+		},
+		{
+			desc: "short",
+			b:    valid[:50],
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "bad actor type",
+			b:    modify(2, tlvPartner),
+			err:  ErrInvalidTLV,
+		},
+		{
+			desc: "bad partner length",
+			b:    modify(23, 0x10),
+			err:  ErrInvalidTLV,
+		},
+		{
+			desc: "bad collector type",
+			b:    modify(42, 0x00),
+			err:  ErrInvalidTLV,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := new(LACPDU).UnmarshalBinary(tt.b); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestLACPDUMarshalInvalidSystem(t *testing.T) {
+	if _, err := new(LACPDU).MarshalBinary(); err != ErrInvalidSystem {
+		t.Fatalf("expected ErrInvalidSystem, but got: %v", err)
+	}
+}
+
+func TestFrameParse(t *testing.T) {
+	l := &LACPDU{
+		Actor:   Info{System: net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}},
+		Partner: Info{System: make(net.HardwareAddr, 6)},
+	}
+
+	f, err := l.Frame(net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad})
+	if err != nil {
+		t.Fatalf("failed to create frame: %v", err)
+	}
+
+	if want, got := Destination, f.Destination; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected destination: %v != %v", want, got)
+	}
+
+	got, err := Parse(f)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if !reflect.DeepEqual(l, got) {
+		t.Fatalf("unexpected LACPDU:\n- want: %#v\n-  got: %#v", l, got)
+	}
+
+	if _, err := Parse(&ethernet.Frame{EtherType: ethernet.EtherTypeIPv4}); err != ethernet.ErrInvalidEtherType {
+		t.Fatalf("expected ErrInvalidEtherType, but got: %v", err)
+	}
+}
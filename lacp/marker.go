@@ -0,0 +1,132 @@
+package lacp
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// Marker PDU TLV types.
+const (
+	tlvMarkerInfo     = 0x01
+	tlvMarkerResponse = 0x02
+
+	// markerInfoLen is the length of a marker information or marker
+	// response TLV.
+	markerInfoLen = 16
+)
+
+// A Marker is a Marker Protocol PDU, which is used to determine when all
+// frames sent on an aggregated link have been received.  A Marker Response
+// echoes the fields of the Marker which it answers.
+type Marker struct {
+	// Response reports whether the Marker is a Marker Response.
+	Response bool
+
+	// RequesterPort and RequesterSystem specify the port and system of the
+	// sender of the original Marker.
+	RequesterPort   uint16
+	RequesterSystem net.HardwareAddr
+
+	// TransactionID specifies the requester's transaction identifier.
+	TransactionID uint32
+}
+
+// ParseMarker unmarshals the Marker PDU carried in the payload of Frame f.
+// If f's EtherType is not EtherType, ethernet.ErrInvalidEtherType is
+// returned.
+func ParseMarker(f *ethernet.Frame) (*Marker, error) {
+	if f.EtherType != EtherType {
+		return nil, ethernet.ErrInvalidEtherType
+	}
+
+	m := new(Marker)
+	if err := m.UnmarshalBinary(f.Payload); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Frame marshals a Marker into the payload of an Ethernet frame sent from
+// hardware address src to Destination.
+func (m *Marker) Frame(src net.HardwareAddr) (*ethernet.Frame, error) {
+	b, err := m.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return newFrame(src, b), nil
+}
+
+// Reply returns a Marker Response which answers Marker m.
+func (m *Marker) Reply() *Marker {
+	r := *m
+	r.Response = true
+	return &r
+}
+
+// MarshalBinary allocates a byte slice and marshals a Marker into binary
+// form.
+func (m *Marker) MarshalBinary() ([]byte, error) {
+	if len(m.RequesterSystem) != 6 {
+		return nil, ErrInvalidSystem
+	}
+
+	b := make([]byte, markerLen)
+	b[0] = subtypeMarker
+	b[1] = version
+
+	b[2] = tlvMarkerInfo
+	if m.Response {
+		b[2] = tlvMarkerResponse
+	}
+	b[3] = markerInfoLen
+
+	binary.BigEndian.PutUint16(b[4:6], m.RequesterPort)
+	copy(b[6:12], m.RequesterSystem)
+	binary.BigEndian.PutUint32(b[12:16], m.TransactionID)
+
+	// Padding, the terminator TLV, and trailing reserved bytes are zero.
+	return b, nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into a Marker.
+func (m *Marker) UnmarshalBinary(b []byte) error {
+	if len(b) < 2 {
+		return io.ErrUnexpectedEOF
+	}
+	if b[0] != subtypeMarker {
+		return ErrInvalidSubtype
+	}
+
+	if len(b) < 2+markerInfoLen {
+		return io.ErrUnexpectedEOF
+	}
+
+	var response bool
+	switch b[2] {
+	case tlvMarkerInfo:
+	case tlvMarkerResponse:
+		response = true
+	default:
+		return ErrInvalidTLV
+	}
+	if b[3] != markerInfoLen {
+		return ErrInvalidTLV
+	}
+
+	system := make(net.HardwareAddr, 6)
+	copy(system, b[6:12])
+
+	*m = Marker{
+		Response:        response,
+		RequesterPort:   binary.BigEndian.Uint16(b[4:6]),
+		RequesterSystem: system,
+		TransactionID:   binary.BigEndian.Uint32(b[12:16]),
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package lacp
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestMarkerMarshalUnmarshal(t *testing.T) {
+	m := &Marker{
+		RequesterPort:   0x0002,
+		RequesterSystem: net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+		TransactionID:   0x01020304,
+	}
+
+	tests := []struct {
+		desc string
+		m    *Marker
+		b    []byte
+	}{
+		{
+			desc: "marker",
+			m:    m,
+			b: []byte{
+				0x02, 0x01,
+				0x01, 0x10,
+				0x00, 0x02,
+				0xde, 0xad, 0xbe, 0xef, 0xde, 0xad,
+				0x01, 0x02, 0x03, 0x04,
+			},
+		},
+		{
+			desc: "response",
+			m:    m.Reply(),
+			b: []byte{
+				0x02, 0x01,
+				0x02, 0x10,
+				0x00, 0x02,
+				0xde, 0xad, 0xbe, 0xef, 0xde, 0xad,
+				0x01, 0x02, 0x03, 0x04,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := tt.m.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			want := append(tt.b, make([]byte, markerLen-len(tt.b))...)
+			if !bytes.Equal(want, b) {
+				t.Fatalf("unexpected bytes:\n- want: %v\n-  got: %v", want, b)
+			}
+
+			got := new(Marker)
+			if err := got.UnmarshalBinary(b); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+
+			if !reflect.DeepEqual(tt.m, got) {
+				t.Fatalf("unexpected Marker:\n- want: %#v\n-  got: %#v", tt.m, got)
+			}
+		})
+	}
+
+	if m.Response {
+		t.Fatal("Reply modified the original Marker")
+	}
+}
+
+func TestMarkerUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		b    []byte
+		err  error
+	}{
+		{
+			desc: "empty",
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "LACPDU",
+			b:    []byte{0x01, 0x01},
+			err:  ErrInvalidSubtype,
+		},
+		{
+			desc: "short",
+			b:    []byte{0x02, 0x01, 0x01, 0x10},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "bad TLV type",
+			b:    append([]byte{0x02, 0x01, 0x03, 0x10}, make([]byte, 14)...),
+			err:  ErrInvalidTLV,
+		},
+		{
+			desc: "bad TLV length",
+			b:    append([]byte{0x02, 0x01, 0x01, 0x14}, make([]byte, 14)...),
+			err:  ErrInvalidTLV,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := new(Marker).UnmarshalBinary(tt.b); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestMarkerFrameParse(t *testing.T) {
+	src := net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}
+	m := &Marker{RequesterSystem: src}
+
+	f, err := m.Frame(src)
+	if err != nil {
+		t.Fatalf("failed to create frame: %v", err)
+	}
+
+	got, err := ParseMarker(f)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if !reflect.DeepEqual(m, got) {
+		t.Fatalf("unexpected Marker:\n- want: %#v\n-  got: %#v", m, got)
+	}
+
+	if _, err := Parse(f); err != ErrInvalidSubtype {
+		t.Fatalf("expected ErrInvalidSubtype, but got: %v", err)
+	}
+}
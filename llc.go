@@ -0,0 +1,343 @@
+package ethernet
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// minEtherType is the minimum value of an EtherType.  Smaller values in
+	// the EtherType position of a frame indicate the length of an IEEE 802.3
+	// frame's LLC header and payload.
+	minEtherType = 0x0600
+
+	// snapLen is the length of a SNAP header.
+	snapLen = 5
+)
+
+// Well-known IEEE 802.2 LLC service access points, for use in the DSAP and
+// SSAP fields of an LLC.
+const (
+	SAPNull    = 0x00 // Null SAP
+	SAPSTP     = 0x42 // IEEE 802.1 Spanning Tree Protocol
+	SAPSNAP    = 0xaa // Subnetwork Access Protocol; a SNAP header follows
+	SAPIPX     = 0xe0 // Novell IPX
+	SAPNetBIOS = 0xf0 // IBM NetBIOS
+	SAPISO     = 0xfe // ISO network layer, such as CLNS and IS-IS
+	SAPGlobal  = 0xff // Global DSAP
+)
+
+var (
+	// ErrInvalidLLC is returned when a SNAP header is present without an
+	// accompanying LLC header.
+	ErrInvalidLLC = errors.New("invalid LLC header")
+
+	// ErrInvalidLength is returned when the contents of an IEEE 802.3 frame
+	// are too large to be described by its length field.
+	ErrInvalidLength = errors.New("invalid IEEE 802.3 length")
+)
+
+// LengthField returns the IEEE 802.3 length carried in place of a Frame's
+// EtherType, and reports whether the EtherType is such a length.  A length
+// specifies the number of bytes in the LLC header and payload, excluding
+// any padding.
+//
+// As with UnmarshalBinary, any value smaller than 0x0600 is treated as a
+// length.  Values from 1501 to 1535 are not valid lengths, and are reported
+// by Validate.
+func (f *Frame) LengthField() (int, bool) {
+	if f.EtherType >= minEtherType {
+		return 0, false
+	}
+
+	return int(f.EtherType), true
+}
+
+// An LLC is an IEEE 802.2 Logical Link Control header, which identifies an
+// upper layer protocol in an IEEE 802.3 frame that does not use an
+// EtherType.
+type LLC struct {
+	// DSAP and SSAP specify the destination and source service access
+	// points of the LLC header.
+	DSAP, SSAP uint8
+
+	// Control specifies the LLC control field.  Unnumbered (U-format)
+	// control fields are 1 byte, and are identified by setting the two
+	// lowest bits of Control.  Information and supervisory control fields
+	// are 2 bytes, and are stored with the first transmitted byte in the
+	// low 8 bits of Control.
+	Control uint16
+}
+
+// MarshalBinary allocates a byte slice and marshals an LLC into binary form.
+func (l *LLC) MarshalBinary() ([]byte, error) {
+	b := make([]byte, l.length())
+	_, err := l.read(b)
+	return b, err
+}
+
+// read reads data from an LLC into b.  read is used to marshal an LLC into
+// binary form, but does not allocate on its own.
+func (l *LLC) read(b []byte) (int, error) {
+	b[0] = l.DSAP
+	b[1] = l.SSAP
+
+	if l.length() == 3 {
+		b[2] = uint8(l.Control)
+		return 3, nil
+	}
+
+	binary.LittleEndian.PutUint16(b[2:4], l.Control)
+	return 4, nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into an LLC.  Trailing bytes
+// after the control field are ignored.
+func (l *LLC) UnmarshalBinary(b []byte) error {
+	_, err := l.unmarshal(b)
+	return err
+}
+
+// unmarshal unmarshals a byte slice into an LLC and returns the number of
+// bytes consumed.
+func (l *LLC) unmarshal(b []byte) (int, error) {
+	if len(b) < 3 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	l.DSAP = b[0]
+	l.SSAP = b[1]
+
+	// U-format control fields are 1 byte; all others are 2 bytes.
+	if b[2]&0x03 == 0x03 {
+		l.Control = uint16(b[2])
+		return 3, nil
+	}
+
+	if len(b) < 4 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	l.Control = binary.LittleEndian.Uint16(b[2:4])
+	return 4, nil
+}
+
+// length calculates the number of bytes required to store an LLC.
+func (l *LLC) length() int {
+	if l.Control&0x03 == 0x03 {
+		return 3
+	}
+
+	return 4
+}
+
+// isSNAP reports whether l indicates that a SNAP header follows.
+func (l *LLC) isSNAP() bool {
+	return l.DSAP == SAPSNAP && l.SSAP == SAPSNAP && l.Control == uint16(UnnumberedUI)
+}
+
+// Well-known organizationally unique identifiers for use in a SNAP header.
+var (
+	// OUIEncapsulatedEthernet indicates that a SNAP header's ProtocolID is
+	// an EtherType, as described in RFC 1042.
+	OUIEncapsulatedEthernet = [3]byte{0x00, 0x00, 0x00}
+
+	// OUIBridgeTunnel indicates that a SNAP header's ProtocolID is an
+	// EtherType, as described in IEEE 802.1H.  It is used for protocols
+	// such as AppleTalk ARP which must be translated as-is by bridges.
+	OUIBridgeTunnel = [3]byte{0x00, 0x00, 0xf8}
+
+	// OUICisco is the OUI used for Cisco protocols such as CDP.
+	OUICisco = [3]byte{0x00, 0x00, 0x0c}
+
+	// OUIApple is the OUI used for AppleTalk (EtherTalk Phase 2).
+	OUIApple = [3]byte{0x08, 0x00, 0x07}
+)
+
+// A ControlFormat is the format of an LLC control field.
+type ControlFormat int
+
+// Possible ControlFormat values.
+const (
+	ControlInformation ControlFormat = iota
+	ControlSupervisory
+	ControlUnnumbered
+)
+
+// String returns the name of a ControlFormat.
+func (f ControlFormat) String() string {
+	switch f {
+	case ControlInformation:
+		return "I"
+	case ControlSupervisory:
+		return "S"
+	case ControlUnnumbered:
+		return "U"
+	default:
+		return fmt.Sprintf("ControlFormat(%d)", int(f))
+	}
+}
+
+// A SupervisoryFunction is the function of a supervisory (S-format) LLC
+// control field.
+type SupervisoryFunction uint8
+
+// Possible SupervisoryFunction values, which are the first byte of the
+// control field.
+const (
+	SupervisoryRR  SupervisoryFunction = 0x01 // Receive ready
+	SupervisoryRNR SupervisoryFunction = 0x05 // Receive not ready
+	SupervisoryREJ SupervisoryFunction = 0x09 // Reject
+)
+
+// An UnnumberedFunction is the function of an unnumbered (U-format) LLC
+// control field.
+type UnnumberedFunction uint8
+
+// Possible UnnumberedFunction values, which are the control field with the
+// poll/final bit cleared.
+const (
+	UnnumberedUI    UnnumberedFunction = 0x03 // Unnumbered information
+	UnnumberedDM    UnnumberedFunction = 0x0f // Disconnected mode
+	UnnumberedDISC  UnnumberedFunction = 0x43 // Disconnect
+	UnnumberedUA    UnnumberedFunction = 0x63 // Unnumbered acknowledgment
+	UnnumberedSABME UnnumberedFunction = 0x6f // Set asynchronous balanced mode extended
+	UnnumberedFRMR  UnnumberedFunction = 0x87 // Frame reject
+	UnnumberedXID   UnnumberedFunction = 0xaf // Exchange identification
+	UnnumberedTEST  UnnumberedFunction = 0xe3 // Test
+)
+
+// pollFinalU is the poll/final bit of a U-format control field.
+const pollFinalU = 0x10
+
+// A Control is a decoded LLC control field, for use with LLC Type 2
+// connection-oriented operation.
+type Control struct {
+	// Format specifies the format of the control field, which determines
+	// which of the remaining fields are used.
+	Format ControlFormat
+
+	// SendSequence is the 7 bit send sequence number, N(S), of an I-format
+	// control field.
+	SendSequence uint8
+
+	// ReceiveSequence is the 7 bit receive sequence number, N(R), of an
+	// I-format or S-format control field.
+	ReceiveSequence uint8
+
+	// PollFinal is the poll/final bit.
+	PollFinal bool
+
+	// Supervisory is the function of an S-format control field.
+	Supervisory SupervisoryFunction
+
+	// Unnumbered is the function of a U-format control field.
+	Unnumbered UnnumberedFunction
+}
+
+// ParseControl decodes the control field of an LLC, as stored in
+// LLC.Control.
+func ParseControl(v uint16) Control {
+	b0, b1 := uint8(v), uint8(v>>8)
+
+	switch {
+	case b0&0x01 == 0:
+		return Control{
+			Format:          ControlInformation,
+			SendSequence:    b0 >> 1,
+			ReceiveSequence: b1 >> 1,
+			PollFinal:       b1&0x01 != 0,
+		}
+	case b0&0x03 == 0x01:
+		return Control{
+			Format:          ControlSupervisory,
+			Supervisory:     SupervisoryFunction(b0),
+			ReceiveSequence: b1 >> 1,
+			PollFinal:       b1&0x01 != 0,
+		}
+	default:
+		return Control{
+			Format:     ControlUnnumbered,
+			Unnumbered: UnnumberedFunction(b0 &^ pollFinalU),
+			PollFinal:  b0&pollFinalU != 0,
+		}
+	}
+}
+
+// Value encodes a Control into the form stored in LLC.Control.  Sequence
+// numbers are truncated to 7 bits.
+func (c Control) Value() uint16 {
+	var pf uint8
+	if c.PollFinal {
+		pf = 1
+	}
+
+	switch c.Format {
+	case ControlInformation:
+		return uint16(c.SendSequence<<1) | uint16(c.ReceiveSequence<<1|pf)<<8
+	case ControlSupervisory:
+		return uint16(c.Supervisory&0x0f|0x01) | uint16(c.ReceiveSequence<<1|pf)<<8
+	default:
+		return uint16(uint8(c.Unnumbered)&^pollFinalU | 0x03 | pf<<4)
+	}
+}
+
+// DecodeControl decodes an LLC's control field.
+func (l *LLC) DecodeControl() Control {
+	return ParseControl(l.Control)
+}
+
+// A SNAP is an IEEE 802 Subnetwork Access Protocol header, which follows an
+// LLC header and identifies an upper layer protocol using an
+// organizationally unique identifier and protocol ID.
+type SNAP struct {
+	// OUI specifies the organizationally unique identifier of the
+	// organization which assigned ProtocolID.  If OUI is zero, ProtocolID
+	// is an EtherType.
+	OUI [3]byte
+
+	// ProtocolID specifies the upper layer protocol.
+	ProtocolID uint16
+}
+
+// EtherType returns the EtherType carried in a SNAP's ProtocolID, and
+// reports whether the SNAP's OUI indicates that ProtocolID is an EtherType,
+// as with OUIEncapsulatedEthernet and OUIBridgeTunnel.
+func (s *SNAP) EtherType() (EtherType, bool) {
+	switch s.OUI {
+	case OUIEncapsulatedEthernet, OUIBridgeTunnel:
+		return EtherType(s.ProtocolID), true
+	default:
+		return 0, false
+	}
+}
+
+// MarshalBinary allocates a byte slice and marshals a SNAP into binary form.
+func (s *SNAP) MarshalBinary() ([]byte, error) {
+	b := make([]byte, snapLen)
+	_, err := s.read(b)
+	return b, err
+}
+
+// read reads data from a SNAP into b.  read is used to marshal a SNAP into
+// binary form, but does not allocate on its own.
+func (s *SNAP) read(b []byte) (int, error) {
+	copy(b[0:3], s.OUI[:])
+	binary.BigEndian.PutUint16(b[3:5], s.ProtocolID)
+	return snapLen, nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into a SNAP.
+func (s *SNAP) UnmarshalBinary(b []byte) error {
+	// SNAP header is always 5 bytes
+	if len(b) != snapLen {
+		return io.ErrUnexpectedEOF
+	}
+
+	copy(s.OUI[:], b[0:3])
+	s.ProtocolID = binary.BigEndian.Uint16(b[3:5])
+
+	return nil
+}
@@ -0,0 +1,431 @@
+package ethernet
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestLLCMarshalBinary(t *testing.T) {
+	tests := []struct {
+		desc string
+		l    *LLC
+		b    []byte
+	}{
+		{
+			desc: "U-format",
+			l: &LLC{
+				DSAP:    0x42,
+				SSAP:    0x42,
+				Control: 0x03,
+			},
+			b: []byte{0x42, 0x42, 0x03},
+		},
+		{
+			desc: "I-format",
+			l: &LLC{
+				DSAP:    0xf0,
+				SSAP:    0xf0,
+				Control: 0x0102,
+			},
+			b: []byte{0xf0, 0xf0, 0x02, 0x01},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := tt.l.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			if want, got := tt.b, b; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected LLC bytes:\n- want: %v\n-  got: %v", want, got)
+			}
+		})
+	}
+}
+
+func TestLLCUnmarshalBinary(t *testing.T) {
+	tests := []struct {
+		desc string
+		b    []byte
+		l    *LLC
+		err  error
+	}{
+		{
+			desc: "short buffer",
+			b:    []byte{0x42, 0x42},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "short I-format control",
+			b:    []byte{0xf0, 0xf0, 0x02},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "U-format",
+			b:    []byte{0x42, 0x42, 0x03, 0xff},
+			l: &LLC{
+				DSAP:    0x42,
+				SSAP:    0x42,
+				Control: 0x03,
+			},
+		},
+		{
+			desc: "S-format",
+			b:    []byte{0xf0, 0xf0, 0x01, 0x03},
+			l: &LLC{
+				DSAP:    0xf0,
+				SSAP:    0xf0,
+				Control: 0x0301,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			l := new(LLC)
+			if err := l.UnmarshalBinary(tt.b); err != nil {
+				if want, got := tt.err, err; want != got {
+					t.Fatalf("unexpected error: %v != %v", want, got)
+				}
+
+				return
+			}
+
+			if want, got := tt.l, l; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected LLC:\n- want: %v\n-  got: %v", want, got)
+			}
+		})
+	}
+}
+
+func TestControl(t *testing.T) {
+	tests := []struct {
+		desc string
+		v    uint16
+		c    Control
+	}{
+		{
+			desc: "I-format",
+			v:    0x0b0a,
+			c: Control{
+				Format:          ControlInformation,
+				SendSequence:    5,
+				ReceiveSequence: 5,
+				PollFinal:       true,
+			},
+		},
+		{
+			desc: "S-format RNR",
+			v:    0x0805,
+			c: Control{
+				Format:          ControlSupervisory,
+				Supervisory:     SupervisoryRNR,
+				ReceiveSequence: 4,
+			},
+		},
+		{
+			desc: "U-format UI",
+			v:    0x0003,
+			c: Control{
+				Format:     ControlUnnumbered,
+				Unnumbered: UnnumberedUI,
+			},
+		},
+		{
+			desc: "U-format SABME poll",
+			v:    0x007f,
+			c: Control{
+				Format:     ControlUnnumbered,
+				Unnumbered: UnnumberedSABME,
+				PollFinal:  true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			l := &LLC{Control: tt.v}
+			if want, got := tt.c, l.DecodeControl(); want != got {
+				t.Fatalf("unexpected control:\n- want: %+v\n-  got: %+v", want, got)
+			}
+
+			if want, got := tt.v, tt.c.Value(); want != got {
+				t.Fatalf("unexpected value: %#04x != %#04x", want, got)
+			}
+		})
+	}
+}
+
+func TestControlFormatString(t *testing.T) {
+	if want, got := "S", ControlSupervisory.String(); want != got {
+		t.Fatalf("unexpected string: %q != %q", want, got)
+	}
+	if want, got := "ControlFormat(10)", ControlFormat(10).String(); want != got {
+		t.Fatalf("unexpected string: %q != %q", want, got)
+	}
+}
+
+func TestSNAPRoundTrip(t *testing.T) {
+	s := &SNAP{
+		OUI:        [3]byte{0x00, 0x00, 0x0c},
+		ProtocolID: 0x2000,
+	}
+
+	b, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	if want, got := []byte{0x00, 0x00, 0x0c, 0x20, 0x00}, b; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected SNAP bytes:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	s2 := new(SNAP)
+	if err := s2.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if want, got := s, s2; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected SNAP:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	if want, got := io.ErrUnexpectedEOF, s2.UnmarshalBinary(b[:4]); want != got {
+		t.Fatalf("unexpected error: %v != %v", want, got)
+	}
+}
+
+func TestSNAPEtherType(t *testing.T) {
+	tests := []struct {
+		desc string
+		s    *SNAP
+		et   EtherType
+		ok   bool
+	}{
+		{
+			desc: "RFC 1042",
+			s:    &SNAP{OUI: OUIEncapsulatedEthernet, ProtocolID: 0x0800},
+			et:   EtherTypeIPv4,
+			ok:   true,
+		},
+		{
+			desc: "IEEE 802.1H",
+			s:    &SNAP{OUI: OUIBridgeTunnel, ProtocolID: 0x80f3},
+			et:   0x80f3,
+			ok:   true,
+		},
+		{
+			desc: "Cisco",
+			s:    &SNAP{OUI: OUICisco, ProtocolID: 0x2000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			et, ok := tt.s.EtherType()
+			if want, got := tt.ok, ok; want != got {
+				t.Fatalf("unexpected ok: %v != %v", want, got)
+			}
+			if want, got := tt.et, et; want != got {
+				t.Fatalf("unexpected EtherType: %v != %v", want, got)
+			}
+		})
+	}
+}
+
+func TestFrameLLC(t *testing.T) {
+	stp := bytes.Repeat([]byte{0xff}, 35)
+	cdp := bytes.Repeat([]byte{0xee}, 8)
+
+	tests := []struct {
+		desc string
+		f    *Frame
+		b    []byte
+	}{
+		{
+			desc: "LLC, STP",
+			f: &Frame{
+				Destination: []byte{0x01, 0x80, 0xc2, 0x00, 0x00, 0x00},
+				Source:      []byte{0, 1, 0, 1, 0, 1},
+				EtherType:   38,
+				LLC: &LLC{
+					DSAP:    0x42,
+					SSAP:    0x42,
+					Control: 0x03,
+				},
+				Payload:       stp,
+				Padding:       make([]byte, 8),
+				Encapsulation: EncapsulationLLC,
+			},
+			b: append(append([]byte{
+				0x01, 0x80, 0xc2, 0x00, 0x00, 0x00,
+				0, 1, 0, 1, 0, 1,
+				0x00, 38,
+				0x42, 0x42, 0x03,
+			}, stp...), bytes.Repeat([]byte{0}, 8)...),
+		},
+		{
+			desc: "SNAP, CDP, C-VLAN: (PRI 0, ID 10)",
+			f: &Frame{
+				Destination: []byte{0x01, 0x00, 0x0c, 0xcc, 0xcc, 0xcc},
+				Source:      []byte{0, 1, 0, 1, 0, 1},
+				VLAN:        &VLAN{ID: 10},
+				EtherType:   16,
+				LLC: &LLC{
+					DSAP:    0xaa,
+					SSAP:    0xaa,
+					Control: 0x03,
+				},
+				SNAP: &SNAP{
+					OUI:        [3]byte{0x00, 0x00, 0x0c},
+					ProtocolID: 0x2000,
+				},
+				Payload:       cdp,
+				Padding:       make([]byte, 30),
+				Encapsulation: EncapsulationSNAP,
+			},
+			b: append(append([]byte{
+				0x01, 0x00, 0x0c, 0xcc, 0xcc, 0xcc,
+				0, 1, 0, 1, 0, 1,
+				0x81, 0x00,
+				0x00, 0x0a,
+				0x00, 16,
+				0xaa, 0xaa, 0x03,
+				0x00, 0x00, 0x0c, 0x20, 0x00,
+			}, cdp...), bytes.Repeat([]byte{0}, 30)...),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := tt.f.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			if want, got := tt.b, b; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected Frame bytes:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			f := new(Frame)
+			if err := f.UnmarshalBinary(b); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+
+			if want, got := tt.f, f; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected Frame:\n- want: %v\n-  got: %v", want, got)
+			}
+		})
+	}
+}
+
+func TestFrameLLCErrors(t *testing.T) {
+	t.Run("SNAP without LLC", func(t *testing.T) {
+		f := &Frame{SNAP: &SNAP{}}
+		if _, err := f.MarshalBinary(); err != ErrInvalidLLC {
+			t.Fatalf("unexpected error: %v != %v", ErrInvalidLLC, err)
+		}
+	})
+
+	t.Run("payload too large", func(t *testing.T) {
+		f := &Frame{
+			LLC:     &LLC{Control: 0x03},
+			Payload: make([]byte, 1536),
+		}
+		if _, err := f.MarshalBinary(); err != ErrInvalidLength {
+			t.Fatalf("unexpected error: %v != %v", ErrInvalidLength, err)
+		}
+	})
+
+	t.Run("short SNAP", func(t *testing.T) {
+		b := []byte{
+			0, 0, 0, 0, 0, 0,
+			0, 0, 0, 0, 0, 0,
+			0x00, 0x06,
+			0xaa, 0xaa, 0x03,
+			0x00, 0x00, 0x0c, 0x20, 0x00,
+		}
+
+		if err := new(Frame).UnmarshalBinary(b); !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Fatalf("unexpected error: %v != %v", io.ErrUnexpectedEOF, err)
+		}
+	})
+}
+
+func TestFrameNovellRaw(t *testing.T) {
+	ipx := append([]byte{0xff, 0xff}, bytes.Repeat([]byte{0x01}, 48)...)
+
+	want := &Frame{
+		Destination:   Broadcast,
+		Source:        []byte{0, 1, 0, 1, 0, 1},
+		EtherType:     50,
+		Payload:       ipx,
+		Encapsulation: EncapsulationNovellRaw,
+	}
+
+	b, err := want.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	if want, got := []byte{0x00, 50, 0xff, 0xff}, b[12:16]; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected length and checksum bytes:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	got := new(Frame)
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected Frame:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	if want, got := "Novell raw", got.Encapsulation.String(); want != got {
+		t.Fatalf("unexpected Encapsulation string: %q != %q", want, got)
+	}
+}
+
+func TestFrameLengthField(t *testing.T) {
+	b := append([]byte{
+		0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0,
+		0x00, 0x40,
+		0x42, 0x42, 0x03,
+	}, make([]byte, 43)...)
+
+	f := new(Frame)
+	if err := f.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal leniently: %v", err)
+	}
+
+	if l, ok := f.LengthField(); !ok || l != 0x40 {
+		t.Fatalf("unexpected length field: %d, %v", l, ok)
+	}
+
+	err := UnmarshalOptions{Strict: true}.Unmarshal(b, f)
+
+	var fe *FieldError
+	if !errors.As(err, &fe) || !errors.Is(err, ErrInvalidLength) {
+		t.Fatalf("expected length *FieldError, but got: %#v", err)
+	}
+	if want, got := (FieldError{Field: "EtherType", Offset: 12, Err: ErrInvalidLength}), *fe; want != got {
+		t.Fatalf("unexpected FieldError: %v != %v", want, got)
+	}
+
+	// Values between the maximum length and the minimum EtherType are
+	// treated as lengths, as when unmarshaling.
+	f.EtherType = 1501
+	if l, ok := f.LengthField(); !ok || l != 1501 {
+		t.Fatalf("unexpected length field: %d, %v", l, ok)
+	}
+
+	f.EtherType = EtherTypeIPv4
+	if _, ok := f.LengthField(); ok {
+		t.Fatal("EtherType should not be a length")
+	}
+}
@@ -0,0 +1,211 @@
+package lldp
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// OUIIEEE8021 is the OUI of IEEE 802.1 organizationally specific TLVs,
+// including the IEEE DCBX TLVs.
+var OUIIEEE8021 = [3]byte{0x00, 0x80, 0xc2}
+
+// IEEE DCBX TLV subtypes.
+const (
+	dcbxETSConfiguration  = 0x09
+	dcbxETSRecommendation = 0x0a
+	dcbxPFC               = 0x0b
+	dcbxAppPriority       = 0x0c
+)
+
+const (
+	// etsLen is the length of an ETS TLV's information string.
+	etsLen = 1 + 4 + 8 + 8
+
+	// appPriorityEntryLen is the length of an application priority table
+	// entry.
+	appPriorityEntryLen = 3
+)
+
+// ETS is an IEEE DCBX Enhanced Transmission Selection Configuration or
+// Recommendation TLV, which maps priorities to traffic classes and
+// allocates bandwidth between them.
+type ETS struct {
+	// Recommendation indicates an ETS Recommendation TLV rather than an
+	// ETS Configuration TLV.  Willing, CBS, and MaxTCs are only used in
+	// Configuration TLVs.
+	Recommendation bool
+
+	// Willing indicates that the station is willing to accept
+	// configuration from its peer.
+	Willing bool
+
+	// CBS indicates support for the credit-based shaper.
+	CBS bool
+
+	// MaxTCs specifies the 3 bit number of traffic classes supported,
+	// where zero indicates 8.
+	MaxTCs uint8
+
+	// PriorityAssignment maps each priority to a 4 bit traffic class.
+	PriorityAssignment [8]uint8
+
+	// Bandwidth specifies the percentage of bandwidth allocated to each
+	// traffic class.
+	Bandwidth [8]uint8
+
+	// TSA specifies the transmission selection algorithm of each traffic
+	// class.
+	TSA [8]uint8
+}
+
+// ParseETS parses an IEEE DCBX ETS Configuration or Recommendation TLV from
+// o.  If o is not such a TLV, ErrInvalidTLV is returned.
+func ParseETS(o OrgTLV) (*ETS, error) {
+	if o.OUI != OUIIEEE8021 ||
+		(o.Subtype != dcbxETSConfiguration && o.Subtype != dcbxETSRecommendation) {
+		return nil, ErrInvalidTLV
+	}
+	if len(o.Info) < etsLen {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	b := o.Info
+	e := &ETS{Recommendation: o.Subtype == dcbxETSRecommendation}
+	if !e.Recommendation {
+		e.Willing = b[0]&0x80 != 0
+		e.CBS = b[0]&0x40 != 0
+		e.MaxTCs = b[0] & 0x07
+	}
+
+	for i := 0; i < 4; i++ {
+		e.PriorityAssignment[2*i] = b[1+i] >> 4
+		e.PriorityAssignment[2*i+1] = b[1+i] & 0x0f
+	}
+	copy(e.Bandwidth[:], b[5:13])
+	copy(e.TSA[:], b[13:21])
+
+	return e, nil
+}
+
+// OrgTLV returns the TLV form of e.  Fields are truncated to their widths.
+func (e *ETS) OrgTLV() OrgTLV {
+	b := make([]byte, etsLen)
+
+	subtype := uint8(dcbxETSRecommendation)
+	if !e.Recommendation {
+		subtype = dcbxETSConfiguration
+
+		b[0] = e.MaxTCs & 0x07
+		if e.Willing {
+			b[0] |= 0x80
+		}
+		if e.CBS {
+			b[0] |= 0x40
+		}
+	}
+
+	for i := 0; i < 4; i++ {
+		b[1+i] = e.PriorityAssignment[2*i]<<4 | e.PriorityAssignment[2*i+1]&0x0f
+	}
+	copy(b[5:13], e.Bandwidth[:])
+	copy(b[13:21], e.TSA[:])
+
+	return OrgTLV{OUI: OUIIEEE8021, Subtype: subtype, Info: b}
+}
+
+// PFC is the IEEE DCBX Priority-based Flow Control Configuration TLV.
+type PFC struct {
+	// Willing indicates that the station is willing to accept
+	// configuration from its peer.
+	Willing bool
+
+	// MBC indicates that the station is capable of bypassing MACsec.
+	MBC bool
+
+	// Capability specifies the 4 bit number of priorities which may
+	// simultaneously have PFC enabled.
+	Capability uint8
+
+	// Enabled is a bitmap of the priorities with PFC enabled, where bit n
+	// corresponds to priority n.
+	Enabled uint8
+}
+
+// ParsePFC parses an IEEE DCBX PFC Configuration TLV from o.  If o is not
+// such a TLV, ErrInvalidTLV is returned.
+func ParsePFC(o OrgTLV) (*PFC, error) {
+	if err := checkOrg(o, OUIIEEE8021, dcbxPFC, 2); err != nil {
+		return nil, err
+	}
+
+	return &PFC{
+		Willing:    o.Info[0]&0x80 != 0,
+		MBC:        o.Info[0]&0x40 != 0,
+		Capability: o.Info[0] & 0x0f,
+		Enabled:    o.Info[1],
+	}, nil
+}
+
+// OrgTLV returns the TLV form of p.  Fields are truncated to their widths.
+func (p *PFC) OrgTLV() OrgTLV {
+	b := []byte{p.Capability & 0x0f, p.Enabled}
+	if p.Willing {
+		b[0] |= 0x80
+	}
+	if p.MBC {
+		b[0] |= 0x40
+	}
+
+	return OrgTLV{OUI: OUIIEEE8021, Subtype: dcbxPFC, Info: b}
+}
+
+// An AppPriority is an entry in an IEEE DCBX Application Priority TLV.
+type AppPriority struct {
+	// Priority specifies the 3 bit priority used by the application.
+	Priority uint8
+
+	// Selector specifies the 3 bit meaning of Protocol, such as 1 for an
+	// EtherType, or 2 for a TCP or SCTP port.
+	Selector uint8
+
+	// Protocol identifies the application.
+	Protocol uint16
+}
+
+// ParseAppPriority parses the entries of an IEEE DCBX Application Priority
+// TLV from o.  If o is not such a TLV, ErrInvalidTLV is returned.
+func ParseAppPriority(o OrgTLV) ([]AppPriority, error) {
+	if err := checkOrg(o, OUIIEEE8021, dcbxAppPriority, 1); err != nil {
+		return nil, err
+	}
+
+	// Skip the reserved byte.
+	b := o.Info[1:]
+	if len(b)%appPriorityEntryLen != 0 {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	aps := make([]AppPriority, 0, len(b)/appPriorityEntryLen)
+	for ; len(b) > 0; b = b[appPriorityEntryLen:] {
+		aps = append(aps, AppPriority{
+			Priority: b[0] >> 5,
+			Selector: b[0] & 0x07,
+			Protocol: binary.BigEndian.Uint16(b[1:3]),
+		})
+	}
+
+	return aps, nil
+}
+
+// AppPriorityTLV returns the IEEE DCBX Application Priority TLV form of
+// aps.  Fields are truncated to their widths.
+func AppPriorityTLV(aps []AppPriority) OrgTLV {
+	b := make([]byte, 1+appPriorityEntryLen*len(aps))
+	for i, ap := range aps {
+		e := b[1+i*appPriorityEntryLen:]
+		e[0] = (ap.Priority&0x07)<<5 | ap.Selector&0x07
+		binary.BigEndian.PutUint16(e[1:3], ap.Protocol)
+	}
+
+	return OrgTLV{OUI: OUIIEEE8021, Subtype: dcbxAppPriority, Info: b}
+}
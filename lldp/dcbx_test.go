@@ -0,0 +1,180 @@
+package lldp
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestETSRoundTrip(t *testing.T) {
+	tests := []struct {
+		desc string
+		tlv  OrgTLV
+		ets  *ETS
+	}{
+		{
+			desc: "configuration",
+			tlv: OrgTLV{
+				OUI:     OUIIEEE8021,
+				Subtype: dcbxETSConfiguration,
+				Info: []byte{
+					0x83,
+					0x01, 0x23, 0x45, 0x67,
+					10, 20, 30, 40, 0, 0, 0, 0,
+					2, 2, 2, 2, 0, 0, 0, 0,
+				},
+			},
+			ets: &ETS{
+				Willing:            true,
+				MaxTCs:             3,
+				PriorityAssignment: [8]uint8{0, 1, 2, 3, 4, 5, 6, 7},
+				Bandwidth:          [8]uint8{10, 20, 30, 40},
+				TSA:                [8]uint8{2, 2, 2, 2},
+			},
+		},
+		{
+			desc: "recommendation",
+			tlv: OrgTLV{
+				OUI:     OUIIEEE8021,
+				Subtype: dcbxETSRecommendation,
+				Info: []byte{
+					0x00,
+					0x11, 0x11, 0x11, 0x11,
+					100, 0, 0, 0, 0, 0, 0, 0,
+					0, 0, 0, 0, 0, 0, 0, 0,
+				},
+			},
+			ets: &ETS{
+				Recommendation:     true,
+				PriorityAssignment: [8]uint8{1, 1, 1, 1, 1, 1, 1, 1},
+				Bandwidth:          [8]uint8{100},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ets, err := ParseETS(tt.tlv)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+
+			if !reflect.DeepEqual(tt.ets, ets) {
+				t.Fatalf("unexpected ETS:\n- want: %#v\n-  got: %#v", tt.ets, ets)
+			}
+
+			if o := ets.OrgTLV(); !reflect.DeepEqual(tt.tlv, o) {
+				t.Fatalf("unexpected TLV:\n- want: %#v\n-  got: %#v", tt.tlv, o)
+			}
+		})
+	}
+}
+
+func TestPFCRoundTrip(t *testing.T) {
+	tlv := OrgTLV{
+		OUI:     OUIIEEE8021,
+		Subtype: dcbxPFC,
+		Info:    []byte{0xc8, 0x08},
+	}
+
+	p, err := ParsePFC(tlv)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	want := &PFC{
+		Willing:    true,
+		MBC:        true,
+		Capability: 8,
+		Enabled:    1 << 3,
+	}
+
+	if !reflect.DeepEqual(want, p) {
+		t.Fatalf("unexpected PFC:\n- want: %#v\n-  got: %#v", want, p)
+	}
+	if o := p.OrgTLV(); !reflect.DeepEqual(tlv, o) {
+		t.Fatalf("unexpected TLV:\n- want: %#v\n-  got: %#v", tlv, o)
+	}
+}
+
+func TestAppPriorityRoundTrip(t *testing.T) {
+	tlv := OrgTLV{
+		OUI:     OUIIEEE8021,
+		Subtype: dcbxAppPriority,
+		Info: []byte{
+			0x00,
+			0x61, 0x89, 0x06,
+			0x82, 0x0c, 0xbc,
+		},
+	}
+
+	aps, err := ParseAppPriority(tlv)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	want := []AppPriority{
+		{Priority: 3, Selector: 1, Protocol: 0x8906},
+		{Priority: 4, Selector: 2, Protocol: 3260},
+	}
+
+	if !reflect.DeepEqual(want, aps) {
+		t.Fatalf("unexpected entries:\n- want: %#v\n-  got: %#v", want, aps)
+	}
+	if o := AppPriorityTLV(aps); !reflect.DeepEqual(tlv, o) {
+		t.Fatalf("unexpected TLV:\n- want: %#v\n-  got: %#v", tlv, o)
+	}
+}
+
+func TestDCBXErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		fn   func() error
+		err  error
+	}{
+		{
+			desc: "ETS wrong subtype",
+			fn: func() error {
+				_, err := ParseETS(OrgTLV{OUI: OUIIEEE8021, Subtype: dcbxPFC})
+				return err
+			},
+			err: ErrInvalidTLV,
+		},
+		{
+			desc: "ETS short",
+			fn: func() error {
+				_, err := ParseETS(OrgTLV{OUI: OUIIEEE8021, Subtype: dcbxETSConfiguration})
+				return err
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "PFC wrong OUI",
+			fn: func() error {
+				_, err := ParsePFC(OrgTLV{OUI: OUIMED, Subtype: dcbxPFC})
+				return err
+			},
+			err: ErrInvalidTLV,
+		},
+		{
+			desc: "application priority partial entry",
+			fn: func() error {
+				_, err := ParseAppPriority(OrgTLV{
+					OUI:     OUIIEEE8021,
+					Subtype: dcbxAppPriority,
+					Info:    []byte{0x00, 0x61},
+				})
+				return err
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := tt.fn(); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+}
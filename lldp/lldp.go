@@ -0,0 +1,372 @@
+// Package lldp implements marshaling and unmarshaling of IEEE 802.1AB Link
+// Layer Discovery Protocol data units carried in Ethernet frames.
+package lldp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// EtherType is the EtherType which indicates an LLDP data unit in a Frame.
+const EtherType = ethernet.EtherTypeLLDP
+
+// Destination hardware addresses for LLDP frames, which determine how far
+// a data unit propagates through bridges.
+var (
+	NearestBridge         = net.HardwareAddr{0x01, 0x80, 0xc2, 0x00, 0x00, 0x0e}
+	NearestNonTPMRBridge  = net.HardwareAddr{0x01, 0x80, 0xc2, 0x00, 0x00, 0x03}
+	NearestCustomerBridge = net.HardwareAddr{0x01, 0x80, 0xc2, 0x00, 0x00, 0x00}
+)
+
+const (
+	// tlvHeaderLen is the length of a TLV's type and length fields.
+	tlvHeaderLen = 2
+
+	// maxTLVLen is the maximum length of a TLV's value, which is specified
+	// using 9 bits.
+	maxTLVLen = 0x1ff
+
+	// orgHeaderLen is the length of an organizationally specific TLV's OUI
+	// and subtype.
+	orgHeaderLen = 4
+)
+
+var (
+	// ErrInvalidTLV is returned when a data unit's mandatory TLVs are
+	// missing, out of order, or malformed.
+	ErrInvalidTLV = errors.New("lldp: invalid TLV")
+
+	// ErrTLVTooLarge is returned when a TLV's value is longer than 511
+	// bytes.
+	ErrTLVTooLarge = errors.New("lldp: TLV too large")
+)
+
+// A TLVType is the type of an LLDP TLV.
+type TLVType uint8
+
+// Possible TLVType values.
+const (
+	TLVEnd                  TLVType = 0
+	TLVChassisID            TLVType = 1
+	TLVPortID               TLVType = 2
+	TLVTTL                  TLVType = 3
+	TLVPortDescription      TLVType = 4
+	TLVSystemName           TLVType = 5
+	TLVSystemDescription    TLVType = 6
+	TLVSystemCapabilities   TLVType = 7
+	TLVManagementAddress    TLVType = 8
+	TLVOrganizationSpecific TLVType = 127
+)
+
+// String returns the name of a TLVType.
+func (t TLVType) String() string {
+	switch t {
+	case TLVEnd:
+		return "End"
+	case TLVChassisID:
+		return "ChassisID"
+	case TLVPortID:
+		return "PortID"
+	case TLVTTL:
+		return "TTL"
+	case TLVPortDescription:
+		return "PortDescription"
+	case TLVSystemName:
+		return "SystemName"
+	case TLVSystemDescription:
+		return "SystemDescription"
+	case TLVSystemCapabilities:
+		return "SystemCapabilities"
+	case TLVManagementAddress:
+		return "ManagementAddress"
+	case TLVOrganizationSpecific:
+		return "OrganizationSpecific"
+	default:
+		return fmt.Sprintf("TLVType(%d)", uint8(t))
+	}
+}
+
+// A ChassisIDSubtype specifies the format of a ChassisID.
+type ChassisIDSubtype uint8
+
+// Possible ChassisIDSubtype values.
+const (
+	ChassisIDChassisComponent ChassisIDSubtype = 1
+	ChassisIDInterfaceAlias   ChassisIDSubtype = 2
+	ChassisIDPortComponent    ChassisIDSubtype = 3
+	ChassisIDMACAddress       ChassisIDSubtype = 4
+	ChassisIDNetworkAddress   ChassisIDSubtype = 5
+	ChassisIDInterfaceName    ChassisIDSubtype = 6
+	ChassisIDLocal            ChassisIDSubtype = 7
+)
+
+// A ChassisID identifies the chassis which transmitted a data unit.
+type ChassisID struct {
+	Subtype ChassisIDSubtype
+	ID      []byte
+}
+
+// A PortIDSubtype specifies the format of a PortID.
+type PortIDSubtype uint8
+
+// Possible PortIDSubtype values.
+const (
+	PortIDInterfaceAlias PortIDSubtype = 1
+	PortIDPortComponent  PortIDSubtype = 2
+	PortIDMACAddress     PortIDSubtype = 3
+	PortIDNetworkAddress PortIDSubtype = 4
+	PortIDInterfaceName  PortIDSubtype = 5
+	PortIDAgentCircuitID PortIDSubtype = 6
+	PortIDLocal          PortIDSubtype = 7
+)
+
+// A PortID identifies the port which transmitted a data unit.
+type PortID struct {
+	Subtype PortIDSubtype
+	ID      []byte
+}
+
+// A TLV is an optional LLDP TLV.
+type TLV struct {
+	Type  TLVType
+	Value []byte
+}
+
+// An OrgTLV is an organizationally specific LLDP TLV.
+type OrgTLV struct {
+	// OUI specifies the organizationally unique identifier of the
+	// organization which defined the TLV.
+	OUI [3]byte
+
+	// Subtype specifies the organization's TLV type.
+	Subtype uint8
+
+	// Info specifies the TLV's information string.
+	Info []byte
+}
+
+// A DataUnit is an LLDP data unit.
+type DataUnit struct {
+	// ChassisID, PortID, and TTL are the mandatory TLVs of a data unit.
+	// TTL is transmitted in whole seconds.
+	ChassisID ChassisID
+	PortID    PortID
+	TTL       time.Duration
+
+	// Optional specifies optional TLVs, other than organizationally
+	// specific TLVs, in the order they appear.
+	Optional []TLV
+
+	// OrgSpecific specifies organizationally specific TLVs, in the order
+	// they appear.
+	OrgSpecific []OrgTLV
+}
+
+// Parse unmarshals the LLDP data unit carried in the payload of Frame f.  If
+// f's EtherType is not EtherType, ethernet.ErrInvalidEtherType is returned.
+func Parse(f *ethernet.Frame) (*DataUnit, error) {
+	if f.EtherType != EtherType {
+		return nil, ethernet.ErrInvalidEtherType
+	}
+
+	du := new(DataUnit)
+	if err := du.UnmarshalBinary(f.Payload); err != nil {
+		return nil, err
+	}
+
+	return du, nil
+}
+
+// Frame marshals a DataUnit into the payload of an Ethernet frame sent from
+// hardware address src to NearestBridge.
+func (du *DataUnit) Frame(src net.HardwareAddr) (*ethernet.Frame, error) {
+	b, err := du.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ethernet.Frame{
+		Destination: append(net.HardwareAddr(nil), NearestBridge...),
+		Source:      src,
+		EtherType:   EtherType,
+		Payload:     b,
+	}, nil
+}
+
+// TLV returns the value of the first optional TLV of type t, and reports
+// whether it was found.
+func (du *DataUnit) TLV(t TLVType) ([]byte, bool) {
+	for _, tlv := range du.Optional {
+		if tlv.Type == t {
+			return tlv.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// MarshalBinary allocates a byte slice and marshals a DataUnit into binary
+// form, terminated by an End TLV.
+func (du *DataUnit) MarshalBinary() ([]byte, error) {
+	var ttl [2]byte
+	binary.BigEndian.PutUint16(ttl[:], uint16(du.TTL/time.Second))
+
+	var (
+		b   []byte
+		err error
+	)
+	for _, tlv := range []TLV{
+		{Type: TLVChassisID, Value: append([]byte{uint8(du.ChassisID.Subtype)}, du.ChassisID.ID...)},
+		{Type: TLVPortID, Value: append([]byte{uint8(du.PortID.Subtype)}, du.PortID.ID...)},
+		{Type: TLVTTL, Value: ttl[:]},
+	} {
+		if b, err = appendTLV(b, tlv); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, tlv := range du.Optional {
+		if tlv.Type <= TLVTTL || tlv.Type == TLVOrganizationSpecific {
+			return nil, ErrInvalidTLV
+		}
+
+		if b, err = appendTLV(b, tlv); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, o := range du.OrgSpecific {
+		v := make([]byte, orgHeaderLen+len(o.Info))
+		copy(v[0:3], o.OUI[:])
+		v[3] = o.Subtype
+		copy(v[orgHeaderLen:], o.Info)
+
+		if b, err = appendTLV(b, TLV{Type: TLVOrganizationSpecific, Value: v}); err != nil {
+			return nil, err
+		}
+	}
+
+	return appendTLV(b, TLV{Type: TLVEnd})
+}
+
+// appendTLV appends the binary form of tlv to b.
+func appendTLV(b []byte, tlv TLV) ([]byte, error) {
+	if len(tlv.Value) > maxTLVLen {
+		return nil, ErrTLVTooLarge
+	}
+
+	var h [tlvHeaderLen]byte
+	binary.BigEndian.PutUint16(h[:], uint16(tlv.Type)<<9|uint16(len(tlv.Value)))
+
+	b = append(b, h[:]...)
+	return append(b, tlv.Value...), nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into a DataUnit.  The mandatory
+// Chassis ID, Port ID, and TTL TLVs must appear first and in order.  Parsing
+// stops at an End TLV or at the end of b, and any trailing bytes, such as
+// Ethernet padding, are ignored.
+func (du *DataUnit) UnmarshalBinary(b []byte) error {
+	var (
+		out DataUnit
+		i   int
+	)
+
+	for len(b) > 0 {
+		if len(b) < tlvHeaderLen {
+			return io.ErrUnexpectedEOF
+		}
+
+		h := binary.BigEndian.Uint16(b[:tlvHeaderLen])
+		t, l := TLVType(h>>9), int(h&maxTLVLen)
+		if len(b[tlvHeaderLen:]) < l {
+			return io.ErrUnexpectedEOF
+		}
+
+		v := make([]byte, l)
+		copy(v, b[tlvHeaderLen:tlvHeaderLen+l])
+		b = b[tlvHeaderLen+l:]
+
+		if t == TLVEnd {
+			break
+		}
+
+		// The first three TLVs must be the mandatory TLVs, in order.
+		if i < 3 {
+			if t != TLVType(i+1) {
+				return ErrInvalidTLV
+			}
+
+			if err := out.parseMandatory(t, v); err != nil {
+				return err
+			}
+
+			i++
+			continue
+		}
+
+		switch t {
+		case TLVChassisID, TLVPortID, TLVTTL:
+			return ErrInvalidTLV
+		case TLVOrganizationSpecific:
+			if len(v) < orgHeaderLen {
+				return ErrInvalidTLV
+			}
+
+			o := OrgTLV{
+				Subtype: v[3],
+				Info:    v[orgHeaderLen:],
+			}
+			copy(o.OUI[:], v[0:3])
+
+			out.OrgSpecific = append(out.OrgSpecific, o)
+		default:
+			out.Optional = append(out.Optional, TLV{Type: t, Value: v})
+		}
+	}
+
+	if i < 3 {
+		return ErrInvalidTLV
+	}
+
+	*du = out
+	return nil
+}
+
+// parseMandatory parses the value of a mandatory TLV.
+func (du *DataUnit) parseMandatory(t TLVType, v []byte) error {
+	switch t {
+	case TLVChassisID:
+		if len(v) < 2 {
+			return ErrInvalidTLV
+		}
+
+		du.ChassisID = ChassisID{
+			Subtype: ChassisIDSubtype(v[0]),
+			ID:      v[1:],
+		}
+	case TLVPortID:
+		if len(v) < 2 {
+			return ErrInvalidTLV
+		}
+
+		du.PortID = PortID{
+			Subtype: PortIDSubtype(v[0]),
+			ID:      v[1:],
+		}
+	case TLVTTL:
+		if len(v) != 2 {
+			return ErrInvalidTLV
+		}
+
+		du.TTL = time.Duration(binary.BigEndian.Uint16(v)) * time.Second
+	}
+
+	return nil
+}
@@ -0,0 +1,229 @@
+package lldp
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestDataUnitMarshalUnmarshalBinary(t *testing.T) {
+	du := &DataUnit{
+		ChassisID: ChassisID{
+			Subtype: ChassisIDMACAddress,
+			ID:      []byte{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+		},
+		PortID: PortID{
+			Subtype: PortIDInterfaceName,
+			ID:      []byte("eth0"),
+		},
+		TTL: 120 * time.Second,
+		Optional: []TLV{{
+			Type:  TLVSystemName,
+			Value: []byte("foo"),
+		}},
+		OrgSpecific: []OrgTLV{{
+			OUI:     [3]byte{0x00, 0x80, 0xc2},
+			Subtype: 1,
+			Info:    []byte{0x00, 0x0a},
+		}},
+	}
+
+	b, err := du.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	want := []byte{
+		// Chassis ID.
+		0x02, 0x07, 0x04, 0xde, 0xad, 0xbe, 0xef, 0xde, 0xad,
+		// Port ID.
+		0x04, 0x05, 0x05, 'e', 't', 'h', '0',
+		// TTL.
+		0x06, 0x02, 0x00, 0x78,
+		// System name.
+		0x0a, 0x03, 'f', 'o', 'o',
+		// Organizationally specific.
+		0xfe, 0x06, 0x00, 0x80, 0xc2, 0x01, 0x00, 0x0a,
+		// End.
+		0x00, 0x00,
+	}
+
+	if !bytes.Equal(want, b) {
+		t.Fatalf("unexpected bytes:\n- want: %v\n-  got: %v", want, b)
+	}
+
+	// Trailing padding is ignored.
+	got := new(DataUnit)
+	if err := got.UnmarshalBinary(append(b, make([]byte, 8)...)); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if !reflect.DeepEqual(du, got) {
+		t.Fatalf("unexpected data unit:\n- want: %#v\n-  got: %#v", du, got)
+	}
+
+	if v, ok := got.TLV(TLVSystemName); !ok || string(v) != "foo" {
+		t.Fatalf("unexpected system name: %q, %v", v, ok)
+	}
+	if _, ok := got.TLV(TLVPortDescription); ok {
+		t.Fatal("unexpected port description")
+	}
+}
+
+func TestDataUnitMarshalBinaryErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		du   *DataUnit
+		err  error
+	}{
+		{
+			desc: "mandatory optional TLV",
+			du: &DataUnit{
+				Optional: []TLV{{Type: TLVTTL}},
+			},
+			err: ErrInvalidTLV,
+		},
+		{
+			desc: "TLV too large",
+			du: &DataUnit{
+				PortID: PortID{ID: make([]byte, maxTLVLen)},
+			},
+			err: ErrTLVTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if _, err := tt.du.MarshalBinary(); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestDataUnitUnmarshalBinaryErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		b    []byte
+		err  error
+	}{
+		{
+			desc: "short header",
+			b:    []byte{0x02},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "short value",
+			b:    []byte{0x02, 0x07, 0x04},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "missing mandatory",
+			b: []byte{
+				0x02, 0x02, 0x04, 0xde,
+				0x00, 0x00,
+			},
+			err: ErrInvalidTLV,
+		},
+		{
+			desc: "out of order",
+			b: []byte{
+				0x04, 0x02, 0x05, 'a',
+			},
+			err: ErrInvalidTLV,
+		},
+		{
+			desc: "bad TTL",
+			b: []byte{
+				0x02, 0x02, 0x04, 0xde,
+				0x04, 0x02, 0x05, 'a',
+				0x06, 0x01, 0x00,
+			},
+			err: ErrInvalidTLV,
+		},
+		{
+			desc: "duplicate mandatory",
+			b: []byte{
+				0x02, 0x02, 0x04, 0xde,
+				0x04, 0x02, 0x05, 'a',
+				0x06, 0x02, 0x00, 0x78,
+				0x06, 0x02, 0x00, 0x78,
+			},
+			err: ErrInvalidTLV,
+		},
+		{
+			desc: "short organizationally specific",
+			b: []byte{
+				0x02, 0x02, 0x04, 0xde,
+				0x04, 0x02, 0x05, 'a',
+				0x06, 0x02, 0x00, 0x78,
+				0xfe, 0x03, 0x00, 0x80, 0xc2,
+			},
+			err: ErrInvalidTLV,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := new(DataUnit).UnmarshalBinary(tt.b); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestFrameParse(t *testing.T) {
+	src := net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}
+	du := &DataUnit{
+		ChassisID: ChassisID{Subtype: ChassisIDMACAddress, ID: src},
+		PortID:    PortID{Subtype: PortIDLocal, ID: []byte{0x01}},
+		TTL:       10 * time.Second,
+	}
+
+	f, err := du.Frame(src)
+	if err != nil {
+		t.Fatalf("failed to create frame: %v", err)
+	}
+
+	if want, got := NearestBridge, f.Destination; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected destination: %v != %v", want, got)
+	}
+
+	// Round trip through the binary form to include padding.
+	b, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal frame: %v", err)
+	}
+
+	f2 := new(ethernet.Frame)
+	if err := f2.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal frame: %v", err)
+	}
+
+	got, err := Parse(f2)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if !reflect.DeepEqual(du, got) {
+		t.Fatalf("unexpected data unit:\n- want: %#v\n-  got: %#v", du, got)
+	}
+
+	if _, err := Parse(&ethernet.Frame{EtherType: ethernet.EtherTypeIPv4}); err != ethernet.ErrInvalidEtherType {
+		t.Fatalf("expected ErrInvalidEtherType, but got: %v", err)
+	}
+}
+
+func TestTLVTypeString(t *testing.T) {
+	if want, got := "SystemName", TLVSystemName.String(); want != got {
+		t.Fatalf("unexpected string: %q != %q", want, got)
+	}
+	if want, got := "TLVType(100)", TLVType(100).String(); want != got {
+		t.Fatalf("unexpected string: %q != %q", want, got)
+	}
+}
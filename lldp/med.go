@@ -0,0 +1,164 @@
+package lldp
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// OUIMED is the OUI of LLDP-MED organizationally specific TLVs, as
+// described in ANSI/TIA-1057.
+var OUIMED = [3]byte{0x00, 0x12, 0xbb}
+
+// LLDP-MED TLV subtypes.
+const (
+	medCapabilities  = 1
+	medNetworkPolicy = 2
+	medLocation      = 3
+)
+
+// MEDCapabilities is the LLDP-MED Capabilities TLV.
+type MEDCapabilities struct {
+	// Capabilities is a bitmap of the LLDP-MED TLVs supported by the
+	// device.
+	Capabilities uint16
+
+	// DeviceType specifies the LLDP-MED device class, such as 3 for an
+	// endpoint such as an IP phone, or 4 for network connectivity.
+	DeviceType uint8
+}
+
+// ParseMEDCapabilities parses an LLDP-MED Capabilities TLV from o.  If o is
+// not such a TLV, ErrInvalidTLV is returned.
+func ParseMEDCapabilities(o OrgTLV) (*MEDCapabilities, error) {
+	if err := checkOrg(o, OUIMED, medCapabilities, 3); err != nil {
+		return nil, err
+	}
+
+	return &MEDCapabilities{
+		Capabilities: binary.BigEndian.Uint16(o.Info[0:2]),
+		DeviceType:   o.Info[2],
+	}, nil
+}
+
+// OrgTLV returns the TLV form of c.
+func (c *MEDCapabilities) OrgTLV() OrgTLV {
+	info := make([]byte, 3)
+	binary.BigEndian.PutUint16(info[0:2], c.Capabilities)
+	info[2] = c.DeviceType
+
+	return OrgTLV{OUI: OUIMED, Subtype: medCapabilities, Info: info}
+}
+
+// NetworkPolicy is the LLDP-MED Network Policy TLV, which advertises the
+// VLAN and quality of service used by an application such as voice.
+type NetworkPolicy struct {
+	// Application specifies the application type, such as 1 for voice.
+	Application uint8
+
+	// Unknown indicates that the policy for the application is not yet
+	// known.
+	Unknown bool
+
+	// Tagged indicates that the application uses VLAN tagged frames.
+	Tagged bool
+
+	// VLAN specifies the 12 bit VLAN ID used by the application.
+	VLAN uint16
+
+	// Priority specifies the 3 bit IEEE 802.1p priority used by the
+	// application.
+	Priority uint8
+
+	// DSCP specifies the 6 bit DiffServ code point used by the
+	// application.
+	DSCP uint8
+}
+
+// ParseNetworkPolicy parses an LLDP-MED Network Policy TLV from o.  If o is
+// not such a TLV, ErrInvalidTLV is returned.
+func ParseNetworkPolicy(o OrgTLV) (*NetworkPolicy, error) {
+	if err := checkOrg(o, OUIMED, medNetworkPolicy, 4); err != nil {
+		return nil, err
+	}
+
+	//  8 bits: application type
+	//  1 bit : unknown policy
+	//  1 bit : tagged
+	//  1 bit : reserved
+	// 12 bits: VLAN ID
+	//  3 bits: L2 priority
+	//  6 bits: DSCP
+	v := binary.BigEndian.Uint32(o.Info[0:4])
+	return &NetworkPolicy{
+		Application: uint8(v >> 24),
+		Unknown:     v&(1<<23) != 0,
+		Tagged:      v&(1<<22) != 0,
+		VLAN:        uint16(v>>9) & 0x0fff,
+		Priority:    uint8(v>>6) & 0x07,
+		DSCP:        uint8(v) & 0x3f,
+	}, nil
+}
+
+// OrgTLV returns the TLV form of p.  Fields are truncated to their widths.
+func (p *NetworkPolicy) OrgTLV() OrgTLV {
+	v := uint32(p.Application)<<24 |
+		uint32(p.VLAN&0x0fff)<<9 |
+		uint32(p.Priority&0x07)<<6 |
+		uint32(p.DSCP&0x3f)
+	if p.Unknown {
+		v |= 1 << 23
+	}
+	if p.Tagged {
+		v |= 1 << 22
+	}
+
+	info := make([]byte, 4)
+	binary.BigEndian.PutUint32(info, v)
+
+	return OrgTLV{OUI: OUIMED, Subtype: medNetworkPolicy, Info: info}
+}
+
+// A Location is the LLDP-MED Location Identification TLV.
+type Location struct {
+	// Format specifies the format of Data: 1 for coordinate-based LCI, 2
+	// for civic address LCI, or 3 for an ECS ELIN.
+	Format uint8
+
+	// Data is the location data.
+	Data []byte
+}
+
+// ParseLocation parses an LLDP-MED Location Identification TLV from o.  If
+// o is not such a TLV, ErrInvalidTLV is returned.
+func ParseLocation(o OrgTLV) (*Location, error) {
+	if err := checkOrg(o, OUIMED, medLocation, 1); err != nil {
+		return nil, err
+	}
+
+	return &Location{
+		Format: o.Info[0],
+		Data:   o.Info[1:],
+	}, nil
+}
+
+// OrgTLV returns the TLV form of l.
+func (l *Location) OrgTLV() OrgTLV {
+	return OrgTLV{
+		OUI:     OUIMED,
+		Subtype: medLocation,
+		Info:    append([]byte{l.Format}, l.Data...),
+	}
+}
+
+// checkOrg verifies that o has the specified OUI and subtype, and that its
+// information string is at least n bytes.
+func checkOrg(o OrgTLV, oui [3]byte, subtype uint8, n int) error {
+	if o.OUI != oui || o.Subtype != subtype {
+		return ErrInvalidTLV
+	}
+	if len(o.Info) < n {
+		return io.ErrUnexpectedEOF
+	}
+
+	return nil
+}
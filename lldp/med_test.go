@@ -0,0 +1,110 @@
+package lldp
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestMEDRoundTrip(t *testing.T) {
+	tests := []struct {
+		desc  string
+		tlv   OrgTLV
+		parse func(o OrgTLV) (interface{}, error)
+		want  interface{}
+	}{
+		{
+			desc: "capabilities",
+			tlv: OrgTLV{
+				OUI:     OUIMED,
+				Subtype: medCapabilities,
+				Info:    []byte{0x00, 0x33, 0x03},
+			},
+			parse: func(o OrgTLV) (interface{}, error) { return ParseMEDCapabilities(o) },
+			want: &MEDCapabilities{
+				Capabilities: 0x0033,
+				DeviceType:   3,
+			},
+		},
+		{
+			desc: "network policy",
+			tlv: OrgTLV{
+				OUI:     OUIMED,
+				Subtype: medNetworkPolicy,
+				Info:    []byte{0x01, 0x40, 0x15, 0x6e},
+			},
+			parse: func(o OrgTLV) (interface{}, error) { return ParseNetworkPolicy(o) },
+			want: &NetworkPolicy{
+				Application: 1,
+				Tagged:      true,
+				VLAN:        10,
+				Priority:    5,
+				DSCP:        46,
+			},
+		},
+		{
+			desc: "location",
+			tlv: OrgTLV{
+				OUI:     OUIMED,
+				Subtype: medLocation,
+				Info:    []byte{0x03, '9', '1', '1'},
+			},
+			parse: func(o OrgTLV) (interface{}, error) { return ParseLocation(o) },
+			want: &Location{
+				Format: 3,
+				Data:   []byte("911"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := tt.parse(tt.tlv)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+
+			if !reflect.DeepEqual(tt.want, got) {
+				t.Fatalf("unexpected value:\n- want: %#v\n-  got: %#v", tt.want, got)
+			}
+
+			o := got.(interface{ OrgTLV() OrgTLV }).OrgTLV()
+			if !reflect.DeepEqual(tt.tlv, o) {
+				t.Fatalf("unexpected TLV:\n- want: %#v\n-  got: %#v", tt.tlv, o)
+			}
+		})
+	}
+}
+
+func TestMEDErrors(t *testing.T) {
+	if _, err := ParseNetworkPolicy(OrgTLV{OUI: OUIMED, Subtype: medLocation}); err != ErrInvalidTLV {
+		t.Fatalf("expected ErrInvalidTLV, but got: %v", err)
+	}
+	if _, err := ParseNetworkPolicy(OrgTLV{OUI: OUIIEEE8021, Subtype: medNetworkPolicy}); err != ErrInvalidTLV {
+		t.Fatalf("expected ErrInvalidTLV, but got: %v", err)
+	}
+
+	o := OrgTLV{OUI: OUIMED, Subtype: medNetworkPolicy, Info: []byte{0x01}}
+	if _, err := ParseNetworkPolicy(o); err != io.ErrUnexpectedEOF {
+		t.Fatalf("expected io.ErrUnexpectedEOF, but got: %v", err)
+	}
+}
+
+func TestMEDDataUnit(t *testing.T) {
+	du := &DataUnit{
+		ChassisID:   ChassisID{Subtype: ChassisIDLocal, ID: []byte{0x01}},
+		PortID:      PortID{Subtype: PortIDLocal, ID: []byte{0x01}},
+		OrgSpecific: []OrgTLV{(&NetworkPolicy{Application: 1, VLAN: 10}).OrgTLV()},
+	}
+
+	b, err := du.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	want := []byte{0xfe, 0x08, 0x00, 0x12, 0xbb, 0x02, 0x01, 0x00, 0x14, 0x00}
+	if !bytes.Contains(b, want) {
+		t.Fatalf("network policy TLV not found:\n- want: %v\n-  got: %v", want, b)
+	}
+}
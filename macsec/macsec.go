@@ -0,0 +1,322 @@
+// Package macsec implements marshaling and unmarshaling of IEEE 802.1AE MAC
+// security SecTAGs and ICVs, and protection of MACsec frames using a
+// caller-supplied AEAD cipher such as GCM-AES-128.
+package macsec
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// EtherType is the EtherType which indicates a SecTAG in a Frame.
+const EtherType = ethernet.EtherTypeMACsec
+
+const (
+	// ICVLen is the length of the integrity check value used by the default
+	// GCM-AES cipher suites.
+	ICVLen = 16
+
+	// secTAGLen is the length of a SecTAG without an SCI, and sciLen is the
+	// length of an SCI.
+	secTAGLen = 6
+	sciLen    = 8
+
+	// maxShortLen is the largest secure data length which is described by
+	// the short length field.
+	maxShortLen = 47
+
+	// nonceLen is the length of a GCM-AES nonce, which is formed from an SCI
+	// and packet number.
+	nonceLen = sciLen + 4
+
+	// tciVersion and tciSC are the version and SCI present bits of the TCI,
+	// which are set by this package.
+	tciVersion = 0x80
+	tciSC      = 0x20
+)
+
+var (
+	// ErrInvalidSecTAG is returned when a SecTAG has a nonzero version, or
+	// when its short length does not fit in 6 bits.
+	ErrInvalidSecTAG = errors.New("macsec: invalid SecTAG")
+
+	// ErrInvalidICV is returned when an ICV is not ICVLen bytes, or when it
+	// fails verification.
+	ErrInvalidICV = errors.New("macsec: invalid ICV")
+)
+
+// A TCI is the tag control information carried in a SecTAG.
+type TCI uint8
+
+// Possible TCI values.  The SC bit is set automatically when a Packet's SCI
+// is not nil.
+const (
+	TCIEndStation TCI = 0x40
+	TCISCB        TCI = 0x10
+	TCIEncrypted  TCI = 0x08
+	TCIChanged    TCI = 0x04
+)
+
+// An SCI is a secure channel identifier, consisting of the hardware address
+// of a system and a port number.
+type SCI struct {
+	Address net.HardwareAddr
+	Port    uint16
+}
+
+// read marshals an SCI into b.
+func (s *SCI) read(b []byte) {
+	copy(b[0:6], s.Address)
+	binary.BigEndian.PutUint16(b[6:8], s.Port)
+}
+
+// A Packet is a MACsec SecTAG, the secure data which follows it, and the
+// trailing ICV.
+type Packet struct {
+	// TCI specifies the tag control information.
+	TCI TCI
+
+	// AN specifies the 2 bit association number.
+	AN uint8
+
+	// PN specifies the packet number.
+	PN uint32
+
+	// SCI specifies an optional explicit secure channel identifier.
+	SCI *SCI
+
+	// Data specifies the secure data, which is encrypted if TCI contains
+	// TCIEncrypted.
+	Data []byte
+
+	// ICV specifies the integrity check value, which must be ICVLen bytes.
+	ICV []byte
+}
+
+// Parse unmarshals the MACsec packet carried in the payload of Frame f.  If
+// f's EtherType is not EtherType, ethernet.ErrInvalidEtherType is returned.
+func Parse(f *ethernet.Frame) (*Packet, error) {
+	if f.EtherType != EtherType {
+		return nil, ethernet.ErrInvalidEtherType
+	}
+
+	p := new(Packet)
+	if err := p.UnmarshalBinary(f.Payload); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Frame marshals a Packet into the payload of an Ethernet frame sent from
+// hardware address src to hardware address dst.
+func (p *Packet) Frame(dst, src net.HardwareAddr) (*ethernet.Frame, error) {
+	b, err := p.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ethernet.Frame{
+		Destination: dst,
+		Source:      src,
+		EtherType:   EtherType,
+		Payload:     b,
+	}, nil
+}
+
+// secTAG allocates a byte slice and marshals a Packet's SecTAG into it.
+func (p *Packet) secTAG() ([]byte, error) {
+	if p.AN > 0x03 {
+		return nil, ErrInvalidSecTAG
+	}
+
+	n := secTAGLen
+	if p.SCI != nil {
+		n += sciLen
+	}
+
+	b := make([]byte, n)
+	b[0] = uint8(p.TCI&^(tciVersion|tciSC)) | p.AN
+	if len(p.Data) <= maxShortLen {
+		b[1] = uint8(len(p.Data))
+	}
+	binary.BigEndian.PutUint32(b[2:6], p.PN)
+
+	if p.SCI != nil {
+		b[0] |= tciSC
+		p.SCI.read(b[secTAGLen:])
+	}
+
+	return b, nil
+}
+
+// MarshalBinary allocates a byte slice and marshals a Packet into binary
+// form.  The short length field is computed automatically.
+func (p *Packet) MarshalBinary() ([]byte, error) {
+	if len(p.ICV) != ICVLen {
+		return nil, ErrInvalidICV
+	}
+
+	b, err := p.secTAG()
+	if err != nil {
+		return nil, err
+	}
+
+	b = append(b, p.Data...)
+	return append(b, p.ICV...), nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into a Packet.  If the SecTAG's
+// short length is nonzero, any bytes following the ICV, such as Ethernet
+// padding, are ignored.
+func (p *Packet) UnmarshalBinary(b []byte) error {
+	if len(b) < secTAGLen {
+		return io.ErrUnexpectedEOF
+	}
+
+	if b[0]&tciVersion != 0 || b[1] > maxShortLen {
+		return ErrInvalidSecTAG
+	}
+
+	out := Packet{
+		TCI: TCI(b[0] &^ (tciSC | 0x03)),
+		AN:  b[0] & 0x03,
+		PN:  binary.BigEndian.Uint32(b[2:6]),
+	}
+
+	n := secTAGLen
+	if b[0]&tciSC != 0 {
+		if len(b) < secTAGLen+sciLen {
+			return io.ErrUnexpectedEOF
+		}
+
+		addr := make(net.HardwareAddr, 6)
+		copy(addr, b[6:12])
+		out.SCI = &SCI{
+			Address: addr,
+			Port:    binary.BigEndian.Uint16(b[12:14]),
+		}
+
+		n += sciLen
+	}
+
+	dl := len(b) - n - ICVLen
+	if sl := int(b[1]); sl != 0 {
+		dl = sl
+	}
+	if dl < 0 || len(b) < n+dl+ICVLen {
+		return io.ErrUnexpectedEOF
+	}
+
+	out.Data = make([]byte, dl)
+	copy(out.Data, b[n:n+dl])
+	out.ICV = make([]byte, ICVLen)
+	copy(out.ICV, b[n+dl:n+dl+ICVLen])
+
+	*p = out
+	return nil
+}
+
+// Seal protects plaintext using aead, which must be a GCM-AES cipher with a
+// 12 byte nonce and a 16 byte tag, and stores the secure data and ICV in
+// the Packet.  If TCI contains TCIEncrypted, plaintext is encrypted, and
+// otherwise it is only integrity protected.  The destination and source
+// addresses dst and src are included in the ICV.
+//
+// The nonce is formed from the Packet's SCI and PN.  If SCI is nil, the
+// implicit SCI of an end station, formed from src and port 1, is used.
+func (p *Packet) Seal(aead cipher.AEAD, dst, src net.HardwareAddr, plaintext []byte) error {
+	if aead.NonceSize() != nonceLen || aead.Overhead() != ICVLen {
+		return ErrInvalidICV
+	}
+
+	// The secure data length determines the short length in the SecTAG, so
+	// store it before computing the additional data.
+	p.Data = plaintext
+
+	ad, err := p.additionalData(dst, src)
+	if err != nil {
+		return err
+	}
+
+	nonce := p.nonce(src)
+	if p.TCI&TCIEncrypted == 0 {
+		p.Data = append([]byte(nil), plaintext...)
+		p.ICV = aead.Seal(nil, nonce, nil, append(ad, plaintext...))
+		return nil
+	}
+
+	out := aead.Seal(nil, nonce, plaintext, ad)
+	p.Data = out[:len(plaintext):len(plaintext)]
+	p.ICV = out[len(plaintext):]
+	return nil
+}
+
+// Open verifies the ICV of the Packet using aead and returns the decrypted
+// secure data.  dst, src, and aead must match those passed to Seal.  If the
+// ICV fails verification, ErrInvalidICV is returned.
+func (p *Packet) Open(aead cipher.AEAD, dst, src net.HardwareAddr) ([]byte, error) {
+	if aead.NonceSize() != nonceLen || aead.Overhead() != ICVLen || len(p.ICV) != ICVLen {
+		return nil, ErrInvalidICV
+	}
+
+	ad, err := p.additionalData(dst, src)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := p.nonce(src)
+	if p.TCI&TCIEncrypted == 0 {
+		if _, err := aead.Open(nil, nonce, p.ICV, append(ad, p.Data...)); err != nil {
+			return nil, ErrInvalidICV
+		}
+
+		return append([]byte(nil), p.Data...), nil
+	}
+
+	ct := make([]byte, 0, len(p.Data)+ICVLen)
+	ct = append(ct, p.Data...)
+	ct = append(ct, p.ICV...)
+
+	out, err := aead.Open(nil, nonce, ct, ad)
+	if err != nil {
+		return nil, ErrInvalidICV
+	}
+
+	return out, nil
+}
+
+// additionalData returns the data which is protected but not encrypted by
+// the ICV: the destination and source addresses, the EtherType, and the
+// SecTAG.
+func (p *Packet) additionalData(dst, src net.HardwareAddr) ([]byte, error) {
+	tag, err := p.secTAG()
+	if err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, 14, 14+len(tag))
+	copy(b[0:6], dst)
+	copy(b[6:12], src)
+	binary.BigEndian.PutUint16(b[12:14], uint16(EtherType))
+
+	return append(b, tag...), nil
+}
+
+// nonce returns the GCM-AES nonce of the Packet.
+func (p *Packet) nonce(src net.HardwareAddr) []byte {
+	sci := p.SCI
+	if sci == nil {
+		sci = &SCI{Address: src, Port: 1}
+	}
+
+	b := make([]byte, nonceLen)
+	sci.read(b[:sciLen])
+	binary.BigEndian.PutUint32(b[sciLen:], p.PN)
+	return b
+}
@@ -0,0 +1,232 @@
+package macsec
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestPacketMarshalUnmarshal(t *testing.T) {
+	icv := bytes.Repeat([]byte{0xcc}, ICVLen)
+
+	tests := []struct {
+		desc string
+		p    *Packet
+		b    []byte
+	}{
+		{
+			desc: "short, no SCI",
+			p: &Packet{
+				TCI:  TCIEndStation | TCIEncrypted | TCIChanged,
+				AN:   2,
+				PN:   1,
+				Data: []byte{0xaa, 0xbb},
+				ICV:  icv,
+			},
+			b: append([]byte{
+				0x4e, 0x02,
+				0x00, 0x00, 0x00, 0x01,
+				0xaa, 0xbb,
+			}, icv...),
+		},
+		{
+			desc: "long, SCI",
+			p: &Packet{
+				AN: 1,
+				PN: 0x01020304,
+				SCI: &SCI{
+					Address: net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+					Port:    2,
+				},
+				Data: bytes.Repeat([]byte{0xaa}, 48),
+				ICV:  icv,
+			},
+			b: append(append([]byte{
+				0x21, 0x00,
+				0x01, 0x02, 0x03, 0x04,
+				0xde, 0xad, 0xbe, 0xef, 0xde, 0xad,
+				0x00, 0x02,
+			}, bytes.Repeat([]byte{0xaa}, 48)...), icv...),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := tt.p.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			if want, got := tt.b, b; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected bytes:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			p := new(Packet)
+			if err := p.UnmarshalBinary(b); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+
+			if !reflect.DeepEqual(tt.p, p) {
+				t.Fatalf("unexpected packet:\n- want: %#v\n-  got: %#v", tt.p, p)
+			}
+		})
+	}
+}
+
+func TestPacketUnmarshalPadding(t *testing.T) {
+	icv := bytes.Repeat([]byte{0xcc}, ICVLen)
+
+	b := append([]byte{
+		0x00, 0x01,
+		0x00, 0x00, 0x00, 0x01,
+		0xaa,
+	}, icv...)
+	b = append(b, make([]byte, 10)...)
+
+	p := new(Packet)
+	if err := p.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if want, got := []byte{0xaa}, p.Data; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected data: %v != %v", want, got)
+	}
+	if want, got := icv, p.ICV; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected ICV: %v != %v", want, got)
+	}
+}
+
+func TestPacketErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		b    []byte
+		err  error
+	}{
+		{
+			desc: "short",
+			b:    []byte{0x00, 0x00, 0x00, 0x00, 0x00},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "version",
+			b:    []byte{0x80, 0x00, 0x00, 0x00, 0x00, 0x00},
+			err:  ErrInvalidSecTAG,
+		},
+		{
+			desc: "short length",
+			b:    []byte{0x00, 0x30, 0x00, 0x00, 0x00, 0x00},
+			err:  ErrInvalidSecTAG,
+		},
+		{
+			desc: "short SCI",
+			b:    []byte{0x20, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "no ICV",
+			b:    []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xaa},
+			err:  io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := new(Packet).UnmarshalBinary(tt.b); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+
+	if _, err := (&Packet{}).MarshalBinary(); err != ErrInvalidICV {
+		t.Fatalf("expected ErrInvalidICV, but got: %v", err)
+	}
+	if _, err := (&Packet{AN: 4, ICV: make([]byte, ICVLen)}).MarshalBinary(); err != ErrInvalidSecTAG {
+		t.Fatalf("expected ErrInvalidSecTAG, but got: %v", err)
+	}
+}
+
+func TestPacketSealOpen(t *testing.T) {
+	block, err := aes.NewCipher(make([]byte, 16))
+	if err != nil {
+		t.Fatalf("failed to create cipher: %v", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatalf("failed to create GCM: %v", err)
+	}
+
+	dst := net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55}
+	src := net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}
+	plaintext := []byte("hello, MACsec")
+
+	tests := []struct {
+		desc string
+		p    *Packet
+	}{
+		{
+			desc: "integrity only",
+			p:    &Packet{TCI: TCIEndStation, PN: 1},
+		},
+		{
+			desc: "encrypted",
+			p: &Packet{
+				TCI: TCIEncrypted | TCIChanged,
+				PN:  2,
+				SCI: &SCI{Address: src, Port: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := tt.p.Seal(aead, dst, src, plaintext); err != nil {
+				t.Fatalf("failed to seal: %v", err)
+			}
+
+			encrypted := tt.p.TCI&TCIEncrypted != 0
+			if want, got := !encrypted, bytes.Equal(plaintext, tt.p.Data); want != got {
+				t.Fatalf("unexpected plaintext data: %v != %v", want, got)
+			}
+
+			// Round trip the packet through a frame before opening it.
+			f, err := tt.p.Frame(dst, src)
+			if err != nil {
+				t.Fatalf("failed to create frame: %v", err)
+			}
+
+			p, err := Parse(f)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+
+			out, err := p.Open(aead, dst, src)
+			if err != nil {
+				t.Fatalf("failed to open: %v", err)
+			}
+
+			if want, got := plaintext, out; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected plaintext: %q != %q", want, got)
+			}
+
+			p.Data[0]++
+			if _, err := p.Open(aead, dst, src); err != ErrInvalidICV {
+				t.Fatalf("expected ErrInvalidICV for modified data, but got: %v", err)
+			}
+
+			p.Data[0]--
+			if _, err := p.Open(aead, src, dst); err != ErrInvalidICV {
+				t.Fatalf("expected ErrInvalidICV for swapped addresses, but got: %v", err)
+			}
+		})
+	}
+
+	if _, err := Parse(&ethernet.Frame{EtherType: ethernet.EtherTypeIPv4}); err != ethernet.ErrInvalidEtherType {
+		t.Fatalf("expected ErrInvalidEtherType, but got: %v", err)
+	}
+}
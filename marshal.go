@@ -0,0 +1,144 @@
+package ethernet
+
+// A MarshalOption configures the behavior of Frame.Marshal.
+type MarshalOption func(c *marshalConfig)
+
+// marshalConfig is the configuration produced by applying each MarshalOption.
+type marshalConfig struct {
+	fcs     bool
+	pad     bool
+	tpid    EtherType
+	buf     []byte
+	maxLen  int
+	unicast bool
+}
+
+// WithFCS appends a 4-byte IEEE CRC32 frame check sequence to the marshaled
+// Frame, as with MarshalFCS.
+func WithFCS() MarshalOption {
+	return func(c *marshalConfig) {
+		c.fcs = true
+	}
+}
+
+// WithPadding specifies whether the marshaled Frame is zero-padded to the
+// minimum Ethernet frame length.  Frames are padded by default, as with
+// MarshalBinary.  Disabling padding is useful when the operating system or
+// hardware pads frames itself.
+func WithPadding(pad bool) MarshalOption {
+	return func(c *marshalConfig) {
+		c.pad = pad
+	}
+}
+
+// WithTPID specifies the Tag Protocol Identifier of the outermost VLAN tag of
+// the marshaled Frame, overriding the VLAN's own TPID field.  WithTPID has no
+// effect on a Frame without VLAN tags.
+func WithTPID(tpid EtherType) MarshalOption {
+	return func(c *marshalConfig) {
+		c.tpid = tpid
+	}
+}
+
+// WithBuffer specifies a buffer whose capacity is reused to store the
+// marshaled Frame, avoiding an allocation if it is large enough.  The
+// contents of b are overwritten.
+func WithBuffer(b []byte) MarshalOption {
+	return func(c *marshalConfig) {
+		c.buf = b
+	}
+}
+
+// WithMaxLength rejects Frames which are longer than n bytes in binary form
+// with ErrFrameTooLarge.  The length includes any padding, but excludes the
+// frame check sequence.  MaxFrameLength and MaxJumboFrameLength are common
+// values.  If n is zero, the length is not limited.
+func WithMaxLength(n int) MarshalOption {
+	return func(c *marshalConfig) {
+		c.maxLen = n
+	}
+}
+
+// WithUnicastSource rejects Frames whose source hardware address has the
+// group (multicast) bit set, which is not permitted by IEEE 802.3, with
+// ErrMulticastSource.  WithUnicastSource is intended for frame generators
+// and conformance testing.
+func WithUnicastSource() MarshalOption {
+	return func(c *marshalConfig) {
+		c.unicast = true
+	}
+}
+
+// newMarshalConfig applies options to the default marshalConfig.
+func newMarshalConfig(options []MarshalOption) marshalConfig {
+	c := marshalConfig{pad: true}
+	for _, o := range options {
+		o(&c)
+	}
+
+	return c
+}
+
+// Marshal marshals a Frame into binary form using the specified options.
+// With no options, Marshal behaves identically to MarshalBinary.
+func (f *Frame) Marshal(options ...MarshalOption) ([]byte, error) {
+	c := newMarshalConfig(options)
+
+	if c.unicast && IsMulticast(f.Source) {
+		return nil, ErrMulticastSource
+	}
+
+	if c.tpid != 0 {
+		f = f.withOuterTPID(c.tpid)
+	}
+
+	// Only the length which will actually be emitted is limited.
+	n := f.length(c.pad)
+	if c.maxLen > 0 && n > c.maxLen {
+		return nil, ErrFrameTooLarge
+	}
+	if c.fcs {
+		// 4 extra bytes for frame check sequence.
+		n += 4
+	}
+
+	var b []byte
+	if c.buf != nil {
+		b = c.buf[:0]
+	}
+	b = grow(b, n)
+
+	if c.fcs {
+		if err := f.readFCS(b); err != nil {
+			return nil, err
+		}
+
+		return b, nil
+	}
+
+	if _, err := f.read(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// withOuterTPID returns a shallow copy of a Frame whose outermost VLAN tag
+// uses the specified TPID.
+func (f *Frame) withOuterTPID(tpid EtherType) *Frame {
+	g := *f
+
+	outer := &g.VLAN
+	if g.ServiceVLAN != nil {
+		outer = &g.ServiceVLAN
+	}
+	if *outer == nil {
+		return f
+	}
+
+	v := **outer
+	v.TPID = tpid
+	*outer = &v
+
+	return &g
+}
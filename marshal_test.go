@@ -0,0 +1,111 @@
+package ethernet
+
+import (
+	"bytes"
+	"net"
+	"testing"
+)
+
+func TestFrameMarshal(t *testing.T) {
+	f := &Frame{
+		Destination: Broadcast,
+		Source:      net.HardwareAddr{0, 1, 0, 1, 0, 1},
+		VLAN:        &VLAN{ID: 10},
+		EtherType:   EtherTypeIPv4,
+		Payload:     []byte{1, 2, 3},
+	}
+
+	bin, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	fcs, err := f.MarshalFCS()
+	if err != nil {
+		t.Fatalf("failed to marshal with FCS: %v", err)
+	}
+
+	buf := make([]byte, 0, 128)
+
+	tests := []struct {
+		desc string
+		opts []MarshalOption
+		b    []byte
+		err  error
+	}{
+		{
+			desc: "no options",
+			b:    bin,
+		},
+		{
+			desc: "FCS",
+			opts: []MarshalOption{WithFCS()},
+			b:    fcs,
+		},
+		{
+			desc: "no padding",
+			opts: []MarshalOption{WithPadding(false)},
+			b:    bin[:21],
+		},
+		{
+			desc: "TPID",
+			opts: []MarshalOption{WithTPID(EtherTypeServiceVLAN)},
+			b:    append(append(append([]byte(nil), bin[:12]...), 0x88, 0xa8), bin[14:]...),
+		},
+		{
+			desc: "buffer",
+			opts: []MarshalOption{WithBuffer(buf), WithFCS()},
+			b:    fcs,
+		},
+		{
+			desc: "max length",
+			opts: []MarshalOption{WithMaxLength(20)},
+			err:  ErrFrameTooLarge,
+		},
+		{
+			desc: "max length without padding",
+			opts: []MarshalOption{WithPadding(false), WithMaxLength(21)},
+			b:    bin[:21],
+		},
+		{
+			desc: "max length excludes FCS",
+			opts: []MarshalOption{WithFCS(), WithMaxLength(len(bin))},
+			b:    fcs,
+		},
+		{
+			desc: "unicast source",
+			opts: []MarshalOption{WithUnicastSource()},
+			b:    bin,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := f.Marshal(tt.opts...)
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error: %v != %v", want, got)
+			}
+			if err != nil {
+				return
+			}
+
+			if want, got := tt.b, b; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected Frame bytes:\n- want: %v\n-  got: %v", want, got)
+			}
+		})
+	}
+
+	// The Frame's own VLAN is not modified by WithTPID.
+	if want, got := EtherType(0), f.VLAN.TPID; want != got {
+		t.Fatalf("unexpected VLAN TPID: %v != %v", want, got)
+	}
+
+	// WithBuffer reuses the buffer's memory.
+	b, err := f.Marshal(WithBuffer(buf))
+	if err != nil {
+		t.Fatalf("failed to marshal with buffer: %v", err)
+	}
+	if &b[0] != &buf[:1][0] {
+		t.Fatal("expected Marshal to reuse buffer")
+	}
+}
@@ -0,0 +1,275 @@
+// Package metrics exposes counters from packages conn and flow as Prometheus
+// metrics, using the Prometheus text exposition format.
+//
+// Package metrics has no dependencies outside of the standard library, so
+// that programs which do not use it do not pay for a Prometheus client.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mdlayher/ethernet/conn"
+	"github.com/mdlayher/ethernet/flow"
+)
+
+// contentType is the Content-Type of the Prometheus text exposition format.
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// A Collector gathers metrics from registered sources and serves them over
+// HTTP.  A Collector is safe for concurrent use.
+//
+// Every metric produced by a Collector carries an "interface" label,
+// specified when its source is registered.
+type Collector struct {
+	mu      sync.Mutex
+	sources []source
+}
+
+// A source produces samples for a Collector.
+type source func(add func(name string, value float64, labels ...string))
+
+// NewCollector creates an empty Collector.
+func NewCollector() *Collector {
+	return &Collector{}
+}
+
+// AddMirror registers a conn.Mirror's counters for interface ifi.
+func (c *Collector) AddMirror(ifi string, m *conn.Mirror) {
+	c.add(func(add func(string, float64, ...string)) {
+		s := m.Stats()
+		add("ethernet_mirror_frames_total", float64(s.Mirrored), "interface", ifi)
+		add("ethernet_mirror_errors_total", float64(s.Errors), "interface", ifi)
+	})
+}
+
+// AddStormControl registers a conn.StormControl's counters for interface ifi.
+func (c *Collector) AddStormControl(ifi string, s *conn.StormControl) {
+	c.add(func(add func(string, float64, ...string)) {
+		st := s.Stats()
+		for _, v := range []struct {
+			class string
+			n     uint64
+		}{
+			{class: "broadcast", n: st.Broadcast},
+			{class: "multicast", n: st.Multicast},
+			{class: "unknown_unicast", n: st.UnknownUnicast},
+		} {
+			add("ethernet_storm_control_dropped_frames_total", float64(v.n),
+				"interface", ifi, "class", v.class)
+		}
+	})
+}
+
+// AddACL registers a conn.ACL's counters for interface ifi.
+func (c *Collector) AddACL(ifi string, a *conn.ACL) {
+	c.add(func(add func(string, float64, ...string)) {
+		s := a.Stats()
+		for _, v := range []struct {
+			action string
+			n      uint64
+		}{
+			{action: "deny", n: s.Denied},
+			{action: "modify", n: s.Modified},
+			{action: "mirror", n: s.Mirrored},
+		} {
+			add("ethernet_acl_frames_total", float64(v.n),
+				"interface", ifi, "action", v.action)
+		}
+	})
+}
+
+// AddScheduler registers a conn.Scheduler's per-queue counters for interface
+// ifi.
+func (c *Collector) AddScheduler(ifi string, s *conn.Scheduler) {
+	c.add(queues("scheduler", ifi, s.Stats))
+}
+
+// AddTimeAwareShaper registers a conn.TimeAwareShaper's per-queue counters
+// for interface ifi.
+func (c *Collector) AddTimeAwareShaper(ifi string, s *conn.TimeAwareShaper) {
+	c.add(queues("time_aware_shaper", ifi, s.Stats))
+}
+
+// AddPolicer registers a conn.Policer's per-priority counters for interface
+// ifi.
+func (c *Collector) AddPolicer(ifi string, p *conn.Policer) {
+	c.add(func(add func(string, float64, ...string)) {
+		for pri, s := range p.Stats() {
+			pl := strconv.Itoa(int(pri))
+			add("ethernet_policer_conformed_frames_total", float64(s.Conformed),
+				"interface", ifi, "priority", pl)
+			add("ethernet_policer_exceeded_frames_total", float64(s.Exceeded),
+				"interface", ifi, "priority", pl)
+			add("ethernet_policer_dropped_frames_total", float64(s.Dropped),
+				"interface", ifi, "priority", pl)
+		}
+	})
+}
+
+// AddFailover registers the active member index of a conn.Failover for
+// interface ifi.
+func (c *Collector) AddFailover(ifi string, f *conn.Failover) {
+	c.add(func(add func(string, float64, ...string)) {
+		add("ethernet_failover_active_member", float64(f.Active()), "interface", ifi)
+	})
+}
+
+// AddFlowTracker registers a flow.Tracker's cumulative per-VLAN and
+// per-EtherType counters for interface ifi.
+func (c *Collector) AddFlowTracker(ifi string, t *flow.Tracker) {
+	c.add(func(add func(string, float64, ...string)) {
+		for _, tot := range t.Totals() {
+			var (
+				vlan = strconv.Itoa(int(tot.VLAN))
+				et   = fmt.Sprintf("0x%04x", uint16(tot.EtherType))
+			)
+
+			add("ethernet_flow_frames_total", float64(tot.Frames),
+				"interface", ifi, "vlan", vlan, "ethertype", et)
+			add("ethernet_flow_bytes_total", float64(tot.Bytes),
+				"interface", ifi, "vlan", vlan, "ethertype", et)
+		}
+
+		add("ethernet_flow_dropped_frames_total", float64(t.Dropped()), "interface", ifi)
+	})
+}
+
+// ServeHTTP implements http.Handler.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	_ = c.WriteText(w)
+}
+
+// WriteText writes all metrics to w in the Prometheus text exposition format.
+func (c *Collector) WriteText(w io.Writer) error {
+	c.mu.Lock()
+	sources := make([]source, len(c.sources))
+	copy(sources, c.sources)
+	c.mu.Unlock()
+
+	families := make(map[string][]sample)
+	for _, s := range sources {
+		s(func(name string, value float64, labels ...string) {
+			families[name] = append(families[name], sample{
+				labels: formatLabels(labels),
+				value:  value,
+			})
+		})
+	}
+
+	names := make([]string, 0, len(families))
+	for n := range families {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	bw := bufio.NewWriter(w)
+	for _, n := range names {
+		d := descs[n]
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s %s\n", n, d.help, n, d.typ)
+
+		ss := families[n]
+		sort.Slice(ss, func(i, j int) bool {
+			return ss[i].labels < ss[j].labels
+		})
+
+		for _, s := range ss {
+			fmt.Fprintf(bw, "%s{%s} %s\n", n, s.labels,
+				strconv.FormatFloat(s.value, 'g', -1, 64))
+		}
+	}
+
+	return bw.Flush()
+}
+
+// add registers a source with c.
+func (c *Collector) add(s source) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sources = append(c.sources, s)
+}
+
+// queues returns a source which reports per-queue counters produced by
+// stats, under metrics with the specified prefix.
+func queues(prefix, ifi string, stats func() []conn.QueueStats) source {
+	return func(add func(string, float64, ...string)) {
+		for i, s := range stats() {
+			q := strconv.Itoa(i)
+			add("ethernet_"+prefix+"_queue_length", float64(s.Length),
+				"interface", ifi, "queue", q)
+			add("ethernet_"+prefix+"_sent_frames_total", float64(s.Sent),
+				"interface", ifi, "queue", q)
+			add("ethernet_"+prefix+"_dropped_frames_total", float64(s.Dropped),
+				"interface", ifi, "queue", q)
+			add("ethernet_"+prefix+"_errors_total", float64(s.Errors),
+				"interface", ifi, "queue", q)
+		}
+	}
+}
+
+// A sample is a single value of a metric, with its formatted labels.
+type sample struct {
+	labels string
+	value  float64
+}
+
+// formatLabels formats key/value label pairs for the text exposition format.
+func formatLabels(kvs []string) string {
+	var sb strings.Builder
+	for i := 0; i+1 < len(kvs); i += 2 {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+
+		sb.WriteString(kvs[i])
+		sb.WriteString(`="`)
+		sb.WriteString(labelEscaper.Replace(kvs[i+1]))
+		sb.WriteByte('"')
+	}
+
+	return sb.String()
+}
+
+// labelEscaper escapes label values, as required by the text exposition
+// format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// A desc describes a metric.
+type desc struct {
+	help, typ string
+}
+
+// descs describes all metrics produced by a Collector.
+var descs = func() map[string]desc {
+	m := map[string]desc{
+		"ethernet_mirror_frames_total":                {"Number of frames sent to a mirror sink.", "counter"},
+		"ethernet_mirror_errors_total":                {"Number of frames which could not be sent to a mirror sink.", "counter"},
+		"ethernet_storm_control_dropped_frames_total": {"Number of frames dropped by storm control, by traffic class.", "counter"},
+		"ethernet_acl_frames_total":                   {"Number of frames acted upon by an ACL, by action.", "counter"},
+		"ethernet_policer_conformed_frames_total":     {"Number of frames which conformed to a policer, by priority.", "counter"},
+		"ethernet_policer_exceeded_frames_total":      {"Number of frames which exceeded a policer, by priority.", "counter"},
+		"ethernet_policer_dropped_frames_total":       {"Number of frames dropped by a policer, by priority.", "counter"},
+		"ethernet_failover_active_member":             {"Index of the active failover member.", "gauge"},
+		"ethernet_flow_frames_total":                  {"Number of frames observed, by VLAN and EtherType.", "counter"},
+		"ethernet_flow_bytes_total":                   {"Number of bytes observed, by VLAN and EtherType.", "counter"},
+		"ethernet_flow_dropped_frames_total":          {"Number of frames not tracked because the flow limit was reached.", "counter"},
+	}
+
+	for _, p := range []string{"scheduler", "time_aware_shaper"} {
+		p = "ethernet_" + p
+		m[p+"_queue_length"] = desc{"Number of frames currently queued, by queue.", "gauge"}
+		m[p+"_sent_frames_total"] = desc{"Number of frames transmitted, by queue.", "counter"}
+		m[p+"_dropped_frames_total"] = desc{"Number of frames dropped because a queue was full, by queue.", "counter"}
+		m[p+"_errors_total"] = desc{"Number of frames which could not be transmitted, by queue.", "counter"}
+	}
+
+	return m
+}()
@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mdlayher/ethernet"
+	"github.com/mdlayher/ethernet/conn"
+	"github.com/mdlayher/ethernet/flow"
+)
+
+func TestCollectorFlowTracker(t *testing.T) {
+	tr := flow.NewTracker(nil)
+	for _, f := range []*ethernet.Frame{
+		{EtherType: ethernet.EtherTypeIPv4},
+		{EtherType: ethernet.EtherTypeIPv4},
+		{EtherType: ethernet.EtherTypeARP, VLAN: &ethernet.VLAN{ID: 10}},
+	} {
+		tr.Observe(f, 64)
+	}
+
+	c := NewCollector()
+	c.AddFlowTracker("eth0", tr)
+
+	want := strings.Join([]string{
+		"# HELP ethernet_flow_bytes_total Number of bytes observed, by VLAN and EtherType.",
+		"# TYPE ethernet_flow_bytes_total counter",
+		`ethernet_flow_bytes_total{interface="eth0",vlan="0",ethertype="0x0800"} 128`,
+		`ethernet_flow_bytes_total{interface="eth0",vlan="10",ethertype="0x0806"} 64`,
+		"# HELP ethernet_flow_dropped_frames_total Number of frames not tracked because the flow limit was reached.",
+		"# TYPE ethernet_flow_dropped_frames_total counter",
+		`ethernet_flow_dropped_frames_total{interface="eth0"} 0`,
+		"# HELP ethernet_flow_frames_total Number of frames observed, by VLAN and EtherType.",
+		"# TYPE ethernet_flow_frames_total counter",
+		`ethernet_flow_frames_total{interface="eth0",vlan="0",ethertype="0x0800"} 2`,
+		`ethernet_flow_frames_total{interface="eth0",vlan="10",ethertype="0x0806"} 1`,
+		"",
+	}, "\n")
+
+	var sb strings.Builder
+	if err := c.WriteText(&sb); err != nil {
+		t.Fatalf("failed to write metrics: %v", err)
+	}
+
+	if got := sb.String(); want != got {
+		t.Fatalf("unexpected metrics:\n- want:\n%s\n-  got:\n%s", want, got)
+	}
+}
+
+func TestCollectorServeHTTP(t *testing.T) {
+	m := conn.NewMirror(&nopConn{}, nil)
+
+	c := NewCollector()
+	c.AddMirror(`eth"0`, m)
+	c.AddMirror("eth1", m)
+
+	w := httptest.NewRecorder()
+	c.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	if want, got := contentType, w.Header().Get("Content-Type"); want != got {
+		t.Fatalf("unexpected Content-Type: %q != %q", want, got)
+	}
+
+	body := w.Body.String()
+	if want, got := 1, strings.Count(body, "# TYPE ethernet_mirror_frames_total counter"); want != got {
+		t.Fatalf("unexpected number of TYPE lines: %v != %v", want, got)
+	}
+
+	for _, s := range []string{
+		`ethernet_mirror_frames_total{interface="eth\"0"} 0`,
+		`ethernet_mirror_frames_total{interface="eth1"} 0`,
+	} {
+		if !strings.Contains(body, s) {
+			t.Fatalf("expected metrics to contain %q:\n%s", s, body)
+		}
+	}
+}
+
+func TestFormatLabels(t *testing.T) {
+	tests := []struct {
+		desc string
+		kvs  []string
+		s    string
+	}{
+		{
+			desc: "empty",
+		},
+		{
+			desc: "one",
+			kvs:  []string{"a", "b"},
+			s:    `a="b"`,
+		},
+		{
+			desc: "escaped",
+			kvs:  []string{"a", "b\\\"\n", "c", "d"},
+			s:    `a="b\\\"\n",c="d"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if want, got := tt.s, formatLabels(tt.kvs); want != got {
+				t.Fatalf("unexpected labels: %q != %q", want, got)
+			}
+		})
+	}
+}
+
+// nopConn is a net.PacketConn which does nothing.
+type nopConn struct {
+	net.PacketConn
+}
@@ -0,0 +1,134 @@
+// Package mpls implements marshaling and unmarshaling of MPLS label stacks
+// carried in Ethernet frames, for use with L2VPN and segment routing
+// applications.
+package mpls
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// EtherType values which indicate an MPLS label stack in a Frame.
+const (
+	EtherTypeUnicast   = ethernet.EtherTypeMPLSUnicast
+	EtherTypeMulticast = ethernet.EtherTypeMPLSMulticast
+)
+
+const (
+	// entryLen is the length of a label stack entry.
+	entryLen = 4
+
+	// maxLabel is the maximum value of a 20 bit label.
+	maxLabel = 0xfffff
+)
+
+// ErrInvalidLabel is returned when a label stack entry's label does not fit
+// in 20 bits or its traffic class does not fit in 3 bits, or when a label
+// stack is empty.
+var ErrInvalidLabel = errors.New("mpls: invalid label stack entry")
+
+// A Label is an MPLS label stack entry.
+type Label struct {
+	// Label specifies the 20 bit label value.
+	Label uint32
+
+	// TrafficClass specifies the 3 bit traffic class.
+	TrafficClass uint8
+
+	// BottomOfStack indicates that this entry is the last in the label
+	// stack.  BottomOfStack is set by UnmarshalBinary, and is ignored by
+	// MarshalBinary, which sets it only on the final entry.
+	BottomOfStack bool
+
+	// TTL specifies the time to live of the packet.
+	TTL uint8
+}
+
+// A Packet is an MPLS label stack and the payload which follows it.
+type Packet struct {
+	// Labels specifies the label stack, from top to bottom.
+	Labels []Label
+
+	// Payload is the data following the bottom of the label stack.
+	Payload []byte
+}
+
+// Parse unmarshals the MPLS packet carried in the payload of Frame f.  If
+// f's EtherType is not EtherTypeUnicast or EtherTypeMulticast,
+// ethernet.ErrInvalidEtherType is returned.
+func Parse(f *ethernet.Frame) (*Packet, error) {
+	if f.EtherType != EtherTypeUnicast && f.EtherType != EtherTypeMulticast {
+		return nil, ethernet.ErrInvalidEtherType
+	}
+
+	p := new(Packet)
+	if err := p.UnmarshalBinary(f.Payload); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// MarshalBinary allocates a byte slice and marshals a Packet into binary
+// form.
+func (p *Packet) MarshalBinary() ([]byte, error) {
+	if len(p.Labels) == 0 {
+		return nil, ErrInvalidLabel
+	}
+
+	b := make([]byte, entryLen*len(p.Labels)+len(p.Payload))
+	for i, l := range p.Labels {
+		if l.Label > maxLabel || l.TrafficClass > 7 {
+			return nil, ErrInvalidLabel
+		}
+
+		// 20 bits: label
+		//  3 bits: traffic class
+		//  1 bit : bottom of stack
+		//  8 bits: TTL
+		ub := l.Label<<12 | uint32(l.TrafficClass)<<9 | uint32(l.TTL)
+		if i == len(p.Labels)-1 {
+			ub |= 1 << 8
+		}
+
+		binary.BigEndian.PutUint32(b[i*entryLen:], ub)
+	}
+
+	copy(b[entryLen*len(p.Labels):], p.Payload)
+	return b, nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into a Packet.  Label stack
+// entries are parsed until one with the bottom of stack bit set is found.
+func (p *Packet) UnmarshalBinary(b []byte) error {
+	var labels []Label
+	for {
+		if len(b) < entryLen {
+			return io.ErrUnexpectedEOF
+		}
+
+		ub := binary.BigEndian.Uint32(b[:entryLen])
+		b = b[entryLen:]
+
+		l := Label{
+			Label:         ub >> 12,
+			TrafficClass:  uint8(ub>>9) & 0x07,
+			BottomOfStack: ub&(1<<8) != 0,
+			TTL:           uint8(ub),
+		}
+		labels = append(labels, l)
+
+		if l.BottomOfStack {
+			break
+		}
+	}
+
+	p.Labels = labels
+	p.Payload = make([]byte, len(b))
+	copy(p.Payload, b)
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package mpls
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestPacketRoundTrip(t *testing.T) {
+	p := &Packet{
+		Labels: []Label{
+			{Label: 16000, TrafficClass: 5, TTL: 64},
+			{Label: 100, TTL: 255},
+		},
+		Payload: []byte{0x45, 0x00},
+	}
+
+	b, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	want := []byte{
+		0x03, 0xe8, 0x0a, 0x40,
+		0x00, 0x06, 0x41, 0xff,
+		0x45, 0x00,
+	}
+	if got := b; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected Packet bytes:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	f := &ethernet.Frame{
+		EtherType: EtherTypeUnicast,
+		Payload:   b,
+	}
+
+	got, err := Parse(f)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	// Bottom of stack is set on the final label by MarshalBinary.
+	p.Labels[1].BottomOfStack = true
+	if !reflect.DeepEqual(p, got) {
+		t.Fatalf("unexpected Packet:\n- want: %v\n-  got: %v", p, got)
+	}
+}
+
+func TestPacketErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		fn   func() error
+		err  error
+	}{
+		{
+			desc: "no labels",
+			fn: func() error {
+				_, err := (&Packet{}).MarshalBinary()
+				return err
+			},
+			err: ErrInvalidLabel,
+		},
+		{
+			desc: "label too large",
+			fn: func() error {
+				_, err := (&Packet{Labels: []Label{{Label: maxLabel + 1}}}).MarshalBinary()
+				return err
+			},
+			err: ErrInvalidLabel,
+		},
+		{
+			desc: "no bottom of stack",
+			fn: func() error {
+				return new(Packet).UnmarshalBinary([]byte{0x00, 0x06, 0x40, 0xff, 0x00})
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "not MPLS",
+			fn: func() error {
+				_, err := Parse(&ethernet.Frame{EtherType: ethernet.EtherTypeIPv4})
+				return err
+			},
+			err: ethernet.ErrInvalidEtherType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if want, got := tt.err, tt.fn(); want != got {
+				t.Fatalf("unexpected error: %v != %v", want, got)
+			}
+		})
+	}
+}
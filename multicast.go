@@ -0,0 +1,72 @@
+package ethernet
+
+import (
+	"errors"
+	"net"
+)
+
+// ErrNotMulticast is returned when a multicast IP address is required, but
+// a unicast IP address is provided.
+var ErrNotMulticast = errors.New("not a multicast IP address")
+
+// MulticastHardwareAddr returns the multicast hardware address to which
+// frames carrying packets for multicast IP address ip are sent.
+//
+// IPv4 addresses map to 01:00:5e:xx:xx:xx using the low 23 bits of the
+// address, as described in RFC 1112.  IPv6 addresses map to
+// 33:33:xx:xx:xx:xx using the low 32 bits of the address, as described in
+// RFC 2464.
+func MulticastHardwareAddr(ip net.IP) (net.HardwareAddr, error) {
+	if !ip.IsMulticast() {
+		return nil, ErrNotMulticast
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		return net.HardwareAddr{0x01, 0x00, 0x5e, ip4[1] & 0x7f, ip4[2], ip4[3]}, nil
+	}
+
+	return net.HardwareAddr{0x33, 0x33, ip[12], ip[13], ip[14], ip[15]}, nil
+}
+
+// NewIPv4MulticastFrame creates a Frame from hardware address src which
+// carries IPv4 packet payload to multicast IPv4 address ip.  The
+// destination of the Frame is derived from ip by MulticastHardwareAddr.
+func NewIPv4MulticastFrame(src net.HardwareAddr, ip net.IP, payload []byte) (*Frame, error) {
+	if ip.To4() == nil {
+		return nil, ErrInvalidIP
+	}
+
+	return newMulticastFrame(src, ip, EtherTypeIPv4, payload)
+}
+
+// NewIPv6MulticastFrame creates a Frame from hardware address src which
+// carries IPv6 packet payload, such as an NDP message, to multicast IPv6
+// address ip.  The destination of the Frame is derived from ip by
+// MulticastHardwareAddr.
+func NewIPv6MulticastFrame(src net.HardwareAddr, ip net.IP, payload []byte) (*Frame, error) {
+	if len(ip) != net.IPv6len || ip.To4() != nil {
+		return nil, ErrInvalidIP
+	}
+
+	return newMulticastFrame(src, ip, EtherTypeIPv6, payload)
+}
+
+// newMulticastFrame creates a Frame from src to the multicast hardware
+// address of ip.
+func newMulticastFrame(src net.HardwareAddr, ip net.IP, et EtherType, payload []byte) (*Frame, error) {
+	if len(src) != 6 {
+		return nil, ErrInvalidHardwareAddr
+	}
+
+	dst, err := MulticastHardwareAddr(ip)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Frame{
+		Destination: dst,
+		Source:      src,
+		EtherType:   et,
+		Payload:     payload,
+	}, nil
+}
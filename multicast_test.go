@@ -0,0 +1,136 @@
+package ethernet
+
+import (
+	"bytes"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestMulticastHardwareAddr(t *testing.T) {
+	tests := []struct {
+		desc string
+		ip   net.IP
+		addr net.HardwareAddr
+		err  error
+	}{
+		{
+			desc: "IPv4 unicast",
+			ip:   net.IPv4(192, 0, 2, 1),
+			err:  ErrNotMulticast,
+		},
+		{
+			desc: "IPv6 unicast",
+			ip:   net.ParseIP("2001:db8::1"),
+			err:  ErrNotMulticast,
+		},
+		{
+			desc: "IPv4 all systems",
+			ip:   net.IPv4(224, 0, 0, 1),
+			addr: net.HardwareAddr{0x01, 0x00, 0x5e, 0x00, 0x00, 0x01},
+		},
+		{
+			desc: "IPv4 high bit discarded",
+			ip:   net.IPv4(239, 255, 1, 2).To4(),
+			addr: net.HardwareAddr{0x01, 0x00, 0x5e, 0x7f, 0x01, 0x02},
+		},
+		{
+			desc: "IPv6 solicited-node",
+			ip:   net.ParseIP("ff02::1:ffab:cdef"),
+			addr: net.HardwareAddr{0x33, 0x33, 0xff, 0xab, 0xcd, 0xef},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			addr, err := MulticastHardwareAddr(tt.ip)
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error: %v != %v", want, got)
+			}
+
+			if want, got := tt.addr, addr; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected hardware address: %v != %v", want, got)
+			}
+		})
+	}
+}
+
+func TestNewMulticastFrame(t *testing.T) {
+	src := net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}
+	payload := []byte{0xaa}
+
+	tests := []struct {
+		desc string
+		fn   func(src net.HardwareAddr, ip net.IP, payload []byte) (*Frame, error)
+		src  net.HardwareAddr
+		ip   net.IP
+		f    *Frame
+		err  error
+	}{
+		{
+			desc: "IPv4 bad source",
+			fn:   NewIPv4MulticastFrame,
+			src:  net.HardwareAddr{0xde},
+			ip:   net.IPv4(224, 0, 0, 251),
+			err:  ErrInvalidHardwareAddr,
+		},
+		{
+			desc: "IPv4 with IPv6 address",
+			fn:   NewIPv4MulticastFrame,
+			src:  src,
+			ip:   net.ParseIP("ff02::1"),
+			err:  ErrInvalidIP,
+		},
+		{
+			desc: "IPv6 with IPv4 address",
+			fn:   NewIPv6MulticastFrame,
+			src:  src,
+			ip:   net.IPv4(224, 0, 0, 251),
+			err:  ErrInvalidIP,
+		},
+		{
+			desc: "IPv6 unicast",
+			fn:   NewIPv6MulticastFrame,
+			src:  src,
+			ip:   net.ParseIP("fe80::1"),
+			err:  ErrNotMulticast,
+		},
+		{
+			desc: "IPv4 OK",
+			fn:   NewIPv4MulticastFrame,
+			src:  src,
+			ip:   net.IPv4(224, 0, 0, 251),
+			f: &Frame{
+				Destination: net.HardwareAddr{0x01, 0x00, 0x5e, 0x00, 0x00, 0xfb},
+				Source:      src,
+				EtherType:   EtherTypeIPv4,
+				Payload:     payload,
+			},
+		},
+		{
+			desc: "IPv6 OK",
+			fn:   NewIPv6MulticastFrame,
+			src:  src,
+			ip:   net.ParseIP("ff02::1"),
+			f: &Frame{
+				Destination: net.HardwareAddr{0x33, 0x33, 0x00, 0x00, 0x00, 0x01},
+				Source:      src,
+				EtherType:   EtherTypeIPv6,
+				Payload:     payload,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			f, err := tt.fn(tt.src, tt.ip, payload)
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error: %v != %v", want, got)
+			}
+
+			if !reflect.DeepEqual(tt.f, f) {
+				t.Fatalf("unexpected frame:\n- want: %#v\n-  got: %#v", tt.f, f)
+			}
+		})
+	}
+}
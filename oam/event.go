@@ -0,0 +1,181 @@
+package oam
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// An EventType is the type of a link event TLV.
+type EventType uint8
+
+// Possible EventType values.
+const (
+	EventErroredSymbolPeriod        EventType = 0x01
+	EventErroredFrame               EventType = 0x02
+	EventErroredFramePeriod         EventType = 0x03
+	EventErroredFrameSecondsSummary EventType = 0x04
+)
+
+// String returns the name of an EventType.
+func (t EventType) String() string {
+	switch t {
+	case EventErroredSymbolPeriod:
+		return "ErroredSymbolPeriod"
+	case EventErroredFrame:
+		return "ErroredFrame"
+	case EventErroredFramePeriod:
+		return "ErroredFramePeriod"
+	case EventErroredFrameSecondsSummary:
+		return "ErroredFrameSecondsSummary"
+	default:
+		return fmt.Sprintf("EventType(%d)", uint8(t))
+	}
+}
+
+// widths returns the widths in bytes of the window, threshold, errors, and
+// error running total fields of an EventType.
+func (t EventType) widths() ([4]int, bool) {
+	switch t {
+	case EventErroredSymbolPeriod:
+		return [4]int{8, 8, 8, 8}, true
+	case EventErroredFrame:
+		return [4]int{2, 4, 4, 8}, true
+	case EventErroredFramePeriod:
+		return [4]int{4, 4, 4, 8}, true
+	case EventErroredFrameSecondsSummary:
+		return [4]int{2, 2, 2, 4}, true
+	default:
+		return [4]int{}, false
+	}
+}
+
+// An EventNotification contains the sequence number and link event TLVs of
+// an Event Notification OAMPDU.
+type EventNotification struct {
+	// Sequence specifies the event notification sequence number.
+	Sequence uint16
+
+	// Events specifies the link events.
+	Events []Event
+}
+
+// An Event is a link event TLV.  The units of Window, Threshold, and Errors
+// depend on Type, and values which do not fit in the fields of a Type are
+// truncated.
+type Event struct {
+	// Type specifies the type of the event.
+	Type EventType
+
+	// Timestamp specifies the time of the event since the OAM entity was
+	// reset, which is transmitted in units of 100 milliseconds.
+	Timestamp time.Duration
+
+	// Window, Threshold, and Errors specify the duration of the period in
+	// which errors are counted, the number of errors which trigger an
+	// event, and the number of errors in the period.
+	Window    uint64
+	Threshold uint64
+	Errors    uint64
+
+	// ErrorRunningTotal and EventRunningTotal specify the number of errors
+	// and events since the OAM entity was reset.
+	ErrorRunningTotal uint64
+	EventRunningTotal uint32
+}
+
+// append appends the binary form of an EventNotification, terminated by an
+// End TLV, to b.
+func (e *EventNotification) append(b []byte) ([]byte, error) {
+	var seq [2]byte
+	binary.BigEndian.PutUint16(seq[:], e.Sequence)
+	b = append(b, seq[:]...)
+
+	for _, ev := range e.Events {
+		ws, ok := ev.Type.widths()
+		if !ok {
+			return nil, ErrInvalidTLV
+		}
+
+		// Type, length, timestamp, variable width fields, and event running
+		// total.
+		n := tlvHeaderLen + 2 + ws[0] + ws[1] + ws[2] + ws[3] + 4
+		v := make([]byte, n)
+		v[0] = uint8(ev.Type)
+		v[1] = uint8(n)
+		binary.BigEndian.PutUint16(v[2:4], uint16(ev.Timestamp/timestampUnit))
+
+		i := 4
+		for j, x := range []uint64{ev.Window, ev.Threshold, ev.Errors, ev.ErrorRunningTotal} {
+			putUint(v[i:i+ws[j]], x)
+			i += ws[j]
+		}
+		binary.BigEndian.PutUint32(v[i:i+4], ev.EventRunningTotal)
+
+		b = append(b, v...)
+	}
+
+	return append(b, 0x00, 0x00), nil
+}
+
+// unmarshal unmarshals an EventNotification from b.  Organization specific
+// event TLVs are ignored.
+func (e *EventNotification) unmarshal(b []byte) error {
+	if len(b) < 2 {
+		return io.ErrUnexpectedEOF
+	}
+
+	e.Sequence = binary.BigEndian.Uint16(b[0:2])
+	b = b[2:]
+
+	for {
+		t, v, rest, err := nextTLV(b)
+		if err != nil {
+			return err
+		}
+		if t == 0 {
+			return nil
+		}
+		b = rest
+
+		if t == tlvOrgSpecific {
+			continue
+		}
+
+		ev := Event{Type: EventType(t)}
+		ws, ok := ev.Type.widths()
+		if !ok || len(v) != 2+ws[0]+ws[1]+ws[2]+ws[3]+4 {
+			return ErrInvalidTLV
+		}
+
+		ev.Timestamp = time.Duration(binary.BigEndian.Uint16(v[0:2])) * timestampUnit
+
+		i := 2
+		for j, x := range []*uint64{&ev.Window, &ev.Threshold, &ev.Errors, &ev.ErrorRunningTotal} {
+			*x = getUint(v[i : i+ws[j]])
+			i += ws[j]
+		}
+		ev.EventRunningTotal = binary.BigEndian.Uint32(v[i : i+4])
+
+		e.Events = append(e.Events, ev)
+	}
+}
+
+// putUint stores the low bytes of v in b in big endian byte order.
+func putUint(b []byte, v uint64) {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i] = uint8(v)
+		v >>= 8
+	}
+}
+
+// getUint parses a big endian unsigned integer of up to 8 bytes from b.
+func getUint(b []byte) uint64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+
+	return v
+}
@@ -0,0 +1,134 @@
+package oam
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEventNotificationMarshalUnmarshal(t *testing.T) {
+	p := &PDU{
+		Flags: FlagCriticalEvent,
+		Code:  CodeEventNotification,
+		Event: &EventNotification{
+			Sequence: 0x0102,
+			Events: []Event{
+				{
+					Type:              EventErroredFrame,
+					Timestamp:         1 * time.Second,
+					Window:            10,
+					Threshold:         1,
+					Errors:            2,
+					ErrorRunningTotal: 3,
+					EventRunningTotal: 4,
+				},
+				{
+					Type:              EventErroredSymbolPeriod,
+					Window:            0x0102030405060708,
+					ErrorRunningTotal: 1,
+				},
+				{
+					Type:              EventErroredFrameSecondsSummary,
+					Timestamp:         300 * time.Millisecond,
+					Window:            600,
+					Errors:            5,
+					EventRunningTotal: 1,
+				},
+			},
+		},
+	}
+
+	b, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	want := []byte{
+		0x03, 0x00, 0x04, 0x01,
+		0x01, 0x02,
+		// Errored Frame.
+		0x02, 0x1a,
+		0x00, 0x0a,
+		0x00, 0x0a,
+		0x00, 0x00, 0x00, 0x01,
+		0x00, 0x00, 0x00, 0x02,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03,
+		0x00, 0x00, 0x00, 0x04,
+		// Errored Symbol Period.
+		0x01, 0x28,
+		0x00, 0x00,
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01,
+		0x00, 0x00, 0x00, 0x00,
+		// Errored Frame Seconds Summary.
+		0x04, 0x12,
+		0x00, 0x03,
+		0x02, 0x58,
+		0x00, 0x00,
+		0x00, 0x05,
+		0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x01,
+		// End.
+		0x00, 0x00,
+	}
+
+	if !bytes.Equal(want, b) {
+		t.Fatalf("unexpected bytes:\n- want: %v\n-  got: %v", want, b)
+	}
+
+	got := new(PDU)
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if !reflect.DeepEqual(p, got) {
+		t.Fatalf("unexpected PDU:\n- want: %#v\n-  got: %#v", p, got)
+	}
+}
+
+func TestEventNotificationErrors(t *testing.T) {
+	p := &PDU{
+		Code: CodeEventNotification,
+		Event: &EventNotification{
+			Events: []Event{{Type: 0x05}},
+		},
+	}
+
+	if _, err := p.MarshalBinary(); err != ErrInvalidTLV {
+		t.Fatalf("expected ErrInvalidTLV, but got: %v", err)
+	}
+
+	tests := []struct {
+		desc string
+		b    []byte
+	}{
+		{
+			desc: "unknown type",
+			b:    []byte{0x03, 0x00, 0x00, 0x01, 0x00, 0x00, 0x05, 0x02},
+		},
+		{
+			desc: "bad length",
+			b:    []byte{0x03, 0x00, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := new(PDU).UnmarshalBinary(tt.b); err != ErrInvalidTLV {
+				t.Fatalf("expected ErrInvalidTLV, but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestEventTypeString(t *testing.T) {
+	if want, got := "ErroredFramePeriod", EventErroredFramePeriod.String(); want != got {
+		t.Fatalf("unexpected string: %q != %q", want, got)
+	}
+	if want, got := "EventType(9)", EventType(9).String(); want != got {
+		t.Fatalf("unexpected string: %q != %q", want, got)
+	}
+}
@@ -0,0 +1,111 @@
+package oam
+
+import (
+	"encoding/binary"
+)
+
+// Information TLV types.
+const (
+	tlvLocalInfo  = 0x01
+	tlvRemoteInfo = 0x02
+)
+
+// Information contains the TLVs of an Information OAMPDU.
+type Information struct {
+	// Local and Remote specify the optional local and remote information
+	// TLVs.
+	Local  *Info
+	Remote *Info
+}
+
+// An Info is the local or remote information of an OAM entity.
+type Info struct {
+	// Version specifies the OAM version, which is 0x01.
+	Version uint8
+
+	// Revision specifies the revision of the TLV.
+	Revision uint16
+
+	// State specifies the parser and multiplexer state.
+	State uint8
+
+	// Config specifies the OAM configuration, such as mode and supported
+	// functions.
+	Config uint8
+
+	// MaxPDUSize specifies the 11 bit maximum OAMPDU size.
+	MaxPDUSize uint16
+
+	// OUI and VendorInfo identify the vendor of the OAM entity.
+	OUI        [3]byte
+	VendorInfo uint32
+}
+
+// append appends the binary form of an Information, terminated by an End
+// TLV, to b.
+func (i *Information) append(b []byte) []byte {
+	for _, tlv := range []struct {
+		t    uint8
+		info *Info
+	}{
+		{t: tlvLocalInfo, info: i.Local},
+		{t: tlvRemoteInfo, info: i.Remote},
+	} {
+		if tlv.info == nil {
+			continue
+		}
+
+		var v [infoLen]byte
+		v[0] = tlv.t
+		v[1] = infoLen
+		v[2] = tlv.info.Version
+		binary.BigEndian.PutUint16(v[3:5], tlv.info.Revision)
+		v[5] = tlv.info.State
+		v[6] = tlv.info.Config
+		binary.BigEndian.PutUint16(v[7:9], tlv.info.MaxPDUSize&0x07ff)
+		copy(v[9:12], tlv.info.OUI[:])
+		binary.BigEndian.PutUint32(v[12:16], tlv.info.VendorInfo)
+
+		b = append(b, v[:]...)
+	}
+
+	return append(b, 0x00, 0x00)
+}
+
+// unmarshal unmarshals Information TLVs from b.  Unknown TLVs, such as
+// organization specific TLVs, are ignored.
+func (i *Information) unmarshal(b []byte) error {
+	for {
+		t, v, rest, err := nextTLV(b)
+		if err != nil {
+			return err
+		}
+		if t == 0 {
+			return nil
+		}
+		b = rest
+
+		if t != tlvLocalInfo && t != tlvRemoteInfo {
+			continue
+		}
+		if len(v) != infoLen-tlvHeaderLen {
+			return ErrInvalidTLV
+		}
+
+		info := &Info{
+			Version:    v[0],
+			Revision:   binary.BigEndian.Uint16(v[1:3]),
+			State:      v[3],
+			Config:     v[4],
+			MaxPDUSize: binary.BigEndian.Uint16(v[5:7]) & 0x07ff,
+			VendorInfo: binary.BigEndian.Uint32(v[10:14]),
+		}
+		copy(info.OUI[:], v[7:10])
+
+		if t == tlvLocalInfo {
+			i.Local = info
+		} else {
+			i.Remote = info
+		}
+	}
+}
@@ -0,0 +1,259 @@
+// Package oam implements marshaling and unmarshaling of IEEE 802.3ah
+// link-layer Operations, Administration, and Maintenance PDUs carried over
+// the slow protocols EtherType.
+package oam
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// EtherType is the EtherType which indicates a slow protocols PDU in a Frame.
+const EtherType = ethernet.EtherTypeSlowProtocols
+
+// Destination is the slow protocols multicast hardware address to which
+// OAMPDUs are sent.
+var Destination = net.HardwareAddr{0x01, 0x80, 0xc2, 0x00, 0x00, 0x02}
+
+const (
+	// subtypeOAM is the slow protocols subtype of an OAMPDU.
+	subtypeOAM = 0x03
+
+	// headerLen is the length of an OAMPDU's subtype, flags, and code.
+	headerLen = 4
+
+	// tlvHeaderLen is the length of a TLV's type and length fields, which
+	// are included in the TLV's length.
+	tlvHeaderLen = 2
+
+	// tlvOrgSpecific is the type of an organization specific information or
+	// event TLV.
+	tlvOrgSpecific = 0xfe
+
+	// infoLen is the length of a local or remote information TLV.
+	infoLen = 16
+
+	// timestampUnit is the unit of event timestamps.
+	timestampUnit = 100 * time.Millisecond
+)
+
+var (
+	// ErrInvalidSubtype is returned when a slow protocols PDU is not an
+	// OAMPDU.
+	ErrInvalidSubtype = errors.New("oam: invalid slow protocols subtype")
+
+	// ErrInvalidTLV is returned when an information or event TLV is
+	// malformed, or when an event TLV's type is not known.
+	ErrInvalidTLV = errors.New("oam: invalid TLV")
+)
+
+// A Code is the type of an OAMPDU.
+type Code uint8
+
+// Possible Code values.
+const (
+	CodeInformation       Code = 0x00
+	CodeEventNotification Code = 0x01
+	CodeVariableRequest   Code = 0x02
+	CodeVariableResponse  Code = 0x03
+	CodeLoopbackControl   Code = 0x04
+	CodeOrgSpecific       Code = 0xfe
+)
+
+// String returns the name of a Code.
+func (c Code) String() string {
+	switch c {
+	case CodeInformation:
+		return "Information"
+	case CodeEventNotification:
+		return "EventNotification"
+	case CodeVariableRequest:
+		return "VariableRequest"
+	case CodeVariableResponse:
+		return "VariableResponse"
+	case CodeLoopbackControl:
+		return "LoopbackControl"
+	case CodeOrgSpecific:
+		return "OrgSpecific"
+	default:
+		return fmt.Sprintf("Code(%d)", uint8(c))
+	}
+}
+
+// Flags are flags carried in an OAMPDU.
+type Flags uint16
+
+// Possible Flags values.
+const (
+	FlagLinkFault        Flags = 0x0001
+	FlagDyingGasp        Flags = 0x0002
+	FlagCriticalEvent    Flags = 0x0004
+	FlagLocalEvaluating  Flags = 0x0008
+	FlagLocalStable      Flags = 0x0010
+	FlagRemoteEvaluating Flags = 0x0020
+	FlagRemoteStable     Flags = 0x0040
+)
+
+// A LoopbackCommand is the command carried in a Loopback Control OAMPDU.
+type LoopbackCommand uint8
+
+// Possible LoopbackCommand values.
+const (
+	LoopbackEnable  LoopbackCommand = 0x01
+	LoopbackDisable LoopbackCommand = 0x02
+)
+
+// A PDU is an OAMPDU.  The field which carries the PDU's data depends on its
+// Code.
+type PDU struct {
+	// Flags specifies the OAMPDU flags.
+	Flags Flags
+
+	// Code specifies the type of the OAMPDU.
+	Code Code
+
+	// Information specifies the TLVs of an Information OAMPDU.
+	Information *Information
+
+	// Event specifies the contents of an Event Notification OAMPDU.
+	Event *EventNotification
+
+	// Loopback specifies the command of a Loopback Control OAMPDU.
+	Loopback LoopbackCommand
+
+	// Data specifies the raw data of OAMPDUs with other codes.  When
+	// unmarshaling, Data includes any Ethernet padding.
+	Data []byte
+}
+
+// Parse unmarshals the OAMPDU carried in the payload of Frame f.  If f's
+// EtherType is not EtherType, ethernet.ErrInvalidEtherType is returned.
+func Parse(f *ethernet.Frame) (*PDU, error) {
+	if f.EtherType != EtherType {
+		return nil, ethernet.ErrInvalidEtherType
+	}
+
+	p := new(PDU)
+	if err := p.UnmarshalBinary(f.Payload); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Frame marshals a PDU into the payload of an Ethernet frame sent from
+// hardware address src to Destination.
+func (p *PDU) Frame(src net.HardwareAddr) (*ethernet.Frame, error) {
+	b, err := p.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ethernet.Frame{
+		Destination: append(net.HardwareAddr(nil), Destination...),
+		Source:      src,
+		EtherType:   EtherType,
+		Payload:     b,
+	}, nil
+}
+
+// MarshalBinary allocates a byte slice and marshals a PDU into binary form.
+// A nil Information or Event is marshaled as one with no TLVs.
+func (p *PDU) MarshalBinary() ([]byte, error) {
+	b := make([]byte, headerLen)
+	b[0] = subtypeOAM
+	binary.BigEndian.PutUint16(b[1:3], uint16(p.Flags))
+	b[3] = uint8(p.Code)
+
+	switch p.Code {
+	case CodeInformation:
+		info := p.Information
+		if info == nil {
+			info = new(Information)
+		}
+
+		return info.append(b), nil
+	case CodeEventNotification:
+		ev := p.Event
+		if ev == nil {
+			ev = new(EventNotification)
+		}
+
+		return ev.append(b)
+	case CodeLoopbackControl:
+		return append(b, uint8(p.Loopback)), nil
+	default:
+		return append(b, p.Data...), nil
+	}
+}
+
+// UnmarshalBinary unmarshals a byte slice into a PDU.
+func (p *PDU) UnmarshalBinary(b []byte) error {
+	if len(b) < 1 {
+		return io.ErrUnexpectedEOF
+	}
+	if b[0] != subtypeOAM {
+		return ErrInvalidSubtype
+	}
+	if len(b) < headerLen {
+		return io.ErrUnexpectedEOF
+	}
+
+	out := PDU{
+		Flags: Flags(binary.BigEndian.Uint16(b[1:3])),
+		Code:  Code(b[3]),
+	}
+
+	data := b[headerLen:]
+	switch out.Code {
+	case CodeInformation:
+		out.Information = new(Information)
+		if err := out.Information.unmarshal(data); err != nil {
+			return err
+		}
+	case CodeEventNotification:
+		out.Event = new(EventNotification)
+		if err := out.Event.unmarshal(data); err != nil {
+			return err
+		}
+	case CodeLoopbackControl:
+		if len(data) < 1 {
+			return io.ErrUnexpectedEOF
+		}
+
+		out.Loopback = LoopbackCommand(data[0])
+	default:
+		out.Data = make([]byte, len(data))
+		copy(out.Data, data)
+	}
+
+	*p = out
+	return nil
+}
+
+// nextTLV returns the type and value of the TLV at the start of b, and the
+// bytes which follow it.  A zero type indicates an End TLV, or padding.
+func nextTLV(b []byte) (uint8, []byte, []byte, error) {
+	if len(b) < 1 || b[0] == 0 {
+		return 0, nil, nil, nil
+	}
+	if len(b) < tlvHeaderLen {
+		return 0, nil, nil, io.ErrUnexpectedEOF
+	}
+
+	l := int(b[1])
+	if l < tlvHeaderLen {
+		return 0, nil, nil, ErrInvalidTLV
+	}
+	if len(b) < l {
+		return 0, nil, nil, io.ErrUnexpectedEOF
+	}
+
+	return b[0], b[tlvHeaderLen:l], b[l:], nil
+}
@@ -0,0 +1,200 @@
+package oam
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestPDUMarshalUnmarshal(t *testing.T) {
+	tests := []struct {
+		desc string
+		p    *PDU
+		b    []byte
+	}{
+		{
+			desc: "Information",
+			p: &PDU{
+				Flags: FlagLocalStable | FlagRemoteStable,
+				Code:  CodeInformation,
+				Information: &Information{
+					Local: &Info{
+						Version:    1,
+						Revision:   2,
+						Config:     0x1d,
+						MaxPDUSize: 1518,
+						OUI:        [3]byte{0x00, 0x11, 0x22},
+						VendorInfo: 0x01020304,
+					},
+					Remote: &Info{
+						Version:    1,
+						MaxPDUSize: 64,
+					},
+				},
+			},
+			b: []byte{
+				0x03, 0x00, 0x50, 0x00,
+				// Local.
+				0x01, 0x10, 0x01, 0x00,
+				0x02, 0x00, 0x1d, 0x05,
+				0xee, 0x00, 0x11, 0x22,
+				0x01, 0x02, 0x03, 0x04,
+				// Remote.
+				0x02, 0x10, 0x01, 0x00,
+				0x00, 0x00, 0x00, 0x00,
+				0x40, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00,
+				// End.
+				0x00, 0x00,
+			},
+		},
+		{
+			desc: "Information, no TLVs",
+			p: &PDU{
+				Code:        CodeInformation,
+				Information: &Information{},
+			},
+			b: []byte{0x03, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+		{
+			desc: "Loopback Control",
+			p: &PDU{
+				Flags:    FlagLocalStable,
+				Code:     CodeLoopbackControl,
+				Loopback: LoopbackEnable,
+			},
+			b: []byte{0x03, 0x00, 0x10, 0x04, 0x01},
+		},
+		{
+			desc: "Variable Request",
+			p: &PDU{
+				Code: CodeVariableRequest,
+				Data: []byte{0x07, 0x00, 0x02},
+			},
+			b: []byte{0x03, 0x00, 0x00, 0x02, 0x07, 0x00, 0x02},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := tt.p.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			if want, got := tt.b, b; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected bytes:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			p := new(PDU)
+			if err := p.UnmarshalBinary(b); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+
+			if !reflect.DeepEqual(tt.p, p) {
+				t.Fatalf("unexpected PDU:\n- want: %#v\n-  got: %#v", tt.p, p)
+			}
+		})
+	}
+}
+
+func TestPDUUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		b    []byte
+		err  error
+	}{
+		{
+			desc: "empty",
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "LACPDU",
+			b:    []byte{0x01, 0x01},
+			err:  ErrInvalidSubtype,
+		},
+		{
+			desc: "short header",
+			b:    []byte{0x03, 0x00, 0x00},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "short loopback",
+			b:    []byte{0x03, 0x00, 0x00, 0x04},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "short TLV header",
+			b:    []byte{0x03, 0x00, 0x00, 0x00, 0x01},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "TLV length too small",
+			b:    []byte{0x03, 0x00, 0x00, 0x00, 0x01, 0x01},
+			err:  ErrInvalidTLV,
+		},
+		{
+			desc: "truncated TLV",
+			b:    []byte{0x03, 0x00, 0x00, 0x00, 0x01, 0x10, 0x01},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "bad local info length",
+			b:    []byte{0x03, 0x00, 0x00, 0x00, 0x01, 0x03, 0x01},
+			err:  ErrInvalidTLV,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := new(PDU).UnmarshalBinary(tt.b); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestFrameParse(t *testing.T) {
+	p := &PDU{
+		Code: CodeInformation,
+		Information: &Information{
+			Local: &Info{Version: 1, MaxPDUSize: 1518},
+		},
+	}
+
+	f, err := p.Frame(net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad})
+	if err != nil {
+		t.Fatalf("failed to create frame: %v", err)
+	}
+
+	// Round trip through binary form to add Ethernet padding, and skip an
+	// organization specific TLV.
+	f.Payload = append(f.Payload[:len(f.Payload)-2], 0xfe, 0x05, 0x00, 0x11, 0x22)
+
+	b, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal frame: %v", err)
+	}
+
+	f = new(ethernet.Frame)
+	if err := f.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal frame: %v", err)
+	}
+
+	got, err := Parse(f)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if !reflect.DeepEqual(p, got) {
+		t.Fatalf("unexpected PDU:\n- want: %#v\n-  got: %#v", p, got)
+	}
+
+	if _, err := Parse(&ethernet.Frame{EtherType: ethernet.EtherTypeIPv4}); err != ethernet.ErrInvalidEtherType {
+		t.Fatalf("expected ErrInvalidEtherType, but got: %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package ethernet
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+)
+
+// EtherTypePBB is the EtherType of an IEEE 802.1ah Provider Backbone Bridging
+// backbone service instance tag (I-TAG).
+const EtherTypePBB EtherType = 0x88e7
+
+const (
+	// itagLen is the length of an I-TAG, excluding its EtherType.
+	itagLen = 4
+
+	// maxISID is the maximum value of a 24 bit backbone service instance
+	// identifier.
+	maxISID = 0xffffff
+)
+
+// ErrInvalidITag is returned when an I-TAG has a priority greater than 7 or
+// a service instance identifier which does not fit in 24 bits.
+var ErrInvalidITag = errors.New("invalid I-TAG")
+
+// An ITag is an IEEE 802.1ah backbone service instance tag (I-TAG), which
+// identifies the service instance of a customer frame carried in a PBBFrame.
+type ITag struct {
+	// Priority and DropEligible specify the priority and drop eligibility of
+	// the service instance, as with a VLAN.
+	Priority     Priority
+	DropEligible bool
+
+	// UseCustomerAddresses indicates that the customer hardware addresses of
+	// the encapsulated frame should be used for service delivery.
+	UseCustomerAddresses bool
+
+	// ISID specifies the 24 bit backbone service instance identifier.
+	ISID uint32
+}
+
+// MarshalBinary allocates a byte slice and marshals an ITag into binary
+// form.
+func (t *ITag) MarshalBinary() ([]byte, error) {
+	b := make([]byte, itagLen)
+	_, err := t.read(b)
+	return b, err
+}
+
+// read reads data from an ITag into b.  read is used to marshal an ITag into
+// binary form, but does not allocate on its own.
+func (t *ITag) read(b []byte) (int, error) {
+	if t.Priority > PriorityNetworkControl || t.ISID > maxISID {
+		return 0, ErrInvalidITag
+	}
+
+	// 3 bits: priority
+	// 1 bit : drop eligible
+	// 1 bit : use customer addresses
+	// 3 bits: reserved
+	// 24 bits: I-SID
+	ub := uint32(t.Priority) << 29
+	if t.DropEligible {
+		ub |= 1 << 28
+	}
+	if t.UseCustomerAddresses {
+		ub |= 1 << 27
+	}
+	ub |= t.ISID
+
+	binary.BigEndian.PutUint32(b, ub)
+	return itagLen, nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into an ITag.
+func (t *ITag) UnmarshalBinary(b []byte) error {
+	// I-TAG is always 4 bytes
+	if len(b) != itagLen {
+		return io.ErrUnexpectedEOF
+	}
+
+	ub := binary.BigEndian.Uint32(b)
+	t.Priority = Priority(ub >> 29)
+	t.DropEligible = ub&(1<<28) != 0
+	t.UseCustomerAddresses = ub&(1<<27) != 0
+	t.ISID = ub & maxISID
+
+	return nil
+}
+
+// A PBBFrame is an IEEE 802.1ah Provider Backbone Bridging (MAC-in-MAC)
+// frame, which encapsulates a customer Ethernet frame within a backbone
+// Ethernet frame.
+type PBBFrame struct {
+	// Destination and Source specify the backbone destination and source
+	// hardware addresses (B-DA and B-SA).
+	Destination net.HardwareAddr
+	Source      net.HardwareAddr
+
+	// BTag specifies an optional backbone VLAN tag (B-TAG), which uses the
+	// EtherTypeServiceVLAN TPID.
+	BTag *VLAN
+
+	// ITag specifies the backbone service instance tag.
+	ITag ITag
+
+	// Customer is the encapsulated customer frame, which begins with the
+	// customer destination and source hardware addresses.
+	Customer *Frame
+}
+
+// MarshalBinary allocates a byte slice and marshals a PBBFrame into binary
+// form.  If Customer is nil, only the backbone headers are marshaled.
+func (p *PBBFrame) MarshalBinary() ([]byte, error) {
+	var cb []byte
+	if p.Customer != nil {
+		b, err := p.Customer.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		cb = b
+	}
+
+	n := 6 + 6 + 2 + itagLen
+	if p.BTag != nil {
+		n += 4
+	}
+
+	b := make([]byte, n+len(cb))
+	copy(b[0:6], p.Destination)
+	copy(b[6:12], p.Source)
+
+	n = 12
+	if p.BTag != nil {
+		binary.BigEndian.PutUint16(b[n:n+2], uint16(EtherTypeServiceVLAN))
+		if _, err := p.BTag.read(b[n+2 : n+4]); err != nil {
+			return nil, err
+		}
+		n += 4
+	}
+
+	binary.BigEndian.PutUint16(b[n:n+2], uint16(EtherTypePBB))
+	if _, err := p.ITag.read(b[n+2 : n+2+itagLen]); err != nil {
+		return nil, err
+	}
+	n += 2 + itagLen
+
+	copy(b[n:], cb)
+	return b, nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into a PBBFrame.
+//
+// If b is malformed, the returned error is a *FieldError which identifies
+// the offending field and its offset.
+func (p *PBBFrame) UnmarshalBinary(b []byte) error {
+	if len(b) < 14 {
+		return headerError(len(b))
+	}
+
+	n := 12
+	p.BTag = nil
+	if EtherType(binary.BigEndian.Uint16(b[n:n+2])) == EtherTypeServiceVLAN {
+		if len(b) < n+6 {
+			return &FieldError{Field: "BTag", Offset: n, Err: io.ErrUnexpectedEOF}
+		}
+
+		v := new(VLAN)
+		if err := v.UnmarshalBinary(b[n+2 : n+4]); err != nil {
+			return &FieldError{Field: "BTag.ID", Offset: n + 2, Err: err}
+		}
+		p.BTag = v
+		n += 4
+	}
+
+	if EtherType(binary.BigEndian.Uint16(b[n:n+2])) != EtherTypePBB {
+		return &FieldError{Field: "ITag", Offset: n, Err: ErrInvalidITag}
+	}
+	if len(b) < n+2+itagLen {
+		return &FieldError{Field: "ITag", Offset: n, Err: io.ErrUnexpectedEOF}
+	}
+
+	_ = p.ITag.UnmarshalBinary(b[n+2 : n+2+itagLen])
+	n += 2 + itagLen
+
+	c := new(Frame)
+	if err := c.UnmarshalBinary(b[n:]); err != nil {
+		var fe *FieldError
+		if errors.As(err, &fe) {
+			return &FieldError{Field: "Customer." + fe.Field, Offset: n + fe.Offset, Err: fe.Err}
+		}
+
+		return err
+	}
+
+	// Allocate a single byte slice for both backbone hardware addresses.
+	bb := make([]byte, 12)
+	copy(bb, b[:12])
+	p.Destination = bb[0:6:6]
+	p.Source = bb[6:12:12]
+	p.Customer = c
+
+	return nil
+}
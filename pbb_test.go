@@ -0,0 +1,95 @@
+package ethernet
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestITagRoundTrip(t *testing.T) {
+	tag := &ITag{
+		Priority:             PriorityVoice,
+		DropEligible:         true,
+		UseCustomerAddresses: true,
+		ISID:                 0x123456,
+	}
+
+	b, err := tag.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	if want, got := []byte{0xb8, 0x12, 0x34, 0x56}, b; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected I-TAG bytes:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	tag2 := new(ITag)
+	if err := tag2.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if want, got := tag, tag2; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected I-TAG:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	if _, err := (&ITag{ISID: maxISID + 1}).MarshalBinary(); err != ErrInvalidITag {
+		t.Fatalf("unexpected error: %v != %v", ErrInvalidITag, err)
+	}
+}
+
+func TestPBBFrame(t *testing.T) {
+	customer := &Frame{
+		Destination: net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+		Source:      net.HardwareAddr{0, 1, 0, 1, 0, 1},
+		VLAN:        &VLAN{ID: 10},
+		EtherType:   EtherTypeIPv4,
+		Payload:     bytes.Repeat([]byte{0xff}, 46),
+	}
+
+	p := &PBBFrame{
+		Destination: Broadcast,
+		Source:      net.HardwareAddr{1, 0, 1, 0, 1, 0},
+		BTag:        &VLAN{ID: 100},
+		ITag:        ITag{Priority: PriorityVideo, ISID: 0x010203},
+		Customer:    customer,
+	}
+
+	b, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	wantHeader := []byte{
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		1, 0, 1, 0, 1, 0,
+		0x88, 0xa8, 0x00, 0x64,
+		0x88, 0xe7, 0x80, 0x01, 0x02, 0x03,
+		0xde, 0xad, 0xbe, 0xef, 0xde, 0xad,
+	}
+	if want, got := wantHeader, b[:len(wantHeader)]; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected PBB header:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	p2 := new(PBBFrame)
+	if err := p2.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if want, got := p, p2; !reflect.DeepEqual(want, got) {
+		t.Fatalf("unexpected PBBFrame:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	// Truncating the customer frame produces an error for its fields.
+	err = p2.UnmarshalBinary(b[:30])
+
+	var fe *FieldError
+	if !errors.As(err, &fe) || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected *FieldError, but got: %#v", err)
+	}
+	if want, got := "Customer.Source", fe.Field; want != got {
+		t.Fatalf("unexpected field: %q != %q", want, got)
+	}
+}
@@ -0,0 +1,298 @@
+// Package pcap implements reading and writing of classic libpcap and pcapng
+// capture files containing Ethernet frames, without cgo.
+package pcap
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+	"github.com/mdlayher/ethernet/sll"
+)
+
+// pcap file format constants.
+const (
+	magicMicroseconds = 0xa1b2c3d4
+	magicNanoseconds  = 0xa1b23c4d
+
+	versionMajor = 2
+	versionMinor = 4
+
+	// Link types supported by this package.  LinkTypeLinuxSLL and
+	// LinkTypeLinuxSLL2 are Linux cooked captures, which are converted into
+	// Ethernet frames by Packet.Frame.
+	LinkTypeEthernet  = 1
+	LinkTypeLinuxSLL  = 113
+	LinkTypeLinuxSLL2 = 276
+
+	// MaxSnapLen is the largest snapshot length used by libpcap.  Records
+	// larger than MaxSnapLen are rejected.
+	MaxSnapLen = 262144
+
+	headerLen       = 24
+	recordHeaderLen = 16
+)
+
+var (
+	// ErrInvalidHeader is returned when a pcap file header has an unknown
+	// magic number.
+	ErrInvalidHeader = errors.New("pcap: invalid file header")
+
+	// ErrLinkType is returned when a pcap file uses an unsupported link
+	// type, or when a Packet written to a file uses a link type other than
+	// the file's.
+	ErrLinkType = errors.New("pcap: unsupported link type")
+
+	// ErrInvalidRecord is returned when a pcap record's captured length is
+	// larger than MaxSnapLen or its original length.
+	ErrInvalidRecord = errors.New("pcap: invalid record")
+)
+
+// A Packet is a captured Ethernet frame.
+type Packet struct {
+	// Timestamp is the time at which the frame was captured.
+	Timestamp time.Time
+
+	// Length is the original length of the frame, which may be larger than
+	// the length of Data if the frame was truncated during capture.
+	Length int
+
+	// Data is the captured frame.
+	Data []byte
+
+	// Interface is the index of the pcapng interface on which the frame
+	// was captured.  Interface is always zero for classic pcap files.
+	Interface int
+
+	// LinkType is the link type of Data.  Zero is treated as
+	// LinkTypeEthernet.
+	LinkType int
+}
+
+// Frame unmarshals the Packet's data into an Ethernet frame, converting
+// Linux cooked captures using package sll.
+func (p *Packet) Frame() (*ethernet.Frame, error) {
+	var parse func(b []byte) (*sll.Header, []byte, error)
+	switch p.LinkType {
+	case 0, LinkTypeEthernet:
+		f := new(ethernet.Frame)
+		if err := f.UnmarshalBinary(p.Data); err != nil {
+			return nil, err
+		}
+
+		return f, nil
+	case LinkTypeLinuxSLL:
+		parse = sll.Parse
+	case LinkTypeLinuxSLL2:
+		parse = sll.Parse2
+	default:
+		return nil, ErrLinkType
+	}
+
+	h, payload, err := parse(p.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.Frame(payload)
+}
+
+// packetLinkType returns the link type of p, treating zero as
+// LinkTypeEthernet.
+func packetLinkType(p *Packet) int {
+	if p.LinkType == 0 {
+		return LinkTypeEthernet
+	}
+
+	return p.LinkType
+}
+
+// supportedLinkType reports whether lt is supported by this package.
+func supportedLinkType(lt int) bool {
+	switch lt {
+	case LinkTypeEthernet, LinkTypeLinuxSLL, LinkTypeLinuxSLL2:
+		return true
+	default:
+		return false
+	}
+}
+
+// A Reader reads Packets from a pcap file.
+type Reader struct {
+	r        io.Reader
+	order    binary.ByteOrder
+	nano     bool
+	snapLen  int
+	linkType int
+}
+
+// NewReader creates a Reader which reads a pcap file from r.  The pcap file
+// header is read and verified immediately.  Files with either byte order and
+// either microsecond or nanosecond resolution timestamps are supported.
+func NewReader(r io.Reader) (*Reader, error) {
+	var h [headerLen]byte
+	if _, err := io.ReadFull(r, h[:]); err != nil {
+		return nil, err
+	}
+
+	pr := &Reader{r: r}
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		switch order.Uint32(h[0:4]) {
+		case magicMicroseconds:
+			pr.order = order
+		case magicNanoseconds:
+			pr.order, pr.nano = order, true
+		}
+	}
+	if pr.order == nil {
+		return nil, ErrInvalidHeader
+	}
+
+	pr.linkType = int(pr.order.Uint32(h[20:24]))
+	if !supportedLinkType(pr.linkType) {
+		return nil, ErrLinkType
+	}
+	pr.snapLen = int(pr.order.Uint32(h[16:20]))
+
+	return pr, nil
+}
+
+// SnapLen returns the snapshot length recorded in the pcap file header.
+func (r *Reader) SnapLen() int {
+	return r.snapLen
+}
+
+// LinkType returns the link type recorded in the pcap file header.
+func (r *Reader) LinkType() int {
+	return r.linkType
+}
+
+// Next reads the next Packet.  io.EOF is returned when no Packets remain.
+func (r *Reader) Next() (*Packet, error) {
+	var rh [recordHeaderLen]byte
+	if _, err := io.ReadFull(r.r, rh[:]); err != nil {
+		return nil, err
+	}
+
+	var (
+		sec  = int64(r.order.Uint32(rh[0:4]))
+		frac = int64(r.order.Uint32(rh[4:8]))
+		n    = r.order.Uint32(rh[8:12])
+		l    = r.order.Uint32(rh[12:16])
+	)
+	if n > MaxSnapLen || n > l {
+		return nil, ErrInvalidRecord
+	}
+
+	if !r.nano {
+		frac *= int64(time.Microsecond)
+	}
+
+	p := &Packet{
+		Timestamp: time.Unix(sec, frac),
+		Length:    int(l),
+		Data:      make([]byte, n),
+		LinkType:  r.linkType,
+	}
+
+	if _, err := io.ReadFull(r.r, p.Data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// A Writer writes Packets to a pcap file.  Packets are buffered internally;
+// Flush must be called once all Packets are written.
+type Writer struct {
+	w       *bufio.Writer
+	snapLen int
+}
+
+// NewWriter creates a Writer which writes a little endian pcap file with
+// nanosecond resolution timestamps to w, and immediately writes its file
+// header.  A positive snapLen truncates captured frames to snapLen bytes.
+// If snapLen is zero, MaxSnapLen is used.
+func NewWriter(w io.Writer, snapLen int) (*Writer, error) {
+	if snapLen <= 0 || snapLen > MaxSnapLen {
+		snapLen = MaxSnapLen
+	}
+
+	pw := &Writer{
+		w:       bufio.NewWriter(w),
+		snapLen: snapLen,
+	}
+
+	var h [headerLen]byte
+	binary.LittleEndian.PutUint32(h[0:4], magicNanoseconds)
+	binary.LittleEndian.PutUint16(h[4:6], versionMajor)
+	binary.LittleEndian.PutUint16(h[6:8], versionMinor)
+	binary.LittleEndian.PutUint32(h[16:20], uint32(snapLen))
+	binary.LittleEndian.PutUint32(h[20:24], LinkTypeEthernet)
+	if _, err := pw.w.Write(h[:]); err != nil {
+		return nil, err
+	}
+
+	return pw, nil
+}
+
+// WritePacket writes p, truncating its data to the Writer's snapshot
+// length.  If p.Length is smaller than the length of p.Data, the length of
+// p.Data is recorded as the original length.
+//
+// A Writer always writes Ethernet frames, so ErrLinkType is returned if
+// p.LinkType is not LinkTypeEthernet or zero.  Packet.Frame and WriteFrame
+// may be used to convert Linux cooked captures into Ethernet frames.
+func (w *Writer) WritePacket(p *Packet) error {
+	if packetLinkType(p) != LinkTypeEthernet {
+		return ErrLinkType
+	}
+
+	data := p.Data
+	if len(data) > w.snapLen {
+		data = data[:w.snapLen]
+	}
+
+	l := p.Length
+	if l < len(p.Data) {
+		l = len(p.Data)
+	}
+
+	var rh [recordHeaderLen]byte
+	binary.LittleEndian.PutUint32(rh[0:4], uint32(p.Timestamp.Unix()))
+	binary.LittleEndian.PutUint32(rh[4:8], uint32(p.Timestamp.Nanosecond()))
+	binary.LittleEndian.PutUint32(rh[8:12], uint32(len(data)))
+	binary.LittleEndian.PutUint32(rh[12:16], uint32(l))
+	if _, err := w.w.Write(rh[:]); err != nil {
+		return err
+	}
+
+	_, err := w.w.Write(data)
+	return err
+}
+
+// WriteFrame marshals f and writes it as a Packet captured at time ts.
+func (w *Writer) WriteFrame(ts time.Time, f *ethernet.Frame) error {
+	b, err := f.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	return w.WritePacket(&Packet{
+		Timestamp: ts,
+		Length:    len(b),
+		Data:      b,
+	})
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
@@ -0,0 +1,301 @@
+package pcap
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestReadWrite(t *testing.T) {
+	f := &ethernet.Frame{
+		Destination: ethernet.Broadcast,
+		Source:      ethernet.Broadcast,
+		EtherType:   ethernet.EtherTypeIPv4,
+		Payload:     bytes.Repeat([]byte{0xff}, 46),
+	}
+	ts := time.Unix(1, 2)
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, 0)
+	if err != nil {
+		t.Fatalf("failed to create writer: %v", err)
+	}
+
+	if err := w.WriteFrame(ts, f); err != nil {
+		t.Fatalf("failed to write frame: %v", err)
+	}
+	if err := w.WritePacket(&Packet{Timestamp: ts, Length: 100, Data: []byte{0xaa}}); err != nil {
+		t.Fatalf("failed to write packet: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("failed to flush: %v", err)
+	}
+
+	r, err := NewReader(&buf)
+	if err != nil {
+		t.Fatalf("failed to create reader: %v", err)
+	}
+
+	if want, got := MaxSnapLen, r.SnapLen(); want != got {
+		t.Fatalf("unexpected snapshot length: %v != %v", want, got)
+	}
+
+	p, err := r.Next()
+	if err != nil {
+		t.Fatalf("failed to read packet: %v", err)
+	}
+
+	if !p.Timestamp.Equal(ts) {
+		t.Fatalf("unexpected timestamp: %v != %v", ts, p.Timestamp)
+	}
+	if want, got := 60, p.Length; want != got {
+		t.Fatalf("unexpected length: %v != %v", want, got)
+	}
+
+	got, err := p.Frame()
+	if err != nil {
+		t.Fatalf("failed to unmarshal frame: %v", err)
+	}
+	if !reflect.DeepEqual(f, got) {
+		t.Fatalf("unexpected frame:\n- want: %#v\n-  got: %#v", f, got)
+	}
+
+	p, err = r.Next()
+	if err != nil {
+		t.Fatalf("failed to read packet: %v", err)
+	}
+
+	want := &Packet{Timestamp: ts, Length: 100, Data: []byte{0xaa}, LinkType: LinkTypeEthernet}
+	if !reflect.DeepEqual(want, p) {
+		t.Fatalf("unexpected packet:\n- want: %#v\n-  got: %#v", want, p)
+	}
+
+	if _, err := r.Next(); err != io.EOF {
+		t.Fatalf("expected io.EOF, but got: %v", err)
+	}
+}
+
+func TestWriterSnapLen(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, 2)
+	if err != nil {
+		t.Fatalf("failed to create writer: %v", err)
+	}
+
+	if err := w.WritePacket(&Packet{Timestamp: time.Unix(1, 2), Data: []byte{0xaa, 0xbb, 0xcc}}); err != nil {
+		t.Fatalf("failed to write packet: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("failed to flush: %v", err)
+	}
+
+	want := []byte{
+		// Global header: magic, version 2.4, zone, sigfigs, snaplen, link type.
+		0x4d, 0x3c, 0xb2, 0xa1,
+		0x02, 0x00, 0x04, 0x00,
+		0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00,
+		0x02, 0x00, 0x00, 0x00,
+		0x01, 0x00, 0x00, 0x00,
+		// Record header: seconds, nanoseconds, captured and original length.
+		0x01, 0x00, 0x00, 0x00,
+		0x02, 0x00, 0x00, 0x00,
+		0x02, 0x00, 0x00, 0x00,
+		0x03, 0x00, 0x00, 0x00,
+		// Data.
+		0xaa, 0xbb,
+	}
+
+	if got := buf.Bytes(); !bytes.Equal(want, got) {
+		t.Fatalf("unexpected pcap:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestReader(t *testing.T) {
+	tests := []struct {
+		desc string
+		b    []byte
+		ts   time.Time
+		err  error
+	}{
+		{
+			desc: "bad magic",
+			b:    make([]byte, headerLen),
+			err:  ErrInvalidHeader,
+		},
+		{
+			desc: "bad link type",
+			b: []byte{
+				0xd4, 0xc3, 0xb2, 0xa1,
+				0x02, 0x00, 0x04, 0x00,
+				0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00,
+				0xff, 0xff, 0x00, 0x00,
+				0x69, 0x00, 0x00, 0x00,
+			},
+			err: ErrLinkType,
+		},
+		{
+			desc: "big endian microseconds",
+			b: []byte{
+				0xa1, 0xb2, 0xc3, 0xd4,
+				0x00, 0x02, 0x00, 0x04,
+				0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0xff, 0xff,
+				0x00, 0x00, 0x00, 0x01,
+
+				0x00, 0x00, 0x00, 0x01,
+				0x00, 0x00, 0x00, 0x02,
+				0x00, 0x00, 0x00, 0x01,
+				0x00, 0x00, 0x00, 0x01,
+				0xaa,
+			},
+			ts: time.Unix(1, 2000),
+		},
+		{
+			desc: "invalid record",
+			b: []byte{
+				0xd4, 0xc3, 0xb2, 0xa1,
+				0x02, 0x00, 0x04, 0x00,
+				0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00,
+				0xff, 0xff, 0x00, 0x00,
+				0x01, 0x00, 0x00, 0x00,
+
+				0x01, 0x00, 0x00, 0x00,
+				0x02, 0x00, 0x00, 0x00,
+				0x02, 0x00, 0x00, 0x00,
+				0x01, 0x00, 0x00, 0x00,
+			},
+			err: ErrInvalidRecord,
+		},
+		{
+			desc: "truncated record",
+			b: []byte{
+				0xd4, 0xc3, 0xb2, 0xa1,
+				0x02, 0x00, 0x04, 0x00,
+				0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00,
+				0xff, 0xff, 0x00, 0x00,
+				0x01, 0x00, 0x00, 0x00,
+
+				0x01, 0x00, 0x00, 0x00,
+				0x02, 0x00, 0x00, 0x00,
+				0x02, 0x00, 0x00, 0x00,
+				0x02, 0x00, 0x00, 0x00,
+				0xaa,
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			r, err := NewReader(bytes.NewReader(tt.b))
+			if err == nil {
+				var p *Packet
+				p, err = r.Next()
+				if err == nil && !p.Timestamp.Equal(tt.ts) {
+					t.Fatalf("unexpected timestamp: %v != %v", tt.ts, p.Timestamp)
+				}
+			}
+
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error: %v != %v", want, got)
+			}
+		})
+	}
+}
+
+func TestWriterLinuxSLL(t *testing.T) {
+	p := &Packet{
+		LinkType: LinkTypeLinuxSLL,
+		Data: []byte{
+			// SLL header: broadcast, Ethernet, 6 byte address, ARP.
+			0x00, 0x01, 0x00, 0x01,
+			0x00, 0x06, 0xde, 0xad,
+			0xbe, 0xef, 0xde, 0xad,
+			0x00, 0x00, 0x08, 0x06,
+			0xaa,
+		},
+	}
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, 0)
+	if err != nil {
+		t.Fatalf("failed to create writer: %v", err)
+	}
+
+	if err := w.WritePacket(p); err != ErrLinkType {
+		t.Fatalf("unexpected error: %v != %v", ErrLinkType, err)
+	}
+
+	// Converting the packet into an Ethernet frame allows it to be written.
+	f, err := p.Frame()
+	if err != nil {
+		t.Fatalf("failed to convert frame: %v", err)
+	}
+	if err := w.WriteFrame(p.Timestamp, f); err != nil {
+		t.Fatalf("failed to write frame: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("failed to flush: %v", err)
+	}
+
+	r, err := NewReader(&buf)
+	if err != nil {
+		t.Fatalf("failed to create reader: %v", err)
+	}
+
+	got, err := r.Next()
+	if err != nil {
+		t.Fatalf("failed to read packet: %v", err)
+	}
+	if want, got := LinkTypeEthernet, got.LinkType; want != got {
+		t.Fatalf("unexpected link type: %v != %v", want, got)
+	}
+
+	if _, err := r.Next(); err != io.EOF {
+		t.Fatalf("expected io.EOF, but got: %v", err)
+	}
+}
+
+func TestPacketFrameLinuxSLL(t *testing.T) {
+	p := &Packet{
+		LinkType: LinkTypeLinuxSLL,
+		Data: []byte{
+			// SLL header: broadcast, Ethernet, 6 byte address, ARP.
+			0x00, 0x01, 0x00, 0x01,
+			0x00, 0x06, 0xde, 0xad,
+			0xbe, 0xef, 0xde, 0xad,
+			0x00, 0x00, 0x08, 0x06,
+			0xaa,
+		},
+	}
+
+	f, err := p.Frame()
+	if err != nil {
+		t.Fatalf("failed to convert frame: %v", err)
+	}
+
+	want := &ethernet.Frame{
+		Destination: ethernet.Broadcast,
+		Source:      net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+		EtherType:   ethernet.EtherTypeARP,
+		Payload:     []byte{0xaa},
+	}
+
+	if !reflect.DeepEqual(want, f) {
+		t.Fatalf("unexpected frame:\n- want: %#v\n-  got: %#v", want, f)
+	}
+
+	if _, err := (&Packet{LinkType: 105}).Frame(); err != ErrLinkType {
+		t.Fatalf("expected ErrLinkType, but got: %v", err)
+	}
+}
@@ -0,0 +1,485 @@
+package pcap
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"time"
+)
+
+// pcapng block types.
+const (
+	blockInterfaceDescription = 0x00000001
+	blockSimplePacket         = 0x00000003
+	blockEnhancedPacket       = 0x00000006
+	blockSectionHeader        = 0x0a0d0d0a
+)
+
+// pcapng option codes.
+const (
+	optEndOfOpt    = 0
+	optIfName      = 2
+	optIfDesc      = 3
+	optShbUserAppl = 4
+	optIfMACAddr   = 6
+	optIfTSResol   = 9
+)
+
+const (
+	// byteOrderMagic identifies the byte order of a pcapng section.
+	byteOrderMagic = 0x1a2b3c4d
+
+	// ngMaxBlockLen bounds the size of a single pcapng block.
+	ngMaxBlockLen = 16 << 20
+
+	// tsResolNanoseconds is the if_tsresol value for nanosecond timestamps.
+	tsResolNanoseconds = 9
+
+	// tsResolDefault is the resolution of timestamps for interfaces without
+	// an if_tsresol option: microseconds.
+	tsResolDefault = 6
+)
+
+var (
+	// ErrInvalidBlock is returned when a pcapng block is malformed.
+	ErrInvalidBlock = errors.New("pcap: invalid pcapng block")
+
+	// ErrUnknownInterface is returned when a pcapng packet refers to an
+	// interface which has not been described.
+	ErrUnknownInterface = errors.New("pcap: unknown pcapng interface")
+)
+
+// An Interface describes a capture interface in a pcapng file.
+type Interface struct {
+	// Name and Description specify the if_name and if_description options
+	// of an interface.  Empty values are omitted.
+	Name        string
+	Description string
+
+	// HardwareAddr specifies the if_MACaddr option of an interface, and is
+	// omitted if nil.
+	HardwareAddr net.HardwareAddr
+
+	// SnapLen specifies the maximum number of bytes captured from each
+	// frame.  If zero, MaxSnapLen is used when writing.
+	SnapLen int
+
+	// LinkType specifies the link type of the interface.  If zero,
+	// LinkTypeEthernet is used when writing.
+	LinkType int
+
+	// tsResol is the raw if_tsresol option value.
+	tsResol uint8
+}
+
+// An NgReader reads Packets from a pcapng file.  All interfaces in the file
+// must use a link type supported by this package.
+type NgReader struct {
+	r      *bufio.Reader
+	order  binary.ByteOrder
+	ifaces []Interface
+}
+
+// NewNgReader creates an NgReader which reads a pcapng file from r.  The
+// first section header block is read and verified immediately.
+func NewNgReader(r io.Reader) (*NgReader, error) {
+	nr := &NgReader{r: bufio.NewReader(r)}
+
+	typ, _, err := nr.block()
+	if err != nil {
+		return nil, err
+	}
+	if typ != blockSectionHeader {
+		return nil, ErrInvalidHeader
+	}
+
+	return nr, nil
+}
+
+// Interfaces returns the Interfaces described in the current section of the
+// file so far.  A Packet's Interface field is an index into the returned
+// slice.
+func (r *NgReader) Interfaces() []Interface {
+	return r.ifaces
+}
+
+// Next reads the next Packet, processing any interface description and
+// section header blocks which precede it.  Blocks of other types are
+// skipped.  io.EOF is returned when no Packets remain.
+func (r *NgReader) Next() (*Packet, error) {
+	for {
+		typ, body, err := r.block()
+		if err != nil {
+			return nil, err
+		}
+
+		switch typ {
+		case blockInterfaceDescription:
+			if err := r.parseInterface(body); err != nil {
+				return nil, err
+			}
+		case blockEnhancedPacket:
+			return r.parseEnhancedPacket(body)
+		case blockSimplePacket:
+			return r.parseSimplePacket(body)
+		}
+	}
+}
+
+// block reads the next block's type and body.  A section header block resets
+// the byte order and interfaces of the NgReader.
+func (r *NgReader) block() (uint32, []byte, error) {
+	var h [12]byte
+	if _, err := io.ReadFull(r.r, h[:8]); err != nil {
+		return 0, nil, err
+	}
+
+	// The byte order of a section header is determined by its byte order
+	// magic, which immediately follows the block length.
+	if binary.BigEndian.Uint32(h[0:4]) == blockSectionHeader {
+		if _, err := io.ReadFull(r.r, h[8:12]); err != nil {
+			return 0, nil, unexpectedEOF(err)
+		}
+
+		switch {
+		case binary.LittleEndian.Uint32(h[8:12]) == byteOrderMagic:
+			r.order = binary.LittleEndian
+		case binary.BigEndian.Uint32(h[8:12]) == byteOrderMagic:
+			r.order = binary.BigEndian
+		default:
+			return 0, nil, ErrInvalidHeader
+		}
+
+		r.ifaces = nil
+	} else if r.order == nil {
+		return 0, nil, ErrInvalidHeader
+	}
+
+	typ := r.order.Uint32(h[0:4])
+	l := r.order.Uint32(h[4:8])
+	if l < 12 || l%4 != 0 || l > ngMaxBlockLen {
+		return 0, nil, ErrInvalidBlock
+	}
+
+	// Read the remainder of the block, including the trailing length.
+	b := make([]byte, l-8)
+	n := copy(b, h[8:])
+	if typ != blockSectionHeader {
+		n = 0
+	}
+	if _, err := io.ReadFull(r.r, b[n:]); err != nil {
+		return 0, nil, unexpectedEOF(err)
+	}
+
+	if r.order.Uint32(b[len(b)-4:]) != l {
+		return 0, nil, ErrInvalidBlock
+	}
+
+	return typ, b[:len(b)-4], nil
+}
+
+// parseInterface parses an interface description block body.
+func (r *NgReader) parseInterface(b []byte) error {
+	if len(b) < 8 {
+		return ErrInvalidBlock
+	}
+	lt := int(r.order.Uint16(b[0:2]))
+	if !supportedLinkType(lt) {
+		return ErrLinkType
+	}
+
+	ifi := Interface{
+		SnapLen:  int(r.order.Uint32(b[4:8])),
+		LinkType: lt,
+		tsResol:  tsResolDefault,
+	}
+
+	err := r.parseOptions(b[8:], func(code uint16, v []byte) {
+		switch code {
+		case optIfName:
+			ifi.Name = string(v)
+		case optIfDesc:
+			ifi.Description = string(v)
+		case optIfMACAddr:
+			if len(v) == 6 {
+				ifi.HardwareAddr = net.HardwareAddr(append([]byte(nil), v...))
+			}
+		case optIfTSResol:
+			if len(v) == 1 {
+				ifi.tsResol = v[0]
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	r.ifaces = append(r.ifaces, ifi)
+	return nil
+}
+
+// parseOptions calls fn for each option in b.
+func (r *NgReader) parseOptions(b []byte, fn func(code uint16, v []byte)) error {
+	for len(b) >= 4 {
+		code := r.order.Uint16(b[0:2])
+		l := int(r.order.Uint16(b[2:4]))
+		if code == optEndOfOpt {
+			return nil
+		}
+
+		padded := 4 + pad4(l)
+		if padded > len(b) {
+			return ErrInvalidBlock
+		}
+
+		fn(code, b[4:4+l])
+		b = b[padded:]
+	}
+
+	return nil
+}
+
+// parseEnhancedPacket parses an enhanced packet block body.
+func (r *NgReader) parseEnhancedPacket(b []byte) (*Packet, error) {
+	if len(b) < 20 {
+		return nil, ErrInvalidBlock
+	}
+
+	id := int(r.order.Uint32(b[0:4]))
+	if id >= len(r.ifaces) {
+		return nil, ErrUnknownInterface
+	}
+
+	var (
+		ts = uint64(r.order.Uint32(b[4:8]))<<32 | uint64(r.order.Uint32(b[8:12]))
+		n  = int(r.order.Uint32(b[12:16]))
+		l  = int(r.order.Uint32(b[16:20]))
+	)
+	if n > len(b[20:]) {
+		return nil, ErrInvalidBlock
+	}
+
+	return &Packet{
+		Timestamp: timestamp(ts, r.ifaces[id].tsResol),
+		Length:    l,
+		Data:      append([]byte(nil), b[20:20+n]...),
+		Interface: id,
+		LinkType:  r.ifaces[id].LinkType,
+	}, nil
+}
+
+// parseSimplePacket parses a simple packet block body, which is always
+// associated with the first interface.
+func (r *NgReader) parseSimplePacket(b []byte) (*Packet, error) {
+	if len(b) < 4 {
+		return nil, ErrInvalidBlock
+	}
+	if len(r.ifaces) == 0 {
+		return nil, ErrUnknownInterface
+	}
+
+	l := int(r.order.Uint32(b[0:4]))
+	n := l
+	if s := r.ifaces[0].SnapLen; s > 0 && n > s {
+		n = s
+	}
+	if n > len(b[4:]) {
+		return nil, ErrInvalidBlock
+	}
+
+	return &Packet{
+		Length:   l,
+		Data:     append([]byte(nil), b[4:4+n]...),
+		LinkType: r.ifaces[0].LinkType,
+	}, nil
+}
+
+// timestamp converts a pcapng timestamp with the specified if_tsresol value
+// into a time.Time.
+func timestamp(ts uint64, resol uint8) time.Time {
+	// The most significant bit indicates a power of two resolution, rather
+	// than a power of ten.
+	if resol&0x80 != 0 {
+		units := math.Ldexp(1, int(resol&0x7f))
+		sec := math.Floor(float64(ts) / units)
+		frac := float64(ts) - sec*units
+
+		return time.Unix(int64(sec), int64(frac/units*1e9))
+	}
+
+	units := uint64(1)
+	for i := uint8(0); i < resol && i < 19; i++ {
+		units *= 10
+	}
+
+	sec, frac := ts/units, ts%units
+	if units < 1e9 {
+		frac *= 1e9 / units
+	} else {
+		frac /= units / 1e9
+	}
+
+	return time.Unix(int64(sec), int64(frac))
+}
+
+// An NgWriter writes Packets to a pcapng file.  Packets are buffered
+// internally; Flush must be called once all Packets are written.
+type NgWriter struct {
+	w      *bufio.Writer
+	ifaces []Interface
+}
+
+// NewNgWriter creates an NgWriter which writes a little endian pcapng file
+// to w, and immediately writes a section header block and an interface
+// description block for each of ifaces.  All interfaces use nanosecond
+// resolution timestamps.  If ifaces is empty, a single unnamed Ethernet
+// interface is described.
+func NewNgWriter(w io.Writer, ifaces []Interface) (*NgWriter, error) {
+	if len(ifaces) == 0 {
+		ifaces = []Interface{{}}
+	}
+
+	nw := &NgWriter{
+		w:      bufio.NewWriter(w),
+		ifaces: make([]Interface, 0, len(ifaces)),
+	}
+
+	// Section header: byte order magic, version 1.0, unknown section
+	// length.
+	shb := make([]byte, 16)
+	binary.LittleEndian.PutUint32(shb[0:4], byteOrderMagic)
+	binary.LittleEndian.PutUint16(shb[4:6], 1)
+	binary.LittleEndian.PutUint64(shb[8:16], math.MaxUint64)
+	shb = appendOption(shb, optShbUserAppl, []byte("github.com/mdlayher/ethernet"))
+	shb = appendOption(shb, optEndOfOpt, nil)
+	if err := nw.writeBlock(blockSectionHeader, shb); err != nil {
+		return nil, err
+	}
+
+	for _, ifi := range ifaces {
+		if ifi.SnapLen <= 0 || ifi.SnapLen > MaxSnapLen {
+			ifi.SnapLen = MaxSnapLen
+		}
+		if ifi.LinkType == 0 {
+			ifi.LinkType = LinkTypeEthernet
+		}
+		if !supportedLinkType(ifi.LinkType) {
+			return nil, ErrLinkType
+		}
+		ifi.tsResol = tsResolNanoseconds
+
+		idb := make([]byte, 8)
+		binary.LittleEndian.PutUint16(idb[0:2], uint16(ifi.LinkType))
+		binary.LittleEndian.PutUint32(idb[4:8], uint32(ifi.SnapLen))
+		if ifi.Name != "" {
+			idb = appendOption(idb, optIfName, []byte(ifi.Name))
+		}
+		if ifi.Description != "" {
+			idb = appendOption(idb, optIfDesc, []byte(ifi.Description))
+		}
+		if len(ifi.HardwareAddr) == 6 {
+			idb = appendOption(idb, optIfMACAddr, ifi.HardwareAddr)
+		}
+		idb = appendOption(idb, optIfTSResol, []byte{tsResolNanoseconds})
+		idb = appendOption(idb, optEndOfOpt, nil)
+
+		if err := nw.writeBlock(blockInterfaceDescription, idb); err != nil {
+			return nil, err
+		}
+
+		nw.ifaces = append(nw.ifaces, ifi)
+	}
+
+	return nw, nil
+}
+
+// WritePacket writes p as an enhanced packet block on the interface
+// specified by p.Interface, truncating its data to the interface's snapshot
+// length.  If p.Length is smaller than the length of p.Data, the length of
+// p.Data is recorded as the original length.  ErrLinkType is returned if
+// p.LinkType does not match the link type of the interface.
+func (w *NgWriter) WritePacket(p *Packet) error {
+	if p.Interface < 0 || p.Interface >= len(w.ifaces) {
+		return ErrUnknownInterface
+	}
+	if packetLinkType(p) != w.ifaces[p.Interface].LinkType {
+		return ErrLinkType
+	}
+
+	data := p.Data
+	if s := w.ifaces[p.Interface].SnapLen; len(data) > s {
+		data = data[:s]
+	}
+
+	l := p.Length
+	if l < len(p.Data) {
+		l = len(p.Data)
+	}
+
+	ts := uint64(p.Timestamp.UnixNano())
+
+	epb := make([]byte, 20, 20+pad4(len(data))+4)
+	binary.LittleEndian.PutUint32(epb[0:4], uint32(p.Interface))
+	binary.LittleEndian.PutUint32(epb[4:8], uint32(ts>>32))
+	binary.LittleEndian.PutUint32(epb[8:12], uint32(ts))
+	binary.LittleEndian.PutUint32(epb[12:16], uint32(len(data)))
+	binary.LittleEndian.PutUint32(epb[16:20], uint32(l))
+	epb = append(epb, data...)
+	epb = append(epb, make([]byte, pad4(len(data))-len(data))...)
+
+	return w.writeBlock(blockEnhancedPacket, epb)
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+func (w *NgWriter) Flush() error {
+	return w.w.Flush()
+}
+
+// writeBlock writes a block of the specified type with body b, whose length
+// must be a multiple of 4.
+func (w *NgWriter) writeBlock(typ uint32, b []byte) error {
+	l := uint32(12 + len(b))
+
+	var h [8]byte
+	binary.LittleEndian.PutUint32(h[0:4], typ)
+	binary.LittleEndian.PutUint32(h[4:8], l)
+	if _, err := w.w.Write(h[:]); err != nil {
+		return err
+	}
+	if _, err := w.w.Write(b); err != nil {
+		return err
+	}
+
+	_, err := w.w.Write(h[4:8])
+	return err
+}
+
+// appendOption appends a pcapng option with the specified code and value to
+// b, padding the value to a multiple of 4 bytes.
+func appendOption(b []byte, code uint16, v []byte) []byte {
+	var h [4]byte
+	binary.LittleEndian.PutUint16(h[0:2], code)
+	binary.LittleEndian.PutUint16(h[2:4], uint16(len(v)))
+
+	b = append(b, h[:]...)
+	b = append(b, v...)
+	return append(b, make([]byte, pad4(len(v))-len(v))...)
+}
+
+// pad4 rounds n up to a multiple of 4.
+func pad4(n int) int {
+	return (n + 3) &^ 3
+}
+
+// unexpectedEOF converts io.EOF to io.ErrUnexpectedEOF, for use when a
+// block is truncated.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
@@ -0,0 +1,281 @@
+package pcap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNgReadWrite(t *testing.T) {
+	ifaces := []Interface{
+		{
+			Name:         "eth0",
+			Description:  "uplink",
+			HardwareAddr: net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+			SnapLen:      2,
+		},
+		{
+			Name: "eth1",
+		},
+	}
+
+	ps := []*Packet{
+		{
+			Timestamp: time.Unix(1, 2),
+			Length:    3,
+			Data:      []byte{0xaa, 0xbb, 0xcc},
+			LinkType:  LinkTypeEthernet,
+		},
+		{
+			Timestamp: time.Unix(1500000000, 123456789),
+			Length:    5,
+			Data:      []byte{0xaa, 0xbb, 0xcc, 0xdd, 0xee},
+			Interface: 1,
+			LinkType:  LinkTypeEthernet,
+		},
+	}
+
+	var buf bytes.Buffer
+	w, err := NewNgWriter(&buf, ifaces)
+	if err != nil {
+		t.Fatalf("failed to create writer: %v", err)
+	}
+
+	for _, p := range ps {
+		if err := w.WritePacket(p); err != nil {
+			t.Fatalf("failed to write packet: %v", err)
+		}
+	}
+	if err := w.WritePacket(&Packet{Interface: 2}); err != ErrUnknownInterface {
+		t.Fatalf("expected ErrUnknownInterface, but got: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("failed to flush: %v", err)
+	}
+
+	r, err := NewNgReader(&buf)
+	if err != nil {
+		t.Fatalf("failed to create reader: %v", err)
+	}
+
+	// The first packet is truncated to its interface's snapshot length.
+	ps[0].Data = ps[0].Data[:2]
+
+	for i, want := range ps {
+		got, err := r.Next()
+		if err != nil {
+			t.Fatalf("failed to read packet %d: %v", i, err)
+		}
+
+		if !want.Timestamp.Equal(got.Timestamp) {
+			t.Fatalf("unexpected timestamp %d: %v != %v", i, want.Timestamp, got.Timestamp)
+		}
+
+		got.Timestamp = want.Timestamp
+		if !reflect.DeepEqual(want, got) {
+			t.Fatalf("unexpected packet %d:\n- want: %#v\n-  got: %#v", i, want, got)
+		}
+	}
+
+	if _, err := r.Next(); err != io.EOF {
+		t.Fatalf("expected io.EOF, but got: %v", err)
+	}
+
+	wantIfaces := []Interface{
+		{
+			Name:         "eth0",
+			Description:  "uplink",
+			HardwareAddr: net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+			SnapLen:      2,
+			LinkType:     LinkTypeEthernet,
+			tsResol:      tsResolNanoseconds,
+		},
+		{
+			Name:     "eth1",
+			SnapLen:  MaxSnapLen,
+			LinkType: LinkTypeEthernet,
+			tsResol:  tsResolNanoseconds,
+		},
+	}
+
+	if got := r.Interfaces(); !reflect.DeepEqual(wantIfaces, got) {
+		t.Fatalf("unexpected interfaces:\n- want: %#v\n-  got: %#v", wantIfaces, got)
+	}
+}
+
+func TestNgReaderBigEndianSimplePacket(t *testing.T) {
+	be := binary.BigEndian
+
+	block := func(typ uint32, body ...[]byte) []byte {
+		var b []byte
+		for _, bb := range body {
+			b = append(b, bb...)
+		}
+
+		out := make([]byte, 8, 12+len(b))
+		be.PutUint32(out[0:4], typ)
+		be.PutUint32(out[4:8], uint32(12+len(b)))
+		out = append(out, b...)
+		return append(out, out[4:8]...)
+	}
+
+	b := concat(
+		// Section header: byte order magic, version, section length.
+		block(blockSectionHeader, []byte{
+			0x1a, 0x2b, 0x3c, 0x4d,
+			0x00, 0x01, 0x00, 0x00,
+			0xff, 0xff, 0xff, 0xff,
+			0xff, 0xff, 0xff, 0xff,
+		}),
+		// Interface description: Ethernet, snaplen 2, no options.
+		block(blockInterfaceDescription, []byte{
+			0x00, 0x01, 0x00, 0x00,
+			0x00, 0x00, 0x00, 0x02,
+		}),
+		// Unknown block, skipped.
+		block(0x0bad, []byte{0x00, 0x00, 0x00, 0x00}),
+		// Simple packet: original length 3, truncated data.
+		block(blockSimplePacket, []byte{
+			0x00, 0x00, 0x00, 0x03,
+			0xaa, 0xbb, 0x00, 0x00,
+		}),
+	)
+
+	r, err := NewNgReader(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("failed to create reader: %v", err)
+	}
+
+	p, err := r.Next()
+	if err != nil {
+		t.Fatalf("failed to read packet: %v", err)
+	}
+
+	want := &Packet{Length: 3, Data: []byte{0xaa, 0xbb}, LinkType: LinkTypeEthernet}
+	if !reflect.DeepEqual(want, p) {
+		t.Fatalf("unexpected packet:\n- want: %#v\n-  got: %#v", want, p)
+	}
+}
+
+func TestNgReaderErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		b    []byte
+		err  error
+	}{
+		{
+			desc: "empty",
+			err:  io.EOF,
+		},
+		{
+			desc: "not a section header",
+			b: []byte{
+				0x01, 0x00, 0x00, 0x00,
+				0x0c, 0x00, 0x00, 0x00,
+				0x0c, 0x00, 0x00, 0x00,
+			},
+			err: ErrInvalidHeader,
+		},
+		{
+			desc: "bad byte order magic",
+			b: []byte{
+				0x0a, 0x0d, 0x0d, 0x0a,
+				0x1c, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00,
+			},
+			err: ErrInvalidHeader,
+		},
+		{
+			desc: "bad block length",
+			b: []byte{
+				0x0a, 0x0d, 0x0d, 0x0a,
+				0x1d, 0x00, 0x00, 0x00,
+				0x4d, 0x3c, 0x2b, 0x1a,
+			},
+			err: ErrInvalidBlock,
+		},
+		{
+			desc: "truncated",
+			b: []byte{
+				0x0a, 0x0d, 0x0d, 0x0a,
+				0x1c, 0x00, 0x00, 0x00,
+				0x4d, 0x3c, 0x2b, 0x1a,
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "mismatched trailing length",
+			b: []byte{
+				0x0a, 0x0d, 0x0d, 0x0a,
+				0x10, 0x00, 0x00, 0x00,
+				0x4d, 0x3c, 0x2b, 0x1a,
+				0x0c, 0x00, 0x00, 0x00,
+			},
+			err: ErrInvalidBlock,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if _, err := NewNgReader(bytes.NewReader(tt.b)); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestTimestamp(t *testing.T) {
+	tests := []struct {
+		desc  string
+		ts    uint64
+		resol uint8
+		want  time.Time
+	}{
+		{
+			desc:  "microseconds",
+			ts:    1000002,
+			resol: 6,
+			want:  time.Unix(1, 2000),
+		},
+		{
+			desc:  "nanoseconds",
+			ts:    1000000002,
+			resol: 9,
+			want:  time.Unix(1, 2),
+		},
+		{
+			desc:  "picoseconds",
+			ts:    1000000002000,
+			resol: 12,
+			want:  time.Unix(1, 2),
+		},
+		{
+			desc:  "power of two",
+			ts:    3<<10 | 512,
+			resol: 0x80 | 10,
+			want:  time.Unix(3, 500000000),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if got := timestamp(tt.ts, tt.resol); !tt.want.Equal(got) {
+				t.Fatalf("unexpected timestamp: %v != %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// concat concatenates byte slices into a new slice.
+func concat(bs ...[]byte) []byte {
+	var out []byte
+	for _, b := range bs {
+		out = append(out, b...)
+	}
+
+	return out
+}
@@ -0,0 +1,264 @@
+// Package pppoe implements marshaling and unmarshaling of PPP over Ethernet
+// discovery and session stage packets, as described in RFC 2516.
+package pppoe
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// EtherTypes which indicate PPPoE discovery and session stage packets in a
+// Frame.
+const (
+	EtherTypeDiscovery = ethernet.EtherTypePPPoEDiscovery
+	EtherTypeSession   = ethernet.EtherTypePPPoESession
+)
+
+const (
+	// headerLen is the length of a PPPoE header.
+	headerLen = 6
+
+	// tagHeaderLen is the length of a tag's type and length fields.
+	tagHeaderLen = 4
+
+	// verType is the version and type of PPPoE packets, which are both 1.
+	verType = 0x11
+)
+
+var (
+	// ErrInvalidHeader is returned when a PPPoE header's version or type is
+	// not 1, or when a packet's payload is too long for its length field.
+	ErrInvalidHeader = errors.New("pppoe: invalid header")
+
+	// ErrInvalidCode is returned when a packet's code does not match its
+	// stage: session stage packets must use CodeSession, and discovery
+	// stage packets must not.
+	ErrInvalidCode = errors.New("pppoe: invalid code for stage")
+)
+
+// A Code is the type of a PPPoE packet.
+type Code uint8
+
+// Possible Code values.
+const (
+	CodeSession Code = 0x00
+	CodePADO    Code = 0x07
+	CodePADI    Code = 0x09
+	CodePADR    Code = 0x19
+	CodePADS    Code = 0x65
+	CodePADT    Code = 0xa7
+)
+
+// String returns the name of a Code.
+func (c Code) String() string {
+	switch c {
+	case CodeSession:
+		return "Session"
+	case CodePADO:
+		return "PADO"
+	case CodePADI:
+		return "PADI"
+	case CodePADR:
+		return "PADR"
+	case CodePADS:
+		return "PADS"
+	case CodePADT:
+		return "PADT"
+	default:
+		return fmt.Sprintf("Code(%d)", uint8(c))
+	}
+}
+
+// A TagType is the type of a discovery stage tag.
+type TagType uint16
+
+// Possible TagType values.
+const (
+	TagEndOfList        TagType = 0x0000
+	TagServiceName      TagType = 0x0101
+	TagACName           TagType = 0x0102
+	TagHostUniq         TagType = 0x0103
+	TagACCookie         TagType = 0x0104
+	TagVendorSpecific   TagType = 0x0105
+	TagRelaySessionID   TagType = 0x0110
+	TagServiceNameError TagType = 0x0201
+	TagACSystemError    TagType = 0x0202
+	TagGenericError     TagType = 0x0203
+)
+
+// A Tag is a discovery stage tag.
+type Tag struct {
+	Type  TagType
+	Value []byte
+}
+
+// A Packet is a PPPoE discovery or session stage packet.
+type Packet struct {
+	// Code specifies the type of the packet.  Session stage packets use
+	// CodeSession.
+	Code Code
+
+	// SessionID specifies the PPPoE session, and is zero until a session is
+	// established by a PADS packet.
+	SessionID uint16
+
+	// Tags specifies the tags of a discovery stage packet.  When
+	// unmarshaling, parsing stops at an End-Of-List tag, which is not
+	// included in Tags.
+	Tags []Tag
+
+	// Payload specifies the PPP protocol and data of a session stage
+	// packet.
+	Payload []byte
+}
+
+// Parse unmarshals the PPPoE packet carried in the payload of Frame f.  If
+// f's EtherType is not EtherTypeDiscovery or EtherTypeSession,
+// ethernet.ErrInvalidEtherType is returned.
+func Parse(f *ethernet.Frame) (*Packet, error) {
+	if f.EtherType != EtherTypeDiscovery && f.EtherType != EtherTypeSession {
+		return nil, ethernet.ErrInvalidEtherType
+	}
+
+	p := new(Packet)
+	if err := p.UnmarshalBinary(f.Payload); err != nil {
+		return nil, err
+	}
+
+	if (f.EtherType == EtherTypeSession) != (p.Code == CodeSession) {
+		return nil, ErrInvalidCode
+	}
+
+	return p, nil
+}
+
+// Frame marshals a Packet into the payload of an Ethernet frame sent from
+// hardware address src to hardware address dst.  The Frame's EtherType is
+// EtherTypeSession if the Packet's Code is CodeSession, and is otherwise
+// EtherTypeDiscovery.
+func (p *Packet) Frame(dst, src net.HardwareAddr) (*ethernet.Frame, error) {
+	b, err := p.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	et := EtherTypeDiscovery
+	if p.Code == CodeSession {
+		et = EtherTypeSession
+	}
+
+	return &ethernet.Frame{
+		Destination: dst,
+		Source:      src,
+		EtherType:   et,
+		Payload:     b,
+	}, nil
+}
+
+// Tag returns the value of the first tag of type t, and reports whether it
+// was found.
+func (p *Packet) Tag(t TagType) ([]byte, bool) {
+	for _, tag := range p.Tags {
+		if tag.Type == t {
+			return tag.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// MarshalBinary allocates a byte slice and marshals a Packet into binary
+// form.  Tags are marshaled for discovery stage packets and Payload for
+// session stage packets.
+func (p *Packet) MarshalBinary() ([]byte, error) {
+	b := make([]byte, headerLen)
+	b[0] = verType
+	b[1] = uint8(p.Code)
+	binary.BigEndian.PutUint16(b[2:4], p.SessionID)
+
+	if p.Code == CodeSession {
+		b = append(b, p.Payload...)
+	} else {
+		for _, tag := range p.Tags {
+			if len(tag.Value) > 0xffff {
+				return nil, ErrInvalidHeader
+			}
+
+			var h [tagHeaderLen]byte
+			binary.BigEndian.PutUint16(h[0:2], uint16(tag.Type))
+			binary.BigEndian.PutUint16(h[2:4], uint16(len(tag.Value)))
+
+			b = append(b, h[:]...)
+			b = append(b, tag.Value...)
+		}
+	}
+
+	n := len(b) - headerLen
+	if n > 0xffff {
+		return nil, ErrInvalidHeader
+	}
+	binary.BigEndian.PutUint16(b[4:6], uint16(n))
+
+	return b, nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into a Packet.  Bytes beyond the
+// header's length field, such as Ethernet padding, are ignored.
+func (p *Packet) UnmarshalBinary(b []byte) error {
+	if len(b) < headerLen {
+		return io.ErrUnexpectedEOF
+	}
+
+	if b[0] != verType {
+		return ErrInvalidHeader
+	}
+
+	n := int(binary.BigEndian.Uint16(b[4:6]))
+	if len(b[headerLen:]) < n {
+		return io.ErrUnexpectedEOF
+	}
+
+	out := Packet{
+		Code:      Code(b[1]),
+		SessionID: binary.BigEndian.Uint16(b[2:4]),
+	}
+
+	b = b[headerLen : headerLen+n]
+	if out.Code == CodeSession {
+		out.Payload = make([]byte, len(b))
+		copy(out.Payload, b)
+
+		*p = out
+		return nil
+	}
+
+	for len(b) > 0 {
+		if len(b) < tagHeaderLen {
+			return io.ErrUnexpectedEOF
+		}
+
+		t := TagType(binary.BigEndian.Uint16(b[0:2]))
+		l := int(binary.BigEndian.Uint16(b[2:4]))
+		if len(b[tagHeaderLen:]) < l {
+			return io.ErrUnexpectedEOF
+		}
+
+		if t == TagEndOfList {
+			break
+		}
+
+		v := make([]byte, l)
+		copy(v, b[tagHeaderLen:tagHeaderLen+l])
+		out.Tags = append(out.Tags, Tag{Type: t, Value: v})
+
+		b = b[tagHeaderLen+l:]
+	}
+
+	*p = out
+	return nil
+}
@@ -0,0 +1,205 @@
+package pppoe
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestPacketMarshalUnmarshal(t *testing.T) {
+	tests := []struct {
+		desc string
+		p    *Packet
+		b    []byte
+	}{
+		{
+			desc: "PADI",
+			p: &Packet{
+				Code: CodePADI,
+				Tags: []Tag{
+					{Type: TagServiceName, Value: []byte{}},
+					{Type: TagHostUniq, Value: []byte{0xde, 0xad, 0xbe, 0xef}},
+				},
+			},
+			b: []byte{
+				0x11, 0x09, 0x00, 0x00, 0x00, 0x0c,
+				0x01, 0x01, 0x00, 0x00,
+				0x01, 0x03, 0x00, 0x04, 0xde, 0xad, 0xbe, 0xef,
+			},
+		},
+		{
+			desc: "PADT",
+			p: &Packet{
+				Code:      CodePADT,
+				SessionID: 0x1234,
+			},
+			b: []byte{0x11, 0xa7, 0x12, 0x34, 0x00, 0x00},
+		},
+		{
+			desc: "session",
+			p: &Packet{
+				Code:      CodeSession,
+				SessionID: 0x1234,
+				Payload:   []byte{0xc0, 0x21, 0x09, 0x01, 0x00, 0x04},
+			},
+			b: []byte{
+				0x11, 0x00, 0x12, 0x34, 0x00, 0x06,
+				0xc0, 0x21, 0x09, 0x01, 0x00, 0x04,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := tt.p.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			if want, got := tt.b, b; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected bytes:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			// Trailing padding is ignored.
+			p := new(Packet)
+			if err := p.UnmarshalBinary(append(b, 0x00, 0x00)); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+
+			if !reflect.DeepEqual(tt.p, p) {
+				t.Fatalf("unexpected packet:\n- want: %#v\n-  got: %#v", tt.p, p)
+			}
+		})
+	}
+}
+
+func TestPacketEndOfList(t *testing.T) {
+	b := []byte{
+		0x11, 0x07, 0x00, 0x00, 0x00, 0x0d,
+		0x01, 0x02, 0x00, 0x01, 'a',
+		0x00, 0x00, 0x00, 0x00,
+		0x01, 0x01, 0x00, 0x00,
+	}
+
+	p := new(Packet)
+	if err := p.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	want := []Tag{{Type: TagACName, Value: []byte("a")}}
+	if !reflect.DeepEqual(want, p.Tags) {
+		t.Fatalf("unexpected tags:\n- want: %#v\n-  got: %#v", want, p.Tags)
+	}
+
+	if v, ok := p.Tag(TagACName); !ok || string(v) != "a" {
+		t.Fatalf("unexpected AC-Name tag: %q, %v", v, ok)
+	}
+	if _, ok := p.Tag(TagServiceName); ok {
+		t.Fatal("unexpected Service-Name tag")
+	}
+}
+
+func TestPacketUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		b    []byte
+		err  error
+	}{
+		{
+			desc: "short header",
+			b:    []byte{0x11, 0x09, 0x00, 0x00, 0x00},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "bad version",
+			b:    []byte{0x21, 0x09, 0x00, 0x00, 0x00, 0x00},
+			err:  ErrInvalidHeader,
+		},
+		{
+			desc: "short payload",
+			b:    []byte{0x11, 0x00, 0x00, 0x01, 0x00, 0x02, 0xc0},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "short tag header",
+			b:    []byte{0x11, 0x09, 0x00, 0x00, 0x00, 0x02, 0x01, 0x01},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "short tag value",
+			b:    []byte{0x11, 0x09, 0x00, 0x00, 0x00, 0x04, 0x01, 0x01, 0x00, 0x01},
+			err:  io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := new(Packet).UnmarshalBinary(tt.b); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestFrameParse(t *testing.T) {
+	src := net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}
+
+	tests := []struct {
+		desc string
+		p    *Packet
+		et   ethernet.EtherType
+	}{
+		{
+			desc: "discovery",
+			p:    &Packet{Code: CodePADI},
+			et:   EtherTypeDiscovery,
+		},
+		{
+			desc: "session",
+			p: &Packet{
+				Code:      CodeSession,
+				SessionID: 1,
+				Payload:   []byte{0x00, 0x21},
+			},
+			et: EtherTypeSession,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			f, err := tt.p.Frame(ethernet.Broadcast, src)
+			if err != nil {
+				t.Fatalf("failed to create frame: %v", err)
+			}
+
+			if want, got := tt.et, f.EtherType; want != got {
+				t.Fatalf("unexpected EtherType: %v != %v", want, got)
+			}
+
+			p, err := Parse(f)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+
+			if !reflect.DeepEqual(tt.p, p) {
+				t.Fatalf("unexpected packet:\n- want: %#v\n-  got: %#v", tt.p, p)
+			}
+		})
+	}
+
+	f := &ethernet.Frame{
+		EtherType: EtherTypeSession,
+		Payload:   []byte{0x11, 0x09, 0x00, 0x00, 0x00, 0x00},
+	}
+	if _, err := Parse(f); err != ErrInvalidCode {
+		t.Fatalf("expected ErrInvalidCode, but got: %v", err)
+	}
+
+	if _, err := Parse(&ethernet.Frame{EtherType: ethernet.EtherTypeIPv4}); err != ethernet.ErrInvalidEtherType {
+		t.Fatalf("expected ErrInvalidEtherType, but got: %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package probe
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// readBufferSize is the size of the buffer used to read probe frames.
+const readBufferSize = 1514
+
+// errInvalidAddress is returned when a Sender is configured without valid
+// hardware addresses.
+var errInvalidAddress = errors.New("probe: source and destination must be 6 byte hardware addresses")
+
+// A TimestampConn is a net.PacketConn which reports a receive timestamp,
+// such as a hardware timestamp, for each frame.  When a Sender or Reflector
+// uses a TimestampConn, its timestamps are used in place of the system
+// clock when frames are received.
+type TimestampConn interface {
+	net.PacketConn
+	ReadFromTimestamp(b []byte) (int, net.Addr, time.Time, error)
+}
+
+// A Result is the outcome of a single probe.
+type Result struct {
+	// Sequence is the sequence number of the probe.
+	Sequence uint32
+
+	// RoundTrip is the round-trip time of the probe, excluding the time
+	// spent within the reflector.
+	RoundTrip time.Duration
+
+	// Forward and Reverse are the one-way latencies from the sender to the
+	// reflector and back.  They are only meaningful when the clocks of the
+	// sender and reflector are synchronized, for example using PTP.
+	Forward, Reverse time.Duration
+}
+
+// SenderConfig specifies configuration for a Sender.
+type SenderConfig struct {
+	// Source and Destination are the hardware addresses of the sender and
+	// the reflector.
+	Source, Destination net.HardwareAddr
+
+	// Addr specifies the address passed to WriteTo for each probe.
+	Addr net.Addr
+}
+
+// A Sender sends probes to a Reflector and measures their latency.  A Sender
+// is not safe for concurrent use.
+type Sender struct {
+	c   net.PacketConn
+	cfg SenderConfig
+	seq uint32
+	b   []byte
+
+	// now allows tests to control the passage of time.
+	now func() time.Time
+}
+
+// NewSender creates a Sender which sends probes using c.
+func NewSender(c net.PacketConn, cfg SenderConfig) (*Sender, error) {
+	if len(cfg.Source) != 6 || len(cfg.Destination) != 6 {
+		return nil, errInvalidAddress
+	}
+
+	return &Sender{
+		c:   c,
+		cfg: cfg,
+		b:   make([]byte, readBufferSize),
+		now: time.Now,
+	}, nil
+}
+
+// Probe sends a single probe and waits up to timeout for its reply.  Frames
+// which are not replies to the probe are discarded.  If no reply arrives in
+// time, the error from the underlying net.PacketConn is returned.
+func (s *Sender) Probe(timeout time.Duration) (Result, error) {
+	s.seq++
+	seq := s.seq
+
+	sent := s.now()
+	if err := s.c.SetReadDeadline(sent.Add(timeout)); err != nil {
+		return Result{}, err
+	}
+
+	b, err := marshalFrame(s.cfg.Destination, s.cfg.Source, &Probe{
+		Sequence: seq,
+		Sent:     sent,
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	if _, err := s.c.WriteTo(b, s.cfg.Addr); err != nil {
+		return Result{}, err
+	}
+
+	for {
+		n, _, ts, err := readFrom(s.c, s.b, s.now)
+		if err != nil {
+			return Result{}, err
+		}
+
+		_, p, ok := parseFrame(s.b[:n])
+		if !ok || !p.Reply || p.Sequence != seq {
+			continue
+		}
+
+		var (
+			forward = p.Received.Sub(p.Sent)
+			reverse = ts.Sub(p.Reflected)
+		)
+
+		return Result{
+			Sequence:  seq,
+			RoundTrip: forward + reverse,
+			Forward:   forward,
+			Reverse:   reverse,
+		}, nil
+	}
+}
+
+// A Reflector returns probes to their senders.
+type Reflector struct {
+	c net.PacketConn
+	b []byte
+
+	// now allows tests to control the passage of time.
+	now func() time.Time
+}
+
+// NewReflector creates a Reflector which reflects probes received on c.
+func NewReflector(c net.PacketConn) *Reflector {
+	return &Reflector{
+		c:   c,
+		b:   make([]byte, readBufferSize),
+		now: time.Now,
+	}
+}
+
+// Serve reflects probes until reading from or writing to the underlying
+// net.PacketConn fails, and returns that error.  Frames which are not probe
+// requests are discarded.
+func (r *Reflector) Serve() error {
+	for {
+		n, addr, ts, err := readFrom(r.c, r.b, r.now)
+		if err != nil {
+			return err
+		}
+
+		f, p, ok := parseFrame(r.b[:n])
+		if !ok || p.Reply {
+			continue
+		}
+
+		p.Reply = true
+		p.Received = ts
+		p.Reflected = r.now()
+
+		b, err := marshalFrame(f.Source, f.Destination, p)
+		if err != nil {
+			return err
+		}
+
+		if _, err := r.c.WriteTo(b, addr); err != nil {
+			return err
+		}
+	}
+}
+
+// readFrom reads a frame from c, along with its receive timestamp.
+func readFrom(c net.PacketConn, b []byte, now func() time.Time) (int, net.Addr, time.Time, error) {
+	if tc, ok := c.(TimestampConn); ok {
+		return tc.ReadFromTimestamp(b)
+	}
+
+	n, addr, err := c.ReadFrom(b)
+	return n, addr, now(), err
+}
+
+// marshalFrame marshals p into a probe frame.
+func marshalFrame(dst, src net.HardwareAddr, p *Probe) ([]byte, error) {
+	pb, err := p.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	f := &ethernet.Frame{
+		Destination: dst,
+		Source:      src,
+		EtherType:   EtherType,
+		Payload:     pb,
+	}
+
+	return f.MarshalBinary()
+}
+
+// parseFrame parses b as a probe frame, reporting whether it is valid.
+func parseFrame(b []byte) (*ethernet.Frame, *Probe, bool) {
+	var f ethernet.Frame
+	if err := f.UnmarshalBinary(b); err != nil || f.EtherType != EtherType {
+		return nil, nil, false
+	}
+
+	var p Probe
+	if err := p.UnmarshalBinary(f.Payload); err != nil {
+		return nil, nil, false
+	}
+
+	return &f, &p, true
+}
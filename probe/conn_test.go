@@ -0,0 +1,174 @@
+package probe
+
+import (
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+var (
+	senderMAC    = net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0x00, 0x01}
+	reflectorMAC = net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0x00, 0x02}
+)
+
+func TestNewSenderInvalidAddress(t *testing.T) {
+	_, err := NewSender(nil, SenderConfig{Source: senderMAC})
+	if err != errInvalidAddress {
+		t.Fatalf("unexpected error: %v != %v", errInvalidAddress, err)
+	}
+}
+
+func TestSenderReflector(t *testing.T) {
+	sc, rc := newPipe()
+
+	// The reflector uses receive timestamps 3ms after the sender's clock and
+	// reflects 1ms later, while the reply arrives 2ms after that.
+	t0 := time.Now()
+	rc.ts = t0.Add(3 * time.Millisecond)
+	r := NewReflector(rc)
+	r.now = func() time.Time { return rc.ts.Add(time.Millisecond) }
+	sc.ts = t0.Add(6 * time.Millisecond)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := r.Serve(); !errors.Is(err, net.ErrClosed) {
+			panic(err)
+		}
+	}()
+	defer func() {
+		_ = rc.Close()
+		wg.Wait()
+	}()
+
+	s, err := NewSender(sc, SenderConfig{
+		Source:      senderMAC,
+		Destination: reflectorMAC,
+	})
+	if err != nil {
+		t.Fatalf("failed to create sender: %v", err)
+	}
+	s.now = func() time.Time { return t0 }
+
+	// Unrelated traffic must be ignored by both sides.
+	junk := &ethernet.Frame{
+		Destination: reflectorMAC,
+		Source:      senderMAC,
+		EtherType:   ethernet.EtherTypeIPv4,
+		Payload:     make([]byte, 46),
+	}
+	jb, err := junk.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal frame: %v", err)
+	}
+	rc.in <- jb
+
+	for seq := uint32(1); seq <= 2; seq++ {
+		res, err := s.Probe(time.Second)
+		if err != nil {
+			t.Fatalf("failed to probe: %v", err)
+		}
+
+		want := Result{
+			Sequence:  seq,
+			RoundTrip: 5 * time.Millisecond,
+			Forward:   3 * time.Millisecond,
+			Reverse:   2 * time.Millisecond,
+		}
+		if got := res; want != got {
+			t.Fatalf("unexpected result:\n- want: %+v\n-  got: %+v", want, got)
+		}
+	}
+}
+
+func TestSenderTimeout(t *testing.T) {
+	sc, _ := newPipe()
+
+	s, err := NewSender(sc, SenderConfig{
+		Source:      senderMAC,
+		Destination: reflectorMAC,
+	})
+	if err != nil {
+		t.Fatalf("failed to create sender: %v", err)
+	}
+
+	if _, err := s.Probe(10 * time.Millisecond); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("unexpected error: %v != %v", os.ErrDeadlineExceeded, err)
+	}
+}
+
+// newPipe creates a connected pair of pipeConns.
+func newPipe() (*pipeConn, *pipeConn) {
+	var (
+		a = make(chan []byte, 16)
+		b = make(chan []byte, 16)
+	)
+
+	return &pipeConn{in: a, out: b, done: make(chan struct{})},
+		&pipeConn{in: b, out: a, done: make(chan struct{})}
+}
+
+// A pipeConn is an in-memory TimestampConn which reports a fixed receive
+// timestamp.
+type pipeConn struct {
+	net.PacketConn
+
+	in, out chan []byte
+	ts      time.Time
+
+	mu       sync.Mutex
+	deadline time.Time
+	done     chan struct{}
+	once     sync.Once
+}
+
+func (c *pipeConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	n, addr, _, err := c.ReadFromTimestamp(b)
+	return n, addr, err
+}
+
+func (c *pipeConn) ReadFromTimestamp(b []byte) (int, net.Addr, time.Time, error) {
+	c.mu.Lock()
+	deadline := c.deadline
+	c.mu.Unlock()
+
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case p := <-c.in:
+		return copy(b, p), nil, c.ts, nil
+	case <-timeout:
+		return 0, nil, time.Time{}, os.ErrDeadlineExceeded
+	case <-c.done:
+		return 0, nil, time.Time{}, net.ErrClosed
+	}
+}
+
+func (c *pipeConn) WriteTo(b []byte, _ net.Addr) (int, error) {
+	c.out <- append([]byte(nil), b...)
+	return len(b), nil
+}
+
+func (c *pipeConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.deadline = t
+	return nil
+}
+
+func (c *pipeConn) Close() error {
+	c.once.Do(func() { close(c.done) })
+	return nil
+}
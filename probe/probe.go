@@ -0,0 +1,112 @@
+// Package probe implements a simple Layer 2 latency probe protocol, with
+// sender and reflector components, for measuring round-trip and one-way
+// latency and jitter between Ethernet hosts.
+package probe
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// EtherType is the EtherType used for probe frames: IEEE 802 Local
+// Experimental EtherType 1.
+const EtherType ethernet.EtherType = 0x88b5
+
+const (
+	// magic identifies a probe payload.
+	magic = 0x45505242 // "EPRB"
+
+	// version is the current version of the probe protocol.
+	version = 1
+
+	// probeLen is the length of a marshaled Probe.
+	probeLen = 4 + 1 + 1 + 2 + 4 + 3*8
+
+	// flagReply indicates that a Probe is a reply from a reflector.
+	flagReply = 1 << 0
+)
+
+// errInvalidProbe is returned when a payload is not a valid Probe.
+var errInvalidProbe = errors.New("probe: invalid probe payload")
+
+// A Probe is the payload of a probe frame.
+//
+// A sender sets Sequence and Sent.  A reflector sets Reply, Received, and
+// Reflected, and returns the Probe to the sender.
+type Probe struct {
+	// Sequence identifies a Probe within a session.
+	Sequence uint32
+
+	// Reply indicates whether the Probe is a reply from a reflector.
+	Reply bool
+
+	// Sent is the time at which the sender transmitted the Probe.
+	Sent time.Time
+
+	// Received and Reflected are the times at which the reflector received
+	// and transmitted the Probe, respectively.
+	Received, Reflected time.Time
+}
+
+// MarshalBinary allocates a byte slice containing the data from a Probe.
+func (p *Probe) MarshalBinary() ([]byte, error) {
+	b := make([]byte, probeLen)
+
+	binary.BigEndian.PutUint32(b[0:4], magic)
+	b[4] = version
+	if p.Reply {
+		b[5] |= flagReply
+	}
+	binary.BigEndian.PutUint32(b[8:12], p.Sequence)
+	putTime(b[12:20], p.Sent)
+	putTime(b[20:28], p.Received)
+	putTime(b[28:36], p.Reflected)
+
+	return b, nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into a Probe.  Trailing bytes,
+// such as frame padding, are ignored.
+func (p *Probe) UnmarshalBinary(b []byte) error {
+	if len(b) < probeLen {
+		return io.ErrUnexpectedEOF
+	}
+	if binary.BigEndian.Uint32(b[0:4]) != magic || b[4] != version {
+		return errInvalidProbe
+	}
+
+	*p = Probe{
+		Sequence:  binary.BigEndian.Uint32(b[8:12]),
+		Reply:     b[5]&flagReply != 0,
+		Sent:      getTime(b[12:20]),
+		Received:  getTime(b[20:28]),
+		Reflected: getTime(b[28:36]),
+	}
+
+	return nil
+}
+
+// putTime stores t in b as nanoseconds since the Unix epoch.  The zero Time
+// is stored as zero.
+func putTime(b []byte, t time.Time) {
+	var n int64
+	if !t.IsZero() {
+		n = t.UnixNano()
+	}
+
+	binary.BigEndian.PutUint64(b, uint64(n))
+}
+
+// getTime retrieves a Time stored by putTime.
+func getTime(b []byte) time.Time {
+	n := int64(binary.BigEndian.Uint64(b))
+	if n == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, n)
+}
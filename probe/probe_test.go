@@ -0,0 +1,97 @@
+package probe
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestProbeMarshalUnmarshalBinary(t *testing.T) {
+	tests := []struct {
+		desc string
+		p    *Probe
+	}{
+		{
+			desc: "request",
+			p: &Probe{
+				Sequence: 1,
+				Sent:     time.Unix(1, 100),
+			},
+		},
+		{
+			desc: "reply",
+			p: &Probe{
+				Sequence:  0xffffffff,
+				Reply:     true,
+				Sent:      time.Unix(1, 100),
+				Received:  time.Unix(2, 200),
+				Reflected: time.Unix(3, 300),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := tt.p.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			// Frame padding must be ignored.
+			b = append(b, make([]byte, 10)...)
+
+			var p Probe
+			if err := p.UnmarshalBinary(b); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+
+			if want, got := tt.p, &p; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected probe:\n- want: %+v\n-  got: %+v", want, got)
+			}
+		})
+	}
+}
+
+func TestProbeUnmarshalBinaryErrors(t *testing.T) {
+	valid, err := (&Probe{}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	badMagic := append([]byte(nil), valid...)
+	badMagic[0] = 0
+
+	badVersion := append([]byte(nil), valid...)
+	badVersion[4] = 2
+
+	tests := []struct {
+		desc string
+		b    []byte
+		err  error
+	}{
+		{
+			desc: "short",
+			b:    valid[:probeLen-1],
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "bad magic",
+			b:    badMagic,
+			err:  errInvalidProbe,
+		},
+		{
+			desc: "bad version",
+			b:    badVersion,
+			err:  errInvalidProbe,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := (&Probe{}).UnmarshalBinary(tt.b); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package probe
+
+import (
+	"sort"
+	"time"
+)
+
+// A Summary contains statistics computed from a series of latency samples.
+type Summary struct {
+	// Count is the number of samples.
+	Count int
+
+	// Min, Max, and Mean are the minimum, maximum, and arithmetic mean of
+	// the samples.
+	Min, Max, Mean time.Duration
+
+	// Jitter is the mean absolute difference between consecutive samples,
+	// as in IP packet delay variation.
+	Jitter time.Duration
+
+	// P50, P90, and P99 are the 50th, 90th, and 99th percentile samples.
+	P50, P90, P99 time.Duration
+}
+
+// Summarize computes a Summary from samples, which must be in the order they
+// were measured.
+func Summarize(samples []time.Duration) Summary {
+	s := Summary{Count: len(samples)}
+	if len(samples) == 0 {
+		return s
+	}
+
+	sorted := sortDurations(samples)
+	s.Min = sorted[0]
+	s.Max = sorted[len(sorted)-1]
+	s.P50 = percentile(sorted, 50)
+	s.P90 = percentile(sorted, 90)
+	s.P99 = percentile(sorted, 99)
+
+	var sum, jitter time.Duration
+	for i, d := range samples {
+		sum += d
+
+		if i > 0 {
+			diff := d - samples[i-1]
+			if diff < 0 {
+				diff = -diff
+			}
+			jitter += diff
+		}
+	}
+
+	s.Mean = sum / time.Duration(len(samples))
+	if len(samples) > 1 {
+		s.Jitter = jitter / time.Duration(len(samples)-1)
+	}
+
+	return s
+}
+
+// Percentile returns the p-th percentile of samples, using the nearest-rank
+// method.  p is clamped to the range 0 to 100.  If samples is empty,
+// Percentile returns 0.
+func Percentile(samples []time.Duration, p float64) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	return percentile(sortDurations(samples), p)
+}
+
+// percentile returns the p-th percentile of the sorted, non-empty samples.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	switch {
+	case p <= 0:
+		return sorted[0]
+	case p >= 100:
+		return sorted[len(sorted)-1]
+	}
+
+	// Nearest rank: ceil(p/100 * n), 1-indexed.
+	rank := int(p / 100 * float64(len(sorted)))
+	if float64(rank) < p/100*float64(len(sorted)) {
+		rank++
+	}
+
+	return sorted[rank-1]
+}
+
+// sortDurations returns a sorted copy of ds.
+func sortDurations(ds []time.Duration) []time.Duration {
+	sorted := make([]time.Duration, len(ds))
+	copy(sorted, ds)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	return sorted
+}
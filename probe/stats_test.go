@@ -0,0 +1,86 @@
+package probe
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	ms := func(n int) time.Duration { return time.Duration(n) * time.Millisecond }
+
+	tests := []struct {
+		desc    string
+		samples []time.Duration
+		s       Summary
+	}{
+		{
+			desc: "empty",
+		},
+		{
+			desc:    "one",
+			samples: []time.Duration{ms(5)},
+			s: Summary{
+				Count: 1,
+				Min:   ms(5),
+				Max:   ms(5),
+				Mean:  ms(5),
+				P50:   ms(5),
+				P90:   ms(5),
+				P99:   ms(5),
+			},
+		},
+		{
+			desc: "ten",
+			samples: []time.Duration{
+				ms(10), ms(1), ms(9), ms(2), ms(8),
+				ms(3), ms(7), ms(4), ms(6), ms(5),
+			},
+			s: Summary{
+				Count: 10,
+				Min:   ms(1),
+				Max:   ms(10),
+				Mean:  ms(55) / 10,
+				// |Δ|: 9, 8, 7, 6, 5, 4, 3, 2, 1.
+				Jitter: ms(45) / 9,
+				P50:    ms(5),
+				P90:    ms(9),
+				P99:    ms(10),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if want, got := tt.s, Summarize(tt.samples); want != got {
+				t.Fatalf("unexpected summary:\n- want: %+v\n-  got: %+v", want, got)
+			}
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	samples := []time.Duration{4, 1, 3, 2}
+
+	tests := []struct {
+		p float64
+		d time.Duration
+	}{
+		{p: -1, d: 1},
+		{p: 0, d: 1},
+		{p: 25, d: 1},
+		{p: 26, d: 2},
+		{p: 75, d: 3},
+		{p: 100, d: 4},
+		{p: 200, d: 4},
+	}
+
+	for _, tt := range tests {
+		if want, got := tt.d, Percentile(samples, tt.p); want != got {
+			t.Fatalf("unexpected percentile %v: %v != %v", tt.p, want, got)
+		}
+	}
+
+	if want, got := time.Duration(0), Percentile(nil, 50); want != got {
+		t.Fatalf("unexpected empty percentile: %v != %v", want, got)
+	}
+}
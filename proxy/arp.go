@@ -0,0 +1,55 @@
+package proxy
+
+import (
+	"encoding/binary"
+	"net"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// ARP constants for Ethernet and IPv4, as defined in RFC 826.
+const (
+	arpLen           = 28
+	arpHardwareEther = 1
+	arpOpRequest     = 1
+	arpOpReply       = 2
+)
+
+// arpReply returns a reply to ARP request f, if f requests the address of
+// a protected host.
+func (p *Proxy) arpReply(f *ethernet.Frame) *ethernet.Frame {
+	b := f.Payload
+	if len(b) < arpLen ||
+		binary.BigEndian.Uint16(b[0:2]) != arpHardwareEther ||
+		binary.BigEndian.Uint16(b[2:4]) != uint16(ethernet.EtherTypeIPv4) ||
+		b[4] != 6 || b[5] != 4 ||
+		binary.BigEndian.Uint16(b[6:8]) != arpOpRequest {
+		return nil
+	}
+
+	var (
+		sha = b[8:14]
+		spa = b[14:18]
+		tpa = b[24:28]
+	)
+
+	mac, ok := p.lookup(net.IP(tpa))
+	if !ok {
+		return nil
+	}
+
+	out := make([]byte, arpLen)
+	copy(out[0:6], b[0:6])
+	binary.BigEndian.PutUint16(out[6:8], arpOpReply)
+	copy(out[8:14], mac)
+	copy(out[14:18], tpa)
+	copy(out[18:24], sha)
+	copy(out[24:28], spa)
+
+	return &ethernet.Frame{
+		Destination: append(net.HardwareAddr(nil), sha...),
+		Source:      mac,
+		EtherType:   ethernet.EtherTypeARP,
+		Payload:     out,
+	}
+}
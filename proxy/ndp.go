@@ -0,0 +1,130 @@
+package proxy
+
+import (
+	"encoding/binary"
+	"net"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// IPv6 and ICMPv6 Neighbor Discovery constants, as defined in RFC 8200 and
+// RFC 4861.
+const (
+	ipv6HeaderLen = 40
+	ipv6HopLimit  = 255
+	protoICMPv6   = 58
+
+	icmpNeighborSolicitation  = 135
+	icmpNeighborAdvertisement = 136
+
+	// nsLen is the length of a Neighbor Solicitation without options, and
+	// naLen is the length of a Neighbor Advertisement with a target
+	// link-layer address option.
+	nsLen = 24
+	naLen = 24 + 8
+
+	optTargetLinkLayerAddr = 2
+
+	flagSolicited = 0x40
+	flagOverride  = 0x20
+)
+
+// allNodes is the IPv6 link-local all nodes multicast address, and
+// allNodesMAC is its corresponding Ethernet multicast address.
+var (
+	allNodes    = net.ParseIP("ff02::1")
+	allNodesMAC = net.HardwareAddr{0x33, 0x33, 0x00, 0x00, 0x00, 0x01}
+)
+
+// ndpReply returns a Neighbor Advertisement in reply to f, if f is a
+// Neighbor Solicitation for the address of a protected host.
+func (p *Proxy) ndpReply(f *ethernet.Frame) *ethernet.Frame {
+	b := f.Payload
+	if len(b) < ipv6HeaderLen+nsLen || b[0]>>4 != 6 {
+		return nil
+	}
+
+	// Only ICMPv6 directly following the IPv6 header is handled; Neighbor
+	// Discovery messages never carry extension headers in practice.
+	plen := int(binary.BigEndian.Uint16(b[4:6]))
+	if b[6] != protoICMPv6 || b[7] != ipv6HopLimit || plen < nsLen || ipv6HeaderLen+plen > len(b) {
+		return nil
+	}
+
+	icmp := b[ipv6HeaderLen : ipv6HeaderLen+plen]
+	if icmp[0] != icmpNeighborSolicitation || icmp[1] != 0 {
+		return nil
+	}
+
+	var (
+		src    = net.IP(b[8:24])
+		target = net.IP(icmp[8:24])
+	)
+
+	mac, ok := p.lookup(target)
+	if !ok {
+		return nil
+	}
+
+	// Solicitations from the unspecified address, as used in duplicate
+	// address detection, are answered to all nodes and are unsolicited.
+	dst, dstMAC, flags := src, f.Source, byte(flagSolicited|flagOverride)
+	if src.IsUnspecified() {
+		dst, dstMAC, flags = allNodes, allNodesMAC, flagOverride
+	}
+
+	out := make([]byte, ipv6HeaderLen+naLen)
+	out[0] = 6 << 4
+	binary.BigEndian.PutUint16(out[4:6], naLen)
+	out[6] = protoICMPv6
+	out[7] = ipv6HopLimit
+	copy(out[8:24], target.To16())
+	copy(out[24:40], dst.To16())
+
+	na := out[ipv6HeaderLen:]
+	na[0] = icmpNeighborAdvertisement
+	na[4] = flags
+	copy(na[8:24], target.To16())
+	na[24] = optTargetLinkLayerAddr
+	na[25] = 1
+	copy(na[26:32], mac)
+
+	binary.BigEndian.PutUint16(na[2:4], icmpChecksum(out[8:24], out[24:40], na))
+
+	return &ethernet.Frame{
+		Destination: append(net.HardwareAddr(nil), dstMAC...),
+		Source:      mac,
+		EtherType:   ethernet.EtherTypeIPv6,
+		Payload:     out,
+	}
+}
+
+// icmpChecksum computes the ICMPv6 checksum of msg, including the IPv6
+// pseudo-header, as defined in RFC 4443.  The checksum field of msg must be
+// zero.
+func icmpChecksum(src, dst, msg []byte) uint16 {
+	var sum uint32
+	add := func(b []byte) {
+		for i := 0; i+1 < len(b); i += 2 {
+			sum += uint32(binary.BigEndian.Uint16(b[i : i+2]))
+		}
+		if len(b)%2 == 1 {
+			sum += uint32(b[len(b)-1]) << 8
+		}
+	}
+
+	var pseudo [8]byte
+	binary.BigEndian.PutUint32(pseudo[0:4], uint32(len(msg)))
+	pseudo[7] = protoICMPv6
+
+	add(src)
+	add(dst)
+	add(pseudo[:])
+	add(msg)
+
+	for sum > 0xffff {
+		sum = sum>>16 + sum&0xffff
+	}
+
+	return ^uint16(sum)
+}
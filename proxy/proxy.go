@@ -0,0 +1,158 @@
+// Package proxy implements ARP and IPv6 Neighbor Discovery proxies, which
+// answer address resolution requests on behalf of other hosts.
+//
+// A proxy is a building block for Layer 2 gateways and virtual machine
+// migration tooling, where a host's addresses must remain reachable even
+// though the host itself cannot answer.
+package proxy
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// A Host is a protected host, on whose behalf a Proxy answers requests.
+type Host struct {
+	// IP is an IPv4 or IPv6 address of the host.
+	IP net.IP
+
+	// HardwareAddr is the hardware address returned in replies for IP.
+	HardwareAddr net.HardwareAddr
+}
+
+// Config specifies configuration for a Proxy.
+type Config struct {
+	// Hosts specifies the initial set of protected hosts.
+	Hosts []Host
+
+	// NDP enables answering IPv6 Neighbor Solicitations in addition to ARP
+	// requests.
+	NDP bool
+}
+
+// A Proxy is a net.PacketConn which answers ARP requests, and optionally
+// IPv6 Neighbor Solicitations, for protected hosts.  Answered requests are
+// consumed and not returned by ReadFrom; all other frames pass through
+// unmodified.
+type Proxy struct {
+	// Atomics must come first.
+	arp, ndp uint64
+
+	net.PacketConn
+	ndpEnabled bool
+
+	mu    sync.RWMutex
+	hosts map[string]net.HardwareAddr
+}
+
+// Stats contains the number of requests answered by a Proxy.
+type Stats struct {
+	ARP, NDP uint64
+}
+
+// New wraps c with a Proxy using the input configuration.  If cfg is nil,
+// no hosts are protected until Update is called.
+func New(c net.PacketConn, cfg *Config) *Proxy {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+
+	p := &Proxy{
+		PacketConn: c,
+		ndpEnabled: cfg.NDP,
+	}
+	p.Update(cfg.Hosts)
+
+	return p
+}
+
+// Update atomically replaces the set of protected hosts.
+func (p *Proxy) Update(hosts []Host) {
+	m := make(map[string]net.HardwareAddr, len(hosts))
+	for _, h := range hosts {
+		if ip := h.IP.To16(); ip != nil {
+			m[string(ip)] = h.HardwareAddr
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.hosts = m
+}
+
+// ReadFrom implements net.PacketConn.  ReadFrom answers requests for
+// protected hosts and continues reading until a frame which was not answered
+// is received or an error occurs.
+func (p *Proxy) ReadFrom(b []byte) (int, net.Addr, error) {
+	for {
+		n, addr, err := p.PacketConn.ReadFrom(b)
+		if err != nil {
+			return n, addr, err
+		}
+
+		reply, ok := p.answer(b[:n])
+		if !ok {
+			return n, addr, nil
+		}
+
+		if _, err := p.PacketConn.WriteTo(reply, addr); err != nil {
+			return 0, addr, err
+		}
+	}
+}
+
+// Stats returns the number of requests answered by a Proxy.
+func (p *Proxy) Stats() Stats {
+	return Stats{
+		ARP: atomic.LoadUint64(&p.arp),
+		NDP: atomic.LoadUint64(&p.ndp),
+	}
+}
+
+// lookup returns the hardware address of a protected host with address ip.
+func (p *Proxy) lookup(ip net.IP) (net.HardwareAddr, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	mac, ok := p.hosts[string(ip.To16())]
+	return mac, ok
+}
+
+// answer returns a reply to frame b, if b is a request for a protected host.
+func (p *Proxy) answer(b []byte) ([]byte, bool) {
+	var f ethernet.Frame
+	if err := f.UnmarshalBinary(b); err != nil {
+		return nil, false
+	}
+
+	var (
+		reply *ethernet.Frame
+		count *uint64
+	)
+
+	switch {
+	case f.EtherType == ethernet.EtherTypeARP:
+		reply, count = p.arpReply(&f), &p.arp
+	case f.EtherType == ethernet.EtherTypeIPv6 && p.ndpEnabled:
+		reply, count = p.ndpReply(&f), &p.ndp
+	}
+	if reply == nil {
+		return nil, false
+	}
+
+	// Replies use the same VLAN tags as their requests.
+	reply.ServiceVLAN = f.ServiceVLAN
+	reply.VLAN = f.VLAN
+
+	out, err := reply.MarshalBinary()
+	if err != nil {
+		return nil, false
+	}
+
+	atomic.AddUint64(count, 1)
+	return out, true
+}
@@ -0,0 +1,263 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/mdlayher/ethernet"
+)
+
+var (
+	hostMAC   = net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0x00, 0x01}
+	clientMAC = net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0x00, 0x02}
+
+	hostIPv4   = net.IPv4(192, 0, 2, 1)
+	clientIPv4 = net.IPv4(192, 0, 2, 2)
+	otherIPv4  = net.IPv4(192, 0, 2, 3)
+
+	hostIPv6   = net.ParseIP("2001:db8::1")
+	clientIPv6 = net.ParseIP("fe80::2")
+)
+
+func TestProxyARP(t *testing.T) {
+	vlan := &ethernet.VLAN{ID: 10}
+
+	other := arpRequest(t, vlan, otherIPv4)
+	c := &testConn{reads: [][]byte{
+		other,
+		arpRequest(t, vlan, hostIPv4),
+	}}
+
+	p := New(c, &Config{
+		Hosts: []Host{{IP: hostIPv4, HardwareAddr: hostMAC}},
+	})
+
+	// The request for an unprotected address passes through.
+	buf := make([]byte, 128)
+	n, _, err := p.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("failed to read: %v", err)
+	}
+	if want, got := other, buf[:n]; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected frame:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	// The request for a protected address is answered and consumed.
+	if _, _, err := p.ReadFrom(buf); err != io.EOF {
+		t.Fatalf("unexpected error: %v != %v", io.EOF, err)
+	}
+
+	if want, got := 1, len(c.writes); want != got {
+		t.Fatalf("unexpected number of replies: %v != %v", want, got)
+	}
+
+	var f ethernet.Frame
+	if err := f.UnmarshalBinary(c.writes[0]); err != nil {
+		t.Fatalf("failed to unmarshal reply: %v", err)
+	}
+
+	if !bytes.Equal(f.Destination, clientMAC) || !bytes.Equal(f.Source, hostMAC) {
+		t.Fatalf("unexpected reply addresses: %s -> %s", f.Source, f.Destination)
+	}
+	if f.VLAN == nil || f.VLAN.ID != 10 {
+		t.Fatalf("reply does not preserve VLAN: %+v", f.VLAN)
+	}
+
+	b := f.Payload
+	if want, got := uint16(arpOpReply), binary.BigEndian.Uint16(b[6:8]); want != got {
+		t.Fatalf("unexpected ARP operation: %v != %v", want, got)
+	}
+	if !bytes.Equal(b[8:14], hostMAC) || !net.IP(b[14:18]).Equal(hostIPv4) ||
+		!bytes.Equal(b[18:24], clientMAC) || !net.IP(b[24:28]).Equal(clientIPv4) {
+		t.Fatalf("unexpected ARP reply: %v", b[:arpLen])
+	}
+
+	if want, got := (Stats{ARP: 1}), p.Stats(); want != got {
+		t.Fatalf("unexpected stats: %+v != %+v", want, got)
+	}
+}
+
+func TestProxyNDP(t *testing.T) {
+	tests := []struct {
+		desc   string
+		src    net.IP
+		ndp    bool
+		dst    net.IP
+		dstMAC net.HardwareAddr
+		flags  byte
+	}{
+		{
+			desc: "disabled",
+			src:  clientIPv6,
+		},
+		{
+			desc:   "solicited",
+			src:    clientIPv6,
+			ndp:    true,
+			dst:    clientIPv6,
+			dstMAC: clientMAC,
+			flags:  flagSolicited | flagOverride,
+		},
+		{
+			desc:   "duplicate address detection",
+			src:    net.IPv6unspecified,
+			ndp:    true,
+			dst:    allNodes,
+			dstMAC: allNodesMAC,
+			flags:  flagOverride,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			c := &testConn{reads: [][]byte{neighborSolicitation(t, tt.src, hostIPv6)}}
+			p := New(c, &Config{
+				Hosts: []Host{{IP: hostIPv6, HardwareAddr: hostMAC}},
+				NDP:   tt.ndp,
+			})
+
+			_, _, err := p.ReadFrom(make([]byte, 128))
+			if !tt.ndp {
+				if err != nil {
+					t.Fatalf("expected solicitation to pass through, but got: %v", err)
+				}
+				if len(c.writes) != 0 {
+					t.Fatal("expected no replies")
+				}
+				return
+			}
+			if err != io.EOF {
+				t.Fatalf("unexpected error: %v != %v", io.EOF, err)
+			}
+
+			var f ethernet.Frame
+			if err := f.UnmarshalBinary(c.writes[0]); err != nil {
+				t.Fatalf("failed to unmarshal reply: %v", err)
+			}
+
+			if !bytes.Equal(f.Destination, tt.dstMAC) || !bytes.Equal(f.Source, hostMAC) {
+				t.Fatalf("unexpected reply addresses: %s -> %s", f.Source, f.Destination)
+			}
+
+			b := f.Payload
+			if !net.IP(b[8:24]).Equal(hostIPv6) || !net.IP(b[24:40]).Equal(tt.dst) {
+				t.Fatalf("unexpected IPv6 addresses: %s -> %s", net.IP(b[8:24]), net.IP(b[24:40]))
+			}
+
+			na := b[ipv6HeaderLen : ipv6HeaderLen+naLen]
+			if want, got := byte(icmpNeighborAdvertisement), na[0]; want != got {
+				t.Fatalf("unexpected ICMPv6 type: %v != %v", want, got)
+			}
+			if want, got := tt.flags, na[4]; want != got {
+				t.Fatalf("unexpected flags: %#x != %#x", want, got)
+			}
+			if !net.IP(na[8:24]).Equal(hostIPv6) || !bytes.Equal(na[26:32], hostMAC) {
+				t.Fatalf("unexpected advertisement: %v", na)
+			}
+
+			// A valid checksum sums to zero.
+			if want, got := uint16(0), icmpChecksum(b[8:24], b[24:40], na); want != got {
+				t.Fatalf("invalid checksum: %#x", got)
+			}
+		})
+	}
+}
+
+func TestProxyUpdate(t *testing.T) {
+	c := &testConn{reads: [][]byte{arpRequest(t, nil, hostIPv4)}}
+	p := New(c, nil)
+	p.Update([]Host{{IP: hostIPv4, HardwareAddr: hostMAC}})
+
+	if _, _, err := p.ReadFrom(make([]byte, 128)); err != io.EOF {
+		t.Fatalf("unexpected error: %v != %v", io.EOF, err)
+	}
+	if want, got := 1, len(c.writes); want != got {
+		t.Fatalf("unexpected number of replies: %v != %v", want, got)
+	}
+}
+
+// arpRequest creates an ARP request frame from the client for target.
+func arpRequest(t *testing.T, vlan *ethernet.VLAN, target net.IP) []byte {
+	t.Helper()
+
+	b := make([]byte, arpLen)
+	binary.BigEndian.PutUint16(b[0:2], arpHardwareEther)
+	binary.BigEndian.PutUint16(b[2:4], uint16(ethernet.EtherTypeIPv4))
+	b[4], b[5] = 6, 4
+	binary.BigEndian.PutUint16(b[6:8], arpOpRequest)
+	copy(b[8:14], clientMAC)
+	copy(b[14:18], clientIPv4.To4())
+	copy(b[24:28], target.To4())
+
+	return marshal(t, &ethernet.Frame{
+		Destination: ethernet.Broadcast,
+		Source:      clientMAC,
+		VLAN:        vlan,
+		EtherType:   ethernet.EtherTypeARP,
+		Payload:     b,
+	})
+}
+
+// neighborSolicitation creates an IPv6 Neighbor Solicitation frame from the
+// client for target.
+func neighborSolicitation(t *testing.T, src, target net.IP) []byte {
+	t.Helper()
+
+	b := make([]byte, ipv6HeaderLen+nsLen)
+	b[0] = 6 << 4
+	binary.BigEndian.PutUint16(b[4:6], nsLen)
+	b[6] = protoICMPv6
+	b[7] = ipv6HopLimit
+	copy(b[8:24], src.To16())
+	copy(b[24:40], net.ParseIP("ff02::1:ff00:1"))
+
+	ns := b[ipv6HeaderLen:]
+	ns[0] = icmpNeighborSolicitation
+	copy(ns[8:24], target.To16())
+	binary.BigEndian.PutUint16(ns[2:4], icmpChecksum(b[8:24], b[24:40], ns))
+
+	return marshal(t, &ethernet.Frame{
+		Destination: net.HardwareAddr{0x33, 0x33, 0xff, 0x00, 0x00, 0x01},
+		Source:      clientMAC,
+		EtherType:   ethernet.EtherTypeIPv6,
+		Payload:     b,
+	})
+}
+
+// marshal marshals f into binary form.
+func marshal(t *testing.T, f *ethernet.Frame) []byte {
+	t.Helper()
+
+	b, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal frame: %v", err)
+	}
+
+	return b
+}
+
+// testConn is an in-memory net.PacketConn which returns io.EOF once its
+// reads are exhausted.
+type testConn struct {
+	net.PacketConn
+
+	reads, writes [][]byte
+}
+
+func (c *testConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	if len(c.reads) == 0 {
+		return 0, nil, io.EOF
+	}
+
+	n := copy(b, c.reads[0])
+	c.reads = c.reads[1:]
+	return n, nil, nil
+}
+
+func (c *testConn) WriteTo(b []byte, _ net.Addr) (int, error) {
+	c.writes = append(c.writes, append([]byte(nil), b...))
+	return len(b), nil
+}
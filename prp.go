@@ -0,0 +1,118 @@
+package ethernet
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+const (
+	// prpSuffix is the value of the final 2 bytes of a PRP redundancy
+	// control trailer.
+	prpSuffix = 0x88fb
+
+	// rctLen is the length of a PRP redundancy control trailer.
+	rctLen = 6
+)
+
+// PRP LAN identifiers, which indicate the LAN on which a frame was sent.
+const (
+	PRPLANA uint8 = 0xa
+	PRPLANB uint8 = 0xb
+)
+
+// ErrInvalidPRP is returned when a PRP redundancy control trailer has an
+// invalid LAN identifier, LSDU size, or suffix.
+var ErrInvalidPRP = errors.New("invalid PRP trailer")
+
+// A PRPTrailer is an IEC 62439-3 Parallel Redundancy Protocol redundancy
+// control trailer (RCT), which is appended to the payload of a frame sent
+// over two independent LANs.
+type PRPTrailer struct {
+	// SequenceNumber specifies the sequence number of the frame.
+	SequenceNumber uint16
+
+	// LANID specifies the 4 bit LAN identifier, PRPLANA or PRPLANB.
+	LANID uint8
+
+	// LSDUSize specifies the 12 bit size of the link service data unit,
+	// which includes the payload, any padding, and the trailer itself.
+	LSDUSize uint16
+}
+
+// MarshalBinary allocates a byte slice and marshals a PRPTrailer into binary
+// form.
+func (p *PRPTrailer) MarshalBinary() ([]byte, error) {
+	b := make([]byte, rctLen)
+	_, err := p.read(b)
+	return b, err
+}
+
+// read reads data from a PRPTrailer into b.  read is used to marshal a
+// PRPTrailer into binary form, but does not allocate on its own.
+func (p *PRPTrailer) read(b []byte) (int, error) {
+	if p.LANID > 0x0f || p.LSDUSize > 0x0fff {
+		return 0, ErrInvalidPRP
+	}
+
+	binary.BigEndian.PutUint16(b[0:2], p.SequenceNumber)
+
+	//  4 bits: LAN ID
+	// 12 bits: LSDU size
+	binary.BigEndian.PutUint16(b[2:4], uint16(p.LANID)<<12|p.LSDUSize)
+	binary.BigEndian.PutUint16(b[4:6], prpSuffix)
+	return rctLen, nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into a PRPTrailer.  The byte slice
+// must be exactly 6 bytes and end with the PRP suffix.
+func (p *PRPTrailer) UnmarshalBinary(b []byte) error {
+	if len(b) != rctLen {
+		return io.ErrUnexpectedEOF
+	}
+
+	if binary.BigEndian.Uint16(b[4:6]) != prpSuffix {
+		return ErrInvalidPRP
+	}
+
+	p.SequenceNumber = binary.BigEndian.Uint16(b[0:2])
+	ub := binary.BigEndian.Uint16(b[2:4])
+	p.LANID = uint8(ub >> 12)
+	p.LSDUSize = ub & 0x0fff
+	return nil
+}
+
+// PRPTrailer detects a PRP redundancy control trailer at the end of a
+// Frame's payload and padding, and returns it along with the data which
+// precedes it.  If no trailer is detected, PRPTrailer returns false.
+//
+// Because a trailer has no EtherType of its own, detection is heuristic: the
+// final bytes must contain the PRP suffix, a LAN identifier of PRPLANA or
+// PRPLANB, and an LSDU size which matches the length of the payload and
+// padding.
+func (f *Frame) PRPTrailer() (*PRPTrailer, []byte, bool) {
+	b := f.Payload
+	if len(f.Padding) > 0 {
+		b = make([]byte, 0, len(f.Payload)+len(f.Padding))
+		b = append(b, f.Payload...)
+		b = append(b, f.Padding...)
+	}
+
+	if len(b) < rctLen {
+		return nil, nil, false
+	}
+
+	p := new(PRPTrailer)
+	if err := p.UnmarshalBinary(b[len(b)-rctLen:]); err != nil {
+		return nil, nil, false
+	}
+
+	if p.LANID != PRPLANA && p.LANID != PRPLANB {
+		return nil, nil, false
+	}
+	if int(p.LSDUSize) != len(b) {
+		return nil, nil, false
+	}
+
+	return p, b[:len(b)-rctLen], true
+}
@@ -0,0 +1,82 @@
+package ethernet
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFramePRPTrailer(t *testing.T) {
+	p := &PRPTrailer{
+		SequenceNumber: 0x0102,
+		LANID:          PRPLANB,
+		LSDUSize:       52,
+	}
+
+	pb, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	if want, got := []byte{0x01, 0x02, 0xb0, 0x34, 0x88, 0xfb}, pb; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected trailer bytes:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	data := bytes.Repeat([]byte{0xff}, 46)
+
+	tests := []struct {
+		desc string
+		f    *Frame
+		ok   bool
+	}{
+		{
+			desc: "trailer in payload",
+			f: &Frame{
+				EtherType: EtherTypeIPv4,
+				Payload:   append(append([]byte(nil), data...), pb...),
+			},
+			ok: true,
+		},
+		{
+			desc: "trailer in padding",
+			f: &Frame{
+				EtherType: EtherTypeIPv4,
+				Payload:   data[:40],
+				Padding:   append(append([]byte(nil), data[40:]...), pb...),
+			},
+			ok: true,
+		},
+		{
+			desc: "LSDU size mismatch",
+			f: &Frame{
+				EtherType: EtherTypeIPv4,
+				Payload:   append(append([]byte(nil), data[1:]...), pb...),
+			},
+		},
+		{
+			desc: "no trailer",
+			f: &Frame{
+				EtherType: EtherTypeIPv4,
+				Payload:   data,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, b, ok := tt.f.PRPTrailer()
+			if want, got := tt.ok, ok; want != got {
+				t.Fatalf("unexpected trailer detection: %v != %v", want, got)
+			}
+			if !ok {
+				return
+			}
+
+			if want, got := *p, *got; want != got {
+				t.Fatalf("unexpected trailer: %v != %v", want, got)
+			}
+			if want, got := data, b; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected data:\n- want: %v\n-  got: %v", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,421 @@
+// Package ptp implements marshaling and unmarshaling of IEEE 1588 Precision
+// Time Protocol version 2 messages carried directly in Ethernet frames.
+package ptp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// EtherType is the EtherType which indicates a PTP message in a Frame.
+const EtherType = ethernet.EtherTypePTP
+
+// Destination is the hardware address to which PTP messages other than peer
+// delay messages are sent.
+var Destination = net.HardwareAddr{0x01, 0x1b, 0x19, 0x00, 0x00, 0x00}
+
+// Version is the PTP version implemented by this package.
+const Version = 2
+
+const (
+	// headerLen is the length of a PTP common header.
+	headerLen = 34
+
+	// timestampLen and portIdentityLen are the lengths of a timestamp and a
+	// port identity.
+	timestampLen    = 10
+	portIdentityLen = 10
+
+	// announceLen is the length of an Announce message body.
+	announceLen = 30
+)
+
+var (
+	// ErrInvalidVersion is returned when a message's PTP version is not
+	// Version.
+	ErrInvalidVersion = errors.New("ptp: invalid version")
+
+	// ErrInvalidMessageType is returned when a message's type is not
+	// supported by this package.
+	ErrInvalidMessageType = errors.New("ptp: invalid message type")
+
+	// ErrInvalidLength is returned when a message's length field is too
+	// short for its type.
+	ErrInvalidLength = errors.New("ptp: invalid message length")
+)
+
+// A MessageType is the type of a PTP message.
+type MessageType uint8
+
+// Possible MessageType values.
+const (
+	MessageSync               MessageType = 0x0
+	MessageDelayReq           MessageType = 0x1
+	MessagePDelayReq          MessageType = 0x2
+	MessagePDelayResp         MessageType = 0x3
+	MessageFollowUp           MessageType = 0x8
+	MessageDelayResp          MessageType = 0x9
+	MessagePDelayRespFollowUp MessageType = 0xa
+	MessageAnnounce           MessageType = 0xb
+	MessageSignaling          MessageType = 0xc
+	MessageManagement         MessageType = 0xd
+)
+
+// String returns the name of a MessageType.
+func (t MessageType) String() string {
+	switch t {
+	case MessageSync:
+		return "Sync"
+	case MessageDelayReq:
+		return "Delay_Req"
+	case MessagePDelayReq:
+		return "Pdelay_Req"
+	case MessagePDelayResp:
+		return "Pdelay_Resp"
+	case MessageFollowUp:
+		return "Follow_Up"
+	case MessageDelayResp:
+		return "Delay_Resp"
+	case MessagePDelayRespFollowUp:
+		return "Pdelay_Resp_Follow_Up"
+	case MessageAnnounce:
+		return "Announce"
+	case MessageSignaling:
+		return "Signaling"
+	case MessageManagement:
+		return "Management"
+	default:
+		return fmt.Sprintf("MessageType(%d)", uint8(t))
+	}
+}
+
+// Event reports whether a MessageType is an event message, which is
+// timestamped on transmission and reception.
+func (t MessageType) Event() bool {
+	return t < 0x8
+}
+
+// bodyLen returns the length of the body of a supported MessageType, and
+// the value of its control field.
+func (t MessageType) bodyLen() (n int, control uint8, ok bool) {
+	switch t {
+	case MessageSync:
+		return timestampLen, 0, true
+	case MessageDelayReq:
+		return timestampLen, 1, true
+	case MessageFollowUp:
+		return timestampLen, 2, true
+	case MessageDelayResp:
+		return timestampLen + portIdentityLen, 3, true
+	case MessageAnnounce:
+		return announceLen, 5, true
+	default:
+		return 0, 0, false
+	}
+}
+
+// Flags are flags carried in a PTP common header.
+type Flags uint16
+
+// Possible Flags values.
+const (
+	FlagLeap61             Flags = 0x0001
+	FlagLeap59             Flags = 0x0002
+	FlagUTCOffsetValid     Flags = 0x0004
+	FlagPTPTimescale       Flags = 0x0008
+	FlagTimeTraceable      Flags = 0x0010
+	FlagFrequencyTraceable Flags = 0x0020
+	FlagAlternateMaster    Flags = 0x0100
+	FlagTwoStep            Flags = 0x0200
+	FlagUnicast            Flags = 0x0400
+	FlagProfileSpecific1   Flags = 0x2000
+	FlagProfileSpecific2   Flags = 0x4000
+	FlagSecurity           Flags = 0x8000
+)
+
+// A ClockIdentity identifies a PTP clock, and is normally derived from a
+// hardware address.
+type ClockIdentity [8]byte
+
+// String returns the textual form of a ClockIdentity, such as
+// "de:ad:be:ff:fe:ef:de:ad".
+func (c ClockIdentity) String() string {
+	return net.HardwareAddr(c[:]).String()
+}
+
+// A PortIdentity identifies a port of a PTP clock.
+type PortIdentity struct {
+	ClockIdentity ClockIdentity
+	PortNumber    uint16
+}
+
+// read marshals a PortIdentity into b.
+func (p PortIdentity) read(b []byte) {
+	copy(b[0:8], p.ClockIdentity[:])
+	binary.BigEndian.PutUint16(b[8:10], p.PortNumber)
+}
+
+// parsePortIdentity unmarshals a PortIdentity from b.
+func parsePortIdentity(b []byte) PortIdentity {
+	var p PortIdentity
+	copy(p.ClockIdentity[:], b[0:8])
+	p.PortNumber = binary.BigEndian.Uint16(b[8:10])
+	return p
+}
+
+// A Timestamp is a PTP timestamp, which is normally relative to the PTP
+// epoch in the TAI timescale.
+type Timestamp struct {
+	// Seconds specifies the 48 bit seconds field.
+	Seconds uint64
+
+	// Nanoseconds specifies the nanoseconds field.
+	Nanoseconds uint32
+}
+
+// NewTimestamp creates a Timestamp from time t.  No leap second or
+// timescale conversion is performed.
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{
+		Seconds:     uint64(t.Unix()) & 0xffffffffffff,
+		Nanoseconds: uint32(t.Nanosecond()),
+	}
+}
+
+// Time converts a Timestamp into a time.Time.  No leap second or timescale
+// conversion is performed.
+func (ts Timestamp) Time() time.Time {
+	return time.Unix(int64(ts.Seconds), int64(ts.Nanoseconds))
+}
+
+// read marshals a Timestamp into b.
+func (ts Timestamp) read(b []byte) {
+	binary.BigEndian.PutUint16(b[0:2], uint16(ts.Seconds>>32))
+	binary.BigEndian.PutUint32(b[2:6], uint32(ts.Seconds))
+	binary.BigEndian.PutUint32(b[6:10], ts.Nanoseconds)
+}
+
+// parseTimestamp unmarshals a Timestamp from b.
+func parseTimestamp(b []byte) Timestamp {
+	return Timestamp{
+		Seconds:     uint64(binary.BigEndian.Uint16(b[0:2]))<<32 | uint64(binary.BigEndian.Uint32(b[2:6])),
+		Nanoseconds: binary.BigEndian.Uint32(b[6:10]),
+	}
+}
+
+// A Header is a PTP common header.  The message length and control fields
+// are computed automatically.
+type Header struct {
+	// TransportSpecific specifies the 4 bit transport specific field, also
+	// known as the major SDO ID.
+	TransportSpecific uint8
+
+	// MessageType specifies the type of the message.
+	MessageType MessageType
+
+	// Domain specifies the PTP domain number.
+	Domain uint8
+
+	// Flags specifies the header flags.
+	Flags Flags
+
+	// Correction specifies the correction field, in nanoseconds multiplied
+	// by 2^16.
+	Correction int64
+
+	// SourcePortIdentity identifies the port which sent the message.
+	SourcePortIdentity PortIdentity
+
+	// SequenceID specifies the message sequence number.
+	SequenceID uint16
+
+	// LogMessageInterval specifies the base 2 logarithm of the message
+	// interval in seconds.
+	LogMessageInterval int8
+}
+
+// A ClockQuality describes the quality of a grandmaster clock.
+type ClockQuality struct {
+	Class                   uint8
+	Accuracy                uint8
+	OffsetScaledLogVariance uint16
+}
+
+// An Announce contains the fields of an Announce message which follow its
+// origin timestamp.
+type Announce struct {
+	CurrentUTCOffset        int16
+	GrandmasterPriority1    uint8
+	GrandmasterClockQuality ClockQuality
+	GrandmasterPriority2    uint8
+	GrandmasterIdentity     ClockIdentity
+	StepsRemoved            uint16
+	TimeSource              uint8
+}
+
+// A Message is a PTP Sync, Delay_Req, Follow_Up, Delay_Resp, or Announce
+// message.
+type Message struct {
+	Header
+
+	// Timestamp specifies the origin timestamp of Sync, Delay_Req, and
+	// Announce messages, the precise origin timestamp of Follow_Up
+	// messages, and the receive timestamp of Delay_Resp messages.
+	Timestamp Timestamp
+
+	// RequestingPortIdentity identifies the port which sent the Delay_Req
+	// answered by a Delay_Resp message.
+	RequestingPortIdentity PortIdentity
+
+	// Announce specifies the fields of an Announce message, and must be
+	// set when marshaling one.
+	Announce *Announce
+}
+
+// Parse unmarshals the PTP message carried in the payload of Frame f.  If
+// f's EtherType is not EtherType, ethernet.ErrInvalidEtherType is returned.
+func Parse(f *ethernet.Frame) (*Message, error) {
+	if f.EtherType != EtherType {
+		return nil, ethernet.ErrInvalidEtherType
+	}
+
+	m := new(Message)
+	if err := m.UnmarshalBinary(f.Payload); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Frame marshals a Message into the payload of an Ethernet frame sent from
+// hardware address src to Destination.
+func (m *Message) Frame(src net.HardwareAddr) (*ethernet.Frame, error) {
+	b, err := m.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ethernet.Frame{
+		Destination: append(net.HardwareAddr(nil), Destination...),
+		Source:      src,
+		EtherType:   EtherType,
+		Payload:     b,
+	}, nil
+}
+
+// MarshalBinary allocates a byte slice and marshals a Message into binary
+// form.
+func (m *Message) MarshalBinary() ([]byte, error) {
+	n, control, ok := m.MessageType.bodyLen()
+	if !ok || m.TransportSpecific > 0x0f {
+		return nil, ErrInvalidMessageType
+	}
+	if m.MessageType == MessageAnnounce && m.Announce == nil {
+		return nil, ErrInvalidMessageType
+	}
+
+	b := make([]byte, headerLen+n)
+	b[0] = m.TransportSpecific<<4 | uint8(m.MessageType)
+	b[1] = Version
+	binary.BigEndian.PutUint16(b[2:4], uint16(len(b)))
+	b[4] = m.Domain
+	binary.BigEndian.PutUint16(b[6:8], uint16(m.Flags))
+	binary.BigEndian.PutUint64(b[8:16], uint64(m.Correction))
+	m.SourcePortIdentity.read(b[20:30])
+	binary.BigEndian.PutUint16(b[30:32], m.SequenceID)
+	b[32] = control
+	b[33] = uint8(m.LogMessageInterval)
+
+	body := b[headerLen:]
+	m.Timestamp.read(body[0:10])
+
+	switch m.MessageType {
+	case MessageDelayResp:
+		m.RequestingPortIdentity.read(body[10:20])
+	case MessageAnnounce:
+		a := m.Announce
+		binary.BigEndian.PutUint16(body[10:12], uint16(a.CurrentUTCOffset))
+		body[13] = a.GrandmasterPriority1
+		body[14] = a.GrandmasterClockQuality.Class
+		body[15] = a.GrandmasterClockQuality.Accuracy
+		binary.BigEndian.PutUint16(body[16:18], a.GrandmasterClockQuality.OffsetScaledLogVariance)
+		body[18] = a.GrandmasterPriority2
+		copy(body[19:27], a.GrandmasterIdentity[:])
+		binary.BigEndian.PutUint16(body[27:29], a.StepsRemoved)
+		body[29] = a.TimeSource
+	}
+
+	return b, nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into a Message.  Bytes beyond the
+// message length field, such as Ethernet padding, are ignored.
+func (m *Message) UnmarshalBinary(b []byte) error {
+	if len(b) < headerLen {
+		return io.ErrUnexpectedEOF
+	}
+
+	if b[1]&0x0f != Version {
+		return ErrInvalidVersion
+	}
+
+	t := MessageType(b[0] & 0x0f)
+	n, _, ok := t.bodyLen()
+	if !ok {
+		return ErrInvalidMessageType
+	}
+
+	l := int(binary.BigEndian.Uint16(b[2:4]))
+	if l < headerLen+n {
+		return ErrInvalidLength
+	}
+	if len(b) < l {
+		return io.ErrUnexpectedEOF
+	}
+
+	out := Message{
+		Header: Header{
+			TransportSpecific:  b[0] >> 4,
+			MessageType:        t,
+			Domain:             b[4],
+			Flags:              Flags(binary.BigEndian.Uint16(b[6:8])),
+			Correction:         int64(binary.BigEndian.Uint64(b[8:16])),
+			SourcePortIdentity: parsePortIdentity(b[20:30]),
+			SequenceID:         binary.BigEndian.Uint16(b[30:32]),
+			LogMessageInterval: int8(b[33]),
+		},
+	}
+
+	body := b[headerLen:l]
+	out.Timestamp = parseTimestamp(body[0:10])
+
+	switch t {
+	case MessageDelayResp:
+		out.RequestingPortIdentity = parsePortIdentity(body[10:20])
+	case MessageAnnounce:
+		a := &Announce{
+			CurrentUTCOffset:     int16(binary.BigEndian.Uint16(body[10:12])),
+			GrandmasterPriority1: body[13],
+			GrandmasterClockQuality: ClockQuality{
+				Class:                   body[14],
+				Accuracy:                body[15],
+				OffsetScaledLogVariance: binary.BigEndian.Uint16(body[16:18]),
+			},
+			GrandmasterPriority2: body[18],
+			StepsRemoved:         binary.BigEndian.Uint16(body[27:29]),
+			TimeSource:           body[29],
+		}
+		copy(a.GrandmasterIdentity[:], body[19:27])
+
+		out.Announce = a
+	}
+
+	*m = out
+	return nil
+}
@@ -0,0 +1,289 @@
+package ptp
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestMessageMarshalUnmarshal(t *testing.T) {
+	clock := ClockIdentity{0xde, 0xad, 0xbe, 0xff, 0xfe, 0xef, 0xde, 0xad}
+	source := PortIdentity{ClockIdentity: clock, PortNumber: 1}
+
+	header := []byte{
+		// Clock identity and port number.
+		0xde, 0xad, 0xbe, 0xff, 0xfe, 0xef, 0xde, 0xad,
+		0x00, 0x01,
+	}
+
+	tests := []struct {
+		desc string
+		m    *Message
+		b    []byte
+	}{
+		{
+			desc: "Sync",
+			m: &Message{
+				Header: Header{
+					MessageType:        MessageSync,
+					Flags:              FlagTwoStep,
+					SourcePortIdentity: source,
+					SequenceID:         0x0102,
+					LogMessageInterval: -3,
+				},
+			},
+			b: concat(
+				[]byte{
+					0x00, 0x02, 0x00, 0x2c,
+					0x00, 0x00, 0x02, 0x00,
+					0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+					0x00, 0x00, 0x00, 0x00,
+				},
+				header,
+				[]byte{0x01, 0x02, 0x00, 0xfd},
+				make([]byte, timestampLen),
+			),
+		},
+		{
+			desc: "Follow_Up",
+			m: &Message{
+				Header: Header{
+					TransportSpecific:  1,
+					MessageType:        MessageFollowUp,
+					Domain:             24,
+					Correction:         1 << 16,
+					SourcePortIdentity: source,
+				},
+				Timestamp: Timestamp{Seconds: 0x010000000002, Nanoseconds: 3},
+			},
+			b: concat(
+				[]byte{
+					0x18, 0x02, 0x00, 0x2c,
+					0x18, 0x00, 0x00, 0x00,
+					0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00,
+					0x00, 0x00, 0x00, 0x00,
+				},
+				header,
+				[]byte{0x00, 0x00, 0x02, 0x00},
+				[]byte{
+					0x01, 0x00, 0x00, 0x00, 0x00, 0x02,
+					0x00, 0x00, 0x00, 0x03,
+				},
+			),
+		},
+		{
+			desc: "Delay_Resp",
+			m: &Message{
+				Header: Header{
+					MessageType:        MessageDelayResp,
+					SourcePortIdentity: source,
+				},
+				Timestamp: Timestamp{Seconds: 1},
+				RequestingPortIdentity: PortIdentity{
+					ClockIdentity: ClockIdentity{1, 2, 3, 4, 5, 6, 7, 8},
+					PortNumber:    2,
+				},
+			},
+			b: concat(
+				[]byte{
+					0x09, 0x02, 0x00, 0x36,
+					0x00, 0x00, 0x00, 0x00,
+					0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+					0x00, 0x00, 0x00, 0x00,
+				},
+				header,
+				[]byte{0x00, 0x00, 0x03, 0x00},
+				[]byte{
+					0x00, 0x00, 0x00, 0x00, 0x00, 0x01,
+					0x00, 0x00, 0x00, 0x00,
+					0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+					0x00, 0x02,
+				},
+			),
+		},
+		{
+			desc: "Announce",
+			m: &Message{
+				Header: Header{
+					MessageType:        MessageAnnounce,
+					Flags:              FlagPTPTimescale | FlagUTCOffsetValid,
+					SourcePortIdentity: source,
+					LogMessageInterval: 1,
+				},
+				Announce: &Announce{
+					CurrentUTCOffset:     37,
+					GrandmasterPriority1: 128,
+					GrandmasterClockQuality: ClockQuality{
+						Class:                   6,
+						Accuracy:                0x21,
+						OffsetScaledLogVariance: 0x4e5d,
+					},
+					GrandmasterPriority2: 128,
+					GrandmasterIdentity:  clock,
+					StepsRemoved:         1,
+					TimeSource:           0x20,
+				},
+			},
+			b: concat(
+				[]byte{
+					0x0b, 0x02, 0x00, 0x40,
+					0x00, 0x00, 0x00, 0x0c,
+					0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+					0x00, 0x00, 0x00, 0x00,
+				},
+				header,
+				[]byte{0x00, 0x00, 0x05, 0x01},
+				make([]byte, timestampLen),
+				[]byte{
+					0x00, 0x25, 0x00, 0x80,
+					0x06, 0x21, 0x4e, 0x5d,
+					0x80,
+					0xde, 0xad, 0xbe, 0xff, 0xfe, 0xef, 0xde, 0xad,
+					0x00, 0x01, 0x20,
+				},
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := tt.m.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			if want, got := tt.b, b; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected bytes:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			// Trailing padding is ignored.
+			m := new(Message)
+			if err := m.UnmarshalBinary(append(b, 0x00, 0x00)); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+
+			if !reflect.DeepEqual(tt.m, m) {
+				t.Fatalf("unexpected message:\n- want: %#v\n-  got: %#v", tt.m, m)
+			}
+		})
+	}
+}
+
+func TestMessageErrors(t *testing.T) {
+	valid, err := (&Message{Header: Header{MessageType: MessageSync}}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	modify := func(i int, v byte) []byte {
+		b := append([]byte(nil), valid...)
+		b[i] = v
+		return b
+	}
+
+	tests := []struct {
+		desc string
+		b    []byte
+		err  error
+	}{
+		{
+			desc: "short header",
+			b:    valid[:headerLen-1],
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "version 1",
+			b:    modify(1, 0x01),
+			err:  ErrInvalidVersion,
+		},
+		{
+			desc: "management",
+			b:    modify(0, uint8(MessageManagement)),
+			err:  ErrInvalidMessageType,
+		},
+		{
+			desc: "short length field",
+			b:    modify(3, headerLen),
+			err:  ErrInvalidLength,
+		},
+		{
+			desc: "truncated",
+			b:    valid[:len(valid)-1],
+			err:  io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := new(Message).UnmarshalBinary(tt.b); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+
+	for _, m := range []*Message{
+		{Header: Header{MessageType: MessagePDelayReq}},
+		{Header: Header{MessageType: MessageAnnounce}},
+		{Header: Header{TransportSpecific: 0x10}},
+	} {
+		if _, err := m.MarshalBinary(); err != ErrInvalidMessageType {
+			t.Fatalf("expected ErrInvalidMessageType, but got: %v", err)
+		}
+	}
+}
+
+func TestFrameParse(t *testing.T) {
+	m := &Message{
+		Header:    Header{MessageType: MessageDelayReq, SequenceID: 1},
+		Timestamp: NewTimestamp(time.Unix(1500000000, 123456789)),
+	}
+
+	f, err := m.Frame(net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad})
+	if err != nil {
+		t.Fatalf("failed to create frame: %v", err)
+	}
+
+	got, err := Parse(f)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if !reflect.DeepEqual(m, got) {
+		t.Fatalf("unexpected message:\n- want: %#v\n-  got: %#v", m, got)
+	}
+
+	if want, got := time.Unix(1500000000, 123456789), got.Timestamp.Time(); !want.Equal(got) {
+		t.Fatalf("unexpected time: %v != %v", want, got)
+	}
+
+	if _, err := Parse(&ethernet.Frame{EtherType: ethernet.EtherTypeIPv4}); err != ethernet.ErrInvalidEtherType {
+		t.Fatalf("expected ErrInvalidEtherType, but got: %v", err)
+	}
+}
+
+func TestMessageTypeString(t *testing.T) {
+	if want, got := "Follow_Up", MessageFollowUp.String(); want != got {
+		t.Fatalf("unexpected string: %q != %q", want, got)
+	}
+	if want, got := "MessageType(15)", MessageType(15).String(); want != got {
+		t.Fatalf("unexpected string: %q != %q", want, got)
+	}
+	if !MessageDelayReq.Event() || MessageAnnounce.Event() {
+		t.Fatal("unexpected event message classification")
+	}
+}
+
+// concat concatenates byte slices into a new slice.
+func concat(bs ...[]byte) []byte {
+	var out []byte
+	for _, b := range bs {
+		out = append(out, b...)
+	}
+
+	return out
+}
@@ -0,0 +1,59 @@
+package ethernet
+
+// A RawFrame is a Frame which also retains the exact bytes from which it was
+// unmarshaled.  If the Frame is not modified, MarshalBinary reproduces the
+// original bytes exactly, even when they contain non-canonical values such
+// as an IEEE 802.3 length field which does not match the length of the
+// frame, which cannot be represented by a Frame alone.
+//
+// Only RawFrame.MarshalBinary consults the original bytes.  Other methods
+// promoted from Frame, such as MarshalTo and MarshalFCS, operate only on the
+// parsed Frame.
+type RawFrame struct {
+	Frame
+
+	// Raw is a copy of the bytes from which the Frame was unmarshaled.
+	Raw []byte
+
+	// parsed is a snapshot of Frame when it was unmarshaled, used to detect
+	// modifications.
+	parsed *Frame
+}
+
+// MarshalBinary allocates a byte slice and marshals a RawFrame into binary
+// form.  If the Frame has not been modified since it was unmarshaled, a copy
+// of Raw is returned.  Otherwise, the Frame is marshaled as usual.
+func (r *RawFrame) MarshalBinary() ([]byte, error) {
+	if r.Modified() {
+		return r.Frame.MarshalBinary()
+	}
+
+	b := make([]byte, len(r.Raw))
+	copy(b, r.Raw)
+	return b, nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into a RawFrame, retaining a copy
+// of the byte slice in Raw.
+func (r *RawFrame) UnmarshalBinary(b []byte) error {
+	return UnmarshalOptions{}.UnmarshalRaw(b, r)
+}
+
+// UnmarshalRaw unmarshals a byte slice into RawFrame r using the options
+// specified in o, retaining a copy of the byte slice in r.Raw.
+func (o UnmarshalOptions) UnmarshalRaw(b []byte, r *RawFrame) error {
+	r.parsed = nil
+	if err := o.Unmarshal(b, &r.Frame); err != nil {
+		return err
+	}
+
+	r.Raw = append(r.Raw[:0], b...)
+	r.parsed = r.Frame.Clone()
+	return nil
+}
+
+// Modified reports whether a RawFrame's Frame has been modified since it was
+// unmarshaled, or whether it was never unmarshaled at all.
+func (r *RawFrame) Modified() bool {
+	return r.parsed == nil || !r.Frame.Equal(r.parsed)
+}
@@ -0,0 +1,52 @@
+package ethernet
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRawFrame(t *testing.T) {
+	// An IEEE 802.3 frame whose length field exceeds the length of the
+	// frame, which is recomputed when marshaled by Frame.
+	b := append([]byte{
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0, 1, 0, 1, 0, 1,
+		0x00, 0x40,
+		0x42, 0x42, 0x03,
+	}, bytes.Repeat([]byte{0}, 43)...)
+
+	r := new(RawFrame)
+	if !r.Modified() {
+		t.Fatal("expected zero RawFrame to be modified")
+	}
+
+	if err := r.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if r.Modified() {
+		t.Fatal("expected unmarshaled RawFrame to be unmodified")
+	}
+
+	out, err := r.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	if !bytes.Equal(b, out) {
+		t.Fatalf("unexpected unmodified bytes:\n- want: %v\n-  got: %v", b, out)
+	}
+
+	// Modifying the Frame causes it to be marshaled from its fields.
+	r.LLC.DSAP = 0xf0
+	if !r.Modified() {
+		t.Fatal("expected RawFrame to be modified")
+	}
+
+	out, err = r.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	if want, got := []byte{0x00, 0x2e, 0xf0, 0x42, 0x03}, out[12:17]; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected modified length and LLC:\n- want: %v\n-  got: %v", want, got)
+	}
+}
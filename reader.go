@@ -0,0 +1,202 @@
+package ethernet
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// A Framing is a scheme used to delimit Ethernet frames in a byte stream.
+type Framing int
+
+// Possible Framing values.
+const (
+	// FramingLengthPrefixed delimits frames with a 2 byte, big endian
+	// length which precedes each frame.
+	FramingLengthPrefixed Framing = iota
+
+	// FramingPcap delimits frames using the records of a classic libpcap
+	// file with link type Ethernet.  The stream begins with the pcap file
+	// header.
+	FramingPcap
+
+	// FramingSnapLen delimits frames which each occupy a fixed number of
+	// bytes, specified by a snapshot length.
+	FramingSnapLen
+)
+
+// String returns the name of a Framing.
+func (f Framing) String() string {
+	switch f {
+	case FramingLengthPrefixed:
+		return "length-prefixed"
+	case FramingPcap:
+		return "pcap"
+	case FramingSnapLen:
+		return "snaplen"
+	default:
+		return fmt.Sprintf("Framing(%d)", int(f))
+	}
+}
+
+// pcap file format constants.
+const (
+	pcapMagicMicroseconds = 0xa1b2c3d4
+	pcapMagicNanoseconds  = 0xa1b23c4d
+
+	// pcapLinkTypeEthernet is LINKTYPE_ETHERNET.
+	pcapLinkTypeEthernet = 1
+
+	// pcapMaxSnapLen is the largest snapshot length used by libpcap, and
+	// bounds the size of a single pcap record.
+	pcapMaxSnapLen = 262144
+
+	pcapHeaderLen       = 24
+	pcapRecordHeaderLen = 16
+)
+
+var (
+	// ErrInvalidCapture is returned when a FrameReader encounters a
+	// malformed or unsupported capture file.
+	ErrInvalidCapture = errors.New("invalid capture file")
+
+	// ErrInvalidFraming is returned when a FrameReader or FrameWriter is
+	// used with an unknown Framing, or FramingSnapLen without a positive
+	// snapshot length.
+	ErrInvalidFraming = errors.New("invalid framing")
+)
+
+// A FrameReader reads Ethernet frames from an io.Reader, using a Framing
+// to delimit each frame.
+type FrameReader struct {
+	// Options specifies the options used to unmarshal each frame.  If
+	// Options.ZeroCopy is set, each Frame aliases an internal buffer which
+	// is only valid until the next call to Next.
+	Options UnmarshalOptions
+
+	r       io.Reader
+	framing Framing
+	snapLen int
+
+	// order is the byte order of a pcap file, set once its header is read.
+	order binary.ByteOrder
+	buf   []byte
+}
+
+// NewFrameReader creates a FrameReader which reads frames from r using the
+// specified Framing.  snapLen specifies the size of each frame when using
+// FramingSnapLen, and is otherwise ignored.
+func NewFrameReader(r io.Reader, framing Framing, snapLen int) *FrameReader {
+	return &FrameReader{
+		r:       r,
+		framing: framing,
+		snapLen: snapLen,
+	}
+}
+
+// Next reads and unmarshals the next frame.  io.EOF is returned when the
+// stream ends cleanly at a frame boundary, and io.ErrUnexpectedEOF is
+// returned when it ends within a frame.
+func (fr *FrameReader) Next() (*Frame, error) {
+	b, err := fr.next()
+	if err != nil {
+		return nil, err
+	}
+
+	f := new(Frame)
+	if err := fr.Options.Unmarshal(b, f); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// next reads the bytes of the next frame into fr's buffer.
+func (fr *FrameReader) next() ([]byte, error) {
+	switch fr.framing {
+	case FramingLengthPrefixed:
+		var l [2]byte
+		if _, err := io.ReadFull(fr.r, l[:]); err != nil {
+			return nil, err
+		}
+
+		return fr.read(int(binary.BigEndian.Uint16(l[:])))
+	case FramingPcap:
+		if fr.order == nil {
+			if err := fr.readPcapHeader(); err != nil {
+				return nil, err
+			}
+		}
+
+		var rh [pcapRecordHeaderLen]byte
+		if _, err := io.ReadFull(fr.r, rh[:]); err != nil {
+			return nil, err
+		}
+
+		n := fr.order.Uint32(rh[8:12])
+		if n > pcapMaxSnapLen {
+			return nil, ErrInvalidCapture
+		}
+
+		return fr.read(int(n))
+	case FramingSnapLen:
+		if fr.snapLen <= 0 {
+			return nil, ErrInvalidFraming
+		}
+
+		// Only the first byte of a frame may be cleanly absent.
+		b, err := fr.read(fr.snapLen)
+		if err == io.ErrUnexpectedEOF && len(b) == 0 {
+			return nil, io.EOF
+		}
+
+		return b, err
+	default:
+		return nil, ErrInvalidFraming
+	}
+}
+
+// read reads exactly n bytes into fr's buffer.  End of stream is always
+// reported as io.ErrUnexpectedEOF, because n bytes are expected.
+func (fr *FrameReader) read(n int) ([]byte, error) {
+	if cap(fr.buf) < n {
+		fr.buf = make([]byte, n)
+	}
+	b := fr.buf[:n]
+
+	nn, err := io.ReadFull(fr.r, b)
+	switch err {
+	case nil:
+		return b, nil
+	case io.EOF:
+		return b[:nn], io.ErrUnexpectedEOF
+	default:
+		return b[:nn], err
+	}
+}
+
+// readPcapHeader reads and verifies a pcap file header.
+func (fr *FrameReader) readPcapHeader() error {
+	var h [pcapHeaderLen]byte
+	if _, err := io.ReadFull(fr.r, h[:]); err != nil {
+		return err
+	}
+
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		switch order.Uint32(h[0:4]) {
+		case pcapMagicMicroseconds, pcapMagicNanoseconds:
+		default:
+			continue
+		}
+
+		if order.Uint32(h[20:24]) != pcapLinkTypeEthernet {
+			return ErrInvalidCapture
+		}
+
+		fr.order = order
+		return nil
+	}
+
+	return ErrInvalidCapture
+}
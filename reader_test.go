@@ -0,0 +1,185 @@
+package ethernet
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestFrameReader(t *testing.T) {
+	f := &Frame{
+		Destination: Broadcast,
+		Source:      Broadcast,
+		EtherType:   EtherTypeIPv4,
+		Payload:     []byte{0xde, 0xad, 0xbe, 0xef},
+	}
+
+	fb, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	// The frame as it is unmarshaled, including padding.
+	want := new(Frame)
+	if err := want.UnmarshalBinary(fb); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	tests := []struct {
+		desc    string
+		framing Framing
+		snapLen int
+		b       []byte
+		n       int
+		err     error
+	}{
+		{
+			desc:    "length-prefixed",
+			framing: FramingLengthPrefixed,
+			b:       concat([]byte{0x00, 0x3c}, fb, []byte{0x00, 0x3c}, fb),
+			n:       2,
+		},
+		{
+			desc:    "length-prefixed truncated",
+			framing: FramingLengthPrefixed,
+			b:       concat([]byte{0x00, 0x3c}, fb[:20]),
+			err:     io.ErrUnexpectedEOF,
+		},
+		{
+			desc:    "pcap little endian",
+			framing: FramingPcap,
+			b: concat(
+				[]byte{
+					0xd4, 0xc3, 0xb2, 0xa1,
+					0x02, 0x00, 0x04, 0x00,
+					0x00, 0x00, 0x00, 0x00,
+					0x00, 0x00, 0x00, 0x00,
+					0xff, 0xff, 0x00, 0x00,
+					0x01, 0x00, 0x00, 0x00,
+				},
+				[]byte{
+					0x01, 0x00, 0x00, 0x00,
+					0x02, 0x00, 0x00, 0x00,
+					0x3c, 0x00, 0x00, 0x00,
+					0x3c, 0x00, 0x00, 0x00,
+				},
+				fb,
+			),
+			n: 1,
+		},
+		{
+			desc:    "pcap big endian",
+			framing: FramingPcap,
+			b: concat(
+				[]byte{
+					0xa1, 0xb2, 0x3c, 0x4d,
+					0x00, 0x02, 0x00, 0x04,
+					0x00, 0x00, 0x00, 0x00,
+					0x00, 0x00, 0x00, 0x00,
+					0x00, 0x00, 0xff, 0xff,
+					0x00, 0x00, 0x00, 0x01,
+				},
+				[]byte{
+					0x00, 0x00, 0x00, 0x01,
+					0x00, 0x00, 0x00, 0x02,
+					0x00, 0x00, 0x00, 0x3c,
+					0x00, 0x00, 0x00, 0x3c,
+				},
+				fb,
+			),
+			n: 1,
+		},
+		{
+			desc:    "pcap bad link type",
+			framing: FramingPcap,
+			b: []byte{
+				0xd4, 0xc3, 0xb2, 0xa1,
+				0x02, 0x00, 0x04, 0x00,
+				0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00,
+				0xff, 0xff, 0x00, 0x00,
+				0x71, 0x00, 0x00, 0x00,
+			},
+			err: ErrInvalidCapture,
+		},
+		{
+			desc:    "pcap bad magic",
+			framing: FramingPcap,
+			b:       make([]byte, pcapHeaderLen),
+			err:     ErrInvalidCapture,
+		},
+		{
+			desc:    "snaplen",
+			framing: FramingSnapLen,
+			snapLen: len(fb),
+			b:       concat(fb, fb, fb),
+			n:       3,
+		},
+		{
+			desc:    "snaplen truncated",
+			framing: FramingSnapLen,
+			snapLen: len(fb),
+			b:       concat(fb, fb[:1]),
+			n:       1,
+			err:     io.ErrUnexpectedEOF,
+		},
+		{
+			desc:    "snaplen zero",
+			framing: FramingSnapLen,
+			b:       fb,
+			err:     ErrInvalidFraming,
+		},
+		{
+			desc:    "unknown framing",
+			framing: Framing(-1),
+			b:       fb,
+			err:     ErrInvalidFraming,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			fr := NewFrameReader(bytes.NewReader(tt.b), tt.framing, tt.snapLen)
+
+			for i := 0; i < tt.n; i++ {
+				got, err := fr.Next()
+				if err != nil {
+					t.Fatalf("failed to read frame %d: %v", i, err)
+				}
+
+				if !reflect.DeepEqual(want, got) {
+					t.Fatalf("unexpected frame %d:\n- want: %#v\n-  got: %#v", i, want, got)
+				}
+			}
+
+			werr := tt.err
+			if werr == nil {
+				werr = io.EOF
+			}
+
+			if _, err := fr.Next(); err != werr {
+				t.Fatalf("unexpected error: %v != %v", werr, err)
+			}
+		})
+	}
+}
+
+func TestFramingString(t *testing.T) {
+	if want, got := "pcap", FramingPcap.String(); want != got {
+		t.Fatalf("unexpected string: %q != %q", want, got)
+	}
+	if want, got := "Framing(10)", Framing(10).String(); want != got {
+		t.Fatalf("unexpected string: %q != %q", want, got)
+	}
+}
+
+// concat concatenates byte slices into a new slice.
+func concat(bs ...[]byte) []byte {
+	var out []byte
+	for _, b := range bs {
+		out = append(out, b...)
+	}
+
+	return out
+}
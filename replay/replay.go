@@ -0,0 +1,189 @@
+// Package replay implements timing-accurate replay of captured Ethernet
+// frames, reproducing the inter-frame gaps recorded in capture timestamps.
+package replay
+
+import (
+	"context"
+	"errors"
+	"net"
+	"runtime"
+	"time"
+)
+
+// spinThreshold is the duration before a frame's transmit time at which a
+// Replayer stops sleeping and begins polling the clock, to improve timer
+// precision.
+const spinThreshold = 50 * time.Microsecond
+
+// errInvalidSpeed is returned when a Config specifies a negative Speed.
+var errInvalidSpeed = errors.New("replay: speed must not be negative")
+
+// A Packet is a captured frame and the time at which it was captured.
+type Packet struct {
+	Timestamp time.Time
+	Data      []byte
+}
+
+// Config specifies configuration for a Replayer.
+type Config struct {
+	// Speed scales the rate of replay: 2 replays frames twice as fast as
+	// they were captured, and 0.5 replays them at half speed.  If zero,
+	// frames are replayed at their captured rate.
+	Speed float64
+
+	// TopSpeed ignores capture timestamps and replays frames as fast as
+	// possible.
+	TopSpeed bool
+
+	// Loops specifies the number of times frames are replayed.  If zero,
+	// frames are replayed once.  If negative, frames are replayed until the
+	// context passed to Replay is canceled.
+	Loops int
+
+	// LoopDelay specifies an additional gap between the last frame of one
+	// loop and the first frame of the next.
+	LoopDelay time.Duration
+
+	// Addr specifies the address passed to WriteTo for each frame.
+	Addr net.Addr
+}
+
+// Stats contains statistics about a single call to Replay.
+type Stats struct {
+	// Frames and Bytes are the number of frames and bytes written.
+	Frames, Bytes uint64
+
+	// Loops is the number of complete loops replayed.
+	Loops int
+
+	// MaxLag is the greatest delay between a frame's scheduled transmit
+	// time and the time it was actually written.
+	MaxLag time.Duration
+}
+
+// A Replayer replays frames on a net.PacketConn.
+type Replayer struct {
+	c   net.PacketConn
+	cfg Config
+
+	// now and wait allow tests to control the passage of time.
+	now  func() time.Time
+	wait func(ctx context.Context, t time.Time) error
+}
+
+// New creates a Replayer which writes frames to c using the input
+// configuration.  If cfg is nil, a default configuration is used.
+func New(c net.PacketConn, cfg *Config) (*Replayer, error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	if cfg.Speed < 0 {
+		return nil, errInvalidSpeed
+	}
+
+	r := &Replayer{
+		c:    c,
+		cfg:  *cfg,
+		now:  time.Now,
+		wait: wait,
+	}
+
+	if r.cfg.Speed == 0 {
+		r.cfg.Speed = 1
+	}
+	if r.cfg.Loops == 0 {
+		r.cfg.Loops = 1
+	}
+
+	return r, nil
+}
+
+// Replay writes ps in order, pacing them according to their timestamps,
+// until all loops complete, ctx is canceled, or a write fails.  ps must be
+// sorted by timestamp; frames with earlier timestamps than their
+// predecessors are written immediately.
+//
+// Stats are returned even if an error occurs.
+func (r *Replayer) Replay(ctx context.Context, ps []Packet) (Stats, error) {
+	var stats Stats
+	if len(ps) == 0 {
+		return stats, nil
+	}
+
+	var (
+		base  = ps[0].Timestamp
+		start = r.now()
+		last  = start
+	)
+
+	for loop := 0; r.cfg.Loops < 0 || loop < r.cfg.Loops; loop++ {
+		if loop > 0 {
+			// Schedule from the previous loop's last transmit time, rather
+			// than the current time, so that lag does not accumulate.
+			start = last.Add(r.cfg.LoopDelay)
+		}
+
+		for _, p := range ps {
+			if err := ctx.Err(); err != nil {
+				return stats, err
+			}
+
+			if !r.cfg.TopSpeed {
+				t := start.Add(time.Duration(float64(p.Timestamp.Sub(base)) / r.cfg.Speed))
+				if t.After(last) {
+					last = t
+				}
+
+				if err := r.wait(ctx, last); err != nil {
+					return stats, err
+				}
+
+				if lag := r.now().Sub(last); lag > stats.MaxLag {
+					stats.MaxLag = lag
+				}
+			}
+
+			n, err := r.c.WriteTo(p.Data, r.cfg.Addr)
+			if err != nil {
+				return stats, err
+			}
+
+			stats.Frames++
+			stats.Bytes += uint64(n)
+		}
+
+		if r.cfg.TopSpeed {
+			last = r.now()
+		}
+
+		stats.Loops++
+	}
+
+	return stats, nil
+}
+
+// wait blocks until time t or until ctx is canceled.  To improve precision,
+// wait polls the clock immediately before t rather than relying solely on
+// the runtime's timers.
+func wait(ctx context.Context, t time.Time) error {
+	if d := time.Until(t) - spinThreshold; d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	for time.Now().Before(t) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		runtime.Gosched()
+	}
+
+	return nil
+}
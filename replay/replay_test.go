@@ -0,0 +1,155 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewInvalidSpeed(t *testing.T) {
+	if _, err := New(&testConn{}, &Config{Speed: -1}); err != errInvalidSpeed {
+		t.Fatalf("unexpected error: %v != %v", errInvalidSpeed, err)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	t0 := time.Unix(100, 0)
+	ps := []Packet{
+		{Timestamp: t0, Data: []byte{1}},
+		{Timestamp: t0.Add(10 * time.Millisecond), Data: []byte{2, 2}},
+		{Timestamp: t0.Add(30 * time.Millisecond), Data: []byte{3, 3, 3}},
+	}
+
+	ms := func(n int) time.Duration { return time.Duration(n) * time.Millisecond }
+
+	tests := []struct {
+		desc  string
+		cfg   *Config
+		at    []time.Duration
+		stats Stats
+	}{
+		{
+			desc:  "captured rate",
+			at:    []time.Duration{0, ms(10), ms(30)},
+			stats: Stats{Frames: 3, Bytes: 6, Loops: 1},
+		},
+		{
+			desc:  "double speed",
+			cfg:   &Config{Speed: 2},
+			at:    []time.Duration{0, ms(5), ms(15)},
+			stats: Stats{Frames: 3, Bytes: 6, Loops: 1},
+		},
+		{
+			desc:  "top speed",
+			cfg:   &Config{TopSpeed: true},
+			at:    []time.Duration{0, 0, 0},
+			stats: Stats{Frames: 3, Bytes: 6, Loops: 1},
+		},
+		{
+			desc: "loops with delay",
+			cfg:  &Config{Loops: 2, LoopDelay: ms(100)},
+			at: []time.Duration{
+				0, ms(10), ms(30),
+				ms(130), ms(140), ms(160),
+			},
+			stats: Stats{Frames: 6, Bytes: 12, Loops: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			c := &testConn{}
+			r, err := New(c, tt.cfg)
+			if err != nil {
+				t.Fatalf("failed to create replayer: %v", err)
+			}
+
+			start := time.Unix(0, 0)
+			now := start
+			r.now = func() time.Time { return now }
+			r.wait = func(_ context.Context, t time.Time) error {
+				if t.After(now) {
+					now = t
+				}
+				return nil
+			}
+			c.now = func() time.Time { return now }
+
+			stats, err := r.Replay(context.Background(), ps)
+			if err != nil {
+				t.Fatalf("failed to replay: %v", err)
+			}
+
+			var at []time.Duration
+			for _, w := range c.writes {
+				at = append(at, w.Sub(start))
+			}
+
+			if want, got := tt.at, at; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected write times:\n- want: %v\n-  got: %v", want, got)
+			}
+			if want, got := tt.stats, stats; want != got {
+				t.Fatalf("unexpected stats: %+v != %+v", want, got)
+			}
+		})
+	}
+}
+
+func TestReplayForeverCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c := &testConn{}
+	c.now = func() time.Time {
+		// Cancel once a few loops have been written.
+		if len(c.writes) == 4 {
+			cancel()
+		}
+		return time.Time{}
+	}
+
+	r, err := New(c, &Config{Loops: -1, TopSpeed: true})
+	if err != nil {
+		t.Fatalf("failed to create replayer: %v", err)
+	}
+
+	stats, err := r.Replay(ctx, []Packet{{Data: []byte{1}}})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected error: %v != %v", context.Canceled, err)
+	}
+	if want, got := 5, stats.Loops; want != got {
+		t.Fatalf("unexpected number of loops: %v != %v", want, got)
+	}
+}
+
+func TestReplayWriteError(t *testing.T) {
+	errWrite := errors.New("write error")
+
+	r, err := New(&testConn{err: errWrite}, &Config{TopSpeed: true})
+	if err != nil {
+		t.Fatalf("failed to create replayer: %v", err)
+	}
+
+	if _, err := r.Replay(context.Background(), []Packet{{Data: []byte{1}}}); err != errWrite {
+		t.Fatalf("unexpected error: %v != %v", errWrite, err)
+	}
+}
+
+// testConn is a net.PacketConn which records the time of each write.
+type testConn struct {
+	net.PacketConn
+	now    func() time.Time
+	writes []time.Time
+	err    error
+}
+
+func (c *testConn) WriteTo(b []byte, _ net.Addr) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	c.writes = append(c.writes, c.now())
+	return len(b), nil
+}
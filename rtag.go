@@ -0,0 +1,73 @@
+package ethernet
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// EtherTypeRTag is the EtherType of an IEEE 802.1CB Frame Replication and
+// Elimination for Reliability redundancy tag (R-TAG).
+const EtherTypeRTag EtherType = 0xf1c1
+
+// rtagLen is the length of an R-TAG, excluding its own EtherType but
+// including the EtherType of the encapsulated payload.
+const rtagLen = 6
+
+// An RTag is an IEEE 802.1CB redundancy tag (R-TAG), which carries the
+// sequence number used to eliminate duplicate frames in a replicated
+// stream.  An RTag is carried at the beginning of the payload of a Frame
+// whose EtherType is EtherTypeRTag.
+type RTag struct {
+	// SequenceNumber specifies the sequence number of the frame within its
+	// stream.
+	SequenceNumber uint16
+
+	// EtherType specifies the EtherType of the payload following the R-TAG.
+	EtherType EtherType
+}
+
+// MarshalBinary allocates a byte slice and marshals an RTag into binary
+// form.
+func (r *RTag) MarshalBinary() ([]byte, error) {
+	b := make([]byte, rtagLen)
+	_, err := r.read(b)
+	return b, err
+}
+
+// read reads data from an RTag into b.  read is used to marshal an RTag into
+// binary form, but does not allocate on its own.
+func (r *RTag) read(b []byte) (int, error) {
+	// 2 bytes: reserved
+	b[0], b[1] = 0, 0
+	binary.BigEndian.PutUint16(b[2:4], r.SequenceNumber)
+	binary.BigEndian.PutUint16(b[4:6], uint16(r.EtherType))
+	return rtagLen, nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into an RTag.  Trailing bytes
+// after the R-TAG are ignored.
+func (r *RTag) UnmarshalBinary(b []byte) error {
+	if len(b) < rtagLen {
+		return io.ErrUnexpectedEOF
+	}
+
+	r.SequenceNumber = binary.BigEndian.Uint16(b[2:4])
+	r.EtherType = EtherType(binary.BigEndian.Uint16(b[4:6]))
+	return nil
+}
+
+// RTag unmarshals the R-TAG at the beginning of a Frame's payload, and
+// returns it along with the remainder of the payload.  If the Frame's
+// EtherType is not EtherTypeRTag, ErrInvalidEtherType is returned.
+func (f *Frame) RTag() (*RTag, []byte, error) {
+	if f.EtherType != EtherTypeRTag {
+		return nil, nil, ErrInvalidEtherType
+	}
+
+	r := new(RTag)
+	if err := r.UnmarshalBinary(f.Payload); err != nil {
+		return nil, nil, err
+	}
+
+	return r, f.Payload[rtagLen:], nil
+}
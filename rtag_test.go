@@ -0,0 +1,52 @@
+package ethernet
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestFrameRTag(t *testing.T) {
+	r := &RTag{
+		SequenceNumber: 0x1234,
+		EtherType:      EtherTypeIPv6,
+	}
+
+	rb, err := r.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	if want, got := []byte{0x00, 0x00, 0x12, 0x34, 0x86, 0xdd}, rb; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected R-TAG bytes:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	f := &Frame{
+		VLAN:      &VLAN{ID: 10},
+		EtherType: EtherTypeRTag,
+		Payload:   append(rb, 0xff, 0xff),
+	}
+
+	got, payload, err := f.RTag()
+	if err != nil {
+		t.Fatalf("failed to get R-TAG: %v", err)
+	}
+
+	if !reflect.DeepEqual(r, got) {
+		t.Fatalf("unexpected R-TAG:\n- want: %v\n-  got: %v", r, got)
+	}
+	if want, got := []byte{0xff, 0xff}, payload; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected payload:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	f.Payload = f.Payload[:5]
+	if _, _, err := f.RTag(); err != io.ErrUnexpectedEOF {
+		t.Fatalf("unexpected error: %v != %v", io.ErrUnexpectedEOF, err)
+	}
+
+	f.EtherType = EtherTypeIPv4
+	if _, _, err := f.RTag(); err != ErrInvalidEtherType {
+		t.Fatalf("unexpected error: %v != %v", ErrInvalidEtherType, err)
+	}
+}
@@ -0,0 +1,169 @@
+// Package sll implements parsing of Linux "cooked" capture headers, as
+// produced by capturing on the "any" pseudo-interface, and conversion of
+// cooked packets into Ethernet frames.
+package sll
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/mdlayher/ethernet"
+)
+
+const (
+	// HeaderLen and Header2Len are the lengths of SLL and SLL2 headers.
+	HeaderLen  = 16
+	Header2Len = 20
+
+	// maxAddrLen is the size of the link-layer address field.
+	maxAddrLen = 8
+)
+
+// ARPHRDEther is the ARPHRD value of an Ethernet device.
+const ARPHRDEther = 1
+
+// Protocol values which do not specify an EtherType.
+const (
+	// protocolNovellRaw indicates a Novell raw IEEE 802.3 frame.
+	protocolNovellRaw = 0x0001
+
+	// protocolLLC indicates an IEEE 802.2 LLC frame.
+	protocolLLC = 0x0004
+)
+
+// ErrInvalidAddress is returned when a cooked header's link-layer address
+// length is larger than 8 bytes.
+var ErrInvalidAddress = errors.New("sll: invalid link-layer address length")
+
+// A PacketType indicates the direction and addressing of a cooked packet.
+type PacketType uint8
+
+// Possible PacketType values.
+const (
+	PacketHost PacketType = iota
+	PacketBroadcast
+	PacketMulticast
+	PacketOtherHost
+	PacketOutgoing
+)
+
+// String returns the name of a PacketType.
+func (t PacketType) String() string {
+	switch t {
+	case PacketHost:
+		return "host"
+	case PacketBroadcast:
+		return "broadcast"
+	case PacketMulticast:
+		return "multicast"
+	case PacketOtherHost:
+		return "otherhost"
+	case PacketOutgoing:
+		return "outgoing"
+	default:
+		return fmt.Sprintf("PacketType(%d)", uint8(t))
+	}
+}
+
+// A Header is a Linux cooked capture header, in either SLL or SLL2 format.
+type Header struct {
+	// PacketType specifies the direction and addressing of the packet.
+	PacketType PacketType
+
+	// ARPHRD specifies the ARPHRD type of the device on which the packet
+	// was captured.
+	ARPHRD uint16
+
+	// Addr is the link-layer address of the packet's sender.
+	Addr net.HardwareAddr
+
+	// Protocol is the EtherType of the packet, or a Linux ETH_P value for
+	// packets without an EtherType.
+	Protocol ethernet.EtherType
+
+	// InterfaceIndex is the index of the interface on which the packet was
+	// captured.  InterfaceIndex is only present in SLL2 headers.
+	InterfaceIndex int
+}
+
+// Parse parses an SLL header from b, returning the header and the packet
+// which follows it.  The returned values alias b.
+func Parse(b []byte) (*Header, []byte, error) {
+	if len(b) < HeaderLen {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+
+	al := int(binary.BigEndian.Uint16(b[4:6]))
+	if al > maxAddrLen {
+		return nil, nil, ErrInvalidAddress
+	}
+
+	h := &Header{
+		PacketType: PacketType(binary.BigEndian.Uint16(b[0:2])),
+		ARPHRD:     binary.BigEndian.Uint16(b[2:4]),
+		Addr:       net.HardwareAddr(b[6 : 6+al : 6+al]),
+		Protocol:   ethernet.EtherType(binary.BigEndian.Uint16(b[14:16])),
+	}
+
+	return h, b[HeaderLen:], nil
+}
+
+// Parse2 parses an SLL2 header from b, returning the header and the packet
+// which follows it.  The returned values alias b.
+func Parse2(b []byte) (*Header, []byte, error) {
+	if len(b) < Header2Len {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+
+	al := int(b[11])
+	if al > maxAddrLen {
+		return nil, nil, ErrInvalidAddress
+	}
+
+	h := &Header{
+		Protocol:       ethernet.EtherType(binary.BigEndian.Uint16(b[0:2])),
+		InterfaceIndex: int(binary.BigEndian.Uint32(b[4:8])),
+		ARPHRD:         binary.BigEndian.Uint16(b[8:10]),
+		PacketType:     PacketType(b[10]),
+		Addr:           net.HardwareAddr(b[12 : 12+al : 12+al]),
+	}
+
+	return h, b[Header2Len:], nil
+}
+
+// Frame converts a cooked header and the packet which follows it into an
+// Ethernet frame.  The Frame does not alias payload.
+//
+// Cooked headers do not record a destination address, so the destination
+// of the Frame is ethernet.Broadcast for broadcast packets, and is otherwise
+// the all-zeros address.  The source of the Frame is h.Addr if it is a 6
+// byte address, and is otherwise the all-zeros address.  Packets with LLC
+// and Novell raw protocols are converted into IEEE 802.3 frames.
+func (h *Header) Frame(payload []byte) (*ethernet.Frame, error) {
+	b := make([]byte, 14+len(payload))
+	if h.PacketType == PacketBroadcast {
+		copy(b[0:6], ethernet.Broadcast)
+	}
+	if len(h.Addr) == 6 {
+		copy(b[6:12], h.Addr)
+	}
+
+	switch h.Protocol {
+	case protocolNovellRaw, protocolLLC:
+		binary.BigEndian.PutUint16(b[12:14], uint16(len(payload)))
+	default:
+		binary.BigEndian.PutUint16(b[12:14], uint16(h.Protocol))
+	}
+	copy(b[14:], payload)
+
+	// b is not shared, so the Frame may alias it.
+	f := new(ethernet.Frame)
+	if err := (ethernet.UnmarshalOptions{ZeroCopy: true}).Unmarshal(b, f); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
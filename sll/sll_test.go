@@ -0,0 +1,189 @@
+package sll
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestParse(t *testing.T) {
+	addr := net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}
+
+	tests := []struct {
+		desc    string
+		parse   func(b []byte) (*Header, []byte, error)
+		b       []byte
+		h       *Header
+		payload []byte
+		err     error
+	}{
+		{
+			desc:  "SLL short",
+			parse: Parse,
+			b:     make([]byte, HeaderLen-1),
+			err:   io.ErrUnexpectedEOF,
+		},
+		{
+			desc:  "SLL bad address length",
+			parse: Parse,
+			b: []byte{
+				0x00, 0x00, 0x00, 0x01,
+				0x00, 0x09, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x08, 0x00,
+			},
+			err: ErrInvalidAddress,
+		},
+		{
+			desc:  "SLL OK",
+			parse: Parse,
+			b: []byte{
+				0x00, 0x01, 0x00, 0x01,
+				0x00, 0x06, 0xde, 0xad,
+				0xbe, 0xef, 0xde, 0xad,
+				0x00, 0x00, 0x08, 0x06,
+				0xaa,
+			},
+			h: &Header{
+				PacketType: PacketBroadcast,
+				ARPHRD:     ARPHRDEther,
+				Addr:       addr,
+				Protocol:   ethernet.EtherTypeARP,
+			},
+			payload: []byte{0xaa},
+		},
+		{
+			desc:  "SLL2 short",
+			parse: Parse2,
+			b:     make([]byte, Header2Len-1),
+			err:   io.ErrUnexpectedEOF,
+		},
+		{
+			desc:  "SLL2 OK",
+			parse: Parse2,
+			b: []byte{
+				0x86, 0xdd, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x02,
+				0x00, 0x01, 0x04, 0x06,
+				0xde, 0xad, 0xbe, 0xef,
+				0xde, 0xad, 0x00, 0x00,
+				0xaa,
+			},
+			h: &Header{
+				PacketType:     PacketOutgoing,
+				ARPHRD:         ARPHRDEther,
+				Addr:           addr,
+				Protocol:       ethernet.EtherTypeIPv6,
+				InterfaceIndex: 2,
+			},
+			payload: []byte{0xaa},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			h, payload, err := tt.parse(tt.b)
+			if want, got := tt.err, err; want != got {
+				t.Fatalf("unexpected error: %v != %v", want, got)
+			}
+			if err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(tt.h, h) {
+				t.Fatalf("unexpected header:\n- want: %#v\n-  got: %#v", tt.h, h)
+			}
+			if want, got := tt.payload, payload; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected payload: %v != %v", want, got)
+			}
+		})
+	}
+}
+
+func TestHeaderFrame(t *testing.T) {
+	addr := net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}
+	zero := net.HardwareAddr{0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+
+	tests := []struct {
+		desc    string
+		h       *Header
+		payload []byte
+		f       *ethernet.Frame
+	}{
+		{
+			desc: "broadcast",
+			h: &Header{
+				PacketType: PacketBroadcast,
+				Addr:       addr,
+				Protocol:   ethernet.EtherTypeARP,
+			},
+			payload: []byte{0xaa},
+			f: &ethernet.Frame{
+				Destination: ethernet.Broadcast,
+				Source:      addr,
+				EtherType:   ethernet.EtherTypeARP,
+				Payload:     []byte{0xaa},
+			},
+		},
+		{
+			desc: "unknown address",
+			h: &Header{
+				Addr:     net.HardwareAddr{0x01, 0x02},
+				Protocol: ethernet.EtherTypeIPv4,
+			},
+			payload: []byte{0xaa},
+			f: &ethernet.Frame{
+				Destination: zero,
+				Source:      zero,
+				EtherType:   ethernet.EtherTypeIPv4,
+				Payload:     []byte{0xaa},
+			},
+		},
+		{
+			desc: "LLC",
+			h: &Header{
+				Addr:     addr,
+				Protocol: protocolLLC,
+			},
+			payload: []byte{0x42, 0x42, 0x03, 0xaa},
+			f: &ethernet.Frame{
+				Destination:   zero,
+				Source:        addr,
+				EtherType:     4,
+				Encapsulation: ethernet.EncapsulationLLC,
+				LLC: &ethernet.LLC{
+					DSAP:    0x42,
+					SSAP:    0x42,
+					Control: 0x03,
+				},
+				Payload: []byte{0xaa},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			f, err := tt.h.Frame(tt.payload)
+			if err != nil {
+				t.Fatalf("failed to convert frame: %v", err)
+			}
+
+			if !reflect.DeepEqual(tt.f, f) {
+				t.Fatalf("unexpected frame:\n- want: %#v\n-  got: %#v", tt.f, f)
+			}
+		})
+	}
+}
+
+func TestPacketTypeString(t *testing.T) {
+	if want, got := "outgoing", PacketOutgoing.String(); want != got {
+		t.Fatalf("unexpected string: %q != %q", want, got)
+	}
+	if want, got := "PacketType(10)", PacketType(10).String(); want != got {
+		t.Fatalf("unexpected string: %q != %q", want, got)
+	}
+}
@@ -0,0 +1,171 @@
+package stp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+const (
+	// mstLen is the length of the MSTP fields which follow the version 3
+	// length, excluding MSTI configuration messages.
+	mstLen = 64
+
+	// mstiLen is the length of an MSTI configuration message.
+	mstiLen = 16
+
+	// maxNameLen is the length of an MST configuration name.
+	maxNameLen = 32
+)
+
+// FlagMaster is the master flag of an MSTI configuration message, which
+// occupies the position of FlagTopologyChangeAck.
+const FlagMaster Flags = 0x80
+
+// An MST contains the MSTP fields of a Rapid Spanning Tree BPDU, which
+// describe the common and internal spanning tree and each multiple spanning
+// tree instance.
+type MST struct {
+	// ConfigID identifies the MST region of the transmitting bridge.
+	ConfigID ConfigID
+
+	// InternalRootPathCost specifies the CIST internal root path cost.
+	InternalRootPathCost uint32
+
+	// BridgeID specifies the CIST bridge identifier of the transmitting
+	// bridge.
+	BridgeID BridgeID
+
+	// RemainingHops specifies the CIST remaining hops.
+	RemainingHops uint8
+
+	// MSTIs specifies the MSTI configuration messages, one for each
+	// multiple spanning tree instance.
+	MSTIs []MSTI
+}
+
+// A ConfigID is an MST configuration identifier.  Bridges with equal
+// ConfigIDs are members of the same MST region.
+type ConfigID struct {
+	// FormatSelector specifies the format of the ConfigID, which is zero.
+	FormatSelector uint8
+
+	// Name specifies the configuration name, which is at most 32 bytes.
+	Name string
+
+	// Revision specifies the configuration revision level.
+	Revision uint16
+
+	// Digest specifies the HMAC-MD5 digest of the VLAN to MSTI mapping.
+	Digest [16]byte
+}
+
+// An MSTI is an MSTI configuration message, which carries the spanning tree
+// information of a single multiple spanning tree instance.
+type MSTI struct {
+	// Flags specifies the MSTI flags and port role.  FlagMaster is used in
+	// place of FlagTopologyChangeAck.
+	Flags Flags
+
+	// RegionalRootID specifies the bridge identifier of the MSTI regional
+	// root.  The low 12 bits of its priority are the MSTI number.
+	RegionalRootID BridgeID
+
+	// InternalRootPathCost specifies the MSTI internal root path cost.
+	InternalRootPathCost uint32
+
+	// BridgePriority and PortPriority specify the 4 bit bridge and port
+	// priorities of the transmitting bridge and port.
+	BridgePriority uint8
+	PortPriority   uint8
+
+	// RemainingHops specifies the MSTI remaining hops.
+	RemainingHops uint8
+}
+
+// append appends the binary form of an MST, preceded by its version 3
+// length, to b.
+func (m *MST) append(b []byte) ([]byte, error) {
+	if len(m.ConfigID.Name) > maxNameLen {
+		return nil, ErrInvalidMST
+	}
+
+	n := mstLen + len(m.MSTIs)*mstiLen
+	if n > 0xffff {
+		return nil, ErrInvalidMST
+	}
+
+	v := make([]byte, 2+n)
+	binary.BigEndian.PutUint16(v[0:2], uint16(n))
+
+	v[2] = m.ConfigID.FormatSelector
+	copy(v[3:35], m.ConfigID.Name)
+	binary.BigEndian.PutUint16(v[35:37], m.ConfigID.Revision)
+	copy(v[37:53], m.ConfigID.Digest[:])
+
+	binary.BigEndian.PutUint32(v[53:57], m.InternalRootPathCost)
+	if err := m.BridgeID.read(v[57:65]); err != nil {
+		return nil, err
+	}
+	v[65] = m.RemainingHops
+
+	for i, msti := range m.MSTIs {
+		mb := v[66+i*mstiLen : 66+(i+1)*mstiLen]
+
+		mb[0] = uint8(msti.Flags)
+		if err := msti.RegionalRootID.read(mb[1:9]); err != nil {
+			return nil, err
+		}
+		binary.BigEndian.PutUint32(mb[9:13], msti.InternalRootPathCost)
+		mb[13] = msti.BridgePriority << 4
+		mb[14] = msti.PortPriority << 4
+		mb[15] = msti.RemainingHops
+	}
+
+	return append(b, v...), nil
+}
+
+// unmarshal unmarshals the version 3 length and the MSTP fields which
+// follow it from b.
+func (m *MST) unmarshal(b []byte) error {
+	if len(b) < 2 {
+		return io.ErrUnexpectedEOF
+	}
+
+	n := int(binary.BigEndian.Uint16(b[0:2]))
+	if n < mstLen || (n-mstLen)%mstiLen != 0 {
+		return ErrInvalidMST
+	}
+
+	b = b[2:]
+	if len(b) < n {
+		return io.ErrUnexpectedEOF
+	}
+
+	m.ConfigID = ConfigID{
+		FormatSelector: b[0],
+		Name:           string(bytes.TrimRight(b[1:33], "\x00")),
+		Revision:       binary.BigEndian.Uint16(b[33:35]),
+	}
+	copy(m.ConfigID.Digest[:], b[35:51])
+
+	m.InternalRootPathCost = binary.BigEndian.Uint32(b[51:55])
+	m.BridgeID = parseBridgeID(b[55:63])
+	m.RemainingHops = b[63]
+
+	m.MSTIs = nil
+	for i := mstLen; i < n; i += mstiLen {
+		mb := b[i : i+mstiLen]
+
+		m.MSTIs = append(m.MSTIs, MSTI{
+			Flags:                Flags(mb[0]),
+			RegionalRootID:       parseBridgeID(mb[1:9]),
+			InternalRootPathCost: binary.BigEndian.Uint32(mb[9:13]),
+			BridgePriority:       mb[13] >> 4,
+			PortPriority:         mb[14] >> 4,
+			RemainingHops:        mb[15],
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package stp
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMSTPMarshalUnmarshal(t *testing.T) {
+	root := BridgeID{
+		Priority: 0x8000,
+		Address:  net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+	}
+
+	bpdu := &BPDU{
+		Version:      VersionMSTP,
+		Type:         TypeRST,
+		Flags:        FlagLearning.WithRole(RoleRoot),
+		RootID:       root,
+		RootPathCost: 20000,
+		BridgeID:     root,
+		PortID:       0x8001,
+		MaxAge:       20 * time.Second,
+		HelloTime:    2 * time.Second,
+		ForwardDelay: 15 * time.Second,
+		MST: &MST{
+			ConfigID: ConfigID{
+				Name:     "region1",
+				Revision: 2,
+				Digest:   [16]byte{0xac, 0x36, 0x17, 0x7f},
+			},
+			InternalRootPathCost: 200,
+			BridgeID: BridgeID{
+				Priority: 0x8000,
+				Address:  net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55},
+			},
+			RemainingHops: 20,
+			MSTIs: []MSTI{{
+				Flags: (FlagMaster | FlagForwarding).WithRole(RoleDesignated),
+				RegionalRootID: BridgeID{
+					Priority: 0x1001,
+					Address:  net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55},
+				},
+				InternalRootPathCost: 0,
+				BridgePriority:       1,
+				PortPriority:         8,
+				RemainingHops:        20,
+			}},
+		},
+	}
+
+	b, err := bpdu.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	if want, got := rstLen+2+mstLen+mstiLen, len(b); want != got {
+		t.Fatalf("unexpected length: %v != %v", want, got)
+	}
+	if want, got := []byte{0x00, 0x50}, b[36:38]; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected version 3 length: %v != %v", want, got)
+	}
+	if want, got := []byte{0x10, 0x80, 0x14}, b[len(b)-3:]; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected MSTI priorities and hops: %v != %v", want, got)
+	}
+
+	got := new(BPDU)
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if !reflect.DeepEqual(bpdu, got) {
+		t.Fatalf("unexpected BPDU:\n- want: %#v\n-  got: %#v", bpdu, got)
+	}
+}
+
+func TestMSTPErrors(t *testing.T) {
+	valid, err := (&BPDU{
+		Version: VersionMSTP,
+		Type:    TypeRST,
+		RootID:  BridgeID{Address: make(net.HardwareAddr, 6)},
+		BridgeID: BridgeID{
+			Address: make(net.HardwareAddr, 6),
+		},
+		MST: &MST{
+			BridgeID: BridgeID{Address: make(net.HardwareAddr, 6)},
+		},
+	}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	badLength := append([]byte(nil), valid...)
+	badLength[37]++
+
+	tests := []struct {
+		desc string
+		b    []byte
+		err  error
+	}{
+		{
+			desc: "no version 3 length",
+			b:    valid[:rstLen],
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "bad version 3 length",
+			b:    badLength,
+			err:  ErrInvalidMST,
+		},
+		{
+			desc: "truncated",
+			b:    valid[:len(valid)-1],
+			err:  io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := new(BPDU).UnmarshalBinary(tt.b); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+
+	bpdu := &BPDU{
+		Type:     TypeRST,
+		RootID:   BridgeID{Address: make(net.HardwareAddr, 6)},
+		BridgeID: BridgeID{Address: make(net.HardwareAddr, 6)},
+		MST: &MST{
+			ConfigID: ConfigID{Name: strings.Repeat("a", maxNameLen+1)},
+		},
+	}
+
+	if _, err := bpdu.MarshalBinary(); err != ErrInvalidMST {
+		t.Fatalf("expected ErrInvalidMST, but got: %v", err)
+	}
+}
@@ -0,0 +1,366 @@
+// Package stp implements marshaling and unmarshaling of IEEE 802.1D Spanning
+// Tree Protocol, Rapid Spanning Tree Protocol, and IEEE 802.1Q Multiple
+// Spanning Tree Protocol bridge protocol data units carried in IEEE 802.2 LLC
+// frames.
+package stp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// SAP is the IEEE 802.2 LLC service access point which indicates a BPDU in
+// a Frame.
+const SAP = ethernet.SAPSTP
+
+// Destination is the hardware address to which BPDUs are sent.
+var Destination = net.HardwareAddr{0x01, 0x80, 0xc2, 0x00, 0x00, 0x00}
+
+const (
+	// configLen, tcnLen, and rstLen are the lengths of Configuration,
+	// Topology Change Notification, and Rapid Spanning Tree BPDUs.
+	configLen = 35
+	tcnLen    = 4
+	rstLen    = 36
+
+	// controlUI is the LLC control field of an unnumbered information PDU.
+	controlUI = 0x03
+)
+
+var (
+	// ErrInvalidSAP is returned when a Frame does not carry an LLC header
+	// with SAP as both its DSAP and SSAP.
+	ErrInvalidSAP = errors.New("stp: invalid LLC SAP")
+
+	// ErrInvalidProtocol is returned when a BPDU's protocol identifier is
+	// not zero.
+	ErrInvalidProtocol = errors.New("stp: invalid protocol identifier")
+
+	// ErrInvalidType is returned when a BPDU's type is not known.
+	ErrInvalidType = errors.New("stp: invalid BPDU type")
+
+	// ErrInvalidBridgeID is returned when a BridgeID's address is not a 6
+	// byte hardware address.
+	ErrInvalidBridgeID = errors.New("stp: invalid bridge identifier")
+
+	// ErrInvalidMST is returned when an MSTP BPDU's version 3 length does
+	// not describe its MST configuration and MSTI configuration messages,
+	// or when an MST configuration name is longer than 32 bytes.
+	ErrInvalidMST = errors.New("stp: invalid MST configuration")
+)
+
+// Possible BPDU protocol versions.
+const (
+	VersionSTP  = 0
+	VersionRSTP = 2
+	VersionMSTP = 3
+)
+
+// A Type is the type of a BPDU.
+type Type uint8
+
+// Possible Type values.
+const (
+	TypeConfiguration Type = 0x00
+	TypeRST           Type = 0x02
+	TypeTCN           Type = 0x80
+)
+
+// String returns the name of a Type.
+func (t Type) String() string {
+	switch t {
+	case TypeConfiguration:
+		return "Configuration"
+	case TypeRST:
+		return "RST"
+	case TypeTCN:
+		return "TCN"
+	default:
+		return fmt.Sprintf("Type(%d)", uint8(t))
+	}
+}
+
+// Flags are flags carried in a Configuration or Rapid Spanning Tree BPDU.
+// The Proposal, Learning, Forwarding, and Agreement flags and the port role
+// are only used in Rapid Spanning Tree BPDUs.
+type Flags uint8
+
+// Possible Flags values.
+const (
+	FlagTopologyChange    Flags = 0x01
+	FlagProposal          Flags = 0x02
+	FlagLearning          Flags = 0x10
+	FlagForwarding        Flags = 0x20
+	FlagAgreement         Flags = 0x40
+	FlagTopologyChangeAck Flags = 0x80
+
+	// flagRoleMask is the mask of the 2 bit port role.
+	flagRoleMask Flags = 0x0c
+)
+
+// Role returns the port role carried in Flags.
+func (f Flags) Role() PortRole {
+	return PortRole((f & flagRoleMask) >> 2)
+}
+
+// WithRole returns a copy of Flags with its port role set to r.
+func (f Flags) WithRole(r PortRole) Flags {
+	return f&^flagRoleMask | Flags(r&0x03)<<2
+}
+
+// A PortRole is the role of the port which transmitted a Rapid Spanning Tree
+// BPDU.
+type PortRole uint8
+
+// Possible PortRole values.
+const (
+	RoleUnknown         PortRole = 0
+	RoleAlternateBackup PortRole = 1
+	RoleRoot            PortRole = 2
+	RoleDesignated      PortRole = 3
+)
+
+// String returns the name of a PortRole.
+func (r PortRole) String() string {
+	switch r {
+	case RoleUnknown:
+		return "Unknown"
+	case RoleAlternateBackup:
+		return "AlternateBackup"
+	case RoleRoot:
+		return "Root"
+	case RoleDesignated:
+		return "Designated"
+	default:
+		return fmt.Sprintf("PortRole(%d)", uint8(r))
+	}
+}
+
+// A BridgeID is a bridge identifier, consisting of a priority and the
+// hardware address of the bridge.
+type BridgeID struct {
+	// Priority specifies the bridge priority.  The high 4 bits are the
+	// configured priority, and the low 12 bits are the system ID extension,
+	// which is normally a VLAN ID.
+	Priority uint16
+
+	// Address specifies the hardware address of the bridge.
+	Address net.HardwareAddr
+}
+
+// String returns the conventional textual form of a BridgeID, such as
+// "8000.de:ad:be:ef:de:ad".
+func (id BridgeID) String() string {
+	return fmt.Sprintf("%04x.%s", id.Priority, id.Address)
+}
+
+// read marshals a BridgeID into b.
+func (id BridgeID) read(b []byte) error {
+	if len(id.Address) != 6 {
+		return ErrInvalidBridgeID
+	}
+
+	binary.BigEndian.PutUint16(b[0:2], id.Priority)
+	copy(b[2:8], id.Address)
+	return nil
+}
+
+// parseBridgeID unmarshals a BridgeID from b.
+func parseBridgeID(b []byte) BridgeID {
+	addr := make(net.HardwareAddr, 6)
+	copy(addr, b[2:8])
+
+	return BridgeID{
+		Priority: binary.BigEndian.Uint16(b[0:2]),
+		Address:  addr,
+	}
+}
+
+// A BPDU is a spanning tree bridge protocol data unit.  Fields other than
+// Version and Type are only present in Configuration and Rapid Spanning Tree
+// BPDUs.
+//
+// In MSTP BPDUs, RootID, RootPathCost, and BridgeID specify the CIST root
+// identifier, the CIST external root path cost, and the CIST regional root
+// identifier.
+type BPDU struct {
+	// Version specifies the protocol version, such as VersionSTP,
+	// VersionRSTP, or VersionMSTP.
+	Version uint8
+
+	// Type specifies the type of the BPDU.
+	Type Type
+
+	// Flags specifies the topology change flags.
+	Flags Flags
+
+	// RootID specifies the bridge identifier of the root bridge.
+	RootID BridgeID
+
+	// RootPathCost specifies the cost of the path to the root bridge from
+	// the transmitting bridge.
+	RootPathCost uint32
+
+	// BridgeID specifies the bridge identifier of the transmitting bridge.
+	BridgeID BridgeID
+
+	// PortID specifies the port identifier of the transmitting port,
+	// consisting of a 4 bit priority and a 12 bit port number.
+	PortID uint16
+
+	// MessageAge, MaxAge, HelloTime, and ForwardDelay specify the protocol
+	// timers, which are transmitted in units of 1/256 seconds.
+	MessageAge   time.Duration
+	MaxAge       time.Duration
+	HelloTime    time.Duration
+	ForwardDelay time.Duration
+
+	// MST specifies the MSTP fields of a Rapid Spanning Tree BPDU.  MST is
+	// set when unmarshaling BPDUs with a Version of VersionMSTP or greater,
+	// and is marshaled whenever it is not nil.
+	MST *MST
+}
+
+// Parse unmarshals the BPDU carried in the payload of Frame f.  If f does
+// not carry an LLC header addressed from and to SAP, ErrInvalidSAP is
+// returned.
+func Parse(f *ethernet.Frame) (*BPDU, error) {
+	if f.LLC == nil || f.LLC.DSAP != SAP || f.LLC.SSAP != SAP {
+		return nil, ErrInvalidSAP
+	}
+
+	bpdu := new(BPDU)
+	if err := bpdu.UnmarshalBinary(f.Payload); err != nil {
+		return nil, err
+	}
+
+	return bpdu, nil
+}
+
+// Frame marshals a BPDU into the payload of an IEEE 802.3 frame sent from
+// hardware address src to Destination.
+func (bpdu *BPDU) Frame(src net.HardwareAddr) (*ethernet.Frame, error) {
+	b, err := bpdu.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ethernet.Frame{
+		Destination: append(net.HardwareAddr(nil), Destination...),
+		Source:      src,
+		LLC: &ethernet.LLC{
+			DSAP:    SAP,
+			SSAP:    SAP,
+			Control: controlUI,
+		},
+		Payload: b,
+	}, nil
+}
+
+// MarshalBinary allocates a byte slice and marshals a BPDU into binary form.
+func (bpdu *BPDU) MarshalBinary() ([]byte, error) {
+	n := configLen
+	switch bpdu.Type {
+	case TypeTCN:
+		return []byte{0x00, 0x00, bpdu.Version, uint8(bpdu.Type)}, nil
+	case TypeConfiguration:
+	case TypeRST:
+		// The version 1 length is always zero.
+		n = rstLen
+	default:
+		return nil, ErrInvalidType
+	}
+
+	b := make([]byte, n)
+	b[2] = bpdu.Version
+	b[3] = uint8(bpdu.Type)
+	b[4] = uint8(bpdu.Flags)
+
+	if err := bpdu.RootID.read(b[5:13]); err != nil {
+		return nil, err
+	}
+	binary.BigEndian.PutUint32(b[13:17], bpdu.RootPathCost)
+	if err := bpdu.BridgeID.read(b[17:25]); err != nil {
+		return nil, err
+	}
+	binary.BigEndian.PutUint16(b[25:27], bpdu.PortID)
+
+	for i, d := range []time.Duration{
+		bpdu.MessageAge,
+		bpdu.MaxAge,
+		bpdu.HelloTime,
+		bpdu.ForwardDelay,
+	} {
+		binary.BigEndian.PutUint16(b[27+i*2:29+i*2], uint16(d*256/time.Second))
+	}
+
+	if bpdu.Type != TypeRST || bpdu.MST == nil {
+		return b, nil
+	}
+
+	return bpdu.MST.append(b)
+}
+
+// UnmarshalBinary unmarshals a byte slice into a BPDU.  Trailing bytes, such
+// as Ethernet padding, are ignored.
+func (bpdu *BPDU) UnmarshalBinary(b []byte) error {
+	if len(b) < tcnLen {
+		return io.ErrUnexpectedEOF
+	}
+
+	if binary.BigEndian.Uint16(b[0:2]) != 0 {
+		return ErrInvalidProtocol
+	}
+
+	out := BPDU{
+		Version: b[2],
+		Type:    Type(b[3]),
+	}
+
+	n := configLen
+	switch out.Type {
+	case TypeTCN:
+		*bpdu = out
+		return nil
+	case TypeConfiguration:
+	case TypeRST:
+		n = rstLen
+	default:
+		return ErrInvalidType
+	}
+
+	if len(b) < n {
+		return io.ErrUnexpectedEOF
+	}
+
+	out.Flags = Flags(b[4])
+	out.RootID = parseBridgeID(b[5:13])
+	out.RootPathCost = binary.BigEndian.Uint32(b[13:17])
+	out.BridgeID = parseBridgeID(b[17:25])
+	out.PortID = binary.BigEndian.Uint16(b[25:27])
+
+	for i, d := range []*time.Duration{
+		&out.MessageAge,
+		&out.MaxAge,
+		&out.HelloTime,
+		&out.ForwardDelay,
+	} {
+		*d = time.Duration(binary.BigEndian.Uint16(b[27+i*2:29+i*2])) * time.Second / 256
+	}
+
+	if out.Type == TypeRST && out.Version >= VersionMSTP {
+		out.MST = new(MST)
+		if err := out.MST.unmarshal(b[rstLen:]); err != nil {
+			return err
+		}
+	}
+
+	*bpdu = out
+	return nil
+}
@@ -0,0 +1,220 @@
+package stp
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestBPDUMarshalUnmarshal(t *testing.T) {
+	tests := []struct {
+		desc string
+		bpdu *BPDU
+		b    []byte
+	}{
+		{
+			desc: "TCN",
+			bpdu: &BPDU{Type: TypeTCN},
+			b:    []byte{0x00, 0x00, 0x00, 0x80},
+		},
+		{
+			desc: "configuration",
+			bpdu: &BPDU{
+				Type:  TypeConfiguration,
+				Flags: FlagTopologyChange | FlagTopologyChangeAck,
+				RootID: BridgeID{
+					Priority: 0x8001,
+					Address:  net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+				},
+				RootPathCost: 4,
+				BridgeID: BridgeID{
+					Priority: 0x8001,
+					Address:  net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55},
+				},
+				PortID:       0x8002,
+				MessageAge:   1 * time.Second,
+				MaxAge:       20 * time.Second,
+				HelloTime:    2 * time.Second,
+				ForwardDelay: 15 * time.Second,
+			},
+			b: []byte{
+				0x00, 0x00, 0x00, 0x00,
+				0x81,
+				0x80, 0x01, 0xde, 0xad, 0xbe, 0xef, 0xde, 0xad,
+				0x00, 0x00, 0x00, 0x04,
+				0x80, 0x01, 0x00, 0x11, 0x22, 0x33, 0x44, 0x55,
+				0x80, 0x02,
+				0x01, 0x00,
+				0x14, 0x00,
+				0x02, 0x00,
+				0x0f, 0x00,
+			},
+		},
+		{
+			desc: "RST",
+			bpdu: &BPDU{
+				Version: VersionRSTP,
+				Type:    TypeRST,
+				Flags:   (FlagProposal | FlagForwarding).WithRole(RoleDesignated),
+				RootID: BridgeID{
+					Priority: 0x1000,
+					Address:  net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+				},
+				BridgeID: BridgeID{
+					Priority: 0x1000,
+					Address:  net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+				},
+				PortID:       0x8001,
+				MaxAge:       20 * time.Second,
+				HelloTime:    2 * time.Second,
+				ForwardDelay: 15 * time.Second,
+			},
+			b: []byte{
+				0x00, 0x00, 0x02, 0x02,
+				0x2e,
+				0x10, 0x00, 0xde, 0xad, 0xbe, 0xef, 0xde, 0xad,
+				0x00, 0x00, 0x00, 0x00,
+				0x10, 0x00, 0xde, 0xad, 0xbe, 0xef, 0xde, 0xad,
+				0x80, 0x01,
+				0x00, 0x00,
+				0x14, 0x00,
+				0x02, 0x00,
+				0x0f, 0x00,
+				0x00,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := tt.bpdu.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			if want, got := tt.b, b; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected bytes:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			bpdu := new(BPDU)
+			if err := bpdu.UnmarshalBinary(b); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+
+			if !reflect.DeepEqual(tt.bpdu, bpdu) {
+				t.Fatalf("unexpected BPDU:\n- want: %#v\n-  got: %#v", tt.bpdu, bpdu)
+			}
+		})
+	}
+}
+
+func TestBPDUUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		b    []byte
+		err  error
+	}{
+		{
+			desc: "short",
+			b:    []byte{0x00, 0x00, 0x00},
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "bad protocol",
+			b:    []byte{0x00, 0x01, 0x00, 0x80},
+			err:  ErrInvalidProtocol,
+		},
+		{
+			desc: "bad type",
+			b:    []byte{0x00, 0x00, 0x00, 0x01},
+			err:  ErrInvalidType,
+		},
+		{
+			desc: "short configuration",
+			b:    make([]byte, configLen-1),
+			err:  io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := new(BPDU).UnmarshalBinary(tt.b); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestBPDUMarshalInvalidBridgeID(t *testing.T) {
+	bpdu := &BPDU{
+		RootID: BridgeID{Address: net.HardwareAddr{0x01}},
+	}
+
+	if _, err := bpdu.MarshalBinary(); err != ErrInvalidBridgeID {
+		t.Fatalf("expected ErrInvalidBridgeID, but got: %v", err)
+	}
+}
+
+func TestFrameParse(t *testing.T) {
+	src := net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}
+	bpdu := &BPDU{Type: TypeTCN}
+
+	f, err := bpdu.Frame(src)
+	if err != nil {
+		t.Fatalf("failed to create frame: %v", err)
+	}
+
+	b, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal frame: %v", err)
+	}
+
+	f2 := new(ethernet.Frame)
+	if err := f2.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal frame: %v", err)
+	}
+
+	got, err := Parse(f2)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if !reflect.DeepEqual(bpdu, got) {
+		t.Fatalf("unexpected BPDU:\n- want: %#v\n-  got: %#v", bpdu, got)
+	}
+
+	if _, err := Parse(&ethernet.Frame{EtherType: ethernet.EtherTypeIPv4}); err != ErrInvalidSAP {
+		t.Fatalf("expected ErrInvalidSAP, but got: %v", err)
+	}
+}
+
+func TestFlagsRole(t *testing.T) {
+	f := FlagAgreement.WithRole(RoleRoot)
+	if want, got := RoleRoot, f.Role(); want != got {
+		t.Fatalf("unexpected role: %v != %v", want, got)
+	}
+
+	f = f.WithRole(RoleAlternateBackup)
+	if want, got := FlagAgreement|0x04, f; want != got {
+		t.Fatalf("unexpected flags: %#x != %#x", want, got)
+	}
+	if want, got := "AlternateBackup", f.Role().String(); want != got {
+		t.Fatalf("unexpected string: %q != %q", want, got)
+	}
+}
+
+func TestBridgeIDString(t *testing.T) {
+	id := BridgeID{
+		Priority: 0x8001,
+		Address:  net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad},
+	}
+
+	if want, got := "8001.de:ad:be:ef:de:ad", id.String(); want != got {
+		t.Fatalf("unexpected string: %q != %q", want, got)
+	}
+}
@@ -4,35 +4,47 @@ package ethernet
 
 import "fmt"
 
-const (
-	_EtherType_name_0 = "EtherTypeIPv4"
-	_EtherType_name_1 = "EtherTypeARP"
-	_EtherType_name_2 = "EtherTypeVLAN"
-	_EtherType_name_3 = "EtherTypeIPv6"
-	_EtherType_name_4 = "EtherTypeServiceVLAN"
-)
+const _EtherType_name = "EtherTypeIPv4EtherTypeARPEtherTypeWakeOnLANEtherTypeTRILLEtherTypeTEBEtherTypeRARPEtherTypeAppleTalkEtherTypeVLANEtherTypeIPXEtherTypeIPv6EtherTypeFlowControlEtherTypeSlowProtocolsEtherTypeMPLSUnicastEtherTypeMPLSMulticastEtherTypePPPoEDiscoveryEtherTypePPPoESessionEtherTypeEAPOLEtherTypePROFINETEtherTypeEtherCATEtherTypeServiceVLANEtherTypeGOOSEEtherTypeSampledValuesEtherTypeLLDPEtherTypeMACsecEtherTypePBBEtherTypePTPEtherTypeCFMEtherTypeFCoEEtherTypeFIPEtherTypeHSREtherTypeECTPEtherTypeQinQEtherTypeRTag"
 
-var (
-	_EtherType_index_0 = [...]uint8{0, 13}
-	_EtherType_index_1 = [...]uint8{0, 12}
-	_EtherType_index_2 = [...]uint8{0, 13}
-	_EtherType_index_3 = [...]uint8{0, 13}
-	_EtherType_index_4 = [...]uint8{0, 20}
-)
+var _EtherType_map = map[EtherType]string{
+	2048:  _EtherType_name[0:13],
+	2054:  _EtherType_name[13:25],
+	2114:  _EtherType_name[25:43],
+	8947:  _EtherType_name[43:57],
+	25944: _EtherType_name[57:69],
+	32821: _EtherType_name[69:82],
+	32923: _EtherType_name[82:100],
+	33024: _EtherType_name[100:113],
+	33079: _EtherType_name[113:125],
+	34525: _EtherType_name[125:138],
+	34824: _EtherType_name[138:158],
+	34825: _EtherType_name[158:180],
+	34887: _EtherType_name[180:200],
+	34888: _EtherType_name[200:222],
+	34915: _EtherType_name[222:245],
+	34916: _EtherType_name[245:266],
+	34958: _EtherType_name[266:280],
+	34962: _EtherType_name[280:297],
+	34980: _EtherType_name[297:314],
+	34984: _EtherType_name[314:334],
+	35000: _EtherType_name[334:348],
+	35002: _EtherType_name[348:370],
+	35020: _EtherType_name[370:383],
+	35045: _EtherType_name[383:398],
+	35047: _EtherType_name[398:410],
+	35063: _EtherType_name[410:422],
+	35074: _EtherType_name[422:434],
+	35078: _EtherType_name[434:447],
+	35092: _EtherType_name[447:459],
+	35119: _EtherType_name[459:471],
+	36864: _EtherType_name[471:484],
+	37120: _EtherType_name[484:497],
+	61889: _EtherType_name[497:510],
+}
 
-func (i EtherType) String() string {
-	switch {
-	case i == 2048:
-		return _EtherType_name_0
-	case i == 2054:
-		return _EtherType_name_1
-	case i == 33024:
-		return _EtherType_name_2
-	case i == 34525:
-		return _EtherType_name_3
-	case i == 34984:
-		return _EtherType_name_4
-	default:
-		return fmt.Sprintf("EtherType(%d)", i)
+func (i EtherType) builtinString() string {
+	if str, ok := _EtherType_map[i]; ok {
+		return str
 	}
+	return fmt.Sprintf("EtherType(%d)", i)
 }
@@ -0,0 +1,131 @@
+// Package trill implements marshaling and unmarshaling of TRILL headers, as
+// described in RFC 6325, and the Ethernet frames which they encapsulate.
+package trill
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// EtherType is the EtherType which indicates a TRILL header in a Frame.
+const EtherType = ethernet.EtherTypeTRILL
+
+const (
+	// headerLen is the length of a TRILL header without options.
+	headerLen = 6
+
+	// maxHopCount is the maximum value of a 6 bit hop count.
+	maxHopCount = 0x3f
+
+	// maxOptionsLen is the maximum length of TRILL options, whose length is
+	// specified in 4 byte units using 5 bits.
+	maxOptionsLen = 0x1f * 4
+)
+
+// ErrInvalidHeader is returned when a TRILL header's hop count does not fit
+// in 6 bits, its version does not fit in 2 bits, or its options are not a
+// multiple of 4 bytes no longer than 124 bytes.
+var ErrInvalidHeader = errors.New("trill: invalid header")
+
+// A Packet is a TRILL header and the Ethernet frame which it encapsulates.
+type Packet struct {
+	// Version specifies the 2 bit TRILL version, which is currently zero.
+	Version uint8
+
+	// Multidestination indicates that the frame is to be delivered to
+	// multiple destinations, and that Egress is a distribution tree root.
+	Multidestination bool
+
+	// HopCount specifies the 6 bit hop count.
+	HopCount uint8
+
+	// Egress and Ingress specify the egress and ingress RBridge nicknames.
+	Egress  uint16
+	Ingress uint16
+
+	// Options specifies optional TRILL header options.
+	Options []byte
+
+	// Frame is the encapsulated Ethernet frame.
+	Frame *ethernet.Frame
+}
+
+// Parse unmarshals the TRILL packet carried in the payload of Frame f.  If
+// f's EtherType is not EtherType, ethernet.ErrInvalidEtherType is returned.
+func Parse(f *ethernet.Frame) (*Packet, error) {
+	if f.EtherType != EtherType {
+		return nil, ethernet.ErrInvalidEtherType
+	}
+
+	p := new(Packet)
+	if err := p.UnmarshalBinary(f.Payload); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// MarshalBinary allocates a byte slice and marshals a Packet into binary
+// form.
+func (p *Packet) MarshalBinary() ([]byte, error) {
+	if p.Version > 0x03 || p.HopCount > maxHopCount ||
+		len(p.Options)%4 != 0 || len(p.Options) > maxOptionsLen {
+		return nil, ErrInvalidHeader
+	}
+
+	n := headerLen + len(p.Options)
+	b := make([]byte, n, n+p.Frame.Length())
+
+	// 2 bits: version
+	// 2 bits: reserved
+	// 1 bit : multidestination
+	// 5 bits: options length
+	// 6 bits: hop count
+	v := uint16(p.Version)<<14 | uint16(len(p.Options)/4)<<6 | uint16(p.HopCount)
+	if p.Multidestination {
+		v |= 1 << 11
+	}
+
+	binary.BigEndian.PutUint16(b[0:2], v)
+	binary.BigEndian.PutUint16(b[2:4], p.Egress)
+	binary.BigEndian.PutUint16(b[4:6], p.Ingress)
+	copy(b[headerLen:], p.Options)
+
+	return p.Frame.AppendBinary(b)
+}
+
+// UnmarshalBinary unmarshals a byte slice into a Packet.
+func (p *Packet) UnmarshalBinary(b []byte) error {
+	if len(b) < headerLen {
+		return io.ErrUnexpectedEOF
+	}
+
+	v := binary.BigEndian.Uint16(b[0:2])
+	n := headerLen + int(v>>6&0x1f)*4
+	if len(b) < n {
+		return io.ErrUnexpectedEOF
+	}
+
+	f := new(ethernet.Frame)
+	if err := f.UnmarshalBinary(b[n:]); err != nil {
+		return err
+	}
+
+	p.Version = uint8(v >> 14)
+	p.Multidestination = v&(1<<11) != 0
+	p.HopCount = uint8(v & maxHopCount)
+	p.Egress = binary.BigEndian.Uint16(b[2:4])
+	p.Ingress = binary.BigEndian.Uint16(b[4:6])
+
+	p.Options = nil
+	if n > headerLen {
+		p.Options = make([]byte, n-headerLen)
+		copy(p.Options, b[headerLen:n])
+	}
+
+	p.Frame = f
+	return nil
+}
@@ -0,0 +1,144 @@
+package trill
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestPacketMarshalUnmarshalBinary(t *testing.T) {
+	inner := &ethernet.Frame{
+		Destination: ethernet.Broadcast,
+		Source:      ethernet.Broadcast,
+		EtherType:   ethernet.EtherTypeIPv4,
+		Payload:     bytes.Repeat([]byte{0xff}, 46),
+	}
+
+	fb, err := inner.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal frame: %v", err)
+	}
+
+	tests := []struct {
+		desc string
+		p    *Packet
+		b    []byte
+	}{
+		{
+			desc: "unicast",
+			p: &Packet{
+				HopCount: 0x3f,
+				Egress:   0x0102,
+				Ingress:  0x0304,
+				Frame:    inner,
+			},
+			b: append([]byte{
+				0x00, 0x3f,
+				0x01, 0x02,
+				0x03, 0x04,
+			}, fb...),
+		},
+		{
+			desc: "multidestination with options",
+			p: &Packet{
+				Multidestination: true,
+				HopCount:         1,
+				Egress:           0x0102,
+				Ingress:          0x0304,
+				Options:          []byte{0xde, 0xad, 0xbe, 0xef},
+				Frame:            inner,
+			},
+			b: append([]byte{
+				0x08, 0x41,
+				0x01, 0x02,
+				0x03, 0x04,
+				0xde, 0xad, 0xbe, 0xef,
+			}, fb...),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := tt.p.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			if want, got := tt.b, b; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected bytes:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			f := &ethernet.Frame{
+				EtherType: EtherType,
+				Payload:   b,
+			}
+
+			p, err := Parse(f)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+
+			if !reflect.DeepEqual(tt.p, p) {
+				t.Fatalf("unexpected packet:\n- want: %#v\n-  got: %#v", tt.p, p)
+			}
+		})
+	}
+}
+
+func TestPacketMarshalBinaryInvalid(t *testing.T) {
+	for _, p := range []*Packet{
+		{HopCount: maxHopCount + 1},
+		{Version: 4},
+		{Options: []byte{0x00}},
+		{Options: make([]byte, maxOptionsLen+4)},
+	} {
+		if _, err := p.MarshalBinary(); err != ErrInvalidHeader {
+			t.Fatalf("expected ErrInvalidHeader, but got: %v", err)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		f    *ethernet.Frame
+		err  error
+	}{
+		{
+			desc: "bad EtherType",
+			f:    &ethernet.Frame{EtherType: ethernet.EtherTypeIPv4},
+			err:  ethernet.ErrInvalidEtherType,
+		},
+		{
+			desc: "short header",
+			f: &ethernet.Frame{
+				EtherType: EtherType,
+				Payload:   []byte{0x00, 0x3f},
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "short options",
+			f: &ethernet.Frame{
+				EtherType: EtherType,
+				Payload: []byte{
+					0x00, 0x7f,
+					0x01, 0x02,
+					0x03, 0x04,
+				},
+			},
+			err: io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if _, err := Parse(tt.f); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package ethernet
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// maxPayload is the maximum payload size for an Ethernet frame which is
+	// not a jumbo frame.
+	maxPayload = 1500
+
+	// MaxFrameLength is the maximum length of an untagged Ethernet frame
+	// which is not a jumbo frame, excluding the frame check sequence.
+	MaxFrameLength = 14 + maxPayload
+
+	// MaxJumboFrameLength is the maximum length of an Ethernet jumbo frame
+	// supported by most network equipment, excluding the frame check
+	// sequence.
+	MaxJumboFrameLength = 9216
+)
+
+var (
+	// ErrInvalidHardwareAddr is returned when a Frame's hardware address is
+	// not 6 bytes in length.
+	ErrInvalidHardwareAddr = errors.New("invalid hardware address")
+
+	// ErrInvalidEtherType is returned when a Frame's EtherType is neither a
+	// valid IEEE 802.3 length nor a valid EtherType.
+	ErrInvalidEtherType = errors.New("invalid EtherType")
+
+	// ErrPayloadTooLarge is returned when a Frame's payload exceeds the
+	// maximum Ethernet payload size of 1500 bytes.
+	ErrPayloadTooLarge = errors.New("payload too large")
+
+	// ErrFrameTooLarge is returned when a frame exceeds the maximum length
+	// specified by WithMaxLength or UnmarshalOptions.MaxLength.
+	ErrFrameTooLarge = errors.New("frame too large")
+
+	// ErrMulticastSource is returned when WithUnicastSource is set and a
+	// Frame's source hardware address is a multicast address.
+	ErrMulticastSource = errors.New("multicast source address")
+)
+
+// A FieldError is an error associated with a specific field of a Frame.
+// FieldErrors are returned when unmarshaling malformed frames and by
+// Frame.Validate.  Use errors.Is to check for an underlying error such as
+// io.ErrUnexpectedEOF or ErrInvalidVLAN.
+type FieldError struct {
+	// Field is the name of the field, such as "Source" or "VLAN.ID".
+	Field string
+
+	// Offset is the byte offset of the field within the binary form of the
+	// frame.
+	Offset int
+
+	// Err is the underlying error, such as ErrInvalidVLAN.
+	Err error
+}
+
+// Error implements error.
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s at offset %d: %v", e.Field, e.Offset, e.Err)
+}
+
+// Unwrap returns the underlying error of a FieldError.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors is a list of every problem detected by Frame.Validate.
+type ValidationErrors []error
+
+// Error implements error.
+func (e ValidationErrors) Error() string {
+	ss := make([]string, 0, len(e))
+	for _, err := range e {
+		ss = append(ss, err.Error())
+	}
+
+	return strings.Join(ss, "; ")
+}
+
+// Unwrap returns the errors in a ValidationErrors, so that errors.Is and
+// errors.As may inspect each of them.
+func (e ValidationErrors) Unwrap() []error {
+	return e
+}
+
+// Validate checks a Frame for problems which would cause marshaling to fail
+// or produce an invalid frame, including payloads which cannot be decoded by
+// a PayloadDecoder registered using RegisterEtherType.  If any are found,
+// Validate returns a ValidationErrors value containing a *FieldError for
+// every problem, rather than only the first.
+//
+// If options are specified, Validate also checks for any violations of the
+// constraints they impose on Frame.Marshal, such as WithMaxLength and
+// WithUnicastSource.
+func (f *Frame) Validate(options ...MarshalOption) error {
+	c := newMarshalConfig(options)
+
+	var errs ValidationErrors
+	add := func(field string, offset int, err error) {
+		errs = append(errs, &FieldError{Field: field, Offset: offset, Err: err})
+	}
+
+	for _, a := range []struct {
+		field  string
+		offset int
+		addr   []byte
+	}{
+		{field: "Destination", offset: 0, addr: f.Destination},
+		{field: "Source", offset: 6, addr: f.Source},
+	} {
+		if len(a.addr) != 6 {
+			add(a.field, a.offset, ErrInvalidHardwareAddr)
+		}
+	}
+
+	if f.ServiceVLAN != nil && f.VLAN == nil {
+		add("ServiceVLAN", 12, ErrInvalidVLAN)
+	}
+
+	// Track the offset of each field as VLAN tags are added.
+	n := 12
+	for _, v := range []struct {
+		field string
+		vlan  *VLAN
+	}{
+		{field: "ServiceVLAN", vlan: f.ServiceVLAN},
+		{field: "VLAN", vlan: f.VLAN},
+	} {
+		if v.vlan == nil {
+			continue
+		}
+
+		if v.vlan.Priority > PriorityNetworkControl {
+			add(v.field+".Priority", n+2, ErrInvalidVLAN)
+		}
+		if v.vlan.ID >= VLANMax {
+			add(v.field+".ID", n+2, ErrInvalidVLAN)
+		}
+
+		n += 4
+	}
+
+	if f.SNAP != nil && f.LLC == nil {
+		add("SNAP", n+2, ErrInvalidLLC)
+	}
+
+	// The length of an IEEE 802.3 frame is either computed automatically,
+	// or must be specified in the EtherType field.
+	pl := f.llcLength() + len(f.Payload)
+	switch {
+	case f.LLC != nil || f.Encapsulation == EncapsulationNovellRaw:
+		if pl > maxPayload {
+			add("Payload", n+2+f.llcLength(), ErrInvalidLength)
+		}
+	case f.EtherType > maxPayload && f.EtherType < minEtherType:
+		add("EtherType", n, ErrInvalidEtherType)
+	case f.EtherType < minEtherType && int(f.EtherType) != pl:
+		add("EtherType", n, ErrInvalidLength)
+	case pl > maxPayload:
+		add("Payload", n+2+f.llcLength(), ErrPayloadTooLarge)
+	}
+
+	// Payloads with a registered decoder must be decoded successfully.
+	if decode, ok := decoder(f.EtherType); ok && f.LLC == nil {
+		if _, err := decode(f.Payload); err != nil {
+			add("Payload", n+2, err)
+		}
+	}
+
+	if c.unicast && IsMulticast(f.Source) {
+		add("Source", 6, ErrMulticastSource)
+	}
+	if c.maxLen > 0 && f.length(c.pad) > c.maxLen {
+		add("Payload", n+2+f.llcLength(), ErrFrameTooLarge)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
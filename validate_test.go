@@ -0,0 +1,207 @@
+package ethernet
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestFrameValidate(t *testing.T) {
+	var (
+		dst = net.HardwareAddr{0, 1, 0, 1, 0, 1}
+		src = net.HardwareAddr{1, 0, 1, 0, 1, 0}
+	)
+
+	tests := []struct {
+		desc   string
+		f      *Frame
+		fields []string
+	}{
+		{
+			desc: "OK, Ethernet II",
+			f: &Frame{
+				Destination: dst,
+				Source:      src,
+				ServiceVLAN: &VLAN{ID: 100},
+				VLAN:        &VLAN{ID: 101},
+				EtherType:   EtherTypeIPv4,
+				Payload:     make([]byte, 1500),
+			},
+		},
+		{
+			desc: "OK, LLC",
+			f: &Frame{
+				Destination: dst,
+				Source:      src,
+				LLC:         &LLC{DSAP: 0x42, SSAP: 0x42, Control: 0x03},
+				Payload:     make([]byte, 35),
+			},
+		},
+		{
+			desc: "OK, explicit length",
+			f: &Frame{
+				Destination: dst,
+				Source:      src,
+				EtherType:   4,
+				Payload:     make([]byte, 4),
+			},
+		},
+		{
+			desc: "bad addresses and VLANs",
+			f: &Frame{
+				Destination: dst[:5],
+				ServiceVLAN: &VLAN{Priority: 8, ID: 4095},
+				EtherType:   EtherTypeARP,
+			},
+			fields: []string{
+				"Destination",
+				"Source",
+				"ServiceVLAN",
+				"ServiceVLAN.Priority",
+				"ServiceVLAN.ID",
+			},
+		},
+		{
+			desc: "bad VLAN, SNAP without LLC",
+			f: &Frame{
+				Destination: dst,
+				Source:      src,
+				VLAN:        &VLAN{ID: 4095},
+				SNAP:        &SNAP{},
+				EtherType:   EtherTypeARP,
+			},
+			fields: []string{
+				"VLAN.ID",
+				"SNAP",
+			},
+		},
+		{
+			desc: "LLC payload too large",
+			f: &Frame{
+				Destination: dst,
+				Source:      src,
+				LLC:         &LLC{Control: 0x03},
+				Payload:     make([]byte, 1498),
+			},
+			fields: []string{"Payload"},
+		},
+		{
+			desc: "EtherType between length and EtherType",
+			f: &Frame{
+				Destination: dst,
+				Source:      src,
+				EtherType:   0x05ff,
+			},
+			fields: []string{"EtherType"},
+		},
+		{
+			desc: "length mismatch",
+			f: &Frame{
+				Destination: dst,
+				Source:      src,
+				EtherType:   10,
+				Payload:     make([]byte, 4),
+			},
+			fields: []string{"EtherType"},
+		},
+		{
+			desc: "Ethernet II payload too large",
+			f: &Frame{
+				Destination: dst,
+				Source:      src,
+				EtherType:   EtherTypeIPv6,
+				Payload:     make([]byte, 1501),
+			},
+			fields: []string{"Payload"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			err := tt.f.Validate()
+			if len(tt.fields) == 0 {
+				if err != nil {
+					t.Fatalf("failed to validate: %v", err)
+				}
+
+				return
+			}
+
+			var errs ValidationErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("expected ValidationErrors, but got: %#v", err)
+			}
+
+			var fields []string
+			for _, err := range errs {
+				fe, ok := err.(*FieldError)
+				if !ok {
+					t.Fatalf("expected *FieldError, but got: %#v", err)
+				}
+
+				fields = append(fields, fe.Field)
+			}
+
+			if want, got := tt.fields, fields; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected invalid fields:\n- want: %v\n-  got: %v", want, got)
+			}
+		})
+	}
+}
+
+func TestFrameValidateErrorsIs(t *testing.T) {
+	f := &Frame{
+		VLAN: &VLAN{ID: 4095},
+	}
+
+	err := f.Validate()
+	for _, want := range []error{ErrInvalidHardwareAddr, ErrInvalidVLAN} {
+		if !errors.Is(err, want) {
+			t.Fatalf("expected %v in %v", want, err)
+		}
+	}
+
+	if errors.Is(err, ErrInvalidLLC) {
+		t.Fatalf("unexpected %v in %v", ErrInvalidLLC, err)
+	}
+
+	const want = "Destination at offset 0: invalid hardware address; Source at offset 6: invalid hardware address; VLAN.ID at offset 14: invalid VLAN"
+	if got := err.Error(); want != got {
+		t.Fatalf("unexpected error string:\n- want: %q\n-  got: %q", want, got)
+	}
+}
+
+func TestFrameValidateUnicastSource(t *testing.T) {
+	f := &Frame{
+		Destination: Broadcast,
+		Source:      net.HardwareAddr{0x01, 0x00, 0x5e, 0x00, 0x00, 0x01},
+		EtherType:   EtherTypeIPv4,
+	}
+
+	if _, err := f.Marshal(); err != nil {
+		t.Fatalf("failed to marshal without WithUnicastSource: %v", err)
+	}
+	if err := f.Validate(); err != nil {
+		t.Fatalf("failed to validate without WithUnicastSource: %v", err)
+	}
+
+	if _, err := f.Marshal(WithUnicastSource()); err != ErrMulticastSource {
+		t.Fatalf("unexpected error: %v != %v", ErrMulticastSource, err)
+	}
+
+	err := f.Validate(WithUnicastSource())
+	if !errors.Is(err, ErrMulticastSource) {
+		t.Fatalf("expected %v in %v", ErrMulticastSource, err)
+	}
+
+	const want = "Source at offset 6: multicast source address"
+	if got := err.Error(); want != got {
+		t.Fatalf("unexpected error string:\n- want: %q\n-  got: %q", want, got)
+	}
+
+	f.Source = net.HardwareAddr{0x02, 0x00, 0x5e, 0x00, 0x00, 0x01}
+	if err := f.Validate(WithUnicastSource()); err != nil {
+		t.Fatalf("failed to validate unicast source: %v", err)
+	}
+}
@@ -25,6 +25,16 @@ const (
 //   - A customer VLAN does not follow a service VLAN (when using Q-in-Q)
 var ErrInvalidVLAN = errors.New("invalid VLAN")
 
+// ErrTooManyVLANs is returned when a frame carries more VLAN tags than
+// permitted by UnmarshalOptions.MaxVLANTags.
+var ErrTooManyVLANs = errors.New("too many VLAN tags")
+
+// defaultMaxVLANTags is the number of VLAN tags permitted when
+// UnmarshalOptions.MaxVLANTags is zero.  It is larger than the number of
+// tags which Unmarshal inspects, so that the zero value of UnmarshalOptions
+// accepts every frame which it accepted before MaxVLANTags was added.
+const defaultMaxVLANTags = 8
+
 // Priority is an IEEE P802.1p priority level.  Priority can be any value from
 // 0 to 7.
 //
@@ -46,6 +56,41 @@ const (
 	PriorityNetworkControl       Priority = 7
 )
 
+// trafficClasses is the IEEE 802.1Q recommended priority to traffic class
+// mapping, indexed by number of available traffic classes minus one, and
+// then by priority.
+var trafficClasses = [8][8]int{
+	{0, 0, 0, 0, 0, 0, 0, 0},
+	{0, 0, 0, 0, 1, 1, 1, 1},
+	{0, 0, 0, 0, 1, 1, 2, 2},
+	{0, 0, 1, 1, 2, 2, 3, 3},
+	{0, 0, 1, 1, 2, 2, 3, 4},
+	{1, 0, 2, 2, 3, 3, 4, 5},
+	{1, 0, 2, 3, 4, 4, 5, 6},
+	{1, 0, 2, 3, 4, 5, 6, 7},
+}
+
+// TrafficClass returns the traffic class for a Priority on a port with n
+// traffic classes, using the IEEE 802.1Q recommended mapping.  Higher
+// traffic classes should be given precedence during transmission.
+//
+// n is clamped to the range 1 to 8.  An invalid Priority is treated as
+// PriorityBestEffort.
+func (p Priority) TrafficClass(n int) int {
+	switch {
+	case n < 1:
+		n = 1
+	case n > 8:
+		n = 8
+	}
+
+	if p > PriorityNetworkControl {
+		p = PriorityBestEffort
+	}
+
+	return trafficClasses[n-1][p]
+}
+
 // A VLAN is an IEEE 802.1Q Virtual LAN (VLAN) tag.  A VLAN contains
 // information regarding traffic priority and a VLAN identifier for
 // a given Frame.
@@ -64,6 +109,73 @@ type VLAN struct {
 	// If ID is 0 (0x000, VLANNone), no VLAN is specified, and the other fields
 	// simply indicate a Frame's priority.
 	ID uint16
+
+	// TPID optionally specifies the Tag Protocol Identifier which introduces
+	// the VLAN tag in a Frame.  If zero, EtherTypeServiceVLAN is used for a
+	// Frame's ServiceVLAN, and EtherTypeVLAN is used for its VLAN.
+	//
+	// When unmarshaling a Frame, TPID is set only if a tag is introduced by
+	// a TPID other than the default for its position, such as one specified
+	// by UnmarshalOptions.ServiceVLANTPIDs.
+	TPID EtherType
+}
+
+// PriorityTag returns an IEEE 802.1p priority tag: a VLAN with ID VLANNone,
+// which specifies only a Frame's priority.
+func PriorityTag(p Priority) *VLAN {
+	return &VLAN{
+		Priority: p,
+		ID:       VLANNone,
+	}
+}
+
+// IsPriorityTag reports whether v is a priority tag, with ID VLANNone.
+func (v *VLAN) IsPriorityTag() bool {
+	return v != nil && v.ID == VLANNone
+}
+
+// IsPriorityTagOnly reports whether a Frame's only VLAN tag is a priority
+// tag, so that the Frame carries a priority but belongs to no VLAN.
+func (f *Frame) IsPriorityTagOnly() bool {
+	return f.ServiceVLAN == nil && f.VLAN.IsPriorityTag()
+}
+
+// VLANs returns copies of a Frame's VLAN tags, ordered from outermost to
+// innermost: the ServiceVLAN, if present, followed by the VLAN.
+func (f *Frame) VLANs() []VLAN {
+	var vs []VLAN
+	for _, v := range []*VLAN{f.ServiceVLAN, f.VLAN} {
+		if v != nil {
+			vs = append(vs, *v)
+		}
+	}
+
+	return vs
+}
+
+// SetVLANs sets a Frame's VLAN tags from a list ordered from outermost to
+// innermost, as returned by VLANs.  At most two VLAN tags may be set: a
+// single tag sets the VLAN, and two tags set the ServiceVLAN and VLAN.
+// ErrInvalidVLAN is returned if more than two tags are specified.
+func (f *Frame) SetVLANs(vs []VLAN) error {
+	if len(vs) > 2 {
+		return ErrInvalidVLAN
+	}
+
+	f.ServiceVLAN, f.VLAN = nil, nil
+
+	// Copy the tags into a single allocation.
+	cp := make([]VLAN, len(vs))
+	copy(cp, vs)
+
+	switch len(cp) {
+	case 1:
+		f.VLAN = &cp[0]
+	case 2:
+		f.ServiceVLAN, f.VLAN = &cp[0], &cp[1]
+	}
+
+	return nil
 }
 
 // MarshalBinary allocates a byte slice and marshals a VLAN into binary form.
@@ -73,6 +185,18 @@ func (v *VLAN) MarshalBinary() ([]byte, error) {
 	return b, err
 }
 
+// AppendBinary implements encoding.BinaryAppender.  AppendBinary marshals a
+// VLAN into binary form, as with MarshalBinary, and appends it to b.
+func (v *VLAN) AppendBinary(b []byte) ([]byte, error) {
+	n := len(b)
+	b = grow(b, 2)
+	if _, err := v.read(b[n:]); err != nil {
+		return b[:n], err
+	}
+
+	return b, nil
+}
+
 // read reads data from a VLAN into b.  read is used to marshal a VLAN into
 // binary form, but does not allocate on its own.
 func (v *VLAN) read(b []byte) (int, error) {
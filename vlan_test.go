@@ -126,6 +126,57 @@ func TestVLANUnmarshalBinary(t *testing.T) {
 	}
 }
 
+func TestPriorityTrafficClass(t *testing.T) {
+	tests := []struct {
+		desc string
+		n    int
+		tcs  [8]int
+	}{
+		{
+			desc: "too few classes",
+			n:    0,
+			tcs:  [8]int{0, 0, 0, 0, 0, 0, 0, 0},
+		},
+		{
+			desc: "2 classes",
+			n:    2,
+			tcs:  [8]int{0, 0, 0, 0, 1, 1, 1, 1},
+		},
+		{
+			desc: "4 classes",
+			n:    4,
+			tcs:  [8]int{0, 0, 1, 1, 2, 2, 3, 3},
+		},
+		{
+			desc: "8 classes",
+			n:    8,
+			tcs:  [8]int{1, 0, 2, 3, 4, 5, 6, 7},
+		},
+		{
+			desc: "too many classes",
+			n:    16,
+			tcs:  [8]int{1, 0, 2, 3, 4, 5, 6, 7},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var tcs [8]int
+			for p := range tcs {
+				tcs[p] = Priority(p).TrafficClass(tt.n)
+			}
+
+			if want, got := tt.tcs, tcs; want != got {
+				t.Fatalf("unexpected traffic classes: %v != %v", want, got)
+			}
+		})
+	}
+
+	if want, got := 1, Priority(8).TrafficClass(8); want != got {
+		t.Fatalf("unexpected traffic class for invalid priority: %v != %v", want, got)
+	}
+}
+
 // Benchmarks for VLAN.MarshalBinary
 
 func BenchmarkVLANMarshalBinary(b *testing.B) {
@@ -164,3 +215,118 @@ func BenchmarkVLANUnmarshalBinary(b *testing.B) {
 		}
 	}
 }
+
+func TestPriorityTag(t *testing.T) {
+	v := PriorityTag(PriorityVoice)
+	if want, got := (VLAN{Priority: PriorityVoice}), *v; want != got {
+		t.Fatalf("unexpected priority tag: %v != %v", want, got)
+	}
+
+	tests := []struct {
+		desc string
+		f    *Frame
+		ok   bool
+	}{
+		{
+			desc: "untagged",
+			f:    &Frame{},
+		},
+		{
+			desc: "VLAN",
+			f:    &Frame{VLAN: &VLAN{ID: 10}},
+		},
+		{
+			desc: "priority tag",
+			f:    &Frame{VLAN: v},
+			ok:   true,
+		},
+		{
+			desc: "priority tag in service VLAN",
+			f: &Frame{
+				ServiceVLAN: &VLAN{ID: 10},
+				VLAN:        v,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if want, got := tt.ok, tt.f.IsPriorityTagOnly(); want != got {
+				t.Fatalf("unexpected priority tag only: %v != %v", want, got)
+			}
+		})
+	}
+}
+
+func TestVLANAppendBinary(t *testing.T) {
+	v := &VLAN{
+		Priority: PriorityVoice,
+		ID:       100,
+	}
+
+	b, err := v.AppendBinary([]byte{0x81, 0x00})
+	if err != nil {
+		t.Fatalf("failed to append: %v", err)
+	}
+
+	if want, got := []byte{0x81, 0x00, 0xa0, 0x64}, b; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected VLAN bytes:\n- want: %v\n-  got: %v", want, got)
+	}
+
+	if _, err := (&VLAN{ID: VLANMax}).AppendBinary(nil); err != ErrInvalidVLAN {
+		t.Fatalf("unexpected error: %v != %v", ErrInvalidVLAN, err)
+	}
+}
+
+func TestFrameVLANs(t *testing.T) {
+	tests := []struct {
+		desc string
+		vs   []VLAN
+		f    *Frame
+		err  error
+	}{
+		{
+			desc: "none",
+			f:    &Frame{},
+		},
+		{
+			desc: "C-VLAN",
+			vs:   []VLAN{{ID: 10}},
+			f:    &Frame{VLAN: &VLAN{ID: 10}},
+		},
+		{
+			desc: "S-VLAN and C-VLAN",
+			vs:   []VLAN{{ID: 100}, {ID: 10}},
+			f: &Frame{
+				ServiceVLAN: &VLAN{ID: 100},
+				VLAN:        &VLAN{ID: 10},
+			},
+		},
+		{
+			desc: "too many",
+			vs:   []VLAN{{ID: 1}, {ID: 2}, {ID: 3}},
+			err:  ErrInvalidVLAN,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			f := new(Frame)
+			if err := f.SetVLANs(tt.vs); err != nil {
+				if want, got := tt.err, err; want != got {
+					t.Fatalf("unexpected error: %v != %v", want, got)
+				}
+
+				return
+			}
+
+			if want, got := tt.f, f; !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected Frame:\n- want: %v\n-  got: %v", want, got)
+			}
+
+			if want, got := tt.vs, f.VLANs(); !reflect.DeepEqual(want, got) {
+				t.Fatalf("unexpected VLANs:\n- want: %v\n-  got: %v", want, got)
+			}
+		})
+	}
+}
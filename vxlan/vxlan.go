@@ -0,0 +1,78 @@
+// Package vxlan implements marshaling and unmarshaling of VXLAN packets, as
+// described in RFC 7348, which carry Ethernet frames over UDP.
+package vxlan
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// Port is the IANA assigned UDP destination port for VXLAN.
+const Port = 4789
+
+const (
+	// headerLen is the length of a VXLAN header.
+	headerLen = 8
+
+	// flagVNI is the I flag, which indicates a valid VNI.
+	flagVNI = 0x08
+
+	// maxVNI is the maximum value of a 24 bit VNI.
+	maxVNI = 0xffffff
+)
+
+var (
+	// ErrInvalidVNI is returned when a VNI does not fit in 24 bits.
+	ErrInvalidVNI = errors.New("vxlan: invalid VNI")
+
+	// ErrInvalidFlags is returned when a VXLAN header does not have the I
+	// flag set.
+	ErrInvalidFlags = errors.New("vxlan: invalid flags")
+)
+
+// A Packet is a VXLAN header and the Ethernet frame which it encapsulates.
+type Packet struct {
+	// VNI specifies the 24 bit VXLAN network identifier.
+	VNI uint32
+
+	// Frame is the encapsulated Ethernet frame.
+	Frame *ethernet.Frame
+}
+
+// MarshalBinary allocates a byte slice and marshals a Packet into binary
+// form, suitable for use as the payload of a UDP datagram.
+func (p *Packet) MarshalBinary() ([]byte, error) {
+	if p.VNI > maxVNI {
+		return nil, ErrInvalidVNI
+	}
+
+	b := make([]byte, headerLen, headerLen+p.Frame.Length())
+	b[0] = flagVNI
+	binary.BigEndian.PutUint32(b[4:8], p.VNI<<8)
+
+	return p.Frame.AppendBinary(b)
+}
+
+// UnmarshalBinary unmarshals a byte slice into a Packet.  The I flag must be
+// set; all other flags and reserved fields are ignored.
+func (p *Packet) UnmarshalBinary(b []byte) error {
+	if len(b) < headerLen {
+		return io.ErrUnexpectedEOF
+	}
+	if b[0]&flagVNI == 0 {
+		return ErrInvalidFlags
+	}
+
+	f := new(ethernet.Frame)
+	if err := f.UnmarshalBinary(b[headerLen:]); err != nil {
+		return err
+	}
+
+	p.VNI = binary.BigEndian.Uint32(b[4:8]) >> 8
+	p.Frame = f
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package vxlan
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestPacketMarshalUnmarshalBinary(t *testing.T) {
+	f := &ethernet.Frame{
+		Destination: ethernet.Broadcast,
+		Source:      ethernet.Broadcast,
+		EtherType:   ethernet.EtherTypeIPv4,
+		Payload:     bytes.Repeat([]byte{0xff}, 46),
+	}
+
+	fb, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal frame: %v", err)
+	}
+
+	p := &Packet{
+		VNI:   0x123456,
+		Frame: f,
+	}
+
+	b, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal packet: %v", err)
+	}
+
+	want := append([]byte{
+		0x08, 0x00, 0x00, 0x00,
+		0x12, 0x34, 0x56, 0x00,
+	}, fb...)
+
+	if !bytes.Equal(want, b) {
+		t.Fatalf("unexpected packet bytes:\n- want: %v\n-  got: %v", want, b)
+	}
+
+	got := new(Packet)
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal packet: %v", err)
+	}
+
+	if !reflect.DeepEqual(p, got) {
+		t.Fatalf("unexpected packet:\n- want: %#v\n-  got: %#v", p, got)
+	}
+}
+
+func TestPacketMarshalBinaryInvalidVNI(t *testing.T) {
+	p := &Packet{
+		VNI:   maxVNI + 1,
+		Frame: &ethernet.Frame{},
+	}
+
+	if _, err := p.MarshalBinary(); err != ErrInvalidVNI {
+		t.Fatalf("expected ErrInvalidVNI, but got: %v", err)
+	}
+}
+
+func TestPacketUnmarshalBinary(t *testing.T) {
+	tests := []struct {
+		desc string
+		b    []byte
+		err  error
+	}{
+		{
+			desc: "short",
+			b:    make([]byte, headerLen-1),
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "no I flag",
+			b:    make([]byte, headerLen),
+			err:  ErrInvalidFlags,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if want, got := tt.err, new(Packet).UnmarshalBinary(tt.b); want != got {
+				t.Fatalf("unexpected error: %v != %v", want, got)
+			}
+		})
+	}
+}
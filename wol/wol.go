@@ -0,0 +1,197 @@
+// Package wol implements marshaling, unmarshaling, and detection of
+// Wake-on-LAN magic packets, including optional SecureOn passwords.
+package wol
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"errors"
+	"io"
+	"net"
+
+	"github.com/mdlayher/ethernet"
+)
+
+// EtherType is the EtherType which indicates a magic packet in a Frame.
+const EtherType = ethernet.EtherTypeWakeOnLAN
+
+const (
+	// syncLen is the length of the synchronization stream which begins a
+	// magic packet.
+	syncLen = 6
+
+	// repetitions is the number of times the target hardware address is
+	// repeated in a magic packet.
+	repetitions = 16
+
+	// magicLen is the length of a magic packet.
+	magicLen = syncLen + repetitions*6
+)
+
+var (
+	// ErrInvalidMagicPacket is returned when a magic packet does not begin
+	// with its synchronization stream, or does not repeat its target
+	// hardware address 16 times.
+	ErrInvalidMagicPacket = errors.New("wol: invalid magic packet")
+
+	// ErrInvalidTarget is returned when a magic packet's target is not a 6
+	// byte hardware address.
+	ErrInvalidTarget = errors.New("wol: invalid target hardware address")
+
+	// ErrInvalidPassword is returned when a SecureOn password is not 4 or 6
+	// bytes.
+	ErrInvalidPassword = errors.New("wol: invalid SecureOn password")
+)
+
+// sync is the synchronization stream which begins a magic packet.
+var sync = []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+
+// A MagicPacket is a Wake-on-LAN magic packet.
+type MagicPacket struct {
+	// Target specifies the hardware address of the machine to wake.
+	Target net.HardwareAddr
+
+	// Password specifies an optional SecureOn password, which follows the
+	// repetitions of Target.  Password is normally 6 bytes, but some
+	// devices accept a 4 byte password.
+	Password []byte
+}
+
+// Parse unmarshals the magic packet carried in the payload of Frame f.  If
+// f's EtherType is not EtherType, ethernet.ErrInvalidEtherType is returned.
+func Parse(f *ethernet.Frame) (*MagicPacket, error) {
+	if f.EtherType != EtherType {
+		return nil, ethernet.ErrInvalidEtherType
+	}
+
+	m := new(MagicPacket)
+	if err := m.UnmarshalBinary(f.Payload); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Frame marshals a MagicPacket into the payload of an Ethernet frame
+// broadcast from hardware address src.
+func (m *MagicPacket) Frame(src net.HardwareAddr) (*ethernet.Frame, error) {
+	b, err := m.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ethernet.Frame{
+		Destination: append(net.HardwareAddr(nil), ethernet.Broadcast...),
+		Source:      src,
+		EtherType:   EtherType,
+		Payload:     b,
+	}, nil
+}
+
+// MarshalBinary allocates a byte slice and marshals a MagicPacket into
+// binary form.
+func (m *MagicPacket) MarshalBinary() ([]byte, error) {
+	if len(m.Target) != 6 {
+		return nil, ErrInvalidTarget
+	}
+	if !validPassword(m.Password) {
+		return nil, ErrInvalidPassword
+	}
+
+	b := make([]byte, magicLen, magicLen+len(m.Password))
+	copy(b, sync)
+	for i := 0; i < repetitions; i++ {
+		copy(b[syncLen+i*6:], m.Target)
+	}
+
+	return append(b, m.Password...), nil
+}
+
+// UnmarshalBinary unmarshals a byte slice into a MagicPacket.  The magic
+// packet must begin at the start of b.  If exactly 4 or 6 bytes follow the
+// repetitions of the target hardware address, they are unmarshaled as a
+// SecureOn password, and otherwise trailing bytes, such as Ethernet
+// padding, are ignored.
+func (m *MagicPacket) UnmarshalBinary(b []byte) error {
+	if len(b) < magicLen {
+		return io.ErrUnexpectedEOF
+	}
+
+	if !isMagic(b) {
+		return ErrInvalidMagicPacket
+	}
+
+	target := make(net.HardwareAddr, 6)
+	copy(target, b[syncLen:syncLen+6])
+
+	var password []byte
+	if rest := b[magicLen:]; validPassword(rest) && len(rest) > 0 {
+		password = make([]byte, len(rest))
+		copy(password, rest)
+	}
+
+	m.Target = target
+	m.Password = password
+	return nil
+}
+
+// Verify reports whether a MagicPacket's SecureOn password matches password.
+// A MagicPacket without a password only matches an empty password.  The
+// comparison is performed in constant time.
+func (m *MagicPacket) Verify(password []byte) bool {
+	return subtle.ConstantTimeCompare(m.Password, password) == 1
+}
+
+// validPassword reports whether b is a valid SecureOn password, or is empty.
+func validPassword(b []byte) bool {
+	switch len(b) {
+	case 0, 4, 6:
+		return true
+	default:
+		return false
+	}
+}
+
+// Find searches b for a magic packet, such as one carried in the payload of
+// a UDP datagram, and returns its target hardware address.  Find reports
+// whether a magic packet was found.
+func Find(b []byte) (net.HardwareAddr, bool) {
+	for i := 0; len(b[i:]) >= magicLen; i++ {
+		j := bytes.Index(b[i:], sync)
+		if j == -1 {
+			return nil, false
+		}
+
+		i += j
+		if len(b[i:]) < magicLen {
+			return nil, false
+		}
+
+		// A longer run of 0xff bytes may precede the synchronization
+		// stream, so keep searching one byte at a time on failure.
+		if isMagic(b[i:]) {
+			target := make(net.HardwareAddr, 6)
+			copy(target, b[i+syncLen:i+syncLen+6])
+			return target, true
+		}
+	}
+
+	return nil, false
+}
+
+// isMagic reports whether b begins with a magic packet.  b must be at least
+// magicLen bytes.
+func isMagic(b []byte) bool {
+	if !bytes.Equal(b[:syncLen], sync) {
+		return false
+	}
+
+	target := b[syncLen : syncLen+6]
+	for i := 1; i < repetitions; i++ {
+		if !bytes.Equal(target, b[syncLen+i*6:syncLen+(i+1)*6]) {
+			return false
+		}
+	}
+
+	return true
+}
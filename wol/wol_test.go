@@ -0,0 +1,246 @@
+package wol
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/mdlayher/ethernet"
+)
+
+func TestMagicPacketMarshalUnmarshal(t *testing.T) {
+	target := net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}
+	m := &MagicPacket{Target: target}
+
+	b, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	want := bytes.Repeat([]byte{0xff}, 6)
+	for i := 0; i < 16; i++ {
+		want = append(want, target...)
+	}
+
+	if !bytes.Equal(want, b) {
+		t.Fatalf("unexpected bytes:\n- want: %v\n-  got: %v", want, b)
+	}
+
+	got := new(MagicPacket)
+	if err := got.UnmarshalBinary(append(b, 0x00, 0x00)); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if !reflect.DeepEqual(m, got) {
+		t.Fatalf("unexpected magic packet:\n- want: %#v\n-  got: %#v", m, got)
+	}
+}
+
+func TestMagicPacketSecureOn(t *testing.T) {
+	target := net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}
+
+	tests := []struct {
+		desc     string
+		password []byte
+	}{
+		{
+			desc:     "4 bytes",
+			password: []byte{192, 168, 1, 1},
+		},
+		{
+			desc:     "6 bytes",
+			password: []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			m := &MagicPacket{
+				Target:   target,
+				Password: tt.password,
+			}
+
+			b, err := m.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+
+			if want, got := tt.password, b[magicLen:]; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected password: %v != %v", want, got)
+			}
+
+			got := new(MagicPacket)
+			if err := got.UnmarshalBinary(b); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+
+			if !reflect.DeepEqual(m, got) {
+				t.Fatalf("unexpected magic packet:\n- want: %#v\n-  got: %#v", m, got)
+			}
+
+			if !got.Verify(tt.password) {
+				t.Fatal("expected password to verify")
+			}
+			if got.Verify(nil) || got.Verify([]byte{0x00, 0x00, 0x00, 0x00}) {
+				t.Fatal("expected incorrect password to fail verification")
+			}
+		})
+	}
+
+	// Packets without a password only verify an empty password.
+	m := &MagicPacket{Target: target}
+	if !m.Verify(nil) || m.Verify([]byte{0x01, 0x02, 0x03, 0x04}) {
+		t.Fatal("unexpected verification of packet without password")
+	}
+
+	if _, err := (&MagicPacket{Target: target, Password: []byte{0x01}}).MarshalBinary(); err != ErrInvalidPassword {
+		t.Fatalf("expected ErrInvalidPassword, but got: %v", err)
+	}
+}
+
+func TestMagicPacketErrors(t *testing.T) {
+	valid, err := (&MagicPacket{Target: net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	modify := func(i int, v byte) []byte {
+		b := append([]byte(nil), valid...)
+		b[i] = v
+		return b
+	}
+
+	tests := []struct {
+		desc string
+		b    []byte
+		err  error
+	}{
+		{
+			desc: "short",
+			b:    valid[:len(valid)-1],
+			err:  io.ErrUnexpectedEOF,
+		},
+		{
+			desc: "bad sync",
+			b:    modify(0, 0x00),
+			err:  ErrInvalidMagicPacket,
+		},
+		{
+			desc: "bad repetition",
+			b:    modify(len(valid)-1, 0x00),
+			err:  ErrInvalidMagicPacket,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := new(MagicPacket).UnmarshalBinary(tt.b); err != tt.err {
+				t.Fatalf("unexpected error: %v != %v", tt.err, err)
+			}
+		})
+	}
+
+	if _, err := (&MagicPacket{}).MarshalBinary(); err != ErrInvalidTarget {
+		t.Fatalf("expected ErrInvalidTarget, but got: %v", err)
+	}
+}
+
+func TestFind(t *testing.T) {
+	target := net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}
+	m, err := (&MagicPacket{Target: target}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	tests := []struct {
+		desc   string
+		b      []byte
+		target net.HardwareAddr
+		ok     bool
+	}{
+		{
+			desc: "empty",
+		},
+		{
+			desc: "no magic packet",
+			b:    make([]byte, 200),
+		},
+		{
+			desc:   "start",
+			b:      m,
+			target: target,
+			ok:     true,
+		},
+		{
+			desc:   "offset",
+			b:      append([]byte{0x00, 0x01, 0x02}, m...),
+			target: target,
+			ok:     true,
+		},
+		{
+			desc:   "extra sync bytes",
+			b:      append([]byte{0xff, 0xff}, m...),
+			target: target,
+			ok:     true,
+		},
+		{
+			desc: "truncated",
+			b:    append([]byte{0x00}, m[:len(m)-1]...),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			target, ok := Find(tt.b)
+			if want, got := tt.ok, ok; want != got {
+				t.Fatalf("unexpected found: %v != %v", want, got)
+			}
+			if want, got := tt.target, target; !bytes.Equal(want, got) {
+				t.Fatalf("unexpected target: %v != %v", want, got)
+			}
+		})
+	}
+}
+
+func TestFrameParse(t *testing.T) {
+	m := &MagicPacket{Target: net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}}
+
+	f, err := m.Frame(net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55})
+	if err != nil {
+		t.Fatalf("failed to create frame: %v", err)
+	}
+
+	if want, got := ethernet.Broadcast, f.Destination; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected destination: %v != %v", want, got)
+	}
+
+	got, err := Parse(f)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if !reflect.DeepEqual(m, got) {
+		t.Fatalf("unexpected magic packet:\n- want: %#v\n-  got: %#v", m, got)
+	}
+
+	if _, err := Parse(&ethernet.Frame{EtherType: ethernet.EtherTypeIPv4}); err != ethernet.ErrInvalidEtherType {
+		t.Fatalf("expected ErrInvalidEtherType, but got: %v", err)
+	}
+}
+
+func TestMagicPacketFrameDestinationCopy(t *testing.T) {
+	m := &MagicPacket{Target: net.HardwareAddr{0xde, 0xad, 0xbe, 0xef, 0xde, 0xad}}
+
+	f, err := m.Frame(net.HardwareAddr{0, 1, 0, 1, 0, 1})
+	if err != nil {
+		t.Fatalf("failed to create frame: %v", err)
+	}
+
+	// Modifying the Frame must not modify the package-level address.
+	f.Destination[0] = 0x00
+	if want, got := (net.HardwareAddr{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}), ethernet.Broadcast; !bytes.Equal(want, got) {
+		t.Fatalf("unexpected broadcast address: %v != %v", want, got)
+	}
+}
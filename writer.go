@@ -0,0 +1,157 @@
+package ethernet
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+// A FrameWriter writes Ethernet frames to an io.Writer, using a Framing to
+// delimit each frame.  Frames are buffered internally; Flush must be called
+// once all frames are written.
+type FrameWriter struct {
+	w       *bufio.Writer
+	framing Framing
+	snapLen int
+	options []MarshalOption
+
+	// header reports whether a pcap file header has been written.
+	header bool
+	buf    []byte
+
+	// now allows tests to control pcap record timestamps.
+	now func() time.Time
+}
+
+// NewFrameWriter creates a FrameWriter which writes frames to w using the
+// specified Framing.  Each Frame is marshaled using options, so WithFCS can
+// be used to append a frame check sequence to each frame.
+//
+// snapLen specifies the size of each frame when using FramingSnapLen, in
+// which case frames are truncated or zero-padded to exactly snapLen bytes.
+// When using FramingPcap, a positive snapLen truncates frames and is
+// recorded in the file header.  snapLen is otherwise ignored.
+func NewFrameWriter(w io.Writer, framing Framing, snapLen int, options ...MarshalOption) *FrameWriter {
+	return &FrameWriter{
+		w:       bufio.NewWriter(w),
+		framing: framing,
+		snapLen: snapLen,
+		options: options,
+		now:     time.Now,
+	}
+}
+
+// WriteFrame marshals and writes f.  Frames marshaled into more than 65535
+// bytes cannot be written using FramingLengthPrefixed, and are rejected
+// with ErrFrameTooLarge.
+func (fw *FrameWriter) WriteFrame(f *Frame) error {
+	switch fw.framing {
+	case FramingLengthPrefixed, FramingPcap:
+	case FramingSnapLen:
+		if fw.snapLen <= 0 {
+			return ErrInvalidFraming
+		}
+	default:
+		return ErrInvalidFraming
+	}
+
+	// WithBuffer is applied last so that the FrameWriter's buffer is always
+	// reused.
+	b, err := f.Marshal(append(fw.options[:len(fw.options):len(fw.options)], WithBuffer(fw.buf))...)
+	if err != nil {
+		return err
+	}
+	fw.buf = b
+
+	switch fw.framing {
+	case FramingLengthPrefixed:
+		if len(b) > 0xffff {
+			return ErrFrameTooLarge
+		}
+
+		var l [2]byte
+		binary.BigEndian.PutUint16(l[:], uint16(len(b)))
+		if _, err := fw.w.Write(l[:]); err != nil {
+			return err
+		}
+	case FramingPcap:
+		if err := fw.writePcapHeader(); err != nil {
+			return err
+		}
+
+		n := len(b)
+		if fw.snapLen > 0 && len(b) > fw.snapLen {
+			b = b[:fw.snapLen]
+		}
+
+		ts := fw.now()
+
+		var rh [pcapRecordHeaderLen]byte
+		binary.LittleEndian.PutUint32(rh[0:4], uint32(ts.Unix()))
+		binary.LittleEndian.PutUint32(rh[4:8], uint32(ts.Nanosecond()))
+		binary.LittleEndian.PutUint32(rh[8:12], uint32(len(b)))
+		binary.LittleEndian.PutUint32(rh[12:16], uint32(n))
+		if _, err := fw.w.Write(rh[:]); err != nil {
+			return err
+		}
+	case FramingSnapLen:
+		if len(b) > fw.snapLen {
+			b = b[:fw.snapLen]
+		}
+		if _, err := fw.w.Write(b); err != nil {
+			return err
+		}
+
+		// Pad the frame to exactly the snapshot length.
+		for i := len(b); i < fw.snapLen; i++ {
+			if err := fw.w.WriteByte(0x00); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	_, err = fw.w.Write(b)
+	return err
+}
+
+// Flush writes any buffered data to the underlying io.Writer.  When using
+// FramingPcap, Flush writes the file header if no frames have been written,
+// so that the output is always a valid pcap file.
+func (fw *FrameWriter) Flush() error {
+	if fw.framing == FramingPcap {
+		if err := fw.writePcapHeader(); err != nil {
+			return err
+		}
+	}
+
+	return fw.w.Flush()
+}
+
+// writePcapHeader writes a little endian pcap file header with nanosecond
+// resolution timestamps, if one has not been written already.
+func (fw *FrameWriter) writePcapHeader() error {
+	if fw.header {
+		return nil
+	}
+
+	snapLen := fw.snapLen
+	if snapLen <= 0 {
+		snapLen = pcapMaxSnapLen
+	}
+
+	var h [pcapHeaderLen]byte
+	binary.LittleEndian.PutUint32(h[0:4], pcapMagicNanoseconds)
+	binary.LittleEndian.PutUint16(h[4:6], 2)
+	binary.LittleEndian.PutUint16(h[6:8], 4)
+	binary.LittleEndian.PutUint32(h[16:20], uint32(snapLen))
+	binary.LittleEndian.PutUint32(h[20:24], pcapLinkTypeEthernet)
+	if _, err := fw.w.Write(h[:]); err != nil {
+		return err
+	}
+
+	fw.header = true
+	return nil
+}
@@ -0,0 +1,207 @@
+package ethernet
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFrameWriterRoundTrip(t *testing.T) {
+	f := &Frame{
+		Destination: Broadcast,
+		Source:      Broadcast,
+		VLAN:        &VLAN{ID: 10},
+		EtherType:   EtherTypeIPv4,
+		Payload:     bytes.Repeat([]byte{0xff}, 50),
+	}
+
+	tests := []struct {
+		desc    string
+		framing Framing
+		snapLen int
+	}{
+		{
+			desc:    "length-prefixed",
+			framing: FramingLengthPrefixed,
+		},
+		{
+			desc:    "pcap",
+			framing: FramingPcap,
+		},
+		{
+			desc:    "snaplen",
+			framing: FramingSnapLen,
+			snapLen: f.Length(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			fw := NewFrameWriter(&buf, tt.framing, tt.snapLen)
+
+			for i := 0; i < 3; i++ {
+				if err := fw.WriteFrame(f); err != nil {
+					t.Fatalf("failed to write frame: %v", err)
+				}
+			}
+
+			// Nothing is written until the buffer is flushed.
+			if want, got := 0, buf.Len(); want != got {
+				t.Fatalf("unexpected buffered length: %v != %v", want, got)
+			}
+			if err := fw.Flush(); err != nil {
+				t.Fatalf("failed to flush: %v", err)
+			}
+
+			fr := NewFrameReader(&buf, tt.framing, tt.snapLen)
+			for i := 0; i < 3; i++ {
+				got, err := fr.Next()
+				if err != nil {
+					t.Fatalf("failed to read frame %d: %v", i, err)
+				}
+
+				if !reflect.DeepEqual(f, got) {
+					t.Fatalf("unexpected frame %d:\n- want: %#v\n-  got: %#v", i, f, got)
+				}
+			}
+
+			if _, err := fr.Next(); err != io.EOF {
+				t.Fatalf("expected io.EOF, but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestFrameWriterFCS(t *testing.T) {
+	f := &Frame{
+		Destination: Broadcast,
+		Source:      Broadcast,
+		EtherType:   EtherTypeARP,
+	}
+
+	var buf bytes.Buffer
+	fw := NewFrameWriter(&buf, FramingLengthPrefixed, 0, WithFCS())
+	if err := fw.WriteFrame(f); err != nil {
+		t.Fatalf("failed to write frame: %v", err)
+	}
+	if err := fw.Flush(); err != nil {
+		t.Fatalf("failed to flush: %v", err)
+	}
+
+	want, err := f.MarshalFCS()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	want = append([]byte{0x00, byte(len(want))}, want...)
+
+	if got := buf.Bytes(); !bytes.Equal(want, got) {
+		t.Fatalf("unexpected output:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestFrameWriterPcap(t *testing.T) {
+	var buf bytes.Buffer
+	fw := NewFrameWriter(&buf, FramingPcap, 14)
+	fw.now = func() time.Time { return time.Unix(1, 2) }
+
+	f := &Frame{
+		Destination: Broadcast,
+		Source:      Broadcast,
+		EtherType:   EtherTypeARP,
+	}
+	if err := fw.WriteFrame(f); err != nil {
+		t.Fatalf("failed to write frame: %v", err)
+	}
+	if err := fw.Flush(); err != nil {
+		t.Fatalf("failed to flush: %v", err)
+	}
+
+	want := concat(
+		[]byte{
+			// Global header: magic, version 2.4, zone, sigfigs, snaplen, link type.
+			0x4d, 0x3c, 0xb2, 0xa1,
+			0x02, 0x00, 0x04, 0x00,
+			0x00, 0x00, 0x00, 0x00,
+			0x00, 0x00, 0x00, 0x00,
+			0x0e, 0x00, 0x00, 0x00,
+			0x01, 0x00, 0x00, 0x00,
+			// Record header: seconds, nanoseconds, captured and original length.
+			0x01, 0x00, 0x00, 0x00,
+			0x02, 0x00, 0x00, 0x00,
+			0x0e, 0x00, 0x00, 0x00,
+			0x3c, 0x00, 0x00, 0x00,
+		},
+		Broadcast, Broadcast, []byte{0x08, 0x06},
+	)
+
+	if got := buf.Bytes(); !bytes.Equal(want, got) {
+		t.Fatalf("unexpected pcap:\n- want: %v\n-  got: %v", want, got)
+	}
+}
+
+func TestFrameWriterEmptyPcap(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewFrameWriter(&buf, FramingPcap, 0).Flush(); err != nil {
+		t.Fatalf("failed to flush: %v", err)
+	}
+
+	if want, got := pcapHeaderLen, buf.Len(); want != got {
+		t.Fatalf("unexpected length: %v != %v", want, got)
+	}
+}
+
+func TestFrameWriterSnapLen(t *testing.T) {
+	f := &Frame{
+		Destination: Broadcast,
+		Source:      Broadcast,
+		EtherType:   EtherTypeARP,
+	}
+
+	tests := []struct {
+		desc    string
+		snapLen int
+		want    []byte
+	}{
+		{
+			desc:    "truncated",
+			snapLen: 13,
+			want:    concat(Broadcast, Broadcast, []byte{0x08}),
+		},
+		{
+			desc:    "padded",
+			snapLen: 64,
+			want:    concat(Broadcast, Broadcast, []byte{0x08, 0x06}, make([]byte, 50)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			fw := NewFrameWriter(&buf, FramingSnapLen, tt.snapLen)
+			if err := fw.WriteFrame(f); err != nil {
+				t.Fatalf("failed to write frame: %v", err)
+			}
+			if err := fw.Flush(); err != nil {
+				t.Fatalf("failed to flush: %v", err)
+			}
+
+			if got := buf.Bytes(); !bytes.Equal(tt.want, got) {
+				t.Fatalf("unexpected output:\n- want: %v\n-  got: %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFrameWriterInvalidFraming(t *testing.T) {
+	for _, fw := range []*FrameWriter{
+		NewFrameWriter(io.Discard, Framing(-1), 0),
+		NewFrameWriter(io.Discard, FramingSnapLen, 0),
+	} {
+		if err := fw.WriteFrame(&Frame{}); err != ErrInvalidFraming {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}